@@ -0,0 +1,14 @@
+package main
+
+// The general information of the v2 Swagger document, generated with the v2 instance name
+// from this file rather than main.go.
+
+//	@title						go-boilerplate-rest-api-chi
+//	@version					2.0
+//	@description				This is a sample API boilerplate with Chi.
+//	@BasePath					/api/v2
+//	@schemes					http
+//	@securityDefinitions.apiKey	ApiKeyAuth
+//	@in							header
+//	@name						Authorization
+//	@description				JWT security accessToken or API key. Please add it in the format "Bearer {AccessToken}" or "ApiKey {key}" to authorize your requests.
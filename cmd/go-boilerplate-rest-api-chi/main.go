@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -21,12 +22,12 @@ import (
 // @title						go-boilerplate-rest-api-chi
 // @version					1.0
 // @description				This is a sample API boilerplate with Chi.
-// @BasePath					/api
+// @BasePath					/api/v1
 // @schemes					http
 // @securityDefinitions.apiKey	ApiKeyAuth
 // @in							header
 // @name						Authorization
-// @description				JWT security accessToken. Please add it in the format "Bearer {AccessToken}" to authorize your requests.
+// @description				JWT security accessToken or API key. Please add it in the format "Bearer {AccessToken}" or "ApiKey {key}" to authorize your requests.
 func main() {
 	config, err := config.LoadConfig()
 	if err != nil {
@@ -43,13 +44,21 @@ func main() {
 		log.Fatal("failed to init connection with database", err)
 	}
 
-	handler := api.CreateApi(config, logger, database.Gorm)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	repositories := api.NewRepositories(ctx, config, logger, database.Gorm)
+	handler, shutdownApi := api.CreateApi(ctx, config, logger, database.Gorm, repositories)
 
 	addr := fmt.Sprintf("%s:%d", config.Api.Host, config.Api.Port)
 	srv := &http.Server{
 		Addr:              addr,
 		Handler:           handler,
-		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       config.HTTP.ReadTimeout,
+		ReadHeaderTimeout: config.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      config.HTTP.WriteTimeout,
+		IdleTimeout:       config.HTTP.IdleTimeout,
+		MaxHeaderBytes:    config.HTTP.MaxHeaderBytes,
 	}
 
 	go func() {
@@ -59,8 +68,21 @@ func main() {
 		}
 	}()
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
+	grpcServer := api.CreateGrpcServer(config, logger, database.Gorm, repositories)
+	grpcAddr := fmt.Sprintf("%s:%d", config.Api.Host, config.GRPC.Port)
+
+	go func() {
+		listener, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			logger.Error().Err(err).Msg("gRPC listen error")
+			return
+		}
+
+		logger.Info().Msgf("gRPC server listening on %s", grpcAddr)
+		if err := grpcServer.Serve(listener); err != nil {
+			logger.Error().Err(err).Msg("gRPC serve error")
+		}
+	}()
 
 	<-ctx.Done()
 	logger.Info().Msg("Shutting down server...")
@@ -68,10 +90,24 @@ func main() {
 	ctxShutdown, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// Both servers drain their requests in parallel, before the database is closed.
+	grpcStopped := make(chan error, 1)
+	go func() {
+		grpcStopped <- grpcServer.Shutdown(ctxShutdown)
+	}()
+
 	if err := srv.Shutdown(ctxShutdown); err != nil {
 		logger.Error().Err(err).Msg("Forced shutdown")
 	}
 
+	if err := <-grpcStopped; err != nil {
+		logger.Error().Err(err).Msg("Forced shutdown of the gRPC server")
+	}
+
+	if err := shutdownApi(ctxShutdown); err != nil {
+		logger.Error().Err(err).Msg("Forced shutdown of the websocket connections and export jobs")
+	}
+
 	if err := database.Close(); err != nil {
 		logger.Error().Err(err).Msg("Failed to close database")
 	}
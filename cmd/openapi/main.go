@@ -0,0 +1,51 @@
+// Command openapi converts the Swagger 2.0 document generated by swag into the OpenAPI 3.1
+// documents embedded in the binary.
+//
+//	go run ./cmd/openapi -in docs/swagger.json -out docs/openapi
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"go-boilerplate-rest-api-chi/internal/openapi"
+)
+
+func main() {
+	in := flag.String("in", "docs/swagger.json", "the Swagger 2.0 document generated by swag")
+	out := flag.String("out", "docs/openapi", "the path of the OpenAPI documents, without the .json and .yaml extensions")
+	flag.Parse()
+
+	swagger, err := os.ReadFile(*in)
+	if err != nil {
+		log.Fatal("failed to read the swagger document: ", err)
+	}
+
+	doc, err := openapi.Convert(swagger)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	jsonContent, err := json.MarshalIndent(doc, "", "    ")
+	if err != nil {
+		log.Fatal("failed to encode the json document: ", err)
+	}
+	if err := os.WriteFile(*out+".json", append(jsonContent, '\n'), 0o644); err != nil {
+		log.Fatal("failed to write the json document: ", err)
+	}
+
+	var yamlContent bytes.Buffer
+	encoder := yaml.NewEncoder(&yamlContent)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		log.Fatal("failed to encode the yaml document: ", err)
+	}
+	if err := os.WriteFile(*out+".yaml", yamlContent.Bytes(), 0o644); err != nil {
+		log.Fatal("failed to write the yaml document: ", err)
+	}
+}
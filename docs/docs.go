@@ -15,27 +15,72 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/authors": {
+        "/api-keys": {
+            "get": {
+                "description": "Get the API keys of the tenant of the caller, every key for a caller of the whole deployment, revoked ones included, without their secret values. Requires the api_keys:admin scope.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "List API keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_apikey.KeysSuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
             "post": {
-                "description": "Create a new author with the provided data",
+                "description": "Create a key for a machine client, sent as \"Authorization: ApiKey \u003ckey\u003e\" or in X-API-Key. The key is only returned here; its prefix identifies it afterwards. The caller must hold every scope it grants, and a caller bound to a tenant only creates keys of its tenant. Requires the api_keys:admin scope.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "authors"
+                    "api-keys"
                 ],
-                "summary": "Create a new author",
+                "summary": "Create an API key",
                 "parameters": [
                     {
-                        "description": "Author data",
-                        "name": "author",
+                        "description": "API key data",
+                        "name": "api_key",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_author_dto.CreateAuthorRequest"
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_apikey_dto.CreateKeyRequest"
                         }
                     }
                 ],
@@ -43,7 +88,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/internal_author.AuthorSuccessResponse"
+                            "$ref": "#/definitions/internal_apikey.KeySuccessResponse"
                         }
                     },
                     "400": {
@@ -52,36 +97,66 @@ const docTemplate = `{
                             "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
                         }
                     },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/authors/{author_id}": {
-            "get": {
-                "description": "Get a single author by its ID",
+        "/api-keys/{key_id}": {
+            "delete": {
+                "description": "Refuse the key from now on. It stays listed with its revocation date. Only a caller holding every scope of the key may revoke it. Requires the api_keys:admin scope.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "authors"
+                    "api-keys"
                 ],
-                "summary": "Get author by id",
+                "summary": "Revoke an API key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Author ID",
-                        "name": "author_id",
+                        "format": "uuid",
+                        "description": "API key ID",
+                        "name": "key_id",
                         "in": "path",
                         "required": true
                     }
@@ -90,7 +165,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_author.AuthorSuccessResponse"
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.SuccessResponse"
                         }
                     },
                     "400": {
@@ -99,6 +174,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -111,24 +198,59 @@ const docTemplate = `{
                             "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/books": {
-            "get": {
-                "description": "Get a list of all books",
+        "/api-keys/{key_id}/rotate": {
+            "post": {
+                "description": "Replace the secret value of a key, keeping its scopes and expiry. The previous value is refused at once and the new one is only returned here, to a caller holding every scope of the key. Requires the api_keys:admin scope.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "books"
+                    "api-keys"
+                ],
+                "summary": "Rotate an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "API key ID",
+                        "name": "key_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all books",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_book.BooksSuccessResponse"
+                            "$ref": "#/definitions/internal_apikey.KeySuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
                         }
                     },
                     "404": {
@@ -137,34 +259,50 @@ const docTemplate = `{
                             "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
                         }
                     }
-                }
-            },
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/authors": {
             "post": {
-                "description": "Create a new book with the provided data",
+                "description": "Create a new author with the provided data",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack",
+                    "text/csv"
                 ],
                 "tags": [
-                    "books"
+                    "authors"
                 ],
-                "summary": "Create a new book",
+                "summary": "Create a new author",
                 "parameters": [
                     {
-                        "description": "Book data",
-                        "name": "book",
+                        "description": "Author data",
+                        "name": "author",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_book_dto.CreateBookRequest"
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_author_dto.CreateAuthorRequest"
                         }
                     }
                 ],
@@ -172,7 +310,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/internal_book.BookSuccessResponse"
+                            "$ref": "#/definitions/internal_author.AuthorSuccessResponse"
                         }
                     },
                     "400": {
@@ -187,6 +325,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
                         }
                     },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -196,61 +346,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/books/secure": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Authenticated test route",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "books"
-                ],
-                "summary": "Authenticated test route",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.SuccessResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/books/{book_id}": {
+        "/authors/{author_id}": {
             "get": {
-                "description": "Get a single book by its ID",
+                "description": "Get a single author by its ID",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack",
+                    "text/csv"
                 ],
                 "tags": [
-                    "books"
+                    "authors"
                 ],
-                "summary": "Get book by id",
+                "summary": "Get author by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Book ID",
-                        "name": "book_id",
+                        "format": "uuid",
+                        "description": "Author ID",
+                        "name": "author_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. id,name,books.title",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "books"
+                        ],
+                        "type": "string",
+                        "description": "Comma separated relationships to load",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of the copy held, answered 304 when the author has not changed since, its books and stats not being loaded",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_book.BookSuccessResponse"
+                            "$ref": "#/definitions/internal_author.AuthorSuccessResponse"
+                        },
+                        "headers": {
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Date of the last change of the author, its books and stats not being loaded"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
                         }
                     },
                     "404": {
@@ -267,29 +426,51 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Delete a book by its ID",
-                "produces": [
+            "patch": {
+                "description": "Change the name of an author and emit author.renamed",
+                "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack",
+                    "text/csv"
+                ],
                 "tags": [
-                    "books"
+                    "authors"
                 ],
-                "summary": "Delete a book",
+                "summary": "Rename an author",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Book ID",
-                        "name": "book_id",
+                        "format": "uuid",
+                        "description": "Author ID",
+                        "name": "author_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "New name",
+                        "name": "author",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_author_dto.RenameAuthorRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.SuccessResponse"
+                            "$ref": "#/definitions/internal_author.AuthorSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
                         }
                     },
                     "404": {
@@ -298,6 +479,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -305,42 +504,309 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Update a book with the provided data",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/authors/{author_id}/books": {
+            "get": {
+                "description": "Get a paginated list of the books written by an author",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack",
+                    "text/csv"
                 ],
                 "tags": [
-                    "books"
+                    "authors"
                 ],
-                "summary": "Update a book",
+                "summary": "List the books of an author",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Book ID",
-                        "name": "book_id",
+                        "format": "uuid",
+                        "description": "Author ID",
+                        "name": "author_id",
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_author.AuthorBooksSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books": {
+            "get": {
+                "description": "Get a list of all books",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack",
+                    "text/csv"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Get all books",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter on a part of the title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Filter on the author ID",
+                        "name": "author_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. id,title,author.name",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "author"
+                        ],
+                        "type": "string",
+                        "description": "Comma separated relationships to load",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_book.BooksSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new book with the provided data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack",
+                    "text/csv"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Create a new book",
+                "parameters": [
                     {
                         "description": "Book data",
                         "name": "book",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_book_dto.UpdateBookRequest"
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_book_dto.CreateBookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_book.BookSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/books/export": {
+            "get": {
+                "description": "Stream every book matching the filters as a downloadable file",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Export books",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter on a part of the title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Filter on the author ID",
+                        "name": "author_id",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.SuccessResponse"
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/exports": {
+            "post": {
+                "description": "Start an asynchronous export of the books matching the filters",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Create an export job",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter on a part of the title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Filter on the author ID",
+                        "name": "author_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/internal_book.ExportJobSuccessResponse"
                         }
                     },
                     "400": {
@@ -349,111 +815,1670 @@ const docTemplate = `{
                             "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/exports/{job_id}": {
+            "get": {
+                "description": "Get the status of an asynchronous export",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Get an export job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Export job ID",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_book.ExportJobSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/books/exports/{job_id}/download": {
+            "get": {
+                "description": "Download the file produced by a completed export job",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Download an export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Export job ID",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
                         }
-                    }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/secure": {
+            "get": {
+                "description": "Authenticated test route",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Authenticated test route",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/books/{book_id}": {
+            "get": {
+                "description": "Get a single book by its ID",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack",
+                    "text/csv"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Get book by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Book ID",
+                        "name": "book_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. id,title,author.name",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "author"
+                        ],
+                        "type": "string",
+                        "description": "Comma separated relationships to load",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of the copy held, answered 304 when the book and its author have not changed since",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_book.BookSuccessResponse"
+                        },
+                        "headers": {
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Date of the last change of the book or of its author"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a book by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Delete a book",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Book ID",
+                        "name": "book_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update a book with the provided data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Update a book",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Book ID",
+                        "name": "book_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Book data",
+                        "name": "book",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_book_dto.UpdateBookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "Server-Sent Events feed of the book and author changes. Every message has the event type as \"event\", an \"id\" to send back in Last-Event-ID when reconnecting, and the event as JSON \"data\". Comment lines are sent as heartbeats. A client falling too far behind is disconnected and resumes from its last event.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Stream change events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated event types, wildcards allowed, e.g. book.*,author.renamed",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated IDs of the books or authors to follow",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received, to resume the feed",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "event stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "post": {
+                "description": "Executes a query or a mutation of the GraphQL schema over the books and authors. Queries can also be sent with GET in the query, operationName and variables parameters. Errors are returned in \"errors\" with their code in \"extensions\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "graphql"
+                ],
+                "summary": "Execute a GraphQL query",
+                "parameters": [
+                    {
+                        "description": "GraphQL request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_graph.Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Result"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Result"
+                        }
+                    },
+                    "405": {
+                        "description": "Method Not Allowed",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Result"
+                        }
+                    }
+                }
+            }
+        },
+        "/tenants": {
+            "get": {
+                "description": "Get every tenant, disabled ones included. Requires the tenants:admin scope, held by a caller of the whole deployment.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "tenants"
+                ],
+                "summary": "List tenants",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_tenant.TenantsSuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Host a new catalogue, named by its ID in the X-Tenant-ID header or in the subdomain. Requires the tenants:admin scope, held by a caller of the whole deployment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "tenants"
+                ],
+                "summary": "Create a tenant",
+                "parameters": [
+                    {
+                        "description": "Tenant data",
+                        "name": "tenant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_tenant_dto.CreateTenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_tenant.TenantSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/tenants/{tenant_id}": {
+            "get": {
+                "description": "Requires the tenants:admin scope, held by a caller of the whole deployment.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "tenants"
+                ],
+                "summary": "Get a tenant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "tenant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_tenant.TenantSuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Rename a tenant, or disable it: the requests to its catalogue are refused with 403 while its data is kept. Requires the tenants:admin scope, held by a caller of the whole deployment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "tenants"
+                ],
+                "summary": "Update a tenant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "tenant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tenant changes",
+                        "name": "tenant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_tenant_dto.UpdateTenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_tenant.TenantSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/deliveries": {
+            "get": {
+                "description": "Get the delivery history, newest first. Use status=dead to read the dead-letter queue. Requires the webhooks:admin scope.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "succeeded",
+                            "dead"
+                        ],
+                        "type": "string",
+                        "description": "Delivery status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Subscription ID",
+                        "name": "subscription_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook.DeliveriesSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/deliveries/{delivery_id}": {
+            "get": {
+                "description": "Get a single delivery with its attempts and last error. Requires the webhooks:admin scope.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get a webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook.DeliverySuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/deliveries/{delivery_id}/replay": {
+            "post": {
+                "description": "Queue the payload of a delivery again, for instance one from the dead-letter queue. Requires the webhooks:admin scope.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Replay a webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook.DeliverySuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/subscriptions": {
+            "get": {
+                "description": "Get every registered webhook endpoint, without their secrets. Requires the webhooks:admin scope.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook.SubscriptionsSuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Subscribe an endpoint to event types (book.created, book.updated, book.deleted, author.created, author.renamed, or wildcards such as author.*). Payloads are signed with HMAC-SHA256 over \"\u003cX-Webhook-Timestamp\u003e.\u003cbody\u003e\" in X-Webhook-Signature. The secret is only returned here. Requires the webhooks:admin scope.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Register a webhook endpoint",
+                "parameters": [
+                    {
+                        "description": "Subscription data",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_webhook_dto.CreateSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook.SubscriptionSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/subscriptions/{subscription_id}": {
+            "delete": {
+                "description": "Delete a webhook endpoint and its delivery history. Requires the webhooks:admin scope.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Subscription ID",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/ws": {
+            "get": {
+                "description": "Upgrades to a WebSocket with the \"library.v1\" subprotocol. Browsers, which cannot set the Authorization header, offer the token as a \"bearer.\u003ctoken\u003e\" subprotocol. Clients send {\"type\":\"subscribe\"|\"unsubscribe\",\"book_id\":\"...\"}, a book of their tenant; the server pushes \"subscribed\"/\"unsubscribed\" acknowledgements, the \"presence\" of the viewers of the books followed, the \"book.updated\" and \"book.deleted\" changes made by other users, and \"error\" messages. The connection is closed with status 1001 when the server shuts down.",
+                "tags": [
+                    "realtime"
+                ],
+                "summary": "Open the WebSocket API",
+                "responses": {
+                    "101": {
+                        "description": "switching protocols",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        }
+    },
+    "definitions": {
+        "go-boilerplate-rest-api-chi_internal_apikey_dto.CreateKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "description": "ExpiresAt is left empty for a key that never expires.",
+                    "type": "string",
+                    "example": "2027-01-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Partner catalogue sync"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "books:read",
+                        "books:write"
+                    ]
+                },
+                "tenant_id": {
+                    "description": "TenantID binds the key to a tenant, whose catalogue is the only one it reaches. It is\nleft empty for a key of the whole deployment.",
+                    "type": "string",
+                    "maxLength": 63,
+                    "example": "city-library"
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_apikey_dto.KeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "8b7c6d5e-4f3a-4b2c-9d1e-0f9a8b7c6d5e"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "lib_3f9a1c2e_6b1d0e7f9c8a4b3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2a1b0c9d8e7f"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Partner catalogue sync"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "lib_3f9a1c2e"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "books:read",
+                        "books:write"
+                    ]
+                },
+                "tenant_id": {
+                    "type": "string",
+                    "example": "city-library"
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_author_dto.AuthorBookResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_author_dto.AuthorDetailResponse": {
+            "type": "object",
+            "properties": {
+                "books": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_author_dto.AuthorBookResponse"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "stats": {
+                    "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_author_dto.AuthorStatsResponse"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_author_dto.AuthorResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_author_dto.AuthorStatsResponse": {
+            "type": "object",
+            "properties": {
+                "book_count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "first_published_at": {
+                    "type": "string"
+                },
+                "latest_published_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_author_dto.CreateAuthorRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_author_dto.RenameAuthorRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_book_dto.BookResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_author_dto.AuthorResponse"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_book_dto.CreateBookRequest": {
+            "type": "object",
+            "required": [
+                "author_id",
+                "description",
+                "title"
+            ],
+            "properties": {
+                "author_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_book_dto.ExportJobResponse": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "download_url": {
+                    "type": "string",
+                    "example": "/api/books/exports/0d6f1b8e-8e0a-4a43-9a55-2f1c8b7b3e42/download"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "csv"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0d6f1b8e-8e0a-4a43-9a55-2f1c8b7b3e42"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "completed"
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_book_dto.UpdateBookRequest": {
+            "type": "object",
+            "required": [
+                "description"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_pagination.Meta": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "total_items": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_response.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "An error occurred"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "error"
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_response.SuccessResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Operation completed successfully"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_response.ValidationErrorDetail": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "email"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "email is required"
+                },
+                "param": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string",
+                    "example": "required"
                 }
             }
-        }
-    },
-    "definitions": {
-        "go-boilerplate-rest-api-chi_internal_author_dto.AuthorResponse": {
+        },
+        "go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "string"
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorDetail"
+                    }
                 },
-                "name": {
-                    "type": "string"
+                "message": {
+                    "type": "string",
+                    "example": "Validation failed"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "error"
                 }
             }
         },
-        "go-boilerplate-rest-api-chi_internal_author_dto.CreateAuthorRequest": {
+        "go-boilerplate-rest-api-chi_internal_tenant_dto.CreateTenantRequest": {
             "type": "object",
             "required": [
+                "id",
                 "name"
             ],
             "properties": {
+                "id": {
+                    "description": "ID names the tenant in its subdomain and in the X-Tenant-ID header.",
+                    "type": "string",
+                    "maxLength": 63,
+                    "example": "city-library"
+                },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "City library"
                 }
             }
         },
-        "go-boilerplate-rest-api-chi_internal_book_dto.BookResponse": {
+        "go-boilerplate-rest-api-chi_internal_tenant_dto.TenantResponse": {
             "type": "object",
             "properties": {
-                "author": {
-                    "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_author_dto.AuthorResponse"
+                "created_at": {
+                    "type": "string"
                 },
-                "description": {
+                "disabled_at": {
                     "type": "string"
                 },
                 "id": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "city-library"
                 },
-                "title": {
+                "name": {
+                    "type": "string",
+                    "example": "City library"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "go-boilerplate-rest-api-chi_internal_book_dto.CreateBookRequest": {
+        "go-boilerplate-rest-api-chi_internal_tenant_dto.UpdateTenantRequest": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "description": "Disabled refuses the requests to the catalogue of the tenant, its data being kept.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "City library"
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_webhook_dto.CreateSubscriptionRequest": {
             "type": "object",
             "required": [
-                "author_id",
-                "description",
-                "title"
+                "event_types",
+                "url"
             ],
             "properties": {
-                "author_id": {
+                "event_types": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "book.created",
+                        "author.*"
+                    ]
+                },
+                "secret": {
+                    "description": "Secret signs the payloads. A random one is generated when it is left empty.",
+                    "type": "string",
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/library"
+                }
+            }
+        },
+        "go-boilerplate-rest-api-chi_internal_webhook_dto.DeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "description": {
+                "event_id": {
+                    "type": "string",
+                    "example": "5c1e8a2b-7d4f-4e3a-9b6c-1a2b3c4d5e6f"
+                },
+                "event_type": {
+                    "type": "string",
+                    "example": "book.created"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0d6f1b8e-8e0a-4a43-9a55-2f1c8b7b3e42"
+                },
+                "last_error": {
                     "type": "string"
                 },
-                "title": {
+                "last_status_code": {
+                    "type": "integer",
+                    "example": 500
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "subscription_id": {
+                    "type": "string",
+                    "example": "6f0b8a5e-3b7e-4c55-9f0e-8b1c2d3e4f50"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "go-boilerplate-rest-api-chi_internal_book_dto.UpdateBookRequest": {
+        "go-boilerplate-rest-api-chi_internal_webhook_dto.SubscriptionResponse": {
             "type": "object",
-            "required": [
-                "description"
-            ],
             "properties": {
-                "description": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "book.created",
+                        "author.*"
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6f0b8a5e-3b7e-4c55-9f0e-8b1c2d3e4f50"
+                },
+                "secret": {
                     "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/library"
                 }
             }
         },
-        "go-boilerplate-rest-api-chi_internal_response.ErrorResponse": {
+        "gqlerrors.FormattedError": {
             "type": "object",
             "properties": {
+                "extensions": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "locations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/location.SourceLocation"
+                    }
+                },
                 "message": {
-                    "type": "string",
-                    "example": "An error occurred"
+                    "type": "string"
                 },
-                "status": {
-                    "type": "string",
-                    "example": "error"
+                "path": {
+                    "type": "array",
+                    "items": {}
                 }
             }
         },
-        "go-boilerplate-rest-api-chi_internal_response.SuccessResponse": {
+        "graphql.Result": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gqlerrors.FormattedError"
+                    }
+                },
+                "extensions": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "internal_apikey.KeySuccessResponse": {
             "type": "object",
             "properties": {
+                "api_key": {
+                    "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_apikey_dto.KeyResponse"
+                },
                 "message": {
                     "type": "string",
-                    "example": "Operation completed successfully"
+                    "example": "API key created successfully"
                 },
                 "status": {
                     "type": "string",
@@ -461,35 +2486,44 @@ const docTemplate = `{
                 }
             }
         },
-        "go-boilerplate-rest-api-chi_internal_response.ValidationErrorDetail": {
+        "internal_apikey.KeysSuccessResponse": {
             "type": "object",
             "properties": {
-                "field": {
-                    "type": "string",
-                    "example": "email"
+                "api_keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_apikey_dto.KeyResponse"
+                    }
                 },
                 "message": {
                     "type": "string",
-                    "example": "Email is required"
+                    "example": "API keys retrieved successfully"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
                 }
             }
         },
-        "go-boilerplate-rest-api-chi_internal_response.ValidationErrorResponse": {
+        "internal_author.AuthorBooksSuccessResponse": {
             "type": "object",
             "properties": {
-                "errors": {
+                "books": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_response.ValidationErrorDetail"
+                        "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_author_dto.AuthorBookResponse"
                     }
                 },
                 "message": {
                     "type": "string",
-                    "example": "Validation failed"
+                    "example": "Books retrieved successfully"
+                },
+                "pagination": {
+                    "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_pagination.Meta"
                 },
                 "status": {
                     "type": "string",
-                    "example": "error"
+                    "example": "success"
                 }
             }
         },
@@ -497,7 +2531,7 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "author": {
-                    "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_author_dto.AuthorResponse"
+                    "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_author_dto.AuthorDetailResponse"
                 },
                 "message": {
                     "type": "string",
@@ -543,11 +2577,159 @@ const docTemplate = `{
                     "example": "success"
                 }
             }
+        },
+        "internal_book.ExportJobSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "job": {
+                    "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_book_dto.ExportJobResponse"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Export job retrieved successfully"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "internal_graph.Request": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "{ books(first: 10) { nodes { title author { name } } } }"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "internal_tenant.TenantSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Tenant created successfully"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "tenant": {
+                    "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_tenant_dto.TenantResponse"
+                }
+            }
+        },
+        "internal_tenant.TenantsSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Tenants retrieved successfully"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "tenants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_tenant_dto.TenantResponse"
+                    }
+                }
+            }
+        },
+        "internal_webhook.DeliveriesSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_webhook_dto.DeliveryResponse"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Webhook deliveries retrieved successfully"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "internal_webhook.DeliverySuccessResponse": {
+            "type": "object",
+            "properties": {
+                "delivery": {
+                    "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_webhook_dto.DeliveryResponse"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Webhook delivery retrieved successfully"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "internal_webhook.SubscriptionSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Webhook subscription created successfully"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "subscription": {
+                    "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_webhook_dto.SubscriptionResponse"
+                }
+            }
+        },
+        "internal_webhook.SubscriptionsSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Webhook subscriptions retrieved successfully"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-boilerplate-rest-api-chi_internal_webhook_dto.SubscriptionResponse"
+                    }
+                }
+            }
+        },
+        "location.SourceLocation": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "integer"
+                },
+                "line": {
+                    "type": "integer"
+                }
+            }
         }
     },
     "securityDefinitions": {
         "ApiKeyAuth": {
-            "description": "JWT security accessToken. Please add it in the format \"Bearer {AccessToken}\" to authorize your requests.",
+            "description": "JWT security accessToken or API key. Please add it in the format \"Bearer {AccessToken}\" or \"ApiKey {key}\" to authorize your requests.",
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
@@ -559,10 +2741,10 @@ const docTemplate = `{
 var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
 	Host:             "",
-	BasePath:         "/api",
+	BasePath:         "/api/v1",
 	Schemes:          []string{"http"},
 	Title:            "go-boilerplate-rest-api-chi",
-	Description:      "This is a sample API with Chi.",
+	Description:      "This is a sample API boilerplate with Chi.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
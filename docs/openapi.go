@@ -0,0 +1,9 @@
+package docs
+
+import "embed"
+
+// OpenAPI holds the OpenAPI 3.1 documents generated by cmd/openapi from the swag output, one
+// per API version.
+//
+//go:embed openapi.json openapi.yaml v2_openapi.json v2_openapi.yaml
+var OpenAPI embed.FS
@@ -1,26 +1,49 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"os"
-	"time"
+	"slices"
+	"strings"
 
-	"github.com/MarceloPetrucio/go-scalar-api-reference"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
-	"github.com/go-chi/httprate"
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"gorm.io/gorm"
 
+	"go-boilerplate-rest-api-chi/docs"
+	"go-boilerplate-rest-api-chi/internal/apikey"
+	"go-boilerplate-rest-api-chi/internal/auth"
 	"go-boilerplate-rest-api-chi/internal/author"
 	"go-boilerplate-rest-api-chi/internal/book"
+	"go-boilerplate-rest-api-chi/internal/cache"
 	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/database"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/export"
+	"go-boilerplate-rest-api-chi/internal/graph"
+	"go-boilerplate-rest-api-chi/internal/httpcache"
+	"go-boilerplate-rest-api-chi/internal/openapi"
+	"go-boilerplate-rest-api-chi/internal/outbox"
+	"go-boilerplate-rest-api-chi/internal/ratelimit"
+	"go-boilerplate-rest-api-chi/internal/realtime"
+	"go-boilerplate-rest-api-chi/internal/request"
+	"go-boilerplate-rest-api-chi/internal/sse"
+	"go-boilerplate-rest-api-chi/internal/tenant"
 	internalValidator "go-boilerplate-rest-api-chi/internal/validator"
+	"go-boilerplate-rest-api-chi/internal/versioning"
+	"go-boilerplate-rest-api-chi/internal/webhook"
 )
 
-func CreateApi(cfg config.Config, logger zerolog.Logger, db *gorm.DB) http.Handler {
+// CreateApi builds the router. The background workers it starts stop when ctx is cancelled.
+// The returned shutdown waits for the WebSocket connections, which http.Server.Shutdown does
+// not track, to be closed, and cancels the export jobs.
+func CreateApi(ctx context.Context, cfg config.Config, logger zerolog.Logger, db *gorm.DB, repositories *Repositories) (http.Handler, func(context.Context) error) {
 	r := chi.NewRouter()
 
 	r.Use(
@@ -31,78 +54,376 @@ func CreateApi(cfg config.Config, logger zerolog.Logger, db *gorm.DB) http.Handl
 		middleware.CleanPath,
 		middleware.StripSlashes,
 		middleware.GetHead,
-		middleware.Timeout(10*time.Second),
-		middleware.Throttle(100), // limit the number of request globaly for all the api
-		httprate.LimitByRealIP(100, 1*time.Minute),
+		internalValidator.Languages,
 	)
 
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE"},
-		AllowedHeaders:   []string{"Authorization", "Content-Type"},
-		AllowCredentials: false,
-		MaxAge:           12 * int(time.Hour),
+		AllowedOrigins:   cfg.HTTP.CORS.AllowedOrigins,
+		AllowedMethods:   cfg.HTTP.CORS.AllowedMethods,
+		AllowedHeaders:   corsAllowedHeaders(cfg),
+		ExposedHeaders:   cfg.HTTP.CORS.ExposedHeaders,
+		AllowCredentials: cfg.HTTP.CORS.AllowCredentials,
+		MaxAge:           int(cfg.HTTP.CORS.MaxAge.Seconds()),
 	}))
 
+	apiKeyService := apikey.NewAPIKeyService(repositories.APIKeys, repositories.Tenants, logger)
+
 	api := chi.NewRouter()
 
 	api.Use(middleware.Heartbeat("/api/alive"))
+	api.Use(auth.Middleware(auth.Chain(auth.NewTokenAuthenticator(cfg.Auth, logger), apikey.NewAuthenticator(apiKeyService))))
+	// The limiter follows the authentication, to count the requests of a user together.
+	limiter := ratelimit.NewLimiter(rateLimitStore(ctx, cfg, logger), cfg.HTTP.RateLimit, logger)
+	api.Use(limiter.Global)
+	api.Use(request.MaxBodySize(cfg.Api.MaxBodySize))
 
 	validator := internalValidator.New()
+	webhook.RegisterValidations(validator)
+	apikey.RegisterValidations(validator)
+
+	tenantService := tenant.NewTenantService(repositories.Tenants, logger)
+	tenantResolver := tenant.NewResolver(tenantService, cfg.Tenancy, logger)
 
 	// -------- Repos / Services / Handlers --------
 
-	bookRepo := book.NewBookRepository(db, logger)
-	authorRepo := author.NewAuthorRepository(db, logger)
+	webhookRepo := webhook.NewWebhookRepository(db, logger)
+	txManager := database.NewTxManager(db, logger)
+
+	// -------- Events --------
+
+	// The relay hands each event once to relayed, for the subscribers whose effects are shared
+	// by the replicas, and the follower of the outbox hands it to the bus of every replica, for
+	// the subscribers serving the clients of the replica.
+	relayed := event.NewBus()
+	bus := event.NewBus()
+
+	dispatcher := webhook.NewDispatcher(webhookRepo, nil, cfg.Webhook, logger)
+	relayed.Subscribe("*", dispatcher.Publish)
+	go dispatcher.Run(ctx)
+
+	broker := sse.NewBroker(cfg.Events)
+	bus.Subscribe("book.*", broker.Publish)
+	bus.Subscribe("author.*", broker.Publish)
+	go func() {
+		<-ctx.Done()
+		broker.Close()
+	}()
+
+	// Through NATS, the hub broadcasts each event to the other replicas itself: it only needs it once.
+	broadcaster := realtimeBroadcaster(ctx, cfg.Realtime, logger)
+	hub := realtime.NewHub(broadcaster, cfg.Realtime, logger)
+	if err := hub.Start(ctx); err != nil {
+		logger.Error().Err(err).Msg("failed to start the realtime hub")
+	}
+	hubBus := bus
+	if _, shared := broadcaster.(*realtime.NATSBroadcaster); shared {
+		hubBus = relayed
+	}
+	hubBus.Subscribe(string(event.BookUpdated), hub.Publish)
+	hubBus.Subscribe(string(event.BookDeleted), hub.Publish)
+
+	outboxRepo := outbox.NewOutboxRepository(db, logger)
+	go outbox.NewFollower(outboxRepo, bus, cfg.Outbox, logger).Run(ctx)
+	if cfg.Outbox.RelayEnabled {
+		relay := outbox.NewRelay(outboxRepo, outboxSinks(ctx, cfg.Outbox, relayed, logger), cfg.Outbox, logger)
+		go relay.Run(ctx)
+	}
 
-	bookService := book.NewBookService(bookRepo, authorRepo, logger)
-	authorService := author.NewAuthorService(authorRepo, logger)
+	bookService := book.NewBookService(repositories.Books, repositories.Authors, txManager, logger)
+	authorService := author.NewAuthorService(repositories.Authors, logger)
+	webhookService := webhook.NewWebhookService(webhookRepo, logger)
 
-	bookHandler := book.NewBookHandler(bookService, validator, logger)
+	exports := export.NewManager(os.TempDir(), cfg.Export, logger)
+	exports.Start(ctx)
+
+	bookHandler := book.NewBookHandler(bookService, exports, validator, logger)
+	bookHandlerV2 := book.NewBookHandlerV2(bookService, exports, validator, logger)
 	authorHandler := author.NewAuthorHandler(authorService, validator, logger)
+	webhookHandler := webhook.NewWebhookHandler(webhookService, validator, logger)
+	apiKeyHandler := apikey.NewAPIKeyHandler(apiKeyService, validator, logger)
+	tenantHandler := tenant.NewTenantHandler(tenantService, validator, logger)
+	eventsHandler := sse.NewEventsHandler(broker, cfg.Events, logger)
+	realtimeHandler := realtime.NewHandler(hub, bookService, cfg.Realtime, logger)
+	graphHandler := graph.NewHandler(bookService, authorService, validator, cfg.GraphQL, logger)
+	docsHandler := openapi.NewHandler(docs.OpenAPI, "openapi", "Boilerplate - rest API - CHI", logger)
+	docsHandlerV2 := openapi.NewHandler(docs.OpenAPI, "v2_openapi", "Boilerplate - rest API - CHI - v2", logger)
 
-	api.Mount("/books", bookHandler.Routes())
-	api.Mount("/authors", authorHandler.Routes())
+	// The documentation is public in development, and served to the authenticated callers in
+	// the other environments where it is enabled.
+	docsEnabled := cfg.Api.Environment == "development" || cfg.Api.DocsEnabled
+	var docsMiddlewares chi.Middlewares
+	if cfg.Api.Environment != "development" {
+		docsMiddlewares = append(docsMiddlewares, auth.Required)
+	}
 
-	if cfg.Api.Environment == "development" {
-		api.Get("/docs", func(w http.ResponseWriter, r *http.Request) {
-			content, err := os.ReadFile("./docs/swagger.json")
-			if err != nil {
-				logger.Error().Err(err).Msg("Impossible de lire le fichier swagger.json")
-				http.Error(w, "Documentation non disponible", http.StatusInternalServerError)
-				return
-			}
-
-			htmlContent, err := scalar.ApiReferenceHTML(&scalar.Options{
-				SpecContent: string(content),
-
-				CustomOptions: scalar.CustomOptions{
-					PageTitle: "Boilerplate - rest API - CHI",
-				},
-				DarkMode:      true,
-				IsEditable:    false,
-				Theme:         scalar.ThemeBluePlanet,
-				HideModels:    true,
-				BaseServerURL: "/api",
-			})
-			if err != nil {
-				logger.Error().Err(err).Msg("Scalar Documentation error")
-			}
-
-			w.Header().Set("Content-Type", "text/html")
-			w.WriteHeader(http.StatusOK)
-			_, err = w.Write([]byte(htmlContent))
-			if err != nil {
-				panic(err)
-			}
-		})
+	// The limits of the routes are built once, for the versions to share their counters.
+	routeLimits := make(map[string]func(http.Handler) http.Handler, len(cfg.HTTP.RateLimit.Routes))
+	for route, limit := range cfg.HTTP.RateLimit.Routes {
+		routeLimits[route] = limiter.Route(route, limit)
+	}
+	limitedRoutes := make(map[string]bool, len(routeLimits))
+	cacheRules := httpCacheRules(cfg, bus, logger)
+	mount := func(r chi.Router, pattern string, handler http.Handler) {
+		if limit, ok := routeLimits[pattern]; ok {
+			r = r.With(limit)
+			limitedRoutes[pattern] = true
+		}
+		if rules, ok := cacheRules[pattern]; ok {
+			r = r.With(rules.Middleware)
+		}
+		r.Mount(pattern, handler)
+	}
+
+	// The versions share the services and differ only in the handlers mapping the DTOs.
+	versionRoutes := func(books http.Handler, exportBooks http.HandlerFunc, spec *openapi.Handler, specName string) chi.Router {
+		v := chi.NewRouter()
+
+		// Long-lived streams stay out of the request timeout and of the throttle counting the
+		// requests in flight.
+		streams := v.With(tenantResolver.Middleware)
+		mount(streams, "/events", eventsHandler.Routes())
+		mount(streams, "/ws", realtimeHandler.Routes())
+		// The streaming export takes precedence over the /books mount, keeping its rate limit.
+		exportRoutes := streams
+		if limit, ok := routeLimits["/books"]; ok {
+			exportRoutes = exportRoutes.With(limit)
+		}
+		exportRoutes.Get("/books/export", exportBooks)
+
+		routes := v.With(requestLimits(cfg.HTTP)...)
+		if cfg.Api.ValidateRequests {
+			routes = routes.With(openapi.NewValidator(docs.OpenAPI, specName, cfg.Api.ValidateResponses, logger).Middleware)
+		}
+
+		// The catalogue and its webhooks belong to the tenant of the request, the administration
+		// to the whole deployment.
+		catalogue := routes.With(tenantResolver.Middleware)
+		mount(catalogue, "/books", books)
+		mount(catalogue, "/authors", authorHandler.Routes())
+		mount(catalogue, "/graphql", graphHandler.Routes())
+		mount(catalogue, "/webhooks", webhookHandler.Routes())
+		mount(routes, "/api-keys", apiKeyHandler.Routes())
+		mount(routes, "/tenants", tenantHandler.Routes())
+
+		if docsEnabled {
+			docRoutes := routes.With(docsMiddlewares...)
+			docRoutes.Get("/docs", spec.Reference)
+			docRoutes.Get("/openapi.json", spec.JSON)
+			docRoutes.Get("/openapi.yaml", spec.YAML)
+		}
+
+		return v
+	}
+
+	v1 := chi.Chain(
+		versioning.Version("1"),
+		versioning.Deprecate(cfg.Api.V1DeprecatedAt, cfg.Api.V1SunsetAt, "/api/v2"),
+	).Handler(versionRoutes(bookHandler.Routes(), bookHandler.ExportBooks, docsHandler, "openapi"))
+	v2 := versioning.Version("2")(versionRoutes(bookHandlerV2.Routes(), bookHandlerV2.ExportBooks, docsHandlerV2, "v2_openapi"))
+
+	api.Mount("/v1", v1)
+	api.Mount("/v2", v2)
+	// The unversioned routes follow the API-Version header, v1 by default.
+	api.Mount("/", versioning.Select(map[string]http.Handler{"1": v1, "2": v2}, "1"))
+
+	for route := range routeLimits {
+		if !limitedRoutes[route] {
+			logger.Warn().Str("route", route).Msg("HTTP_RATE_LIMIT_ROUTES names a route that is not mounted, its limit is ignored")
+		}
+	}
+
+	routes := api.With(requestLimits(cfg.HTTP)...)
+
+	if docsEnabled {
+		docRoutes := routes.With(docsMiddlewares...)
+		docRoutes.Get("/doc/*", httpSwagger.WrapHandler)
+		docRoutes.Get("/doc/v2/*", httpSwagger.Handler(httpSwagger.InstanceName("v2")))
+	}
+
+	if len(repositories.Caches) > 0 {
+		// The caches hold the lookups of every tenant.
+		routes.With(auth.RequireScope(cache.AdminScope), tenant.DeploymentOnly).Get("/cache/stats", cache.StatsHandler(repositories.Caches...))
 	}
 
 	if cfg.Api.Environment == "development" {
-		api.Get("/doc/*", httpSwagger.WrapHandler)
+		routes.Get("/graphiql", graphHandler.Playground("/api/graphql"))
 	}
 
 	r.Mount("/api", api)
 
-	return r
+	shutdown := func(ctx context.Context) error {
+		return errors.Join(hub.Shutdown(ctx), exports.Shutdown(ctx))
+	}
+
+	return r, shutdown
+}
+
+// corsAllowedHeaders adds the tenant header to the allowed ones in multi-tenant mode.
+func corsAllowedHeaders(cfg config.Config) []string {
+	headers := cfg.HTTP.CORS.AllowedHeaders
+	if !cfg.Tenancy.Enabled || slices.ContainsFunc(headers, func(header string) bool {
+		return strings.EqualFold(header, cfg.Tenancy.Header)
+	}) {
+		return headers
+	}
+
+	return append(slices.Clone(headers), cfg.Tenancy.Header)
+}
+
+// rateLimitStore shares the rate limit counters of the replicas through Redis when it is
+// configured, and counts in process otherwise.
+func rateLimitStore(ctx context.Context, cfg config.Config, logger zerolog.Logger) ratelimit.Store {
+	if cfg.HTTP.RateLimit.Store != "redis" {
+		return ratelimit.NewMemoryStore()
+	}
+
+	store, err := ratelimit.ConnectRedis(ctx, cfg.Redis.URL)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to connect to Redis, rate limits are counted per replica")
+		return ratelimit.NewMemoryStore()
+	}
+
+	return store
+}
+
+// httpCacheRules builds the caching rules of the routes of HTTP_CACHE_ROUTES once, for the
+// versions to share the responses kept in memory. The memory is purged on every change of
+// the catalogue.
+func httpCacheRules(cfg config.Config, bus *event.Bus, logger zerolog.Logger) map[string]httpcache.Rules {
+	var memory *httpcache.Memory
+	if cfg.HTTP.Cache.MemorySize > 0 && len(cfg.HTTP.Cache.Routes) > 0 {
+		memory = httpcache.NewMemory(cfg.HTTP.Cache.MemorySize, logger)
+		purge := func(context.Context, event.Event) error {
+			memory.Purge()
+			return nil
+		}
+		bus.Subscribe("book.*", purge)
+		bus.Subscribe("author.*", purge)
+	}
+
+	vary := []string{"Authorization", apikey.Header, versioning.Header}
+	if cfg.Tenancy.Enabled {
+		vary = append(vary, cfg.Tenancy.Header)
+	}
+
+	rules := make(map[string]httpcache.Rules, len(cfg.HTTP.Cache.Routes))
+	for route, policy := range cfg.HTTP.Cache.Routes {
+		rules[route] = httpcache.Rules{Policy: policy, Vary: vary, Memory: memory}
+	}
+	return rules
+}
+
+// requestLimits bounds the duration of the requests and the number handled at once.
+func requestLimits(cfg config.HTTPConfig) chi.Middlewares {
+	var limits chi.Middlewares
+	if cfg.RequestTimeout > 0 {
+		limits = append(limits, middleware.Timeout(cfg.RequestTimeout))
+	}
+	if cfg.MaxInFlight > 0 {
+		limits = append(limits, middleware.Throttle(cfg.MaxInFlight))
+	}
+	return limits
+}
+
+// realtimeBroadcaster shares the presence through NATS when it is configured, and stays
+// in process otherwise.
+func realtimeBroadcaster(ctx context.Context, cfg config.RealtimeConfig, logger zerolog.Logger) realtime.Broadcaster {
+	if cfg.NATSURL == "" {
+		return realtime.NewLocalBroadcaster()
+	}
+
+	broadcaster, err := realtime.ConnectNATS(ctx, cfg.NATSURL, cfg.NATSSubject, logger)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to connect to NATS, presence is not shared with the other replicas")
+		return realtime.NewLocalBroadcaster()
+	}
+
+	return broadcaster
+}
+
+// outboxSinks always relays to the in-process bus of the relayed events, plus NATS and the
+// local Kafka stand-in when they are configured.
+func outboxSinks(ctx context.Context, cfg config.OutboxConfig, bus *event.Bus, logger zerolog.Logger) []outbox.Sink {
+	sinks := []outbox.Sink{outbox.NewBusSink(bus)}
+
+	if cfg.NATSURL != "" {
+		natsSink, err := outbox.ConnectNATS(ctx, cfg.NATSURL, cfg.NATSSubject, logger)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to connect to NATS, events are not relayed to it")
+		} else {
+			sinks = append(sinks, natsSink)
+		}
+	}
+
+	if cfg.KafkaLocal {
+		sinks = append(sinks, outbox.NewKafkaSink(outbox.NewMemoryKafka(3), cfg.KafkaTopic))
+	}
+
+	return sinks
+}
+
+// Repositories are shared by the REST API and the gRPC server, for them to use the same
+// caches and connections.
+type Repositories struct {
+	Books   book.BookRepository
+	Authors author.AuthorRepository
+	Tenants tenant.TenantRepository
+	APIKeys apikey.APIKeyRepository
+	// Caches are the caches of the lookups, empty when they are not cached.
+	Caches []*cache.Cache
+}
+
+// NewRepositories builds the repositories, the lookups of the books, authors and tenants being
+// cached unless CACHE_STORE is none. The memory caches share their invalidations through Redis
+// when it is configured, and stay local to the replica otherwise. The caches stop following
+// the invalidations when ctx is cancelled.
+func NewRepositories(ctx context.Context, cfg config.Config, logger zerolog.Logger, db *gorm.DB) *Repositories {
+	repositories := &Repositories{
+		Books:   book.NewBookRepository(db, logger),
+		Authors: author.NewAuthorRepository(db, logger),
+		Tenants: tenant.NewTenantRepository(db, logger),
+		APIKeys: apikey.NewAPIKeyRepository(db, logger),
+	}
+
+	if cfg.Cache.Store != "memory" && cfg.Cache.Store != "redis" {
+		return repositories
+	}
+
+	var client *redis.Client
+	if cfg.Redis.URL != "" {
+		var err error
+		if client, err = cache.ConnectRedis(ctx, cfg.Redis.URL); err != nil {
+			logger.Error().Err(err).Msg("failed to connect to Redis, the lookups are not cached")
+			return repositories
+		}
+	}
+
+	local := cache.NewLocalNotifier()
+	var caches []*cache.Cache
+	for _, name := range []string{"authors", "books", "tenants"} {
+		var store cache.Store = cache.NewMemoryStore(cfg.Cache.Size)
+		var notifier cache.Notifier = local
+		switch {
+		case cfg.Cache.Store == "redis":
+			store = cache.NewRedisStore(client)
+		case client != nil:
+			notifier = cache.NewRedisNotifier(client, cfg.Cache.Channel, logger)
+		}
+
+		c := cache.New(name, store, notifier, cfg.Cache.TTL, logger)
+		// Without the invalidations of the other replicas, the entries could outlive the
+		// changes made there.
+		if err := c.Start(ctx); err != nil {
+			logger.Error().Err(err).Msg("failed to subscribe to the cache invalidations, the lookups are not cached")
+			return repositories
+		}
+		caches = append(caches, c)
+	}
+
+	repositories.Authors = author.NewCachedAuthorRepository(repositories.Authors, caches[0])
+	repositories.Books = book.NewCachedBookRepository(repositories.Books, repositories.Authors, caches[1])
+	repositories.Tenants = tenant.NewCachedTenantRepository(repositories.Tenants, caches[2])
+	repositories.Caches = caches
+
+	return repositories
 }
@@ -1,27 +1,45 @@
 package api_test
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
 	"go-boilerplate-rest-api-chi/internal/api"
 	"go-boilerplate-rest-api-chi/internal/config"
 	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	"go-boilerplate-rest-api-chi/internal/test-utils"
 )
 
 func TestCreateApi(t *testing.T) {
-	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	_ = db.AutoMigrate(&entity.Book{}, &entity.Author{})
+	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	// Every connection to :memory: opens a database of its own, the background workers must
+	// share the one migrated here.
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.Use(tenant.Plugin{}))
+	_ = db.AutoMigrate(&entity.Tenant{}, &entity.Book{}, &entity.Author{}, &entity.WebhookSubscription{}, &entity.WebhookDelivery{}, &entity.OutboxMessage{}, &entity.APIKey{})
+	require.NoError(t, db.Create(&entity.Tenant{ID: entity.DefaultTenantID, Name: "Default"}).Error)
+
+	createApi := func(t *testing.T, cfg config.Config) (http.Handler, func(context.Context) error) {
+		return api.CreateApi(t.Context(), cfg, zerolog.Nop(), db, api.NewRepositories(t.Context(), cfg, zerolog.Nop(), db))
+	}
 
 	t.Run("development_mode", func(t *testing.T) {
 		cfg := config.Config{Api: config.ApiConfig{Environment: "development"}}
-		handler := api.CreateApi(cfg, zerolog.Nop(), db)
+		handler, _ := createApi(t, cfg)
 
 		req := httptest.NewRequest(http.MethodGet, "/api/alive", nil)
 		rr := httptest.NewRecorder()
@@ -39,15 +57,671 @@ func TestCreateApi(t *testing.T) {
 		rr = httptest.NewRecorder()
 		handler.ServeHTTP(rr, req)
 		assert.NotEmpty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+
+		req = httptest.NewRequest(http.MethodGet, "/api/events?types=unknown", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/ws", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/books/secure", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+		req = httptest.NewRequest(http.MethodPost, "/api/graphql", strings.NewReader(`{"query": "{ books { totalCount } }"}`))
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"data": {"books": {"totalCount": 0}}}`, rr.Body.String())
+
+		req = httptest.NewRequest(http.MethodGet, "/api/graphiql", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/doc/v2/index.html", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/docs", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/html; charset=utf-8", rr.Header().Get("Content-Type"))
+
+		req = httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Body.String(), `"openapi": "3.1.0"`)
+		assert.Contains(t, rr.Body.String(), `"url": "/api/v1"`)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v2/openapi.yaml", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/yaml", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Body.String(), "openapi: 3.1.0")
+		assert.Contains(t, rr.Body.String(), "url: /api/v2")
+	})
+
+	t.Run("versioning", func(t *testing.T) {
+		cfg := config.Config{Api: config.ApiConfig{
+			Environment:    "production",
+			V1DeprecatedAt: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+			V1SunsetAt:     time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC),
+		}}
+		handler, _ := createApi(t, cfg)
+
+		// v1 reports an empty list as not found, v2 as an empty page.
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/books", nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, "1", rr.Header().Get("API-Version"))
+		assert.Equal(t, "@1793491200", rr.Header().Get("Deprecation"))
+		assert.Equal(t, "Sat, 01 May 2027 00:00:00 GMT", rr.Header().Get("Sunset"))
+		assert.Equal(t, `</api/v2>; rel="successor-version"`, rr.Header().Get("Link"))
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v2/books", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "2", rr.Header().Get("API-Version"))
+		assert.Empty(t, rr.Header().Get("Deprecation"))
+		assert.Contains(t, rr.Body.String(), `"pagination"`)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/books", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, "1", rr.Header().Get("API-Version"))
+
+		req = httptest.NewRequest(http.MethodGet, "/api/books", nil)
+		req.Header.Set("API-Version", "2")
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, "2", rr.Header().Get("API-Version"))
+
+		req = httptest.NewRequest(http.MethodGet, "/api/books", nil)
+		req.Header.Set("API-Version", "3")
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v2/ws", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
 	})
 
 	t.Run("production_mode", func(t *testing.T) {
 		cfg := config.Config{Api: config.ApiConfig{Environment: "production"}}
-		handler := api.CreateApi(cfg, zerolog.Nop(), db)
+		handler, _ := createApi(t, cfg)
 
 		req := httptest.NewRequest(http.MethodGet, "/api/doc/index.html", nil)
 		rr := httptest.NewRecorder()
 		handler.ServeHTTP(rr, req)
 		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/graphiql", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("production_mode_with_docs", func(t *testing.T) {
+		cfg := config.Config{
+			Api:  config.ApiConfig{Environment: "production", DocsEnabled: true},
+			Auth: config.AuthConfig{JWTSecret: "test-secret"},
+		}
+		handler, _ := createApi(t, cfg)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/doc/index.html", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub": "user-1",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte("test-secret"))
+		require.NoError(t, err)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v2/openapi.json", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"url": "/api/v2"`)
+	})
+	t.Run("api_keys", func(t *testing.T) {
+		cfg := config.Config{
+			Api:  config.ApiConfig{Environment: "production"},
+			Auth: config.AuthConfig{JWTSecret: "test-secret"},
+		}
+		handler, _ := createApi(t, cfg)
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":   "admin-1",
+			"scope": "api_keys:admin books:read",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte("test-secret"))
+		require.NoError(t, err)
+
+		call := func(method, target, body string, headers map[string]string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, target, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			for name, value := range headers {
+				req.Header.Set(name, value)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			return rr
+		}
+		admin := map[string]string{"Authorization": "Bearer " + token}
+
+		// A key never gets a scope its creator does not hold.
+		rr := call(http.MethodPost, "/api/v2/api-keys", `{"name": "Partner", "scopes": ["tenants:admin"]}`, admin)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		rr = call(http.MethodPost, "/api/v2/api-keys", `{"name": "Partner", "scopes": ["books:read"]}`, admin)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		var created struct {
+			Key struct {
+				ID  string `json:"id"`
+				Key string `json:"key"`
+			} `json:"api_key"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+
+		// The key authenticates through both headers, but cannot manage the keys.
+		rr = call(http.MethodGet, "/api/books/secure", "", map[string]string{"Authorization": "ApiKey " + created.Key.Key})
+		assert.Equal(t, http.StatusOK, rr.Code)
+		rr = call(http.MethodGet, "/api/books/secure", "", map[string]string{"X-API-Key": created.Key.Key})
+		assert.Equal(t, http.StatusOK, rr.Code)
+		rr = call(http.MethodGet, "/api/v2/api-keys", "", map[string]string{"X-API-Key": created.Key.Key})
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		rr = call(http.MethodGet, "/api/v2/api-keys", "", admin)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"last_used_at"`)
+		assert.NotContains(t, rr.Body.String(), created.Key.Key)
+
+		rr = call(http.MethodPost, "/api/v2/api-keys/"+created.Key.ID+"/rotate", "", admin)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var rotated struct {
+			Key struct {
+				Key string `json:"key"`
+			} `json:"api_key"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &rotated))
+
+		rr = call(http.MethodGet, "/api/books/secure", "", map[string]string{"X-API-Key": created.Key.Key})
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		rr = call(http.MethodGet, "/api/books/secure", "", map[string]string{"X-API-Key": rotated.Key.Key})
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = call(http.MethodDelete, "/api/v2/api-keys/"+created.Key.ID, "", admin)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		rr = call(http.MethodGet, "/api/books/secure", "", map[string]string{"X-API-Key": rotated.Key.Key})
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+	t.Run("oidc_mode", func(t *testing.T) {
+		provider := testutils.NewOIDCServer(t)
+		cfg := config.Config{
+			Api: config.ApiConfig{Environment: "production"},
+			Auth: config.AuthConfig{Mode: "oidc", OIDC: config.OIDCConfig{
+				Issuer:       provider.Issuer(),
+				JWKSCacheTTL: time.Hour,
+				RoleClaims:   []string{"realm_access.roles"},
+				RoleScopes:   map[string]string{"admin": "api_keys:admin books:read"},
+			}},
+		}
+		handler, _ := createApi(t, cfg)
+
+		call := func(method, target, token string) int {
+			req := httptest.NewRequest(method, target, strings.NewReader(`{"name": "Partner", "scopes": ["books:read"]}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			return rr.Code
+		}
+		claims := func(roles ...any) jwt.MapClaims {
+			return jwt.MapClaims{
+				"sub":          "user-1",
+				"exp":          time.Now().Add(time.Hour).Unix(),
+				"realm_access": map[string]any{"roles": roles},
+			}
+		}
+
+		assert.Equal(t, http.StatusOK, call(http.MethodGet, "/api/books/secure", provider.Sign(t, claims())))
+		assert.Equal(t, http.StatusForbidden, call(http.MethodPost, "/api/api-keys", provider.Sign(t, claims())))
+		assert.Equal(t, http.StatusCreated, call(http.MethodPost, "/api/api-keys", provider.Sign(t, claims("admin"))))
+
+		// The tokens signed with the shared secret are not accepted anymore.
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims()).SignedString([]byte("test-secret"))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, call(http.MethodGet, "/api/books/secure", token))
+	})
+	t.Run("multi_tenancy", func(t *testing.T) {
+		cfg := config.Config{
+			Api:     config.ApiConfig{Environment: "production"},
+			Auth:    config.AuthConfig{JWTSecret: "test-secret"},
+			Tenancy: config.TenancyConfig{Enabled: true, Header: "X-Tenant-ID", BaseDomain: "library.example.com"},
+		}
+		handler, _ := createApi(t, cfg)
+
+		sign := func(claims jwt.MapClaims) string {
+			claims["exp"] = time.Now().Add(time.Hour).Unix()
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+			require.NoError(t, err)
+			return token
+		}
+		admin := sign(jwt.MapClaims{"sub": "admin-1", "scope": "tenants:admin"})
+
+		call := func(method, target, body, tenantID, token string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, target, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tenantID != "" {
+				req.Header.Set("X-Tenant-ID", tenantID)
+			}
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			return rr
+		}
+		var created struct {
+			Author struct {
+				ID string `json:"id"`
+			} `json:"author"`
+			Book struct {
+				ID string `json:"id"`
+			} `json:"book"`
+		}
+		books := make(map[string]string)
+		authors := make(map[string]string)
+
+		for _, id := range []string{"acme", "globex"} {
+			rr := call(http.MethodPost, "/api/tenants", `{"id": "`+id+`", "name": "`+id+` library"}`, "", admin)
+			require.Equal(t, http.StatusCreated, rr.Code)
+
+			// The names and titles are unique per tenant only.
+			rr = call(http.MethodPost, "/api/authors", `{"name": "Victor Hugo"}`, id, "")
+			require.Equal(t, http.StatusCreated, rr.Code)
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+			authors[id] = created.Author.ID
+
+			rr = call(http.MethodPost, "/api/books", `{"title": "Les misérables", "description": "Jean Valjean", "author_id": "`+created.Author.ID+`"}`, id, "")
+			require.Equal(t, http.StatusCreated, rr.Code)
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+			books[id] = created.Book.ID
+		}
+		assert.Equal(t, http.StatusConflict, call(http.MethodPost, "/api/tenants", `{"id": "acme", "name": "Acme"}`, "", admin).Code)
+
+		// Each tenant sees its own catalogue only.
+		rr := call(http.MethodGet, "/api/books", "", "acme", "")
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), books["acme"])
+		assert.NotContains(t, rr.Body.String(), books["globex"])
+		assert.Equal(t, http.StatusNotFound, call(http.MethodGet, "/api/books/"+books["globex"], "", "acme", "").Code)
+		assert.Equal(t, http.StatusNotFound, call(http.MethodDelete, "/api/books/"+books["globex"], "", "acme", "").Code)
+		assert.NotEqual(t, http.StatusCreated, call(http.MethodPost, "/api/books", `{"title": "Notre-Dame de Paris", "description": "Quasimodo", "author_id": "`+authors["globex"]+`"}`, "acme", "").Code)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/books/"+books["globex"], nil)
+		req.Host = "globex.library.example.com"
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		assert.Equal(t, http.StatusBadRequest, call(http.MethodGet, "/api/books", "", "", "").Code)
+		assert.Equal(t, http.StatusNotFound, call(http.MethodGet, "/api/books", "", "initech", "").Code)
+
+		// A token bound to a tenant cannot reach the others.
+		member := sign(jwt.MapClaims{"sub": "user-1", "tenant": "acme"})
+		assert.Equal(t, http.StatusOK, call(http.MethodGet, "/api/books/"+books["acme"], "", "", member).Code)
+		assert.Equal(t, http.StatusForbidden, call(http.MethodGet, "/api/books/"+books["globex"], "", "globex", member).Code)
+
+		rr = call(http.MethodPatch, "/api/tenants/globex", `{"disabled": true}`, "", admin)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, http.StatusForbidden, call(http.MethodGet, "/api/books", "", "globex", "").Code)
+	})
+	t.Run("lookup_cache", func(t *testing.T) {
+		cfg := config.Config{
+			Api:   config.ApiConfig{Environment: "production"},
+			Auth:  config.AuthConfig{JWTSecret: "test-secret"},
+			Cache: config.CacheConfig{Store: "memory", TTL: time.Minute, Size: 100},
+		}
+		handler, _ := createApi(t, cfg)
+
+		call := func(method, target, body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, target, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			return rr
+		}
+		var created struct {
+			Author struct {
+				ID string `json:"id"`
+			} `json:"author"`
+			Book struct {
+				ID string `json:"id"`
+			} `json:"book"`
+		}
+
+		rr := call(http.MethodPost, "/api/authors", `{"name": "Alexandre Dumas"}`)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+		rr = call(http.MethodPost, "/api/books", `{"title": "Les Trois Mousquetaires", "description": "D'Artagnan", "author_id": "`+created.Author.ID+`"}`)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+
+		assert.Equal(t, http.StatusOK, call(http.MethodGet, "/api/books/"+created.Book.ID, "").Code)
+		rr = call(http.MethodPatch, "/api/books/"+created.Book.ID, `{"description": "Athos, Porthos et Aramis"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		// The update is seen at once, the author being read from its own cache.
+		rr = call(http.MethodGet, "/api/books/"+created.Book.ID+"?expand=author", "")
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Athos, Porthos et Aramis")
+		assert.Contains(t, rr.Body.String(), "Alexandre Dumas")
+		assert.Equal(t, http.StatusOK, call(http.MethodGet, "/api/books/"+created.Book.ID, "").Code)
+
+		assert.Equal(t, http.StatusUnauthorized, call(http.MethodGet, "/api/cache/stats", "").Code)
+
+		stats := func(claims jwt.MapClaims) *httptest.ResponseRecorder {
+			claims["exp"] = time.Now().Add(time.Hour).Unix()
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/cache/stats", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			return rr
+		}
+
+		assert.Equal(t, http.StatusForbidden, stats(jwt.MapClaims{"sub": "user-1"}).Code)
+		// The caches are shared by every tenant: a caller bound to one is refused.
+		assert.Equal(t, http.StatusForbidden, stats(jwt.MapClaims{"sub": "admin-1", "scope": "cache:admin", "tenant": "acme"}).Code)
+
+		rr = stats(jwt.MapClaims{"sub": "admin-1", "scope": "cache:admin"})
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{
+			"status": "success",
+			"message": "Cache statistics retrieved successfully",
+			"caches": {
+				"books": {"hits": 1, "misses": 2},
+				"authors": {"hits": 2, "misses": 1},
+				"tenants": {"hits": 0, "misses": 0}
+			}
+		}`, rr.Body.String())
+	})
+	t.Run("http_cache", func(t *testing.T) {
+		cfg := config.Config{
+			Api: config.ApiConfig{Environment: "production"},
+			HTTP: config.HTTPConfig{Cache: config.HTTPCacheConfig{
+				Routes:     map[string]config.CachePolicy{"/books": {MaxAge: time.Minute, StaleWhileRevalidate: 30 * time.Second}},
+				MemorySize: 100,
+			}},
+		}
+		handler, _ := createApi(t, cfg)
+
+		call := func(method, target, body string, header http.Header) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, target, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			for name, values := range header {
+				req.Header[name] = values
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			return rr
+		}
+		var created struct {
+			Author struct {
+				ID string `json:"id"`
+			} `json:"author"`
+			Book struct {
+				ID string `json:"id"`
+			} `json:"book"`
+		}
+
+		rr := call(http.MethodPost, "/api/authors", `{"name": "Jules Verne"}`, nil)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+		assert.Empty(t, rr.Header().Get("Cache-Control"), "the writes are not cached")
+		rr = call(http.MethodPost, "/api/books", `{"title": "Vingt mille lieues sous les mers", "description": "Nemo", "author_id": "`+created.Author.ID+`"}`, nil)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+
+		rr = call(http.MethodGet, "/api/books/"+created.Book.ID, "", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "public, max-age=60, stale-while-revalidate=30", rr.Header().Get("Cache-Control"))
+		assert.Equal(t, "Accept, Origin, Authorization, X-API-Key, API-Version", rr.Header().Get("Vary"))
+		lastModified := rr.Header().Get("Last-Modified")
+		require.NotEmpty(t, lastModified)
+
+		rr = call(http.MethodGet, "/api/books/"+created.Book.ID, "", http.Header{"If-Modified-Since": {lastModified}})
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+		assert.Empty(t, rr.Body.String())
+
+		// The list is kept in memory for the anonymous callers.
+		first := call(http.MethodGet, "/api/books?title=Vingt", "", nil)
+		assert.Equal(t, http.StatusOK, first.Code)
+		second := call(http.MethodGet, "/api/books?title=Vingt", "", nil)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.NotEmpty(t, second.Header().Get("Age"))
+
+		// The export jobs and the routes without a policy are left alone.
+		assert.Empty(t, call(http.MethodGet, "/api/books/exports/"+created.Book.ID, "", nil).Header().Get("Cache-Control"))
+		assert.Empty(t, call(http.MethodGet, "/api/authors/"+created.Author.ID, "", nil).Header().Get("Cache-Control"))
+	})
+	t.Run("openapi_validation", func(t *testing.T) {
+		cfg := config.Config{Api: config.ApiConfig{Environment: "development", ValidateRequests: true, ValidateResponses: true}}
+		handler, _ := createApi(t, cfg)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/books/42", nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.JSONEq(t, `{"status": "error", "message": "Validation failed", "errors": [{"field": "book_id", "message": "book_id must be a valid uuid"}]}`, rr.Body.String())
+
+		req = httptest.NewRequest(http.MethodPost, "/api/v2/books", strings.NewReader(`{"title": "Les Misérables"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.JSONEq(t, `{"status": "error", "message": "Validation failed", "errors": [
+			{"field": "author_id", "message": "author_id is required"},
+			{"field": "description", "message": "description is required"}
+		]}`, rr.Body.String())
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v2/books?page=0", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"field":"page"`)
+
+		// The responses match the document.
+		req = httptest.NewRequest(http.MethodPost, "/api/authors", strings.NewReader(`{"name": "Victor Hugo"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v2/books", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/books/f3c1b7a2-6d0e-4a8b-9c5d-2e7f1a3b4c5d", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code, rr.Body.String())
+	})
+	t.Run("request_body", func(t *testing.T) {
+		cfg := config.Config{Api: config.ApiConfig{Environment: "development", MaxBodySize: 64}}
+		handler, _ := createApi(t, cfg)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/authors", strings.NewReader(`{"name": "Victor Hugo", "nom": "Hugo"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.JSONEq(t, `{"status": "error", "message": "Invalid request body", "errors": [
+			{"field": "nom", "message": "nom is not a known field at line 1, column 25 (offset 24)", "tag": "unknown_field"}
+		]}`, rr.Body.String())
+
+		req = httptest.NewRequest(http.MethodPost, "/api/v2/authors", strings.NewReader(`{"name": "`+strings.Repeat("Hugo", 20)+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+
+		req = httptest.NewRequest(http.MethodPost, "/api/v2/authors", strings.NewReader(`name=Victor+Hugo`))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	})
+	t.Run("export_stream", func(t *testing.T) {
+		setup, _ := createApi(t, config.Config{Api: config.ApiConfig{Environment: "development"}})
+		post := func(target, body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			setup.ServeHTTP(rr, req)
+			return rr
+		}
+		var created struct {
+			Author struct {
+				ID string `json:"id"`
+			} `json:"author"`
+		}
+		rr := post("/api/authors", `{"name": "Gaston Leroux"}`)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+		rr = post("/api/books", `{"title": "Le Mystère de la chambre jaune", "description": "Rouletabille", "author_id": "`+created.Author.ID+`"}`)
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		// A request timeout shorter than any query: the stream must not inherit it.
+		cfg := config.Config{
+			Api:  config.ApiConfig{Environment: "development"},
+			HTTP: config.HTTPConfig{RequestTimeout: time.Nanosecond},
+		}
+		handler, _ := createApi(t, cfg)
+
+		for _, target := range []string{"/api/v1/books/export?format=ndjson", "/api/v2/books/export?format=ndjson"} {
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			rr = httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Contains(t, rr.Body.String(), `"title":"Le Mystère de la chambre jaune"`, target)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/books", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.NotEqual(t, http.StatusOK, rr.Code, "the other routes keep the timeout")
+	})
+	t.Run("cors_headers", func(t *testing.T) {
+		cfg := config.Config{
+			Api: config.ApiConfig{Environment: "production"},
+			HTTP: config.HTTPConfig{
+				CORS: config.CORSConfig{
+					AllowedOrigins: []string{"*"},
+					AllowedMethods: []string{"GET"},
+					AllowedHeaders: []string{"Authorization", "X-API-Key"},
+					ExposedHeaders: []string{"RateLimit-Remaining", "ETag"},
+				},
+			},
+			Tenancy: config.TenancyConfig{Enabled: true, Header: "X-Org"},
+		}
+		handler, _ := createApi(t, cfg)
+
+		// The tenant header is allowed along with the configured ones.
+		req := httptest.NewRequest(http.MethodOptions, "/api/books", nil)
+		req.Header.Set("Origin", "https://admin.example.com")
+		req.Header.Set("Access-Control-Request-Method", "GET")
+		req.Header.Set("Access-Control-Request-Headers", "x-api-key,x-org")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, "*", rr.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "X-Api-Key, X-Org", rr.Header().Get("Access-Control-Allow-Headers"))
+
+		req = httptest.NewRequest(http.MethodGet, "/api/alive", nil)
+		req.Header.Set("Origin", "https://admin.example.com")
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, "Ratelimit-Remaining, Etag", rr.Header().Get("Access-Control-Expose-Headers"))
+	})
+	t.Run("http_config", func(t *testing.T) {
+		cfg := config.Config{
+			Api: config.ApiConfig{Environment: "production"},
+			HTTP: config.HTTPConfig{
+				CORS: config.CORSConfig{
+					AllowedOrigins: []string{"https://*.example.com"},
+					AllowedMethods: []string{"GET", "PATCH"},
+					AllowedHeaders: []string{"Authorization", "Content-Type"},
+					MaxAge:         12 * time.Hour,
+				},
+				RateLimit: config.RateLimitConfig{
+					Routes: map[string]config.RateLimit{"/authors": {Requests: 1, Window: time.Minute}},
+				},
+			},
+		}
+		handler, _ := createApi(t, cfg)
+
+		req := httptest.NewRequest(http.MethodOptions, "/api/v2/books/f3c1b7a2-6d0e-4a8b-9c5d-2e7f1a3b4c5d", nil)
+		req.Header.Set("Origin", "https://admin.example.com")
+		req.Header.Set("Access-Control-Request-Method", "PATCH")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, "https://admin.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "PATCH", rr.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "43200", rr.Header().Get("Access-Control-Max-Age"))
+
+		req.Header.Set("Origin", "https://example.org")
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+
+		// The limit of the route is shared by the versions.
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/authors/f3c1b7a2-6d0e-4a8b-9c5d-2e7f1a3b4c5d", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v2/authors/f3c1b7a2-6d0e-4a8b-9c5d-2e7f1a3b4c5d", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "1;w=60", rr.Header().Get("RateLimit-Policy"))
+		assert.Equal(t, "60", rr.Header().Get("Retry-After"))
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v2/books", nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
 	})
 }
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"go-boilerplate-rest-api-chi/internal/apikey"
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/author"
+	"go-boilerplate-rest-api-chi/internal/book"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/database"
+	"go-boilerplate-rest-api-chi/internal/rpc"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	internalValidator "go-boilerplate-rest-api-chi/internal/validator"
+)
+
+// CreateGrpcServer builds the gRPC server on the same services and repositories as the REST
+// API, given the Repositories of CreateApi so they share their caches. The chi router of
+// CreateApi is the REST side of the gRPC services: no gateway is generated from the protos.
+// The changes go through the outbox too, so they are relayed by the workers of CreateApi.
+func CreateGrpcServer(cfg config.Config, logger zerolog.Logger, db *gorm.DB, repositories *Repositories) *rpc.Server {
+	validator := internalValidator.New()
+
+	txManager := database.NewTxManager(db, logger)
+
+	bookService := book.NewBookService(repositories.Books, repositories.Authors, txManager, logger)
+	authorService := author.NewAuthorService(repositories.Authors, logger)
+	apiKeyService := apikey.NewAPIKeyService(repositories.APIKeys, repositories.Tenants, logger)
+	tenantService := tenant.NewTenantService(repositories.Tenants, logger)
+
+	return rpc.NewServer(
+		rpc.NewBookServer(bookService, validator, logger),
+		rpc.NewAuthorServer(authorService, validator, logger),
+		auth.Chain(auth.NewTokenAuthenticator(cfg.Auth, logger), apikey.NewAuthenticator(apiKeyService)),
+		tenant.NewResolver(tenantService, cfg.Tenancy, logger),
+		cfg.GRPC,
+		logger,
+	)
+}
@@ -0,0 +1,60 @@
+package apikey
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+)
+
+const (
+	// Header carries the API key, for the clients that cannot set Authorization.
+	Header = "X-API-Key"
+	// scheme introduces the API key in the Authorization header: "ApiKey <key>".
+	scheme = "ApiKey"
+)
+
+// Authenticator accepts the valid API keys, the principal being the key: its Subject is
+// api_key:<id>, recorded as the actor of the changes, and its Tenant the one of the key.
+type Authenticator struct {
+	service APIKeyService
+}
+
+func NewAuthenticator(service APIKeyService) *Authenticator {
+	return &Authenticator{service: service}
+}
+
+func (a *Authenticator) Authenticate(r *http.Request) (*auth.Principal, error) {
+	value := Key(r)
+	if value == "" {
+		return nil, auth.ErrNoCredentials
+	}
+
+	key, err := a.service.Authenticate(r.Context(), value)
+	switch {
+	case errors.Is(err, ErrInvalidKey), errors.Is(err, ErrRevoked), errors.Is(err, ErrExpired):
+		return nil, auth.ErrInvalidCredentials
+	case err != nil:
+		return nil, err
+	}
+
+	return &auth.Principal{
+		Subject:  "api_key:" + key.ID.String(),
+		Name:     key.Name,
+		Scopes:   strings.Fields(key.Scopes),
+		APIKeyID: key.ID.String(),
+		Tenant:   key.TenantID,
+	}, nil
+}
+
+// Key reads the API key of the Authorization header, "ApiKey <key>", or of the X-API-Key
+// header.
+func Key(r *http.Request) string {
+	s, key, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(s, scheme) {
+		return strings.TrimSpace(key)
+	}
+
+	return strings.TrimSpace(r.Header.Get(Header))
+}
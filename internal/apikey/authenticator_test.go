@@ -0,0 +1,174 @@
+package apikey_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"go-boilerplate-rest-api-chi/internal/apikey"
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+)
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	partner := &entity.APIKey{ID: keyID, Name: "Partner", Scopes: "books:read books:write"}
+	expectedPrincipal := &auth.Principal{
+		Subject:  "api_key:" + keyID.String(),
+		Name:     "Partner",
+		Scopes:   []string{"books:read", "books:write"},
+		APIKeyID: keyID.String(),
+	}
+
+	tests := []struct {
+		name              string
+		headers           map[string]string
+		configureMock     func(*mocks.MockAPIKeyService)
+		expectedPrincipal *auth.Principal
+		expectedError     error
+	}{
+		{
+			name:    "success authorization header",
+			headers: map[string]string{"Authorization": "ApiKey " + keyValue},
+			configureMock: func(mockService *mocks.MockAPIKeyService) {
+				mockService.EXPECT().Authenticate(gomock.Any(), keyValue).Return(partner, nil)
+			},
+			expectedPrincipal: expectedPrincipal,
+		},
+		{
+			name:    "success x-api-key header",
+			headers: map[string]string{apikey.Header: keyValue},
+			configureMock: func(mockService *mocks.MockAPIKeyService) {
+				mockService.EXPECT().Authenticate(gomock.Any(), keyValue).Return(partner, nil)
+			},
+			expectedPrincipal: expectedPrincipal,
+		},
+		{
+			name:    "success key of a tenant",
+			headers: map[string]string{apikey.Header: keyValue},
+			configureMock: func(mockService *mocks.MockAPIKeyService) {
+				mockService.EXPECT().Authenticate(gomock.Any(), keyValue).Return(&entity.APIKey{ID: keyID, Name: "Acme sync", Scopes: "books:read", TenantID: "acme"}, nil)
+			},
+			expectedPrincipal: &auth.Principal{
+				Subject:  "api_key:" + keyID.String(),
+				Name:     "Acme sync",
+				Scopes:   []string{"books:read"},
+				APIKeyID: keyID.String(),
+				Tenant:   "acme",
+			},
+		},
+		{
+			name:          "error no key",
+			headers:       map[string]string{"Authorization": "Bearer token"},
+			configureMock: func(mockService *mocks.MockAPIKeyService) {},
+			expectedError: auth.ErrNoCredentials,
+		},
+		{
+			name:    "error revoked key",
+			headers: map[string]string{apikey.Header: keyValue},
+			configureMock: func(mockService *mocks.MockAPIKeyService) {
+				mockService.EXPECT().Authenticate(gomock.Any(), keyValue).Return(nil, apikey.ErrRevoked)
+			},
+			expectedError: auth.ErrInvalidCredentials,
+		},
+		{
+			name:    "error database error",
+			headers: map[string]string{apikey.Header: keyValue},
+			configureMock: func(mockService *mocks.MockAPIKeyService) {
+				mockService.EXPECT().Authenticate(gomock.Any(), keyValue).Return(nil, errors.New("database connection failed"))
+			},
+			expectedError: errors.New("database connection failed"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockService := mocks.NewMockAPIKeyService(ctrl)
+			test.configureMock(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+
+			principal, err := apikey.NewAuthenticator(mockService).Authenticate(req)
+
+			assert.Equal(t, test.expectedPrincipal, principal)
+			assert.Equal(t, test.expectedError, err)
+		})
+	}
+}
+
+func TestAuthenticator_TenantOfTheKey(t *testing.T) {
+	tests := []struct {
+		name               string
+		tenantHeader       string
+		configureMock      func(*mocks.MockTenantService)
+		expectedStatusCode int
+		expectedTenant     string
+	}{
+		{
+			name: "success tenant of the key",
+			configureMock: func(mockService *mocks.MockTenantService) {
+				mockService.EXPECT().GetTenant(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme"}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedTenant:     "acme",
+		},
+		{
+			name:         "success same tenant named",
+			tenantHeader: "acme",
+			configureMock: func(mockService *mocks.MockTenantService) {
+				mockService.EXPECT().GetTenant(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme"}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedTenant:     "acme",
+		},
+		{
+			name:               "error other tenant named",
+			tenantHeader:       "globex",
+			configureMock:      func(*mocks.MockTenantService) {},
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockKeys := mocks.NewMockAPIKeyService(ctrl)
+			mockKeys.EXPECT().Authenticate(gomock.Any(), keyValue).Return(&entity.APIKey{ID: keyID, Scopes: "books:read", TenantID: "acme"}, nil)
+			mockTenants := mocks.NewMockTenantService(ctrl)
+			test.configureMock(mockTenants)
+			resolver := tenant.NewResolver(mockTenants, config.TenancyConfig{Enabled: true, Header: "X-Tenant-ID"}, zerolog.Nop())
+
+			var resolved string
+			handler := auth.Middleware(apikey.NewAuthenticator(mockKeys))(resolver.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				resolved, _ = tenant.FromContext(r.Context())
+			})))
+
+			req := httptest.NewRequest(http.MethodGet, "/books", nil)
+			req.Header.Set(apikey.Header, keyValue)
+			if test.tenantHeader != "" {
+				req.Header.Set("X-Tenant-ID", test.tenantHeader)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedTenant, resolved)
+		})
+	}
+}
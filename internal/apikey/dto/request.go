@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+type CreateKeyRequest struct {
+	Name   string   `json:"name" validate:"required,notblank,no_html,max=100" example:"Partner catalogue sync"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,required,scope" example:"books:read,books:write"`
+	// TenantID binds the key to a tenant, whose catalogue is the only one it reaches. It is
+	// left empty for a key of the whole deployment.
+	TenantID string `json:"tenant_id" validate:"omitempty,slug,max=63" example:"city-library"`
+	// ExpiresAt is left empty for a key that never expires.
+	ExpiresAt *time.Time `json:"expires_at" example:"2027-01-01T00:00:00Z"`
+}
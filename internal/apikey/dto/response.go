@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+)
+
+type KeyResponse struct {
+	ID         string     `json:"id" xml:"id" example:"8b7c6d5e-4f3a-4b2c-9d1e-0f9a8b7c6d5e"`
+	Name       string     `json:"name" xml:"name" example:"Partner catalogue sync"`
+	Prefix     string     `json:"prefix" xml:"prefix" example:"lib_3f9a1c2e"`
+	Scopes     []string   `json:"scopes" xml:"scopes>scope" example:"books:read,books:write"`
+	TenantID   string     `json:"tenant_id,omitempty" xml:"tenant_id,omitempty" example:"city-library"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" xml:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" xml:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" xml:"revoked_at,omitempty"`
+	Key        string     `json:"key,omitempty" xml:"key,omitempty" example:"lib_3f9a1c2e_6b1d0e7f9c8a4b3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2a1b0c9d8e7f"`
+	CreatedAt  time.Time  `json:"created_at" xml:"created_at"`
+}
+
+// ToKeyResponse leaves the key out; it is only returned once, on creation and rotation.
+func ToKeyResponse(key *entity.APIKey) *KeyResponse {
+	return &KeyResponse{
+		ID:         key.ID.String(),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     strings.Fields(key.Scopes),
+		TenantID:   key.TenantID,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -0,0 +1,14 @@
+package apikey
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("api key not found")
+	ErrRevoked       = errors.New("api key revoked")
+	ErrExpired       = errors.New("api key expired")
+	ErrInvalidKey    = errors.New("invalid api key")
+	ErrInvalidExpiry = errors.New("expiry must be in the future")
+	// ErrNotGranted is returned when a key would exceed the rights of the caller managing it:
+	// a scope it does not hold, or another tenant than its own.
+	ErrNotGranted = errors.New("api key not granted to the caller")
+)
@@ -0,0 +1,234 @@
+package apikey
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/apikey/dto"
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/request"
+	"go-boilerplate-rest-api-chi/internal/response"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	internalValidator "go-boilerplate-rest-api-chi/internal/validator"
+)
+
+// AdminScope grants the management of the API keys.
+const AdminScope = "api_keys:admin"
+
+type KeySuccessResponse struct {
+	Status  string           `json:"status" xml:"status" example:"success"`
+	Message string           `json:"message" xml:"message" example:"API key created successfully"`
+	Key     *dto.KeyResponse `json:"api_key" xml:"api_key"`
+}
+
+type KeysSuccessResponse struct {
+	Status  string            `json:"status" xml:"status" example:"success"`
+	Message string            `json:"message" xml:"message" example:"API keys retrieved successfully"`
+	Keys    []dto.KeyResponse `json:"api_keys" xml:"api_keys>api_key"`
+}
+
+type APIKeyHandler struct {
+	service   APIKeyService
+	validator *internalValidator.Validator
+	logger    zerolog.Logger
+}
+
+func NewAPIKeyHandler(service APIKeyService, validator *internalValidator.Validator, logger zerolog.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *APIKeyHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Use(auth.RequireScope(AdminScope))
+
+	// routes
+	r.With(response.Acceptable(KeySuccessResponse{})).Post("/", h.CreateKey)
+	r.Get("/", h.GetKeys)
+	r.With(response.Acceptable(KeySuccessResponse{})).Post("/{key_id}/rotate", h.RotateKey)
+	r.Delete("/{key_id}", h.RevokeKey)
+
+	return r
+}
+
+// CreateKey godoc
+//
+//	@Summary		Create an API key
+//	@Description	Create a key for a machine client, sent as "Authorization: ApiKey <key>" or in X-API-Key. The key is only returned here; its prefix identifies it afterwards. The caller must hold every scope it grants, and a caller bound to a tenant only creates keys of its tenant. Requires the api_keys:admin scope.
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Param			api_key	body		dto.CreateKeyRequest	true	"API key data"
+//	@Success		201		{object}	KeySuccessResponse
+//	@Failure		400		{object}	response.ValidationErrorResponse
+//	@Failure		401		{object}	response.ErrorResponse
+//	@Failure		403		{object}	response.ErrorResponse
+//	@Failure		404		{object}	response.ErrorResponse
+//	@Failure		413		{object}	response.ValidationErrorResponse
+//	@Failure		415		{object}	response.ValidationErrorResponse
+//	@Failure		500		{object}	response.ErrorResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api-keys [post]
+func (h *APIKeyHandler) CreateKey(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateKeyRequest
+
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.validator.FormatErrors(r.Context(), err)
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	key, value, err := h.service.CreateKey(r.Context(), &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	keyResponse := dto.ToKeyResponse(key)
+	keyResponse.Key = value
+
+	response.Render(w, r, http.StatusCreated, KeySuccessResponse{
+		Status:  "success",
+		Message: "API key created successfully",
+		Key:     keyResponse,
+	})
+}
+
+// GetKeys godoc
+//
+//	@Summary		List API keys
+//	@Description	Get the API keys of the tenant of the caller, every key for a caller of the whole deployment, revoked ones included, without their secret values. Requires the api_keys:admin scope.
+//	@Tags			api-keys
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Success		200	{object}	KeysSuccessResponse
+//	@Failure		401	{object}	response.ErrorResponse
+//	@Failure		403	{object}	response.ErrorResponse
+//	@Failure		500	{object}	response.ErrorResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api-keys [get]
+func (h *APIKeyHandler) GetKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.service.GetKeys(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	keysResponse := make([]dto.KeyResponse, len(keys))
+	for i, key := range keys {
+		keysResponse[i] = *dto.ToKeyResponse(key)
+	}
+
+	response.Render(w, r, http.StatusOK, KeysSuccessResponse{
+		Status:  "success",
+		Message: "API keys retrieved successfully",
+		Keys:    keysResponse,
+	})
+}
+
+// RotateKey godoc
+//
+//	@Summary		Rotate an API key
+//	@Description	Replace the secret value of a key, keeping its scopes and expiry. The previous value is refused at once and the new one is only returned here, to a caller holding every scope of the key. Requires the api_keys:admin scope.
+//	@Tags			api-keys
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Param			key_id	path		string	true	"API key ID"	format(uuid)
+//	@Success		200		{object}	KeySuccessResponse
+//	@Failure		400		{object}	response.ErrorResponse
+//	@Failure		401		{object}	response.ErrorResponse
+//	@Failure		403		{object}	response.ErrorResponse
+//	@Failure		404		{object}	response.ErrorResponse
+//	@Failure		409		{object}	response.ErrorResponse
+//	@Failure		500		{object}	response.ErrorResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api-keys/{key_id}/rotate [post]
+func (h *APIKeyHandler) RotateKey(w http.ResponseWriter, r *http.Request) {
+	keyID, err := uuid.Parse(chi.URLParam(r, "key_id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid uuid")
+		return
+	}
+
+	key, value, err := h.service.RotateKey(r.Context(), keyID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	keyResponse := dto.ToKeyResponse(key)
+	keyResponse.Key = value
+
+	response.Render(w, r, http.StatusOK, KeySuccessResponse{
+		Status:  "success",
+		Message: "API key rotated successfully",
+		Key:     keyResponse,
+	})
+}
+
+// RevokeKey godoc
+//
+//	@Summary		Revoke an API key
+//	@Description	Refuse the key from now on. It stays listed with its revocation date. Only a caller holding every scope of the key may revoke it. Requires the api_keys:admin scope.
+//	@Tags			api-keys
+//	@Produce		json
+//	@Param			key_id	path		string	true	"API key ID"	format(uuid)
+//	@Success		200		{object}	response.SuccessResponse
+//	@Failure		400		{object}	response.ErrorResponse
+//	@Failure		401		{object}	response.ErrorResponse
+//	@Failure		403		{object}	response.ErrorResponse
+//	@Failure		404		{object}	response.ErrorResponse
+//	@Failure		500		{object}	response.ErrorResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api-keys/{key_id} [delete]
+func (h *APIKeyHandler) RevokeKey(w http.ResponseWriter, r *http.Request) {
+	keyID, err := uuid.Parse(chi.URLParam(r, "key_id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid uuid")
+		return
+	}
+
+	if err := h.service.RevokeKey(r.Context(), keyID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, "API key revoked successfully")
+}
+
+func (h *APIKeyHandler) handleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		response.Error(w, http.StatusNotFound, "API key not found")
+	case errors.Is(err, ErrRevoked):
+		response.Error(w, http.StatusConflict, "API key is revoked")
+	case errors.Is(err, ErrNotGranted):
+		response.Error(w, http.StatusForbidden, "API key exceeds the rights of the caller")
+	case errors.Is(err, tenant.ErrNotFound):
+		response.Error(w, http.StatusNotFound, "Tenant not found")
+	case errors.Is(err, ErrInvalidExpiry):
+		response.ValidationError(w, []response.ValidationErrorDetail{{
+			Field:   "expires_at",
+			Message: "expires_at must be in the future",
+		}})
+	default:
+		h.logger.Error().Err(err).Msg("unexpected error")
+		response.Error(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
@@ -0,0 +1,292 @@
+package apikey_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"go-boilerplate-rest-api-chi/internal/apikey"
+	"go-boilerplate-rest-api-chi/internal/apikey/dto"
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/response"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	"go-boilerplate-rest-api-chi/internal/validator"
+)
+
+var admin = &auth.Principal{Subject: "admin-1", Scopes: []string{apikey.AdminScope}}
+
+func serve(handler *apikey.APIKeyHandler, principal *auth.Principal, method, target string, body any) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buf).Encode(body)
+	}
+
+	req := httptest.NewRequest(method, target, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if principal != nil {
+		req = req.WithContext(auth.WithPrincipal(req.Context(), principal))
+	}
+	w := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Mount("/api-keys", handler.Routes())
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestAPIKeyHandler_CreateKey(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		principal          *auth.Principal
+		requestBody        any
+		configureMock      func(*mocks.MockAPIKeyService)
+		expectedStatusCode int
+		expectedResponse   any
+	}{
+		{
+			name:        "success create key shown once",
+			principal:   admin,
+			requestBody: dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read"}},
+			configureMock: func(mockService *mocks.MockAPIKeyService) {
+				mockService.EXPECT().
+					CreateKey(gomock.Any(), &dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read"}}).
+					Return(&entity.APIKey{ID: keyID, Name: "Partner", Prefix: "lib_3f9a1c2e", Hash: hashOf(keyValue), Scopes: "books:read", CreatedAt: createdAt}, keyValue, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse: apikey.KeySuccessResponse{
+				Status:  "success",
+				Message: "API key created successfully",
+				Key: &dto.KeyResponse{
+					ID:        keyID.String(),
+					Name:      "Partner",
+					Prefix:    "lib_3f9a1c2e",
+					Scopes:    []string{"books:read"},
+					Key:       keyValue,
+					CreatedAt: createdAt,
+				},
+			},
+		},
+		{
+			name:               "error validation fails scope with space",
+			principal:          admin,
+			requestBody:        dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read books:write"}},
+			configureMock:      func(mockService *mocks.MockAPIKeyService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ValidationErrorResponse{
+				Status:  "error",
+				Message: "Validation failed",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "scopes[0]",
+					Message: "scopes[0] must be a scope such as books:read",
+					Tag:     "scope",
+				}},
+			},
+		},
+		{
+			name:        "error expiry in the past",
+			principal:   admin,
+			requestBody: dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read"}},
+			configureMock: func(mockService *mocks.MockAPIKeyService) {
+				mockService.EXPECT().CreateKey(gomock.Any(), gomock.Any()).Return(nil, "", apikey.ErrInvalidExpiry)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ValidationErrorResponse{
+				Status:  "error",
+				Message: "Validation failed",
+				Errors:  []response.ValidationErrorDetail{{Field: "expires_at", Message: "expires_at must be in the future"}},
+			},
+		},
+		{
+			name:        "error scope not held by the caller",
+			principal:   admin,
+			requestBody: dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"tenants:admin"}},
+			configureMock: func(mockService *mocks.MockAPIKeyService) {
+				mockService.EXPECT().CreateKey(gomock.Any(), gomock.Any()).Return(nil, "", fmt.Errorf("%w: scope %q", apikey.ErrNotGranted, "tenants:admin"))
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "API key exceeds the rights of the caller"},
+		},
+		{
+			name:        "error unknown tenant",
+			principal:   admin,
+			requestBody: dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read"}, TenantID: "globex"},
+			configureMock: func(mockService *mocks.MockAPIKeyService) {
+				mockService.EXPECT().CreateKey(gomock.Any(), gomock.Any()).Return(nil, "", tenant.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "Tenant not found"},
+		},
+		{
+			name:               "error anonymous",
+			requestBody:        dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read"}},
+			configureMock:      func(mockService *mocks.MockAPIKeyService) {},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "Unauthorized"},
+		},
+		{
+			name:               "error missing admin scope",
+			principal:          &auth.Principal{Subject: "user-1", Scopes: []string{"books:write"}},
+			requestBody:        dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read"}},
+			configureMock:      func(mockService *mocks.MockAPIKeyService) {},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "Forbidden"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockService := mocks.NewMockAPIKeyService(ctrl)
+			test.configureMock(mockService)
+			v := validator.New()
+			apikey.RegisterValidations(v)
+			handler := apikey.NewAPIKeyHandler(mockService, v, zerolog.Nop())
+
+			w := serve(handler, test.principal, http.MethodPost, "/api-keys", test.requestBody)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+
+			expectedJSON, err := json.Marshal(test.expectedResponse)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, string(expectedJSON), w.Body.String())
+		})
+	}
+}
+
+func TestAPIKeyHandler_CreateKey_NotAcceptable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	// No CreateKey expected: the key is not created for a response the client refuses.
+	mockService := mocks.NewMockAPIKeyService(ctrl)
+	handler := apikey.NewAPIKeyHandler(mockService, validator.New(), zerolog.Nop())
+
+	w := serve(handler, admin, http.MethodPost, "/api-keys?format=csv", dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read"}})
+
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	assert.JSONEq(t, `{"status":"error","message":"Not acceptable"}`, w.Body.String())
+}
+
+func TestAPIKeyHandler_GetKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockAPIKeyService(ctrl)
+	handler := apikey.NewAPIKeyHandler(mockService, validator.New(), zerolog.Nop())
+
+	revokedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	mockService.EXPECT().GetKeys(gomock.Any()).Return([]*entity.APIKey{
+		{ID: keyID, Name: "Partner", Prefix: "lib_3f9a1c2e", Hash: hashOf(keyValue), Scopes: "books:read books:write", RevokedAt: &revokedAt},
+	}, nil)
+
+	w := serve(handler, admin, http.MethodGet, "/api-keys", nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body apikey.KeysSuccessResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Keys, 1)
+	assert.Equal(t, []string{"books:read", "books:write"}, body.Keys[0].Scopes)
+	assert.Equal(t, &revokedAt, body.Keys[0].RevokedAt)
+	assert.Empty(t, body.Keys[0].Key)
+	assert.NotContains(t, w.Body.String(), hashOf(keyValue))
+}
+
+func TestAPIKeyHandler_RotateAndRevokeKey(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		target             string
+		configureMock      func(*mocks.MockAPIKeyService)
+		expectedStatusCode int
+		expectedMessage    string
+		expectedKey        string
+	}{
+		{
+			name:   "success rotate key",
+			method: http.MethodPost,
+			target: "/api-keys/" + keyID.String() + "/rotate",
+			configureMock: func(mockService *mocks.MockAPIKeyService) {
+				mockService.EXPECT().RotateKey(gomock.Any(), keyID).Return(&entity.APIKey{ID: keyID, Prefix: "lib_3f9a1c2e"}, keyValue, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedMessage:    "API key rotated successfully",
+			expectedKey:        keyValue,
+		},
+		{
+			name:   "error rotate revoked key",
+			method: http.MethodPost,
+			target: "/api-keys/" + keyID.String() + "/rotate",
+			configureMock: func(mockService *mocks.MockAPIKeyService) {
+				mockService.EXPECT().RotateKey(gomock.Any(), keyID).Return(nil, "", apikey.ErrRevoked)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedMessage:    "API key is revoked",
+		},
+		{
+			name:   "success revoke key",
+			method: http.MethodDelete,
+			target: "/api-keys/" + keyID.String(),
+			configureMock: func(mockService *mocks.MockAPIKeyService) {
+				mockService.EXPECT().RevokeKey(gomock.Any(), keyID).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedMessage:    "API key revoked successfully",
+		},
+		{
+			name:   "error revoke unknown key",
+			method: http.MethodDelete,
+			target: "/api-keys/" + keyID.String(),
+			configureMock: func(mockService *mocks.MockAPIKeyService) {
+				mockService.EXPECT().RevokeKey(gomock.Any(), keyID).Return(apikey.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedMessage:    "API key not found",
+		},
+		{
+			name:               "error invalid uuid",
+			method:             http.MethodDelete,
+			target:             "/api-keys/invalid-uuid",
+			configureMock:      func(mockService *mocks.MockAPIKeyService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedMessage:    "Invalid uuid",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockService := mocks.NewMockAPIKeyService(ctrl)
+			test.configureMock(mockService)
+			handler := apikey.NewAPIKeyHandler(mockService, validator.New(), zerolog.Nop())
+
+			w := serve(handler, admin, test.method, test.target, nil)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			var body struct {
+				Message string          `json:"message"`
+				Key     dto.KeyResponse `json:"api_key"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, test.expectedMessage, body.Message)
+			assert.Equal(t, test.expectedKey, body.Key.Key)
+		})
+	}
+}
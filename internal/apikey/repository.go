@@ -0,0 +1,106 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+)
+
+//go:generate mockgen -destination=../mocks/mock_api_key_repository.go -package=mocks go-boilerplate-rest-api-chi/internal/apikey APIKeyRepository
+type APIKeyRepository interface {
+	CreateKey(ctx context.Context, key *entity.APIKey) (*entity.APIKey, error)
+	// The keys are scoped to the tenant of ctx by tenant.Plugin, see tenant.AllTenants.
+	GetKeys(ctx context.Context) ([]*entity.APIKey, error)
+	GetKeyByID(ctx context.Context, keyID uuid.UUID) (*entity.APIKey, error)
+	GetKeyByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error)
+	UpdateSecret(ctx context.Context, keyID uuid.UUID, prefix, hash string) error
+	RevokeKey(ctx context.Context, keyID uuid.UUID, at time.Time) error
+	TouchKey(ctx context.Context, keyID uuid.UUID, at time.Time) error
+}
+
+type apiKeyRepository struct {
+	db     *gorm.DB
+	logger zerolog.Logger
+}
+
+func NewAPIKeyRepository(db *gorm.DB, logger zerolog.Logger) APIKeyRepository {
+	return &apiKeyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *apiKeyRepository) CreateKey(ctx context.Context, key *entity.APIKey) (*entity.APIKey, error) {
+	if err := r.db.WithContext(ctx).Create(key).Error; err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (r *apiKeyRepository) GetKeys(ctx context.Context) ([]*entity.APIKey, error) {
+	var keys []*entity.APIKey
+
+	if err := r.db.WithContext(ctx).Order("created_at").Find(&keys).Error; err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyRepository) GetKeyByID(ctx context.Context, keyID uuid.UUID) (*entity.APIKey, error) {
+	return r.getKey(r.db.WithContext(ctx), "id = ?", keyID)
+}
+
+func (r *apiKeyRepository) GetKeyByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	return r.getKey(r.db.WithContext(ctx), "prefix = ?", prefix)
+}
+
+func (r *apiKeyRepository) getKey(db *gorm.DB, query string, args ...any) (*entity.APIKey, error) {
+	var key *entity.APIKey
+
+	if err := db.Where(query, args...).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (r *apiKeyRepository) UpdateSecret(ctx context.Context, keyID uuid.UUID, prefix, hash string) error {
+	return r.update(ctx, keyID, map[string]any{"prefix": prefix, "hash": hash})
+}
+
+func (r *apiKeyRepository) RevokeKey(ctx context.Context, keyID uuid.UUID, at time.Time) error {
+	return r.update(ctx, keyID, map[string]any{"revoked_at": at})
+}
+
+func (r *apiKeyRepository) TouchKey(ctx context.Context, keyID uuid.UUID, at time.Time) error {
+	return r.update(ctx, keyID, map[string]any{"last_used_at": at})
+}
+
+func (r *apiKeyRepository) update(ctx context.Context, keyID uuid.UUID, values map[string]any) error {
+	result := r.db.WithContext(ctx).Model(&entity.APIKey{}).Where("id = ?", keyID).Updates(values)
+	if result.Error != nil {
+		r.logger.Error().Err(result.Error).Msg("database error")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package apikey_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"go-boilerplate-rest-api-chi/internal/apikey"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	"go-boilerplate-rest-api-chi/internal/test-utils"
+)
+
+func TestAPIKeyRepository_GetKeyByPrefix(t *testing.T) {
+	tests := []struct {
+		name          string
+		configureMock func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "success get key",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `api_keys` WHERE prefix = \\?").
+					WithArgs("lib_3f9a1c2e", 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "prefix", "hash", "scopes"}).
+						AddRow(keyID, "Partner", "lib_3f9a1c2e", hashOf(keyValue), "books:read"))
+			},
+		},
+		{
+			name: "error key not found",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `api_keys` WHERE prefix = \\?").
+					WithArgs("lib_3f9a1c2e", 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			expectedError: apikey.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := testutils.NewGormMySQL(t)
+			test.configureMock(mock)
+
+			repo := apikey.NewAPIKeyRepository(db, zerolog.Nop())
+
+			key, err := repo.GetKeyByPrefix(context.Background(), "lib_3f9a1c2e")
+
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, keyID, key.ID)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAPIKeyRepository_GetKeyByID(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		configureMock func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "success any tenant",
+			ctx:  tenant.AllTenants(context.Background()),
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `api_keys` WHERE id = \\? ORDER").
+					WithArgs(keyID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id"}).AddRow(keyID, "globex"))
+			},
+		},
+		{
+			name: "error key of another tenant",
+			ctx:  tenant.WithID(context.Background(), "acme"),
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `api_keys` WHERE id = \\? AND `api_keys`.`tenant_id` = \\?").
+					WithArgs(keyID, "acme", 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			expectedError: apikey.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := testutils.NewGormMySQL(t)
+			require.NoError(t, db.Use(tenant.Plugin{}))
+			test.configureMock(mock)
+
+			repo := apikey.NewAPIKeyRepository(db, zerolog.Nop())
+
+			key, err := repo.GetKeyByID(test.ctx, keyID)
+
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, keyID, key.ID)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAPIKeyRepository_RevokeKey(t *testing.T) {
+	tests := []struct {
+		name          string
+		rowsAffected  int64
+		expectedError error
+	}{
+		{
+			name:         "success revoke key",
+			rowsAffected: 1,
+		},
+		{
+			name:          "error key not found",
+			rowsAffected:  0,
+			expectedError: apikey.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := testutils.NewGormMySQL(t)
+			revokedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+			mock.ExpectExec("UPDATE `api_keys` SET `revoked_at`=\\?,`updated_at`=\\? WHERE id = \\?").
+				WithArgs(revokedAt, sqlmock.AnyArg(), keyID).
+				WillReturnResult(sqlmock.NewResult(0, test.rowsAffected))
+
+			repo := apikey.NewAPIKeyRepository(db, zerolog.Nop())
+
+			err := repo.RevokeKey(context.Background(), keyID, revokedAt)
+
+			assert.ErrorIs(t, err, test.expectedError)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"go-boilerplate-rest-api-chi/internal/apikey/dto"
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+)
+
+const (
+	// keyPrefix starts every key, so that a leaked one is easy to recognize.
+	keyPrefix = "lib_"
+	// prefixLength is the length of the visible prefix of the keys: keyPrefix and 8 random
+	// hexadecimal digits.
+	prefixLength = len(keyPrefix) + 8
+	// lastUsedResolution spares a write per request: the last use of a key is only recorded
+	// when the previous one is older.
+	lastUsedResolution = time.Minute
+	// generateAttempts bounds the draws of a new prefix when it is already taken.
+	generateAttempts = 3
+)
+
+// APIKeyService manages the keys on behalf of the principal of the context. A principal bound
+// to a tenant only reaches the keys of its tenant, and no caller hands out a scope it does not
+// hold itself.
+//
+//go:generate mockgen -destination=../mocks/mock_api_key_service.go -package=mocks go-boilerplate-rest-api-chi/internal/apikey APIKeyService
+type APIKeyService interface {
+	// CreateKey returns the new key along with its secret value, which is not stored.
+	CreateKey(ctx context.Context, req *dto.CreateKeyRequest) (*entity.APIKey, string, error)
+	GetKeys(ctx context.Context) ([]*entity.APIKey, error)
+	// RotateKey replaces the secret value of a key, the previous one being refused at once.
+	RotateKey(ctx context.Context, keyID uuid.UUID) (*entity.APIKey, string, error)
+	RevokeKey(ctx context.Context, keyID uuid.UUID) error
+	// Authenticate finds the valid key of value and records its use.
+	Authenticate(ctx context.Context, value string) (*entity.APIKey, error)
+}
+
+type apiKeyService struct {
+	repository APIKeyRepository
+	tenants    tenant.TenantRepository
+	logger     zerolog.Logger
+}
+
+func NewAPIKeyService(repository APIKeyRepository, tenants tenant.TenantRepository, logger zerolog.Logger) APIKeyService {
+	return &apiKeyService{
+		repository: repository,
+		tenants:    tenants,
+		logger:     logger,
+	}
+}
+
+// CreateKey binds the keys created by a principal bound to a tenant to that tenant.
+func (s *apiKeyService) CreateKey(ctx context.Context, req *dto.CreateKeyRequest) (*entity.APIKey, string, error) {
+	ctx, principal, err := managed(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := granted(principal, req.Scopes); err != nil {
+		return nil, "", err
+	}
+
+	tenantID := req.TenantID
+	switch {
+	case principal.Tenant == "":
+	case tenantID == "":
+		tenantID = principal.Tenant
+	case tenantID != principal.Tenant:
+		return nil, "", fmt.Errorf("%w: tenant %q", ErrNotGranted, tenantID)
+	}
+
+	if tenantID != "" {
+		if _, err := s.tenants.GetTenantByID(ctx, tenantID); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, "", ErrInvalidExpiry
+	}
+
+	var key *entity.APIKey
+	value, err := withNewValue(func(prefix, hash string) error {
+		var err error
+		key, err = s.repository.CreateKey(ctx, &entity.APIKey{
+			Name:      req.Name,
+			Prefix:    prefix,
+			Hash:      hash,
+			Scopes:    strings.Join(req.Scopes, " "),
+			TenantID:  tenantID,
+			ExpiresAt: req.ExpiresAt,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, value, nil
+}
+
+func (s *apiKeyService) GetKeys(ctx context.Context) ([]*entity.APIKey, error) {
+	ctx, _, err := managed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetKeys(ctx)
+}
+
+// RotateKey hands out the new value only to a caller holding every scope of the key.
+func (s *apiKeyService) RotateKey(ctx context.Context, keyID uuid.UUID) (*entity.APIKey, string, error) {
+	ctx, principal, err := managed(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	key, err := s.repository.GetKeyByID(ctx, keyID)
+	if err != nil {
+		return nil, "", err
+	}
+	if key.RevokedAt != nil {
+		return nil, "", ErrRevoked
+	}
+	if err := granted(principal, strings.Fields(key.Scopes)); err != nil {
+		return nil, "", err
+	}
+
+	value, err := withNewValue(func(prefix, hash string) error {
+		if err := s.repository.UpdateSecret(ctx, keyID, prefix, hash); err != nil {
+			return err
+		}
+		key.Prefix, key.Hash = prefix, hash
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, value, nil
+}
+
+// RevokeKey is only allowed to a caller holding every scope of the key, as RotateKey. It is
+// idempotent: a revoked key keeps the date of its first revocation.
+func (s *apiKeyService) RevokeKey(ctx context.Context, keyID uuid.UUID) error {
+	ctx, principal, err := managed(ctx)
+	if err != nil {
+		return err
+	}
+	key, err := s.repository.GetKeyByID(ctx, keyID)
+	if err != nil {
+		return err
+	}
+	if err := granted(principal, strings.Fields(key.Scopes)); err != nil {
+		return err
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+
+	return s.repository.RevokeKey(ctx, keyID, time.Now())
+}
+
+func (s *apiKeyService) Authenticate(ctx context.Context, value string) (*entity.APIKey, error) {
+	if !strings.HasPrefix(value, keyPrefix) || len(value) <= prefixLength {
+		return nil, ErrInvalidKey
+	}
+
+	// The key is looked up before the tenant of the request is known.
+	ctx = tenant.AllTenants(ctx)
+
+	key, err := s.repository.GetKeyByPrefix(ctx, value[:prefixLength])
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrInvalidKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hash(value)), []byte(key.Hash)) != 1 {
+		return nil, ErrInvalidKey
+	}
+
+	now := time.Now()
+	switch {
+	case key.RevokedAt != nil:
+		return nil, ErrRevoked
+	case key.ExpiresAt != nil && !now.Before(*key.ExpiresAt):
+		return nil, ErrExpired
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		// A failure to record the use must not refuse a valid key.
+		if err := s.repository.TouchKey(ctx, key.ID, now); err != nil {
+			s.logger.Warn().Err(err).Str("api_key_id", key.ID.String()).Msg("failed to record the use of the api key")
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+
+	return key, nil
+}
+
+// managed scopes ctx to the keys its principal may manage: the keys of its tenant, or every key
+// for a principal of the whole deployment. The keys of the other tenants are not found.
+func managed(ctx context.Context) (context.Context, *auth.Principal, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, nil, ErrNotGranted
+	}
+
+	if principal.Tenant != "" {
+		return tenant.WithID(ctx, principal.Tenant), principal, nil
+	}
+	return tenant.AllTenants(ctx), principal, nil
+}
+
+// granted checks that principal holds every scope of scopes.
+func granted(principal *auth.Principal, scopes []string) error {
+	for _, scope := range scopes {
+		if !principal.HasScope(scope) {
+			return fmt.Errorf("%w: scope %q", ErrNotGranted, scope)
+		}
+	}
+
+	return nil
+}
+
+// withNewValue draws a key value and stores its prefix and hash with store, drawing again
+// when the prefix is already taken. It returns the value, shown once to the client.
+func withNewValue(store func(prefix, hash string) error) (string, error) {
+	var err error
+	for range generateAttempts {
+		var value string
+		if value, err = generateValue(); err != nil {
+			return "", err
+		}
+
+		if err = store(value[:prefixLength], hash(value)); !errors.Is(err, gorm.ErrDuplicatedKey) {
+			if err != nil {
+				return "", err
+			}
+			return value, nil
+		}
+	}
+
+	return "", err
+}
+
+// generateValue draws a key such as lib_3f9a1c2e_<64 hexadecimal digits>. Its 256 random bits
+// make a plain SHA-256 enough to store it.
+func generateValue() (string, error) {
+	random := make([]byte, 4+32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	return keyPrefix + hex.EncodeToString(random[:4]) + "_" + hex.EncodeToString(random[4:]), nil
+}
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,462 @@
+package apikey_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"go-boilerplate-rest-api-chi/internal/apikey"
+	"go-boilerplate-rest-api-chi/internal/apikey/dto"
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+)
+
+var (
+	keyID      = uuid.MustParse("8b7c6d5e-4f3a-4b2c-9d1e-0f9a8b7c6d5e")
+	keyPattern = regexp.MustCompile(`^lib_[0-9a-f]{8}_[0-9a-f]{64}$`)
+)
+
+const keyValue = "lib_3f9a1c2e_6b1d0e7f9c8a4b3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2a1b0c9d8e7f"
+
+func hashOf(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+var (
+	deploymentAdmin = &auth.Principal{Subject: "admin-1", Scopes: []string{apikey.AdminScope, "books:read", "books:write"}}
+	acmeAdmin       = &auth.Principal{Subject: "api_key:acme", Scopes: []string{apikey.AdminScope, "books:read"}, Tenant: "acme"}
+)
+
+// ofTenant matches the contexts scoped to the keys of tenantID, or to every key when it is
+// empty.
+func ofTenant(tenantID string) gomock.Matcher {
+	return gomock.Cond(func(ctx context.Context) bool {
+		id, _ := tenant.FromContext(ctx)
+		return id == tenantID
+	})
+}
+
+func TestAPIKeyService_CreateKey(t *testing.T) {
+	future := time.Now().Add(24 * time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name           string
+		principal      *auth.Principal
+		input          *dto.CreateKeyRequest
+		configureMock  func(*mocks.MockAPIKeyRepository, *mocks.MockTenantRepository)
+		expectedTenant string
+		expectedError  error
+	}{
+		{
+			name:      "success create key",
+			principal: deploymentAdmin,
+			input:     &dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read", "books:write"}, TenantID: "acme", ExpiresAt: &future},
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository, mockTenants *mocks.MockTenantRepository) {
+				mockTenants.EXPECT().GetTenantByID(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme"}, nil)
+				mockRepo.EXPECT().
+					CreateKey(gomock.Any(), gomock.Cond(func(k *entity.APIKey) bool {
+						return k.Name == "Partner" && k.Scopes == "books:read books:write" && k.TenantID == "acme" && k.ExpiresAt == &future &&
+							len(k.Prefix) == 12 && len(k.Hash) == 64
+					})).
+					DoAndReturn(func(_ context.Context, k *entity.APIKey) (*entity.APIKey, error) {
+						return k, nil
+					})
+			},
+			expectedTenant: "acme",
+		},
+		{
+			name:      "success prefix taken drawn again",
+			principal: deploymentAdmin,
+			input:     &dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read"}},
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository, mockTenants *mocks.MockTenantRepository) {
+				mockRepo.EXPECT().CreateKey(gomock.Any(), gomock.Any()).Return(nil, gorm.ErrDuplicatedKey)
+				mockRepo.EXPECT().CreateKey(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, k *entity.APIKey) (*entity.APIKey, error) {
+					return k, nil
+				})
+			},
+		},
+		{
+			name:      "success bound caller creates a key of its tenant",
+			principal: acmeAdmin,
+			input:     &dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read"}},
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository, mockTenants *mocks.MockTenantRepository) {
+				mockTenants.EXPECT().GetTenantByID(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme"}, nil)
+				mockRepo.EXPECT().CreateKey(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, k *entity.APIKey) (*entity.APIKey, error) {
+					return k, nil
+				})
+			},
+			expectedTenant: "acme",
+		},
+		{
+			name:          "error bound caller names another tenant",
+			principal:     acmeAdmin,
+			input:         &dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read"}, TenantID: "globex"},
+			configureMock: func(*mocks.MockAPIKeyRepository, *mocks.MockTenantRepository) {},
+			expectedError: apikey.ErrNotGranted,
+		},
+		{
+			name:          "error scope not held by the caller",
+			principal:     acmeAdmin,
+			input:         &dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read", "tenants:admin"}},
+			configureMock: func(*mocks.MockAPIKeyRepository, *mocks.MockTenantRepository) {},
+			expectedError: apikey.ErrNotGranted,
+		},
+		{
+			name:          "error anonymous",
+			input:         &dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read"}},
+			configureMock: func(*mocks.MockAPIKeyRepository, *mocks.MockTenantRepository) {},
+			expectedError: apikey.ErrNotGranted,
+		},
+		{
+			name:      "error unknown tenant",
+			principal: deploymentAdmin,
+			input:     &dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read"}, TenantID: "globex"},
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository, mockTenants *mocks.MockTenantRepository) {
+				mockTenants.EXPECT().GetTenantByID(gomock.Any(), "globex").Return(nil, tenant.ErrNotFound)
+			},
+			expectedError: tenant.ErrNotFound,
+		},
+		{
+			name:          "error expiry in the past",
+			principal:     deploymentAdmin,
+			input:         &dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read"}, ExpiresAt: &past},
+			configureMock: func(*mocks.MockAPIKeyRepository, *mocks.MockTenantRepository) {},
+			expectedError: apikey.ErrInvalidExpiry,
+		},
+		{
+			name:      "error database error",
+			principal: deploymentAdmin,
+			input:     &dto.CreateKeyRequest{Name: "Partner", Scopes: []string{"books:read"}},
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository, mockTenants *mocks.MockTenantRepository) {
+				mockRepo.EXPECT().CreateKey(gomock.Any(), gomock.Any()).Return(nil, errors.New("database connection failed"))
+			},
+			expectedError: errors.New("database connection failed"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+			apiKeyRepoMock := mocks.NewMockAPIKeyRepository(ctrl)
+			tenantRepoMock := mocks.NewMockTenantRepository(ctrl)
+
+			test.configureMock(apiKeyRepoMock, tenantRepoMock)
+			service := apikey.NewAPIKeyService(apiKeyRepoMock, tenantRepoMock, zerolog.Nop())
+
+			ctx := context.Background()
+			if test.principal != nil {
+				ctx = auth.WithPrincipal(ctx, test.principal)
+			}
+			key, value, err := service.CreateKey(ctx, test.input)
+
+			if test.expectedError != nil {
+				assert.ErrorContains(t, err, test.expectedError.Error())
+				assert.Nil(t, key)
+				assert.Empty(t, value)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Regexp(t, keyPattern, value)
+			assert.Equal(t, value[:12], key.Prefix)
+			assert.Equal(t, hashOf(value), key.Hash, "only the hash of the key is stored")
+			assert.Equal(t, test.expectedTenant, key.TenantID)
+		})
+	}
+}
+
+func TestAPIKeyService_GetKeys(t *testing.T) {
+	tests := []struct {
+		name           string
+		principal      *auth.Principal
+		expectedTenant string
+	}{
+		{
+			name:           "success every key for the deployment",
+			principal:      deploymentAdmin,
+			expectedTenant: "",
+		},
+		{
+			name:           "success keys of the tenant of the caller",
+			principal:      acmeAdmin,
+			expectedTenant: "acme",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			apiKeyRepoMock := mocks.NewMockAPIKeyRepository(ctrl)
+			apiKeyRepoMock.EXPECT().GetKeys(ofTenant(test.expectedTenant)).Return([]*entity.APIKey{{ID: keyID}}, nil)
+			service := apikey.NewAPIKeyService(apiKeyRepoMock, mocks.NewMockTenantRepository(ctrl), zerolog.Nop())
+
+			keys, err := service.GetKeys(auth.WithPrincipal(context.Background(), test.principal))
+
+			require.NoError(t, err)
+			assert.Len(t, keys, 1)
+		})
+	}
+}
+
+func TestAPIKeyService_RotateKey(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		principal     *auth.Principal
+		configureMock func(*mocks.MockAPIKeyRepository)
+		expectedError error
+	}{
+		{
+			name:      "success rotate key",
+			principal: deploymentAdmin,
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByID(ofTenant(""), keyID).Return(&entity.APIKey{ID: keyID, Prefix: keyValue[:12], Hash: hashOf(keyValue), Scopes: "books:read"}, nil)
+				mockRepo.EXPECT().UpdateSecret(gomock.Any(), keyID, gomock.Not(keyValue[:12]), gomock.Not(hashOf(keyValue))).Return(nil)
+			},
+		},
+		{
+			name:      "error revoked key",
+			principal: deploymentAdmin,
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByID(ofTenant(""), keyID).Return(&entity.APIKey{ID: keyID, RevokedAt: &revokedAt}, nil)
+			},
+			expectedError: apikey.ErrRevoked,
+		},
+		{
+			name:      "error key not found",
+			principal: deploymentAdmin,
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByID(ofTenant(""), keyID).Return(nil, apikey.ErrNotFound)
+			},
+			expectedError: apikey.ErrNotFound,
+		},
+		{
+			name:      "error key of another tenant",
+			principal: acmeAdmin,
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByID(ofTenant("acme"), keyID).Return(nil, apikey.ErrNotFound)
+			},
+			expectedError: apikey.ErrNotFound,
+		},
+		{
+			name:      "error scope of the key not held by the caller",
+			principal: acmeAdmin,
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByID(ofTenant("acme"), keyID).Return(&entity.APIKey{ID: keyID, Scopes: "books:read books:write", TenantID: "acme"}, nil)
+			},
+			expectedError: apikey.ErrNotGranted,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+			apiKeyRepoMock := mocks.NewMockAPIKeyRepository(ctrl)
+
+			test.configureMock(apiKeyRepoMock)
+			service := apikey.NewAPIKeyService(apiKeyRepoMock, mocks.NewMockTenantRepository(ctrl), zerolog.Nop())
+
+			key, value, err := service.RotateKey(auth.WithPrincipal(context.Background(), test.principal), keyID)
+
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				assert.Nil(t, key)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Regexp(t, keyPattern, value)
+			assert.Equal(t, hashOf(value), key.Hash)
+		})
+	}
+}
+
+func TestAPIKeyService_RevokeKey(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		principal     *auth.Principal
+		configureMock func(*mocks.MockAPIKeyRepository)
+		expectedError error
+	}{
+		{
+			name:      "success revoke key",
+			principal: deploymentAdmin,
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByID(ofTenant(""), keyID).Return(&entity.APIKey{ID: keyID}, nil)
+				mockRepo.EXPECT().RevokeKey(gomock.Any(), keyID, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:      "success already revoked",
+			principal: deploymentAdmin,
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByID(ofTenant(""), keyID).Return(&entity.APIKey{ID: keyID, RevokedAt: &revokedAt}, nil)
+			},
+		},
+		{
+			name:      "error key not found",
+			principal: deploymentAdmin,
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByID(ofTenant(""), keyID).Return(nil, apikey.ErrNotFound)
+			},
+			expectedError: apikey.ErrNotFound,
+		},
+		{
+			name:      "error key of another tenant",
+			principal: acmeAdmin,
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByID(ofTenant("acme"), keyID).Return(nil, apikey.ErrNotFound)
+			},
+			expectedError: apikey.ErrNotFound,
+		},
+		{
+			name:      "error key with a scope the caller lacks",
+			principal: acmeAdmin,
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByID(ofTenant("acme"), keyID).Return(&entity.APIKey{ID: keyID, Scopes: "books:read books:write", TenantID: "acme"}, nil)
+			},
+			expectedError: apikey.ErrNotGranted,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+			apiKeyRepoMock := mocks.NewMockAPIKeyRepository(ctrl)
+
+			test.configureMock(apiKeyRepoMock)
+			service := apikey.NewAPIKeyService(apiKeyRepoMock, mocks.NewMockTenantRepository(ctrl), zerolog.Nop())
+
+			err := service.RevokeKey(auth.WithPrincipal(context.Background(), test.principal), keyID)
+
+			assert.ErrorIs(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	recently := time.Now().Add(-10 * time.Second)
+	longAgo := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	stored := func(modify func(k *entity.APIKey)) *entity.APIKey {
+		k := &entity.APIKey{ID: keyID, Name: "Partner", Prefix: keyValue[:12], Hash: hashOf(keyValue), Scopes: "books:read"}
+		modify(k)
+		return k
+	}
+
+	tests := []struct {
+		name          string
+		value         string
+		configureMock func(*mocks.MockAPIKeyRepository)
+		expectedError error
+	}{
+		{
+			name:  "success records first use",
+			value: keyValue,
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByPrefix(gomock.Any(), "lib_3f9a1c2e").Return(stored(func(*entity.APIKey) {}), nil)
+				mockRepo.EXPECT().TouchKey(gomock.Any(), keyID, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:  "success recent use not recorded again",
+			value: keyValue,
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByPrefix(gomock.Any(), "lib_3f9a1c2e").Return(stored(func(k *entity.APIKey) {
+					k.LastUsedAt = &recently
+					k.ExpiresAt = &future
+				}), nil)
+			},
+		},
+		{
+			name:  "success use not recorded",
+			value: keyValue,
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByPrefix(gomock.Any(), "lib_3f9a1c2e").Return(stored(func(k *entity.APIKey) { k.LastUsedAt = &longAgo }), nil)
+				mockRepo.EXPECT().TouchKey(gomock.Any(), keyID, gomock.Any()).Return(errors.New("database connection failed"))
+			},
+		},
+		{
+			name:          "error malformed key",
+			value:         "3f9a1c2e",
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {},
+			expectedError: apikey.ErrInvalidKey,
+		},
+		{
+			name:  "error unknown prefix",
+			value: keyValue,
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByPrefix(gomock.Any(), "lib_3f9a1c2e").Return(nil, apikey.ErrNotFound)
+			},
+			expectedError: apikey.ErrInvalidKey,
+		},
+		{
+			name:  "error wrong secret",
+			value: keyValue[:len(keyValue)-1] + "0",
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByPrefix(gomock.Any(), "lib_3f9a1c2e").Return(stored(func(*entity.APIKey) {}), nil)
+			},
+			expectedError: apikey.ErrInvalidKey,
+		},
+		{
+			name:  "error revoked key",
+			value: keyValue,
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByPrefix(gomock.Any(), "lib_3f9a1c2e").Return(stored(func(k *entity.APIKey) { k.RevokedAt = &longAgo }), nil)
+			},
+			expectedError: apikey.ErrRevoked,
+		},
+		{
+			name:  "error expired key",
+			value: keyValue,
+			configureMock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().GetKeyByPrefix(gomock.Any(), "lib_3f9a1c2e").Return(stored(func(k *entity.APIKey) { k.ExpiresAt = &longAgo }), nil)
+			},
+			expectedError: apikey.ErrExpired,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+			apiKeyRepoMock := mocks.NewMockAPIKeyRepository(ctrl)
+
+			test.configureMock(apiKeyRepoMock)
+			service := apikey.NewAPIKeyService(apiKeyRepoMock, mocks.NewMockTenantRepository(ctrl), zerolog.Nop())
+
+			key, err := service.Authenticate(context.Background(), test.value)
+
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				assert.Nil(t, key)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, keyID, key.ID)
+		})
+	}
+}
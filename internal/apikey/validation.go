@@ -0,0 +1,22 @@
+package apikey
+
+import (
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+
+	internalValidator "go-boilerplate-rest-api-chi/internal/validator"
+)
+
+// scopePattern matches the scopes such as books:read, stored space separated.
+var scopePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+(?::[A-Za-z0-9_.-]+)*$`)
+
+// RegisterValidations adds the rules of the API key DTOs to v.
+func RegisterValidations(v *internalValidator.Validator) {
+	v.RegisterRule("scope", func(fl validator.FieldLevel) bool {
+		return scopePattern.MatchString(fl.Field().String())
+	}, internalValidator.Messages{
+		"en": "{0} must be a scope such as books:read",
+		"fr": "{0} doit être une portée comme books:read",
+	})
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrNoCredentials      = errors.New("no credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Principal is the authenticated caller of a request: a user, or a machine client holding an
+// API key.
+type Principal struct {
+	Subject string
+	Name    string
+	Scopes  []string
+	// APIKeyID is the key the caller authenticated with, empty for the users.
+	APIKeyID string
+	// Tenant binds the caller to the catalogue of a tenant, empty for the callers of the whole
+	// deployment.
+	Tenant string
+}
+
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+type Authenticator interface {
+	// Authenticate returns ErrNoCredentials when the request carries none, so that anonymous
+	// requests can go on to the public routes.
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+type AuthenticatorFunc func(r *http.Request) (*Principal, error)
+
+func (f AuthenticatorFunc) Authenticate(r *http.Request) (*Principal, error) {
+	return f(r)
+}
+
+// Chain tries the authenticators in turn, each reading its own credentials, and returns the
+// first principal found. ErrInvalidCredentials is returned when none accepts the credentials
+// of the request, ErrNoCredentials when it carries none.
+func Chain(authenticators ...Authenticator) Authenticator {
+	return AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+		result := ErrNoCredentials
+		for _, authenticator := range authenticators {
+			principal, err := authenticator.Authenticate(r)
+			switch {
+			case err == nil:
+				return principal, nil
+			case errors.Is(err, ErrInvalidCredentials):
+				result = ErrInvalidCredentials
+			case !errors.Is(err, ErrNoCredentials):
+				return nil, err
+			}
+		}
+		return nil, result
+	})
+}
+
+type contextKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(contextKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// webSocketTokenPrefix marks the token offered as a WebSocket subprotocol, the only header
+// browsers let a WebSocket client set.
+const webSocketTokenPrefix = "bearer."
+
+// BearerToken reads the token of the Authorization header or, for a WebSocket handshake,
+// of a "bearer.<token>" subprotocol.
+func BearerToken(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return ""
+		}
+		return strings.TrimSpace(token)
+	}
+
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(header, ",") {
+			if token, ok := strings.CutPrefix(strings.TrimSpace(protocol), webSocketTokenPrefix); ok {
+				return token
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,262 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/singleflight"
+)
+
+var errUnknownKey = errors.New("unknown signing key")
+
+// maxDocumentSize bounds the discovery document and the JWKS read from the provider.
+const maxDocumentSize = 1 << 20
+
+// keySet caches the signing keys of an OpenID provider, found through the discovery document
+// of its issuer. The keys are fetched again once expired, or when a token names an unknown
+// key after a rotation, but never more than once per minRefresh: a token with a forged key ID
+// does not reach the provider. When the provider fails, the keys already known are kept.
+// The known keys are served while a refresh runs, and the tokens missing a key at the same
+// time share one refresh.
+type keySet struct {
+	issuer     string
+	client     *http.Client
+	ttl        time.Duration
+	minRefresh time.Duration
+	logger     zerolog.Logger
+
+	group singleflight.Group
+
+	mu          sync.RWMutex
+	jwksURI     string
+	keys        map[string]any
+	expiresAt   time.Time
+	attemptedAt time.Time
+}
+
+func (s *keySet) key(ctx context.Context, kid string) (any, error) {
+	if key, fresh := s.cached(kid); fresh {
+		return key, nil
+	}
+
+	// The refresh is shared with other requests: the cancellation of the one starting it
+	// must not fail the others, the client timeout bounds it.
+	ctx = context.WithoutCancel(ctx)
+	_, _, _ = s.group.Do("refresh", func() (any, error) {
+		now := time.Now()
+		s.mu.Lock()
+		if _, fresh := s.cachedLocked(kid, now); fresh ||
+			(!s.attemptedAt.IsZero() && now.Sub(s.attemptedAt) < s.minRefresh) {
+			s.mu.Unlock()
+			return nil, nil
+		}
+		s.attemptedAt = now
+		jwksURI := s.jwksURI
+		s.mu.Unlock()
+
+		if err := s.refresh(ctx, jwksURI, now); err != nil {
+			s.logger.Error().Err(err).Str("issuer", s.issuer).Msg("Failed to refresh the OIDC signing keys")
+		}
+		return nil, nil
+	})
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if key, found := s.lookup(kid); found {
+		return key, nil
+	}
+	return nil, errUnknownKey
+}
+
+// cached finds a known key, fresh when the keys have not expired.
+func (s *keySet) cached(kid string) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cachedLocked(kid, time.Now())
+}
+
+func (s *keySet) cachedLocked(kid string, now time.Time) (any, bool) {
+	key, found := s.lookup(kid)
+	return key, found && now.Before(s.expiresAt)
+}
+
+// lookup finds the key named by a token, or the only key of the provider when the token
+// names none. mu must be held.
+func (s *keySet) lookup(kid string) (any, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, found := s.keys[kid]
+	return key, found
+}
+
+// refresh fetches the keys without holding mu, then stores them.
+func (s *keySet) refresh(ctx context.Context, jwksURI string, now time.Time) error {
+	if jwksURI == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if _, err := s.get(ctx, strings.TrimSuffix(s.issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+			return fmt.Errorf("discovering the provider: %w", err)
+		}
+		if discovery.Issuer != s.issuer {
+			return fmt.Errorf("the provider names the issuer %q, expected %q", discovery.Issuer, s.issuer)
+		}
+		if discovery.JWKSURI == "" {
+			return errors.New("the provider has no jwks_uri")
+		}
+		jwksURI = discovery.JWKSURI
+		s.mu.Lock()
+		s.jwksURI = jwksURI
+		s.mu.Unlock()
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	header, err := s.get(ctx, jwksURI, &set)
+	if err != nil {
+		return fmt.Errorf("fetching the JWKS: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			s.logger.Warn().Err(err).Str("kid", k.Kid).Msg("Skipping an OIDC signing key")
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return errors.New("the JWKS has no usable signing key")
+	}
+
+	ttl := s.ttl
+	if age := maxAge(header); age > 0 && age < ttl {
+		ttl = age
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	s.expiresAt = now.Add(ttl)
+	return nil
+}
+
+func (s *keySet) get(ctx context.Context, url string, target any) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s answered %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDocumentSize)).Decode(target); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", url, err)
+	}
+	return resp.Header, nil
+}
+
+// maxAge reads the lifetime the provider gives to its JWKS, zero when it gives none.
+func maxAge(header http.Header) time.Duration {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(directive), "max-age="); ok {
+			if d, err := time.ParseDuration(value + "s"); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return 0
+}
+
+// jwk is a public key of a JWKS (RFC 7517): RSA, elliptic curve or Ed25519.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+var curves = map[string]struct {
+	ecdsa elliptic.Curve
+	ecdh  ecdh.Curve
+}{
+	"P-256": {elliptic.P256(), ecdh.P256()},
+	"P-384": {elliptic.P384(), ecdh.P384()},
+	"P-521": {elliptic.P521(), ecdh.P521()},
+}
+
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil || len(n) == 0 {
+			return nil, errors.New("invalid RSA modulus")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+
+	case "EC":
+		curve, ok := curves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if errX != nil || errY != nil {
+			return nil, errors.New("invalid EC coordinates")
+		}
+		// crypto/ecdh checks that the point is on the curve.
+		if _, err := curve.ecdh.NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return nil, fmt.Errorf("invalid EC point: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: curve.ecdsa, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if k.Crv != "Ed25519" || err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
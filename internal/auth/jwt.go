@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/config"
+)
+
+type claims struct {
+	jwt.RegisteredClaims
+	Name   string `json:"name,omitempty"`
+	Scope  string `json:"scope,omitempty"`
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// JWTAuthenticator accepts the HS256 bearer tokens signed with the configured secret. The
+// expiry and the subject are required; the issuer and the audience are checked when set.
+type JWTAuthenticator struct {
+	secret []byte
+	parser *jwt.Parser
+}
+
+// NewTokenAuthenticator verifies the bearer tokens as AUTH_MODE says: signed with the
+// configured secret, or issued by an OpenID provider.
+func NewTokenAuthenticator(cfg config.AuthConfig, logger zerolog.Logger) Authenticator {
+	if cfg.Mode == "oidc" {
+		return NewOIDCAuthenticator(cfg.OIDC, logger)
+	}
+	return NewJWTAuthenticator(cfg)
+}
+
+func NewJWTAuthenticator(cfg config.AuthConfig) *JWTAuthenticator {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.JWTIssuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.JWTIssuer))
+	}
+	if cfg.JWTAudience != "" {
+		options = append(options, jwt.WithAudience(cfg.JWTAudience))
+	}
+
+	return &JWTAuthenticator{
+		secret: []byte(cfg.JWTSecret),
+		parser: jwt.NewParser(options...),
+	}
+}
+
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token := BearerToken(r)
+	if token == "" {
+		if r.Header.Get("Authorization") != "" {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, ErrNoCredentials
+	}
+
+	if len(a.secret) == 0 {
+		return nil, ErrInvalidCredentials
+	}
+
+	var c claims
+	if _, err := a.parser.ParseWithClaims(token, &c, func(*jwt.Token) (any, error) {
+		return a.secret, nil
+	}); err != nil || c.Subject == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &Principal{
+		Subject: c.Subject,
+		Name:    c.Name,
+		Scopes:  strings.Fields(c.Scope),
+		Tenant:  c.Tenant,
+	}, nil
+}
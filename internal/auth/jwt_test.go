@@ -0,0 +1,159 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/config"
+)
+
+const secret = "test-secret"
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+	return token
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "user-1",
+		"name":  "Victor",
+		"scope": "books:read books:write",
+		"iss":   "library",
+		"aud":   "library-api",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestJWTAuthenticator_Authenticate(t *testing.T) {
+	cfg := config.AuthConfig{JWTSecret: secret, JWTIssuer: "library", JWTAudience: "library-api"}
+
+	expired := validClaims()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+
+	noExpiry := validClaims()
+	delete(noExpiry, "exp")
+
+	wrongAudience := validClaims()
+	wrongAudience["aud"] = "other"
+
+	noSubject := validClaims()
+	delete(noSubject, "sub")
+
+	tests := []struct {
+		name              string
+		cfg               config.AuthConfig
+		configureRequest  func(*http.Request)
+		expectedPrincipal *auth.Principal
+		expectedError     error
+	}{
+		{
+			name: "success bearer header",
+			cfg:  cfg,
+			configureRequest: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer "+sign(t, jwt.SigningMethodHS256, []byte(secret), validClaims()))
+			},
+			expectedPrincipal: &auth.Principal{Subject: "user-1", Name: "Victor", Scopes: []string{"books:read", "books:write"}},
+		},
+		{
+			name: "success websocket subprotocol",
+			cfg:  cfg,
+			configureRequest: func(r *http.Request) {
+				r.Header.Set("Sec-WebSocket-Protocol", "library.v1, bearer."+sign(t, jwt.SigningMethodHS256, []byte(secret), validClaims()))
+			},
+			expectedPrincipal: &auth.Principal{Subject: "user-1", Name: "Victor", Scopes: []string{"books:read", "books:write"}},
+		},
+		{
+			name:             "error no credentials",
+			cfg:              cfg,
+			configureRequest: func(*http.Request) {},
+			expectedError:    auth.ErrNoCredentials,
+		},
+		{
+			name: "error other scheme",
+			cfg:  cfg,
+			configureRequest: func(r *http.Request) {
+				r.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+			},
+			expectedError: auth.ErrInvalidCredentials,
+		},
+		{
+			name: "error wrong secret",
+			cfg:  cfg,
+			configureRequest: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer "+sign(t, jwt.SigningMethodHS256, []byte("other"), validClaims()))
+			},
+			expectedError: auth.ErrInvalidCredentials,
+		},
+		{
+			name: "error unexpected algorithm",
+			cfg:  cfg,
+			configureRequest: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer "+sign(t, jwt.SigningMethodHS512, []byte(secret), validClaims()))
+			},
+			expectedError: auth.ErrInvalidCredentials,
+		},
+		{
+			name: "error expired",
+			cfg:  cfg,
+			configureRequest: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer "+sign(t, jwt.SigningMethodHS256, []byte(secret), expired))
+			},
+			expectedError: auth.ErrInvalidCredentials,
+		},
+		{
+			name: "error no expiry",
+			cfg:  cfg,
+			configureRequest: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer "+sign(t, jwt.SigningMethodHS256, []byte(secret), noExpiry))
+			},
+			expectedError: auth.ErrInvalidCredentials,
+		},
+		{
+			name: "error wrong audience",
+			cfg:  cfg,
+			configureRequest: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer "+sign(t, jwt.SigningMethodHS256, []byte(secret), wrongAudience))
+			},
+			expectedError: auth.ErrInvalidCredentials,
+		},
+		{
+			name: "error no subject",
+			cfg:  cfg,
+			configureRequest: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer "+sign(t, jwt.SigningMethodHS256, []byte(secret), noSubject))
+			},
+			expectedError: auth.ErrInvalidCredentials,
+		},
+		{
+			name: "error no secret configured",
+			cfg:  config.AuthConfig{},
+			configureRequest: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer "+sign(t, jwt.SigningMethodHS256, []byte(""), validClaims()))
+			},
+			expectedError: auth.ErrInvalidCredentials,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			test.configureRequest(req)
+
+			principal, err := auth.NewJWTAuthenticator(test.cfg).Authenticate(req)
+
+			assert.ErrorIs(t, err, test.expectedError)
+			assert.Equal(t, test.expectedPrincipal, principal)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+// Middleware attaches the principal of the request to its context. Requests without
+// credentials go on anonymously, Required guards the routes needing a principal, while
+// invalid credentials are rejected right away.
+func Middleware(authenticator Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := authenticator.Authenticate(r)
+			switch {
+			case errors.Is(err, ErrNoCredentials):
+				next.ServeHTTP(w, r)
+			case err != nil:
+				unauthorized(w)
+			default:
+				next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+			}
+		})
+	}
+}
+
+func Required(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := FromContext(r.Context()); !ok {
+			unauthorized(w)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireScope guards the routes needing a principal granted scope.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return Required(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if principal, _ := FromContext(r.Context()); !principal.HasScope(scope) {
+				response.Error(w, http.StatusForbidden, "Forbidden")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
+
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+	response.Error(w, http.StatusUnauthorized, "Unauthorized")
+}
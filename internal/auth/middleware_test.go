@@ -0,0 +1,198 @@
+package auth_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+)
+
+func TestMiddleware(t *testing.T) {
+	authenticator := auth.AuthenticatorFunc(func(r *http.Request) (*auth.Principal, error) {
+		switch r.Header.Get("Authorization") {
+		case "":
+			return nil, auth.ErrNoCredentials
+		case "Bearer valid":
+			return &auth.Principal{Subject: "user-1"}, nil
+		case "Bearer admin":
+			return &auth.Principal{Subject: "admin-1", Scopes: []string{"api_keys:admin"}}, nil
+		default:
+			return nil, auth.ErrInvalidCredentials
+		}
+	})
+
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal, ok := auth.FromContext(r.Context()); ok {
+			_, _ = w.Write([]byte(principal.Subject))
+			return
+		}
+		_, _ = w.Write([]byte("anonymous"))
+	})
+
+	tests := []struct {
+		name           string
+		required       bool
+		scope          string
+		authorization  string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "anonymous on public route",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "anonymous",
+		},
+		{
+			name:           "principal on public route",
+			authorization:  "Bearer valid",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "user-1",
+		},
+		{
+			name:           "invalid credentials on public route",
+			authorization:  "Bearer invalid",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"status":"error","message":"Unauthorized"}`,
+		},
+		{
+			name:           "principal on required route",
+			required:       true,
+			authorization:  "Bearer valid",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "user-1",
+		},
+		{
+			name:           "anonymous on required route",
+			required:       true,
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"status":"error","message":"Unauthorized"}`,
+		},
+		{
+			name:           "principal with scope on scoped route",
+			scope:          "api_keys:admin",
+			authorization:  "Bearer admin",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "admin-1",
+		},
+		{
+			name:           "principal without scope on scoped route",
+			scope:          "api_keys:admin",
+			authorization:  "Bearer valid",
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"status":"error","message":"Forbidden"}`,
+		},
+		{
+			name:           "anonymous on scoped route",
+			scope:          "api_keys:admin",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"status":"error","message":"Unauthorized"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var handler http.Handler = echo
+			if test.required {
+				handler = auth.Required(handler)
+			}
+			if test.scope != "" {
+				handler = auth.RequireScope(test.scope)(handler)
+			}
+			handler = auth.Middleware(authenticator)(handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+			switch test.expectedStatus {
+			case http.StatusUnauthorized:
+				assert.JSONEq(t, test.expectedBody, rr.Body.String())
+				assert.Equal(t, `Bearer realm="api"`, rr.Header().Get("WWW-Authenticate"))
+			case http.StatusForbidden:
+				assert.JSONEq(t, test.expectedBody, rr.Body.String())
+			default:
+				assert.Equal(t, test.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	bearer := auth.AuthenticatorFunc(func(r *http.Request) (*auth.Principal, error) {
+		switch r.Header.Get("Authorization") {
+		case "":
+			return nil, auth.ErrNoCredentials
+		case "Bearer valid":
+			return &auth.Principal{Subject: "user-1"}, nil
+		default:
+			return nil, auth.ErrInvalidCredentials
+		}
+	})
+	apiKey := auth.AuthenticatorFunc(func(r *http.Request) (*auth.Principal, error) {
+		switch r.Header.Get("X-API-Key") {
+		case "":
+			return nil, auth.ErrNoCredentials
+		case "lib_valid":
+			return &auth.Principal{Subject: "api_key:1", APIKeyID: "1"}, nil
+		case "lib_broken":
+			return nil, errors.New("connection refused")
+		default:
+			return nil, auth.ErrInvalidCredentials
+		}
+	})
+
+	tests := []struct {
+		name              string
+		headers           map[string]string
+		expectedPrincipal *auth.Principal
+		expectedError     error
+	}{
+		{
+			name:          "no credentials",
+			expectedError: auth.ErrNoCredentials,
+		},
+		{
+			name:              "first authenticator",
+			headers:           map[string]string{"Authorization": "Bearer valid"},
+			expectedPrincipal: &auth.Principal{Subject: "user-1"},
+		},
+		{
+			name:              "second authenticator after invalid credentials of the first",
+			headers:           map[string]string{"Authorization": "ApiKey lib_valid", "X-API-Key": "lib_valid"},
+			expectedPrincipal: &auth.Principal{Subject: "api_key:1", APIKeyID: "1"},
+		},
+		{
+			name:          "invalid credentials",
+			headers:       map[string]string{"X-API-Key": "lib_unknown"},
+			expectedError: auth.ErrInvalidCredentials,
+		},
+		{
+			name:          "unexpected error",
+			headers:       map[string]string{"X-API-Key": "lib_broken"},
+			expectedError: errors.New("connection refused"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+
+			principal, err := auth.Chain(bearer, apiKey).Authenticate(req)
+
+			assert.Equal(t, test.expectedPrincipal, principal)
+			assert.Equal(t, test.expectedError, err)
+		})
+	}
+}
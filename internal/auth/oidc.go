@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/config"
+)
+
+// oidcMethods are the asymmetric algorithms of the providers. The HS* algorithms are left
+// out, so that a public key can never be used as a shared secret.
+var oidcMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// OIDCAuthenticator accepts the bearer tokens issued by an OpenID provider, verified with the
+// keys of its JWKS. The expiry and the subject are required, the issuer must be the configured
+// one and the audience is checked when set. The scopes of the principal come from the scope
+// claims and from the scopes granted to its roles and groups.
+type OIDCAuthenticator struct {
+	keys        *keySet
+	parser      *jwt.Parser
+	scopeClaims []string
+	roleClaims  []string
+	roleScopes  map[string][]string
+	tenantClaim string
+}
+
+func NewOIDCAuthenticator(cfg config.OIDCConfig, logger zerolog.Logger) *OIDCAuthenticator {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(oidcMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuer(cfg.Issuer),
+		jwt.WithLeeway(30 * time.Second),
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+
+	roleScopes := make(map[string][]string, len(cfg.RoleScopes))
+	for role, scopes := range cfg.RoleScopes {
+		roleScopes[role] = strings.Fields(scopes)
+	}
+
+	return &OIDCAuthenticator{
+		keys: &keySet{
+			issuer:     cfg.Issuer,
+			client:     &http.Client{Timeout: 10 * time.Second},
+			ttl:        cfg.JWKSCacheTTL,
+			minRefresh: cfg.JWKSMinRefresh,
+			logger:     logger,
+		},
+		parser:      jwt.NewParser(options...),
+		scopeClaims: cfg.ScopeClaims,
+		roleClaims:  cfg.RoleClaims,
+		roleScopes:  roleScopes,
+		tenantClaim: cfg.TenantClaim,
+	}
+}
+
+func (a *OIDCAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token := BearerToken(r)
+	if token == "" {
+		if r.Header.Get("Authorization") != "" {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, ErrNoCredentials
+	}
+
+	c := jwt.MapClaims{}
+	if _, err := a.parser.ParseWithClaims(token, c, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return a.keys.key(r.Context(), kid)
+	}); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	subject, _ := c.GetSubject()
+	if subject == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	name, _ := c["name"].(string)
+	if name == "" {
+		name, _ = c["preferred_username"].(string)
+	}
+
+	tenant, _ := c[a.tenantClaim].(string)
+
+	return &Principal{
+		Subject: subject,
+		Name:    name,
+		Scopes:  a.scopes(c),
+		Tenant:  tenant,
+	}, nil
+}
+
+func (a *OIDCAuthenticator) scopes(c jwt.MapClaims) []string {
+	var scopes []string
+	add := func(values []string) {
+		for _, scope := range values {
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	for _, claim := range a.scopeClaims {
+		add(claimValues(c, claim))
+	}
+	for _, claim := range a.roleClaims {
+		for _, role := range claimValues(c, claim) {
+			add(a.roleScopes[role])
+		}
+	}
+
+	return scopes
+}
+
+// claimValues reads a claim, following the dots of its path into the nested objects as in
+// realm_access.roles. A string holds space separated values, as the scope claim does.
+func claimValues(c map[string]any, path string) []string {
+	var value any = c
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+
+	switch value := value.(type) {
+	case string:
+		return strings.Fields(value)
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,277 @@
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/test-utils"
+)
+
+func oidcConfig(issuer string) config.OIDCConfig {
+	return config.OIDCConfig{
+		Issuer:         issuer,
+		Audience:       "library-api",
+		JWKSCacheTTL:   time.Hour,
+		JWKSMinRefresh: time.Minute,
+		ScopeClaims:    []string{"scope", "scp"},
+		RoleClaims:     []string{"roles", "groups", "realm_access.roles"},
+		RoleScopes:     map[string]string{"admin": "api_keys:admin books:write", "/librarians": "books:write"},
+	}
+}
+
+func oidcClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":                "user-1",
+		"preferred_username": "victor",
+		"aud":                "library-api",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func bearer(token string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestOIDCAuthenticator_Authenticate(t *testing.T) {
+	provider := testutils.NewOIDCServer(t)
+
+	with := func(changes jwt.MapClaims) jwt.MapClaims {
+		claims := oidcClaims()
+		for name, value := range changes {
+			if value == nil {
+				delete(claims, name)
+				continue
+			}
+			claims[name] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name              string
+		token             func() string
+		expectedPrincipal *auth.Principal
+		expectedError     error
+	}{
+		{
+			name:              "success scope claim",
+			token:             func() string { return provider.Sign(t, with(jwt.MapClaims{"scope": "openid books:read"})) },
+			expectedPrincipal: &auth.Principal{Subject: "user-1", Name: "victor", Scopes: []string{"openid", "books:read"}},
+		},
+		{
+			name: "success scopes of the roles and groups",
+			token: func() string {
+				return provider.Sign(t, with(jwt.MapClaims{
+					"name":         "Victor",
+					"scp":          []any{"books:read"},
+					"realm_access": map[string]any{"roles": []any{"admin", "offline_access"}},
+					"groups":       []any{"/librarians"},
+				}))
+			},
+			expectedPrincipal: &auth.Principal{Subject: "user-1", Name: "Victor", Scopes: []string{"books:read", "books:write", "api_keys:admin"}},
+		},
+		{
+			name:          "error expired token",
+			token:         func() string { return provider.Sign(t, with(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()})) },
+			expectedError: auth.ErrInvalidCredentials,
+		},
+		{
+			name:          "error other issuer",
+			token:         func() string { return provider.Sign(t, with(jwt.MapClaims{"iss": "https://evil.example.com"})) },
+			expectedError: auth.ErrInvalidCredentials,
+		},
+		{
+			name:          "error other audience",
+			token:         func() string { return provider.Sign(t, with(jwt.MapClaims{"aud": "other-api"})) },
+			expectedError: auth.ErrInvalidCredentials,
+		},
+		{
+			name:          "error no subject",
+			token:         func() string { return provider.Sign(t, with(jwt.MapClaims{"sub": nil})) },
+			expectedError: auth.ErrInvalidCredentials,
+		},
+		{
+			name: "error symmetric algorithm",
+			token: func() string {
+				return sign(t, jwt.SigningMethodHS256, []byte(secret), with(jwt.MapClaims{"iss": provider.Issuer()}))
+			},
+			expectedError: auth.ErrInvalidCredentials,
+		},
+		{
+			name: "error key unknown to the provider",
+			token: func() string {
+				key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				require.NoError(t, err)
+				token := jwt.NewWithClaims(jwt.SigningMethodES256, with(jwt.MapClaims{"iss": provider.Issuer()}))
+				token.Header["kid"] = "forged"
+				signed, err := token.SignedString(key)
+				require.NoError(t, err)
+				return signed
+			},
+			expectedError: auth.ErrInvalidCredentials,
+		},
+	}
+
+	authenticator := auth.NewOIDCAuthenticator(oidcConfig(provider.Issuer()), zerolog.Nop())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			principal, err := authenticator.Authenticate(bearer(test.token()))
+
+			assert.Equal(t, test.expectedPrincipal, principal)
+			assert.Equal(t, test.expectedError, err)
+		})
+	}
+
+	t.Run("error no credentials", func(t *testing.T) {
+		_, err := authenticator.Authenticate(httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, auth.ErrNoCredentials, err)
+	})
+
+	// The forged key ID did not send the authenticator back to the provider.
+	assert.Equal(t, 1, provider.JWKSRequests())
+}
+
+func TestOIDCAuthenticator_KeyRotation(t *testing.T) {
+	newKey := func(t *testing.T) *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		return key
+	}
+
+	t.Run("success new key fetched", func(t *testing.T) {
+		provider := testutils.NewOIDCServer(t)
+		cfg := oidcConfig(provider.Issuer())
+		cfg.JWKSMinRefresh = 0
+		authenticator := auth.NewOIDCAuthenticator(cfg, zerolog.Nop())
+
+		oldToken := provider.Sign(t, oidcClaims())
+		_, err := authenticator.Authenticate(bearer(oldToken))
+		require.NoError(t, err)
+
+		provider.RotateKey(newKey(t), true)
+		_, err = authenticator.Authenticate(bearer(provider.Sign(t, oidcClaims())))
+		require.NoError(t, err)
+		_, err = authenticator.Authenticate(bearer(oldToken))
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, provider.JWKSRequests())
+	})
+
+	t.Run("success one refresh for concurrent tokens", func(t *testing.T) {
+		provider := testutils.NewOIDCServer(t)
+		cfg := oidcConfig(provider.Issuer())
+		cfg.JWKSMinRefresh = 0
+		authenticator := auth.NewOIDCAuthenticator(cfg, zerolog.Nop())
+
+		oldToken := provider.Sign(t, oidcClaims())
+		_, err := authenticator.Authenticate(bearer(oldToken))
+		require.NoError(t, err)
+
+		provider.RotateKey(newKey(t), true)
+		newToken := provider.Sign(t, oidcClaims())
+		var wg sync.WaitGroup
+		errs := make(chan error, 40)
+		for i := range 40 {
+			token := newToken
+			if i%2 == 0 {
+				token = oldToken
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := authenticator.Authenticate(bearer(token))
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 2, provider.JWKSRequests())
+	})
+
+	t.Run("error refresh limited", func(t *testing.T) {
+		provider := testutils.NewOIDCServer(t)
+		authenticator := auth.NewOIDCAuthenticator(oidcConfig(provider.Issuer()), zerolog.Nop())
+
+		_, err := authenticator.Authenticate(bearer(provider.Sign(t, oidcClaims())))
+		require.NoError(t, err)
+
+		provider.RotateKey(newKey(t), false)
+		for range 3 {
+			_, err = authenticator.Authenticate(bearer(provider.Sign(t, oidcClaims())))
+			assert.Equal(t, auth.ErrInvalidCredentials, err)
+		}
+
+		assert.Equal(t, 1, provider.JWKSRequests())
+	})
+
+	t.Run("success expired keys refreshed", func(t *testing.T) {
+		provider := testutils.NewOIDCServer(t)
+		provider.SetCacheControl("public, max-age=1")
+		cfg := oidcConfig(provider.Issuer())
+		cfg.JWKSMinRefresh = 0
+		authenticator := auth.NewOIDCAuthenticator(cfg, zerolog.Nop())
+
+		token := provider.Sign(t, oidcClaims())
+		_, err := authenticator.Authenticate(bearer(token))
+		require.NoError(t, err)
+		_, err = authenticator.Authenticate(bearer(token))
+		require.NoError(t, err)
+		assert.Equal(t, 1, provider.JWKSRequests())
+
+		time.Sleep(time.Second)
+		_, err = authenticator.Authenticate(bearer(token))
+		require.NoError(t, err)
+		assert.Equal(t, 2, provider.JWKSRequests())
+	})
+
+	t.Run("success known keys kept while the provider is down", func(t *testing.T) {
+		provider := testutils.NewOIDCServer(t)
+		provider.SetCacheControl("max-age=1")
+		cfg := oidcConfig(provider.Issuer())
+		cfg.JWKSMinRefresh = 0
+		authenticator := auth.NewOIDCAuthenticator(cfg, zerolog.Nop())
+
+		token := provider.Sign(t, oidcClaims())
+		_, err := authenticator.Authenticate(bearer(token))
+		require.NoError(t, err)
+
+		provider.Close()
+		time.Sleep(time.Second)
+
+		principal, err := authenticator.Authenticate(bearer(token))
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", principal.Subject)
+	})
+
+	t.Run("error provider unreachable", func(t *testing.T) {
+		provider := testutils.NewOIDCServer(t)
+		token := provider.Sign(t, oidcClaims())
+		authenticator := auth.NewOIDCAuthenticator(oidcConfig(provider.Issuer()), zerolog.Nop())
+		provider.Close()
+
+		_, err := authenticator.Authenticate(bearer(token))
+
+		assert.Equal(t, auth.ErrInvalidCredentials, err)
+	})
+}
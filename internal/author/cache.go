@@ -0,0 +1,76 @@
+package author
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"go-boilerplate-rest-api-chi/internal/cache"
+	"go-boilerplate-rest-api-chi/internal/database"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+)
+
+// cachedAuthorRepository serves GetByID from a cache. The authors expanded with their books are
+// read from the database, their books changing with every book written.
+type cachedAuthorRepository struct {
+	AuthorRepository
+	cache *cache.Cache
+}
+
+// NewCachedAuthorRepository decorates repository with c. The entries are invalidated by the
+// Create and Rename of the decorator, whatever the replica.
+func NewCachedAuthorRepository(repository AuthorRepository, c *cache.Cache) AuthorRepository {
+	return &cachedAuthorRepository{
+		AuthorRepository: repository,
+		cache:            c,
+	}
+}
+
+func (r *cachedAuthorRepository) Create(ctx context.Context, newAuthor *entity.Author) (*entity.Author, error) {
+	created, err := r.AuthorRepository.Create(ctx, newAuthor)
+	if err == nil {
+		r.invalidate(ctx, created.ID)
+	}
+
+	return created, err
+}
+
+func (r *cachedAuthorRepository) GetByID(ctx context.Context, authorID uuid.UUID, expand ...string) (*entity.Author, error) {
+	key, ok := cacheKey(ctx, authorID)
+	if !ok || len(expand) > 0 {
+		return r.AuthorRepository.GetByID(ctx, authorID, expand...)
+	}
+
+	return cache.Fetch(ctx, r.cache, key, func(ctx context.Context) (*entity.Author, error) {
+		return r.AuthorRepository.GetByID(ctx, authorID)
+	})
+}
+
+func (r *cachedAuthorRepository) Rename(ctx context.Context, authorID uuid.UUID, name string) (*entity.Author, error) {
+	author, err := r.AuthorRepository.Rename(ctx, authorID, name)
+	r.invalidate(ctx, authorID)
+
+	return author, err
+}
+
+func (r *cachedAuthorRepository) invalidate(ctx context.Context, authorID uuid.UUID) {
+	if tenantID, ok := tenant.FromContext(ctx); ok {
+		// The entry is dropped once committed, or a read of another request in between would
+		// cache the previous value again.
+		database.AfterCommit(ctx, func() {
+			r.cache.Invalidate(ctx, tenantID+":"+authorID.String())
+		})
+	}
+}
+
+// cacheKey names the entry of an author in its tenant. The reads of a transaction skip the
+// cache, which holds committed data only.
+func cacheKey(ctx context.Context, authorID uuid.UUID) (string, bool) {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok || database.InTransaction(ctx) {
+		return "", false
+	}
+
+	return tenantID + ":" + authorID.String(), true
+}
@@ -0,0 +1,103 @@
+package author_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"go-boilerplate-rest-api-chi/internal/author"
+	"go-boilerplate-rest-api-chi/internal/cache"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+)
+
+func TestCachedAuthorRepository(t *testing.T) {
+	authorID := uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626")
+	acme := tenant.WithID(context.Background(), "acme")
+	stored := &entity.Author{ID: authorID, TenantID: "acme", Name: "Victor Hugo"}
+
+	tests := []struct {
+		name          string
+		configureMock func(*mocks.MockAuthorRepository)
+		run           func(t *testing.T, repository author.AuthorRepository)
+	}{
+		{
+			name: "success lookups cached",
+			configureMock: func(mockRepo *mocks.MockAuthorRepository) {
+				mockRepo.EXPECT().GetByID(gomock.Any(), authorID).Return(stored, nil).Times(1)
+			},
+			run: func(t *testing.T, repository author.AuthorRepository) {
+				for range 3 {
+					result, err := repository.GetByID(acme, authorID)
+					require.NoError(t, err)
+					assert.Equal(t, "Victor Hugo", result.Name)
+				}
+			},
+		},
+		{
+			name: "success expanded books not cached",
+			configureMock: func(mockRepo *mocks.MockAuthorRepository) {
+				mockRepo.EXPECT().GetByID(gomock.Any(), authorID, "books").Return(stored, nil).Times(2)
+			},
+			run: func(t *testing.T, repository author.AuthorRepository) {
+				for range 2 {
+					_, err := repository.GetByID(acme, authorID, "books")
+					require.NoError(t, err)
+				}
+			},
+		},
+		{
+			name: "success rename invalidates",
+			configureMock: func(mockRepo *mocks.MockAuthorRepository) {
+				gomock.InOrder(
+					mockRepo.EXPECT().GetByID(gomock.Any(), authorID).Return(stored, nil),
+					mockRepo.EXPECT().Rename(gomock.Any(), authorID, "V. Hugo").Return(&entity.Author{ID: authorID, Name: "V. Hugo"}, nil),
+					mockRepo.EXPECT().GetByID(gomock.Any(), authorID).Return(&entity.Author{ID: authorID, Name: "V. Hugo"}, nil),
+				)
+			},
+			run: func(t *testing.T, repository author.AuthorRepository) {
+				_, _ = repository.GetByID(acme, authorID)
+				_, err := repository.Rename(acme, authorID, "V. Hugo")
+				require.NoError(t, err)
+
+				result, err := repository.GetByID(acme, authorID)
+				require.NoError(t, err)
+				assert.Equal(t, "V. Hugo", result.Name)
+			},
+		},
+		{
+			name: "error not found not cached",
+			configureMock: func(mockRepo *mocks.MockAuthorRepository) {
+				gomock.InOrder(
+					mockRepo.EXPECT().GetByID(gomock.Any(), authorID).Return(nil, author.ErrNotFound),
+					mockRepo.EXPECT().GetByID(gomock.Any(), authorID).Return(stored, nil),
+				)
+			},
+			run: func(t *testing.T, repository author.AuthorRepository) {
+				_, err := repository.GetByID(acme, authorID)
+				assert.Equal(t, author.ErrNotFound, err)
+
+				_, err = repository.GetByID(acme, authorID)
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := mocks.NewMockAuthorRepository(ctrl)
+			test.configureMock(mockRepo)
+
+			c := cache.New("authors", cache.NewMemoryStore(100), cache.NewLocalNotifier(), time.Minute, zerolog.Nop())
+			test.run(t, author.NewCachedAuthorRepository(mockRepo, c))
+		})
+	}
+}
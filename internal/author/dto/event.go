@@ -0,0 +1,18 @@
+package dto
+
+import "go-boilerplate-rest-api-chi/internal/entity"
+
+// AuthorEventData is the payload of the author.* events. PreviousName is only set on
+// author.renamed.
+type AuthorEventData struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	PreviousName string `json:"previous_name,omitempty"`
+}
+
+func ToAuthorEventData(author *entity.Author) AuthorEventData {
+	return AuthorEventData{
+		ID:   author.ID.String(),
+		Name: author.Name,
+	}
+}
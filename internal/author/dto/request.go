@@ -1,5 +1,9 @@
 package dto
 
 type CreateAuthorRequest struct {
-	Name string `json:"name" validate:"required"`
+	Name string `json:"name" validate:"required,notblank,no_html"`
+}
+
+type RenameAuthorRequest struct {
+	Name string `json:"name" validate:"required,notblank,no_html"`
 }
@@ -1,10 +1,20 @@
 package dto
 
-import "go-boilerplate-rest-api-chi/internal/entity"
+import (
+	"time"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+)
 
 type AuthorResponse struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID   string `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name"`
+}
+
+var AuthorCSVHeader = []string{"id", "name"}
+
+func (a AuthorResponse) CSVRecord() []string {
+	return []string{a.ID, a.Name}
 }
 
 func ToAuthorResponse(author *entity.Author) *AuthorResponse {
@@ -13,3 +23,78 @@ func ToAuthorResponse(author *entity.Author) *AuthorResponse {
 		Name: author.Name,
 	}
 }
+
+type AuthorBookResponse struct {
+	ID          string     `json:"id" xml:"id"`
+	Title       string     `json:"title" xml:"title"`
+	Description string     `json:"description" xml:"description"`
+	CreatedAt   *time.Time `json:"created_at,omitempty" xml:"created_at,omitempty"`
+}
+
+var AuthorBookCSVHeader = []string{"id", "title", "description", "created_at"}
+
+func (b AuthorBookResponse) CSVRecord() []string {
+	createdAt := ""
+	if b.CreatedAt != nil {
+		createdAt = b.CreatedAt.Format(time.RFC3339)
+	}
+
+	return []string{b.ID, b.Title, b.Description, createdAt}
+}
+
+func ToAuthorBookResponse(book *entity.Book) AuthorBookResponse {
+	return AuthorBookResponse{
+		ID:          book.ID.String(),
+		Title:       book.Title,
+		Description: book.Description,
+		CreatedAt:   timePtr(book.CreatedAt),
+	}
+}
+
+type AuthorStatsResponse struct {
+	BookCount         int64      `json:"book_count" xml:"book_count" example:"3"`
+	FirstPublishedAt  *time.Time `json:"first_published_at,omitempty" xml:"first_published_at,omitempty"`
+	LatestPublishedAt *time.Time `json:"latest_published_at,omitempty" xml:"latest_published_at,omitempty"`
+}
+
+func ToAuthorStatsResponse(stats *entity.AuthorStats) *AuthorStatsResponse {
+	return &AuthorStatsResponse{
+		BookCount:         stats.BookCount,
+		FirstPublishedAt:  stats.FirstPublishedAt,
+		LatestPublishedAt: stats.LatestPublishedAt,
+	}
+}
+
+type AuthorDetailResponse struct {
+	AuthorResponse
+	CreatedAt *time.Time           `json:"created_at,omitempty" xml:"created_at,omitempty"`
+	UpdatedAt *time.Time           `json:"updated_at,omitempty" xml:"updated_at,omitempty"`
+	Stats     *AuthorStatsResponse `json:"stats,omitempty" xml:"stats,omitempty"`
+	Books     []AuthorBookResponse `json:"books,omitempty" xml:"books>book,omitempty"`
+}
+
+func ToAuthorDetailResponse(author *entity.Author) *AuthorDetailResponse {
+	detail := &AuthorDetailResponse{
+		AuthorResponse: *ToAuthorResponse(author),
+		CreatedAt:      timePtr(author.CreatedAt),
+		UpdatedAt:      timePtr(author.UpdatedAt),
+	}
+
+	if author.Book != nil {
+		detail.Books = make([]AuthorBookResponse, len(author.Book))
+		for i := range author.Book {
+			detail.Books[i] = ToAuthorBookResponse(&author.Book[i])
+		}
+	}
+
+	return detail
+}
+
+// timePtr leaves unset timestamps out of the output instead of rendering the zero time.
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
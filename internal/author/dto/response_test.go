@@ -27,3 +27,37 @@ func TestToAuthorResponse(t *testing.T) {
 		assert.Equal(t, &expectedResponse, response)
 	})
 }
+
+func TestToAuthorDetailResponse(t *testing.T) {
+	t.Run("nominal", func(t *testing.T) {
+		entity := entity.Author{
+			ID:   uuid.MustParse("aeca0955-bae4-47e9-9f85-6818dc68ca51"),
+			Name: "George R.R. Martin",
+			Book: []entity.Book{
+				{
+					ID:          uuid.MustParse("13867a7d-d1c4-4a06-aa60-42741a4fbbbd"),
+					Title:       "A Game of Thrones",
+					Description: "Description1",
+				},
+			},
+		}
+
+		expectedResponse := dto.AuthorDetailResponse{
+			AuthorResponse: dto.AuthorResponse{
+				ID:   "aeca0955-bae4-47e9-9f85-6818dc68ca51",
+				Name: "George R.R. Martin",
+			},
+			Books: []dto.AuthorBookResponse{
+				{
+					ID:          "13867a7d-d1c4-4a06-aa60-42741a4fbbbd",
+					Title:       "A Game of Thrones",
+					Description: "Description1",
+				},
+			},
+		}
+
+		response := dto.ToAuthorDetailResponse(&entity)
+
+		assert.Equal(t, &expectedResponse, response)
+	})
+}
@@ -1,7 +1,6 @@
 package author
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
 
@@ -10,14 +9,53 @@ import (
 	"github.com/rs/zerolog"
 
 	"go-boilerplate-rest-api-chi/internal/author/dto"
+	"go-boilerplate-rest-api-chi/internal/fieldset"
+	"go-boilerplate-rest-api-chi/internal/httpcache"
+	"go-boilerplate-rest-api-chi/internal/pagination"
+	"go-boilerplate-rest-api-chi/internal/request"
 	"go-boilerplate-rest-api-chi/internal/response"
 	internalValidator "go-boilerplate-rest-api-chi/internal/validator"
 )
 
+var authorFieldset = fieldset.Spec{
+	Fields: fieldset.Paths(dto.AuthorDetailResponse{}),
+	Expand: []string{"books"},
+}
+
 type AuthorSuccessResponse struct {
-	Status  string              `json:"status" example:"success"`
-	Message string              `json:"message" example:"Author retrieved successfully"`
-	Author  *dto.AuthorResponse `json:"author"`
+	Status  string                    `json:"status" xml:"status" example:"success"`
+	Message string                    `json:"message" xml:"message" example:"Author retrieved successfully"`
+	Author  *dto.AuthorDetailResponse `json:"author" xml:"author"`
+}
+
+func (a AuthorSuccessResponse) CSVHeader() []string {
+	return dto.AuthorCSVHeader
+}
+
+func (a AuthorSuccessResponse) CSVRecords() [][]string {
+	if a.Author == nil {
+		return nil
+	}
+	return [][]string{a.Author.CSVRecord()}
+}
+
+type AuthorBooksSuccessResponse struct {
+	Status     string                   `json:"status" xml:"status" example:"success"`
+	Message    string                   `json:"message" xml:"message" example:"Books retrieved successfully"`
+	Books      []dto.AuthorBookResponse `json:"books" xml:"books>book"`
+	Pagination pagination.Meta          `json:"pagination" xml:"pagination"`
+}
+
+func (a AuthorBooksSuccessResponse) CSVHeader() []string {
+	return dto.AuthorBookCSVHeader
+}
+
+func (a AuthorBooksSuccessResponse) CSVRecords() [][]string {
+	records := make([][]string, len(a.Books))
+	for i, book := range a.Books {
+		records[i] = book.CSVRecord()
+	}
+	return records
 }
 
 type AuthorHandler struct {
@@ -38,8 +76,10 @@ func (h *AuthorHandler) Routes() http.Handler {
 	r := chi.NewRouter()
 
 	// routes
-	r.Post("/", h.CreateAuthor)
-	r.Get("/{author_id}", h.GetAuthorByID)
+	r.With(response.Acceptable(AuthorSuccessResponse{})).Post("/", h.CreateAuthor)
+	r.With(httpcache.Cacheable).Get("/{author_id}", h.GetAuthorByID)
+	r.With(response.Acceptable(AuthorSuccessResponse{})).Patch("/{author_id}", h.RenameAuthor)
+	r.With(httpcache.Collection).Get("/{author_id}/books", h.GetAuthorBooks)
 
 	return r
 }
@@ -51,22 +91,27 @@ func (h *AuthorHandler) Routes() http.Handler {
 //	@Tags			authors
 //	@Accept			json
 //	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Produce		text/csv
 //	@Param			author	body		dto.CreateAuthorRequest	true	"Author data"
 //	@Success		201		{object}	AuthorSuccessResponse
 //	@Failure		400		{object}	response.ValidationErrorResponse
 //	@Failure		409		{object}	response.ErrorResponse
+//	@Failure		413		{object}	response.ValidationErrorResponse
+//	@Failure		415		{object}	response.ValidationErrorResponse
 //	@Failure		500		{object}	response.ErrorResponse
 //	@Router			/authors [post]
 func (h *AuthorHandler) CreateAuthor(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreateAuthorRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body")
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		request.WriteError(w, err)
 		return
 	}
 
 	if err := h.validator.Struct(&req); err != nil {
-		validationErrors := h.validator.FormatErrors(err)
+		validationErrors := h.validator.FormatErrors(r.Context(), err)
 		response.ValidationError(w, validationErrors)
 		return
 	}
@@ -77,24 +122,85 @@ func (h *AuthorHandler) CreateAuthor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.JSON(w, http.StatusCreated, AuthorSuccessResponse{
+	response.Render(w, r, http.StatusCreated, AuthorSuccessResponse{
 		Status:  "success",
 		Message: "Author created successfully",
-		Author:  dto.ToAuthorResponse(author),
+		Author:  dto.ToAuthorDetailResponse(author),
 	})
 }
 
-// GetAuthorByID godoc
+// RenameAuthor godoc
 //
-//	@Summary		Get author by id
-//	@Description	Get a single author by its ID
+//	@Summary		Rename an author
+//	@Description	Change the name of an author and emit author.renamed
 //	@Tags			authors
+//	@Accept			json
 //	@Produce		json
-//	@Param			author_id	path		string	true	"Author ID"
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Produce		text/csv
+//	@Param			author_id	path		string					true	"Author ID"	format(uuid)
+//	@Param			author		body		dto.RenameAuthorRequest	true	"New name"
 //	@Success		200			{object}	AuthorSuccessResponse
-//	@Failure		400			{object}	response.ErrorResponse
+//	@Failure		400			{object}	response.ValidationErrorResponse
 //	@Failure		404			{object}	response.ErrorResponse
+//	@Failure		409			{object}	response.ErrorResponse
+//	@Failure		413			{object}	response.ValidationErrorResponse
+//	@Failure		415			{object}	response.ValidationErrorResponse
 //	@Failure		500			{object}	response.ErrorResponse
+//	@Router			/authors/{author_id} [patch]
+func (h *AuthorHandler) RenameAuthor(w http.ResponseWriter, r *http.Request) {
+	authorID, err := uuid.Parse(chi.URLParam(r, "author_id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid uuid")
+		return
+	}
+
+	var req dto.RenameAuthorRequest
+
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.validator.FormatErrors(r.Context(), err)
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	author, err := h.service.RenameAuthor(r.Context(), authorID, &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Render(w, r, http.StatusOK, AuthorSuccessResponse{
+		Status:  "success",
+		Message: "Author renamed successfully",
+		Author:  dto.ToAuthorDetailResponse(author),
+	})
+}
+
+// GetAuthorByID godoc
+//
+//	@Summary		Get author by id
+//	@Description	Get a single author by its ID
+//	@Tags			authors
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Produce		text/csv
+//	@Param			author_id			path		string	true	"Author ID"	format(uuid)
+//	@Param			fields				query		string	false	"Comma separated fields to return, e.g. id,name,books.title"
+//	@Param			expand				query		string	false	"Comma separated relationships to load"	Enums(books)
+//	@Param			If-Modified-Since	header		string	false	"Date of the copy held, answered 304 when the author has not changed since, its books and stats not being loaded"
+//	@Success		200					{object}	AuthorSuccessResponse
+//	@Header			200					{string}	Last-Modified	"Date of the last change of the author, its books and stats not being loaded"
+//	@Failure		400					{object}	response.ValidationErrorResponse
+//	@Failure		404					{object}	response.ErrorResponse
+//	@Failure		500					{object}	response.ErrorResponse
+//	@Success		304
 //	@Router			/authors/{author_id} [get]
 func (h *AuthorHandler) GetAuthorByID(w http.ResponseWriter, r *http.Request) {
 	authorID, err := uuid.Parse(chi.URLParam(r, "author_id"))
@@ -103,16 +209,90 @@ func (h *AuthorHandler) GetAuthorByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	author, err := h.service.GetAuthorByID(r.Context(), authorID)
+	selection, errs := fieldset.Parse(r, authorFieldset)
+	if errs != nil {
+		response.ValidationError(w, errs)
+		return
+	}
+
+	relations := selection.Relations(authorFieldset)
+	author, err := h.service.GetAuthorByID(r.Context(), authorID, relations...)
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
-	response.JSON(w, http.StatusOK, AuthorSuccessResponse{
+	// The books and the stats change without the author, dated by its own fields only.
+	if len(relations) == 0 && !selection.Includes("stats") && httpcache.NotModified(w, r, author.UpdatedAt) {
+		return
+	}
+
+	authorResponse := dto.ToAuthorDetailResponse(author)
+
+	if selection.Includes("stats") {
+		stats, err := h.service.GetAuthorStats(r.Context(), authorID)
+		if err != nil {
+			h.handleError(w, err)
+			return
+		}
+		authorResponse.Stats = dto.ToAuthorStatsResponse(stats)
+	}
+
+	selection.Apply(authorResponse)
+
+	response.Render(w, r, http.StatusOK, selection.View(AuthorSuccessResponse{
 		Status:  "success",
 		Message: "Author retrieved successfully",
-		Author:  dto.ToAuthorResponse(author),
+		Author:  authorResponse,
+	}, "author"))
+}
+
+// GetAuthorBooks godoc
+//
+//	@Summary		List the books of an author
+//	@Description	Get a paginated list of the books written by an author
+//	@Tags			authors
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Produce		text/csv
+//	@Param			author_id	path		string	true	"Author ID"		format(uuid)
+//	@Param			page		query		int		false	"Page number"	minimum(1)	default(1)
+//	@Param			page_size	query		int		false	"Page size"		minimum(1)	maximum(100)	default(20)
+//	@Success		200			{object}	AuthorBooksSuccessResponse
+//	@Failure		400			{object}	response.ValidationErrorResponse
+//	@Failure		404			{object}	response.ErrorResponse
+//	@Failure		500			{object}	response.ErrorResponse
+//	@Router			/authors/{author_id}/books [get]
+func (h *AuthorHandler) GetAuthorBooks(w http.ResponseWriter, r *http.Request) {
+	authorID, err := uuid.Parse(chi.URLParam(r, "author_id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid uuid")
+		return
+	}
+
+	params, errs := pagination.Parse(r)
+	if errs != nil {
+		response.ValidationError(w, errs)
+		return
+	}
+
+	books, total, err := h.service.GetAuthorBooks(r.Context(), authorID, params)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	booksResponse := make([]dto.AuthorBookResponse, len(books))
+	for i := range books {
+		booksResponse[i] = dto.ToAuthorBookResponse(&books[i])
+	}
+
+	response.Render(w, r, http.StatusOK, AuthorBooksSuccessResponse{
+		Status:     "success",
+		Message:    "Books retrieved successfully",
+		Books:      booksResponse,
+		Pagination: pagination.NewMeta(params, total),
 	})
 }
 
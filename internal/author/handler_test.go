@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -20,6 +21,7 @@ import (
 	"go-boilerplate-rest-api-chi/internal/author/dto"
 	"go-boilerplate-rest-api-chi/internal/entity"
 	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/pagination"
 	"go-boilerplate-rest-api-chi/internal/response"
 	"go-boilerplate-rest-api-chi/internal/validator"
 )
@@ -53,20 +55,27 @@ func TestAuthorHandler_CreateAuthor(t *testing.T) {
 			expectedResponse: &author.AuthorSuccessResponse{
 				Status:  "success",
 				Message: "Author created successfully",
-				Author: &dto.AuthorResponse{
-					ID:   "aeca0955-bae4-47e9-9f85-6818dc68ca51",
-					Name: "George R.R. Martin",
+				Author: &dto.AuthorDetailResponse{
+					AuthorResponse: dto.AuthorResponse{
+						ID:   "aeca0955-bae4-47e9-9f85-6818dc68ca51",
+						Name: "George R.R. Martin",
+					},
 				},
 			},
 		},
 		{
-			name:               "error invalid JSON",
+			name:               "error empty body",
 			requestBody:        nil,
 			configureMock:      func(mockService *mocks.MockAuthorService) {},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: &response.ErrorResponse{
+			expectedResponse: response.ValidationErrorResponse{
 				Status:  "error",
 				Message: "Invalid request body",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "body",
+					Message: "body must not be empty",
+					Tag:     "required",
+				}},
 			},
 		},
 		{
@@ -80,8 +89,9 @@ func TestAuthorHandler_CreateAuthor(t *testing.T) {
 				Status:  "error",
 				Message: "Validation failed",
 				Errors: []response.ValidationErrorDetail{{
-					Field:   "Name",
-					Message: "Name is required",
+					Field:   "name",
+					Message: "name is required",
+					Tag:     "required",
 				}},
 			},
 		},
@@ -167,10 +177,138 @@ func TestAuthorHandler_CreateAuthor(t *testing.T) {
 	}
 }
 
+func TestAuthorHandler_RenameAuthor(t *testing.T) {
+	authorID := uuid.MustParse("aeca0955-bae4-47e9-9f85-6818dc68ca51")
+
+	tests := []struct {
+		name               string
+		authorID           string
+		requestBody        interface{}
+		configureMock      func(*mocks.MockAuthorService)
+		expectedStatusCode int
+		expectedResponse   interface{}
+	}{
+		{
+			name:        "success rename author",
+			authorID:    authorID.String(),
+			requestBody: dto.RenameAuthorRequest{Name: "George Martin"},
+			configureMock: func(mockService *mocks.MockAuthorService) {
+				mockService.EXPECT().
+					RenameAuthor(gomock.Any(), authorID, &dto.RenameAuthorRequest{Name: "George Martin"}).
+					Return(&entity.Author{ID: authorID, Name: "George Martin"}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &author.AuthorSuccessResponse{
+				Status:  "success",
+				Message: "Author renamed successfully",
+				Author: &dto.AuthorDetailResponse{
+					AuthorResponse: dto.AuthorResponse{
+						ID:   authorID.String(),
+						Name: "George Martin",
+					},
+				},
+			},
+		},
+		{
+			name:               "error invalid uuid",
+			authorID:           "invalid",
+			requestBody:        dto.RenameAuthorRequest{Name: "George Martin"},
+			configureMock:      func(mockService *mocks.MockAuthorService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: &response.ErrorResponse{
+				Status:  "error",
+				Message: "Invalid uuid",
+			},
+		},
+		{
+			name:               "error validation fails empty name",
+			authorID:           authorID.String(),
+			requestBody:        dto.RenameAuthorRequest{},
+			configureMock:      func(mockService *mocks.MockAuthorService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ValidationErrorResponse{
+				Status:  "error",
+				Message: "Validation failed",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "name",
+					Message: "name is required",
+					Tag:     "required",
+				}},
+			},
+		},
+		{
+			name:        "error author not found",
+			authorID:    authorID.String(),
+			requestBody: dto.RenameAuthorRequest{Name: "George Martin"},
+			configureMock: func(mockService *mocks.MockAuthorService) {
+				mockService.EXPECT().
+					RenameAuthor(gomock.Any(), authorID, gomock.Any()).
+					Return(nil, author.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: &response.ErrorResponse{
+				Status:  "error",
+				Message: "Author not found",
+			},
+		},
+		{
+			name:        "error duplicate name",
+			authorID:    authorID.String(),
+			requestBody: dto.RenameAuthorRequest{Name: "Victor Hugo"},
+			configureMock: func(mockService *mocks.MockAuthorService) {
+				mockService.EXPECT().
+					RenameAuthor(gomock.Any(), authorID, gomock.Any()).
+					Return(nil, author.ErrDuplicate)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: &response.ErrorResponse{
+				Status:  "error",
+				Message: "Author with this name already exists",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockService := mocks.NewMockAuthorService(ctrl)
+			test.configureMock(mockService)
+
+			handler := author.NewAuthorHandler(mockService, validator.New(), zerolog.Nop())
+
+			b, err := json.Marshal(test.requestBody)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPatch, "/authors/"+test.authorID, bytes.NewBuffer(b))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Mount("/authors", handler.Routes())
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+
+			expectedJSON, err := json.Marshal(test.expectedResponse)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, string(expectedJSON), w.Body.String())
+		})
+	}
+}
+
 func TestAuthorHandler_GetAuthorByID(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	firstPublishedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	latestPublishedAt := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name               string
 		idInUrlParam       string
+		query              string
 		configureMock      func(*mocks.MockAuthorService)
 		expectedStatusCode int
 		expectedResponse   interface{}
@@ -181,21 +319,122 @@ func TestAuthorHandler_GetAuthorByID(t *testing.T) {
 			configureMock: func(mockService *mocks.MockAuthorService) {
 				mockService.EXPECT().
 					GetAuthorByID(gomock.Any(), uuid.MustParse("aeca0955-bae4-47e9-9f85-6818dc68ca51")).
+					Return(&entity.Author{
+						ID:        uuid.MustParse("aeca0955-bae4-47e9-9f85-6818dc68ca51"),
+						Name:      "George R.R. Martin",
+						CreatedAt: createdAt,
+						UpdatedAt: createdAt,
+					}, nil)
+
+				mockService.EXPECT().
+					GetAuthorStats(gomock.Any(), uuid.MustParse("aeca0955-bae4-47e9-9f85-6818dc68ca51")).
+					Return(&entity.AuthorStats{
+						BookCount:         2,
+						FirstPublishedAt:  &firstPublishedAt,
+						LatestPublishedAt: &latestPublishedAt,
+					}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &author.AuthorSuccessResponse{
+				Status:  "success",
+				Message: "Author retrieved successfully",
+				Author: &dto.AuthorDetailResponse{
+					AuthorResponse: dto.AuthorResponse{
+						ID:   "aeca0955-bae4-47e9-9f85-6818dc68ca51",
+						Name: "George R.R. Martin",
+					},
+					CreatedAt: &createdAt,
+					UpdatedAt: &createdAt,
+					Stats: &dto.AuthorStatsResponse{
+						BookCount:         2,
+						FirstPublishedAt:  &firstPublishedAt,
+						LatestPublishedAt: &latestPublishedAt,
+					},
+				},
+			},
+		},
+		{
+			name:         "success get author with books",
+			idInUrlParam: "aeca0955-bae4-47e9-9f85-6818dc68ca51",
+			query:        "?expand=books",
+			configureMock: func(mockService *mocks.MockAuthorService) {
+				mockService.EXPECT().
+					GetAuthorByID(gomock.Any(), uuid.MustParse("aeca0955-bae4-47e9-9f85-6818dc68ca51"), "books").
 					Return(&entity.Author{
 						ID:   uuid.MustParse("aeca0955-bae4-47e9-9f85-6818dc68ca51"),
 						Name: "George R.R. Martin",
+						Book: []entity.Book{
+							{
+								ID:          uuid.MustParse("13867a7d-d1c4-4a06-aa60-42741a4fbbbd"),
+								Title:       "A Game of Thrones",
+								Description: "Description1",
+							},
+						},
 					}, nil)
+
+				mockService.EXPECT().
+					GetAuthorStats(gomock.Any(), uuid.MustParse("aeca0955-bae4-47e9-9f85-6818dc68ca51")).
+					Return(&entity.AuthorStats{BookCount: 1}, nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse: &author.AuthorSuccessResponse{
 				Status:  "success",
 				Message: "Author retrieved successfully",
-				Author: &dto.AuthorResponse{
-					ID:   "aeca0955-bae4-47e9-9f85-6818dc68ca51",
-					Name: "George R.R. Martin",
+				Author: &dto.AuthorDetailResponse{
+					AuthorResponse: dto.AuthorResponse{
+						ID:   "aeca0955-bae4-47e9-9f85-6818dc68ca51",
+						Name: "George R.R. Martin",
+					},
+					Stats: &dto.AuthorStatsResponse{BookCount: 1},
+					Books: []dto.AuthorBookResponse{
+						{
+							ID:          "13867a7d-d1c4-4a06-aa60-42741a4fbbbd",
+							Title:       "A Game of Thrones",
+							Description: "Description1",
+						},
+					},
 				},
 			},
 		},
+		{
+			name:         "success get author with sparse fields",
+			idInUrlParam: "aeca0955-bae4-47e9-9f85-6818dc68ca51",
+			query:        "?fields=name,books.title",
+			configureMock: func(mockService *mocks.MockAuthorService) {
+				mockService.EXPECT().
+					GetAuthorByID(gomock.Any(), uuid.MustParse("aeca0955-bae4-47e9-9f85-6818dc68ca51"), "books").
+					Return(&entity.Author{
+						ID:   uuid.MustParse("aeca0955-bae4-47e9-9f85-6818dc68ca51"),
+						Name: "George R.R. Martin",
+						Book: []entity.Book{
+							{
+								ID:          uuid.MustParse("13867a7d-d1c4-4a06-aa60-42741a4fbbbd"),
+								Title:       "A Game of Thrones",
+								Description: "Description1",
+							},
+						},
+					}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: json.RawMessage(`{"status": "success", "message": "Author retrieved successfully", "author": {
+				"name": "George R.R. Martin", "books": [{"title": "A Game of Thrones"}]
+			}}`),
+		},
+		{
+			name:               "error unknown field",
+			idInUrlParam:       "aeca0955-bae4-47e9-9f85-6818dc68ca51",
+			query:              "?fields=name,birthdate",
+			configureMock:      func(mockService *mocks.MockAuthorService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ValidationErrorResponse{
+				Status:  "error",
+				Message: "Validation failed",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "fields",
+					Message: `unknown field "birthdate"`,
+				}},
+			},
+		},
 		{
 			name:               "error invalid uuid",
 			idInUrlParam:       "invalid-uuid",
@@ -247,7 +486,120 @@ func TestAuthorHandler_GetAuthorByID(t *testing.T) {
 			v := validator.New()
 			handler := author.NewAuthorHandler(mockService, v, zerolog.Nop())
 
-			url := fmt.Sprintf("/authors/%s", test.idInUrlParam)
+			url := fmt.Sprintf("/authors/%s%s", test.idInUrlParam, test.query)
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			w := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Mount("/authors", handler.Routes())
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+
+			expectedJSON, err := json.Marshal(test.expectedResponse)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, string(expectedJSON), w.Body.String())
+		})
+	}
+}
+
+func TestAuthorHandler_GetAuthorBooks(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		idInUrlParam       string
+		query              string
+		configureMock      func(*mocks.MockAuthorService)
+		expectedStatusCode int
+		expectedResponse   interface{}
+	}{
+		{
+			name:         "success get author books",
+			idInUrlParam: "aeca0955-bae4-47e9-9f85-6818dc68ca51",
+			query:        "?page=2&page_size=1",
+			configureMock: func(mockService *mocks.MockAuthorService) {
+				mockService.EXPECT().
+					GetAuthorBooks(gomock.Any(), uuid.MustParse("aeca0955-bae4-47e9-9f85-6818dc68ca51"), pagination.Params{Page: 2, PageSize: 1}).
+					Return([]entity.Book{
+						{
+							ID:          uuid.MustParse("13867a7d-d1c4-4a06-aa60-42741a4fbbbd"),
+							Title:       "A Clash of Kings",
+							Description: "Description2",
+							CreatedAt:   createdAt,
+						},
+					}, int64(3), nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &author.AuthorBooksSuccessResponse{
+				Status:  "success",
+				Message: "Books retrieved successfully",
+				Books: []dto.AuthorBookResponse{
+					{
+						ID:          "13867a7d-d1c4-4a06-aa60-42741a4fbbbd",
+						Title:       "A Clash of Kings",
+						Description: "Description2",
+						CreatedAt:   &createdAt,
+					},
+				},
+				Pagination: pagination.Meta{Page: 2, PageSize: 1, TotalItems: 3, TotalPages: 3},
+			},
+		},
+		{
+			name:               "error invalid page size",
+			idInUrlParam:       "aeca0955-bae4-47e9-9f85-6818dc68ca51",
+			query:              "?page_size=0",
+			configureMock:      func(mockService *mocks.MockAuthorService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ValidationErrorResponse{
+				Status:  "error",
+				Message: "Validation failed",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "page_size",
+					Message: "page_size must be between 1 and 100",
+				}},
+			},
+		},
+		{
+			name:               "error invalid uuid",
+			idInUrlParam:       "invalid-uuid",
+			configureMock:      func(mockService *mocks.MockAuthorService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ErrorResponse{
+				Status:  "error",
+				Message: "Invalid uuid",
+			},
+		},
+		{
+			name:         "error author not found",
+			idInUrlParam: "aeca0955-bae4-47e9-9f85-6818dc68ca51",
+			configureMock: func(mockService *mocks.MockAuthorService) {
+				mockService.EXPECT().
+					GetAuthorBooks(gomock.Any(), uuid.MustParse("aeca0955-bae4-47e9-9f85-6818dc68ca51"), pagination.Params{Page: 1, PageSize: pagination.DefaultPageSize}).
+					Return(nil, int64(0), author.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: &response.ErrorResponse{
+				Status:  "error",
+				Message: "Author not found",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockService := mocks.NewMockAuthorService(ctrl)
+			test.configureMock(mockService)
+
+			v := validator.New()
+			handler := author.NewAuthorHandler(mockService, v, zerolog.Nop())
+
+			url := fmt.Sprintf("/authors/%s/books%s", test.idInUrlParam, test.query)
 			req := httptest.NewRequest(http.MethodGet, url, nil)
 			w := httptest.NewRecorder()
 
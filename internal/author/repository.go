@@ -7,15 +7,30 @@ import (
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"go-boilerplate-rest-api-chi/internal/author/dto"
+	"go-boilerplate-rest-api-chi/internal/database"
 	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/outbox"
+	"go-boilerplate-rest-api-chi/internal/pagination"
 )
 
 //go:generate mockgen -destination=../mocks/mock_author_repository.go -package=mocks go-boilerplate-rest-api-chi/internal/author AuthorRepository
 type AuthorRepository interface {
 	Create(ctx context.Context, newAuthor *entity.Author) (*entity.Author, error)
-	GetByID(ctx context.Context, authorID uuid.UUID) (*entity.Author, error)
+	GetByID(ctx context.Context, authorID uuid.UUID, expand ...string) (*entity.Author, error)
+	GetByIDs(ctx context.Context, authorIDs []uuid.UUID) ([]*entity.Author, error)
+	Rename(ctx context.Context, authorID uuid.UUID, name string) (*entity.Author, error)
 	Exists(ctx context.Context, authorID uuid.UUID) (bool, error)
+	GetBooks(ctx context.Context, authorID uuid.UUID, params pagination.Params) ([]entity.Book, int64, error)
+	GetStats(ctx context.Context, authorID uuid.UUID) (*entity.AuthorStats, error)
+}
+
+// relations maps the relationship names exposed by the API to the GORM associations.
+var relations = map[string]string{
+	"books": "Book",
 }
 
 type authorRepository struct {
@@ -30,8 +45,16 @@ func NewAuthorRepository(db *gorm.DB, logger zerolog.Logger) AuthorRepository {
 	}
 }
 
+// Create records author.created in the outbox, in the transaction of the insert.
 func (r *authorRepository) Create(ctx context.Context, newAuthor *entity.Author) (*entity.Author, error) {
-	if err := r.db.WithContext(ctx).Create(newAuthor).Error; err != nil {
+	err := database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(newAuthor).Error; err != nil {
+			return err
+		}
+
+		return outbox.Add(tx, event.New(event.AuthorCreated, newAuthor.ID, dto.ToAuthorEventData(newAuthor)))
+	})
+	if err != nil {
 		if errors.Is(err, gorm.ErrDuplicatedKey) {
 			return nil, ErrDuplicate
 		}
@@ -43,10 +66,10 @@ func (r *authorRepository) Create(ctx context.Context, newAuthor *entity.Author)
 	return newAuthor, nil
 }
 
-func (r *authorRepository) GetByID(ctx context.Context, authorID uuid.UUID) (*entity.Author, error) {
+func (r *authorRepository) GetByID(ctx context.Context, authorID uuid.UUID, expand ...string) (*entity.Author, error) {
 	var author *entity.Author
 
-	if err := r.db.WithContext(ctx).First(&author, "id = ?", authorID).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Scopes(preloadScope(expand)).First(&author, "id = ?", authorID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNotFound
 		}
@@ -58,8 +81,115 @@ func (r *authorRepository) GetByID(ctx context.Context, authorID uuid.UUID) (*en
 	return author, nil
 }
 
+// GetByIDs returns the authors found, in no particular order.
+func (r *authorRepository) GetByIDs(ctx context.Context, authorIDs []uuid.UUID) ([]*entity.Author, error) {
+	var authors []*entity.Author
+
+	if err := database.Conn(ctx, r.db).Where("id IN ?", authorIDs).Find(&authors).Error; err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return authors, nil
+}
+
+// Rename records author.renamed in the outbox with the previous name, unless the name is
+// unchanged.
+func (r *authorRepository) Rename(ctx context.Context, authorID uuid.UUID, name string) (*entity.Author, error) {
+	var author entity.Author
+
+	err := database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&author, "id = ?", authorID).Error; err != nil {
+			return err
+		}
+
+		if author.Name == name {
+			return nil
+		}
+
+		previousName := author.Name
+		if err := tx.Model(&author).Update("name", name).Error; err != nil {
+			return err
+		}
+
+		data := dto.ToAuthorEventData(&author)
+		data.PreviousName = previousName
+
+		return outbox.Add(tx, event.New(event.AuthorRenamed, author.ID, data))
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrNotFound
+		case errors.Is(err, gorm.ErrDuplicatedKey):
+			return nil, ErrDuplicate
+		}
+
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return &author, nil
+}
+
+// Within a transaction, Exists also keeps the author from being deleted until it ends.
 func (r *authorRepository) Exists(ctx context.Context, authorID uuid.UUID) (bool, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&entity.Author{}).Where("id = ?", authorID).Count(&count).Error
+
+	query := database.Conn(ctx, r.db).Model(&entity.Author{}).Where("id = ?", authorID)
+	if database.InTransaction(ctx) {
+		query = query.Clauses(clause.Locking{Strength: "SHARE"})
+	}
+
+	err := query.Count(&count).Error
 	return count > 0, err
 }
+
+// GetBooks queries the books table directly so the author package does not depend on
+// the book package, which already depends on it.
+func (r *authorRepository) GetBooks(ctx context.Context, authorID uuid.UUID, params pagination.Params) ([]entity.Book, int64, error) {
+	var total int64
+	query := database.Conn(ctx, r.db).Model(&entity.Book{}).Where("author_id = ?", authorID)
+
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, 0, err
+	}
+
+	var books []entity.Book
+	if err := query.Order("created_at, id").Offset(params.Offset()).Limit(params.PageSize).Find(&books).Error; err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, 0, err
+	}
+
+	return books, total, nil
+}
+
+// GetStats aggregates the books of an author. Books have no publication date yet, so
+// the date a book was added stands for its publication.
+func (r *authorRepository) GetStats(ctx context.Context, authorID uuid.UUID) (*entity.AuthorStats, error) {
+	var stats entity.AuthorStats
+
+	err := database.Conn(ctx, r.db).Model(&entity.Book{}).
+		Select("COUNT(*) AS book_count, MIN(created_at) AS first_published_at, MAX(created_at) AS latest_published_at").
+		Where("author_id = ?", authorID).
+		Scan(&stats).Error
+	if err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+func preloadScope(expand []string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, relation := range expand {
+			if association, ok := relations[relation]; ok {
+				db = db.Preload(association)
+			}
+		}
+
+		return db
+	}
+}
@@ -13,7 +13,9 @@ import (
 	"gorm.io/gorm"
 
 	"go-boilerplate-rest-api-chi/internal/author"
+	"go-boilerplate-rest-api-chi/internal/database"
 	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/pagination"
 	"go-boilerplate-rest-api-chi/internal/test-utils"
 )
 
@@ -31,14 +33,18 @@ func TestAuthorRepository_Create(t *testing.T) {
 				Name: "Victor Hugo",
 			},
 			configureMock: func(mock sqlmock.Sqlmock, input *entity.Author) {
+				mock.ExpectBegin()
 				mock.ExpectExec("INSERT INTO `authors`").
 					WithArgs(
 						sqlmock.AnyArg(), // ID généré
+						entity.DefaultTenantID,
 						input.Name,
 						sqlmock.AnyArg(), // created_at
 						sqlmock.AnyArg(), // updated_at
 					).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO `outbox`").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			expectedError: nil,
 			expectedResponse: &entity.Author{
@@ -51,14 +57,17 @@ func TestAuthorRepository_Create(t *testing.T) {
 				Name: "Duplicate Author",
 			},
 			configureMock: func(mock sqlmock.Sqlmock, input *entity.Author) {
+				mock.ExpectBegin()
 				mock.ExpectExec("INSERT INTO `authors`").
 					WithArgs(
 						sqlmock.AnyArg(), // ID généré
+						entity.DefaultTenantID,
 						input.Name,
 						sqlmock.AnyArg(), // created_at
 						sqlmock.AnyArg(), // updated_at
 					).
 					WillReturnError(gorm.ErrDuplicatedKey)
+				mock.ExpectRollback()
 			},
 			expectedError:    author.ErrDuplicate,
 			expectedResponse: nil,
@@ -69,14 +78,17 @@ func TestAuthorRepository_Create(t *testing.T) {
 				Name: "Test Author",
 			},
 			configureMock: func(mock sqlmock.Sqlmock, input *entity.Author) {
+				mock.ExpectBegin()
 				mock.ExpectExec("INSERT INTO `authors`").
 					WithArgs(
 						sqlmock.AnyArg(), // ID généré
+						entity.DefaultTenantID,
 						input.Name,
 						sqlmock.AnyArg(), // created_at
 						sqlmock.AnyArg(), // updated_at
 					).
 					WillReturnError(gorm.ErrInvalidDB)
+				mock.ExpectRollback()
 			},
 			expectedError:    gorm.ErrInvalidDB,
 			expectedResponse: nil,
@@ -118,6 +130,7 @@ func TestAuthorRepository_GetByID(t *testing.T) {
 	tests := []struct {
 		name             string
 		authorID         uuid.UUID
+		expand           []string
 		configureMock    func(sqlmock.Sqlmock, uuid.UUID)
 		expectedError    error
 		expectedResponse *entity.Author
@@ -141,6 +154,36 @@ func TestAuthorRepository_GetByID(t *testing.T) {
 				Name: "Victor Hugo",
 			},
 		},
+		{
+			name:     "success get author by id with books",
+			authorID: uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626"),
+			expand:   []string{"books"},
+			configureMock: func(mock sqlmock.Sqlmock, id uuid.UUID) {
+				now := time.Now()
+
+				rows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
+					AddRow(id, "Victor Hugo", now, now)
+
+				mock.ExpectQuery("SELECT \\* FROM `authors` WHERE id = \\? ORDER BY `authors`.`id` LIMIT \\?").
+					WithArgs(id, 1).
+					WillReturnRows(rows)
+
+				bookRows := sqlmock.NewRows([]string{"id", "title", "description", "author_id", "created_at", "updated_at"}).
+					AddRow(uuid.MustParse("13867a7d-d1c4-4a06-aa60-42741a4fbbbd"), "Les Misérables", "Description1", id, now, now)
+
+				mock.ExpectQuery("SELECT \\* FROM `books` WHERE `books`.`author_id` = \\?").
+					WithArgs(id).
+					WillReturnRows(bookRows)
+			},
+			expectedError: nil,
+			expectedResponse: &entity.Author{
+				ID:   uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626"),
+				Name: "Victor Hugo",
+				Book: []entity.Book{
+					{Title: "Les Misérables"},
+				},
+			},
+		},
 		{
 			name:     "error author not found",
 			authorID: uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626"),
@@ -172,7 +215,7 @@ func TestAuthorRepository_GetByID(t *testing.T) {
 
 			repo := author.NewAuthorRepository(db, zerolog.Nop())
 
-			author, err := repo.GetByID(context.Background(), test.authorID)
+			author, err := repo.GetByID(context.Background(), test.authorID, test.expand...)
 
 			if test.expectedError != nil {
 				assert.Error(t, err)
@@ -185,6 +228,7 @@ func TestAuthorRepository_GetByID(t *testing.T) {
 				assert.NotNil(t, author)
 				assert.Equal(t, test.expectedResponse.ID, author.ID)
 				assert.Equal(t, test.expectedResponse.Name, author.Name)
+				assert.Len(t, author.Book, len(test.expectedResponse.Book))
 				assert.NotZero(t, author.CreatedAt)
 				assert.NotZero(t, author.UpdatedAt)
 			} else {
@@ -196,11 +240,103 @@ func TestAuthorRepository_GetByID(t *testing.T) {
 	}
 }
 
+func TestAuthorRepository_Rename(t *testing.T) {
+	authorID := uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626")
+	selectQuery := "SELECT \\* FROM `authors` WHERE id = \\? ORDER BY `authors`.`id` LIMIT \\? FOR UPDATE"
+
+	tests := []struct {
+		name          string
+		newName       string
+		configureMock func(sqlmock.Sqlmock)
+		expectedName  string
+		expectedError error
+	}{
+		{
+			name:    "success rename author",
+			newName: "Joanne Rowling",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).
+					WithArgs(authorID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(authorID, "J.K. Rowling"))
+				mock.ExpectExec("UPDATE `authors` SET `name`=\\?,`updated_at`=\\? WHERE `id` = \\?").
+					WithArgs("Joanne Rowling", sqlmock.AnyArg(), authorID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO `outbox`").
+					WithArgs(sqlmock.AnyArg(), "author.renamed", authorID, sqlmock.AnyArg(), sqlmock.AnyArg(), "", "", nil, nil, "", 0, nil, nil, "", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedName: "Joanne Rowling",
+		},
+		{
+			name:    "success unchanged name records no event",
+			newName: "J.K. Rowling",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).
+					WithArgs(authorID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(authorID, "J.K. Rowling"))
+				mock.ExpectCommit()
+			},
+			expectedName: "J.K. Rowling",
+		},
+		{
+			name:    "error author not found",
+			newName: "Joanne Rowling",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).
+					WithArgs(authorID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+				mock.ExpectRollback()
+			},
+			expectedError: author.ErrNotFound,
+		},
+		{
+			name:    "error duplicate name",
+			newName: "Victor Hugo",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).
+					WithArgs(authorID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(authorID, "J.K. Rowling"))
+				mock.ExpectExec("UPDATE `authors`").
+					WillReturnError(gorm.ErrDuplicatedKey)
+				mock.ExpectRollback()
+			},
+			expectedError: author.ErrDuplicate,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := testutils.NewGormMySQL(t)
+			test.configureMock(mock)
+
+			repo := author.NewAuthorRepository(db, zerolog.Nop())
+
+			renamed, err := repo.Rename(context.Background(), authorID, test.newName)
+
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				assert.Nil(t, renamed)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedName, renamed.Name)
+			}
+
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestAuthorRepository_Exists(t *testing.T) {
 	tests := []struct {
 		name          string
 		authorID      uuid.UUID
 		configureMock func(sqlmock.Sqlmock, uuid.UUID)
+		inTransaction bool
 		expectedError error
 		expectedExist bool
 	}{
@@ -230,6 +366,22 @@ func TestAuthorRepository_Exists(t *testing.T) {
 			expectedExist: false,
 			expectedError: nil,
 		},
+		{
+			name:          "success author row locked within a transaction",
+			authorID:      uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626"),
+			inTransaction: true,
+			configureMock: func(mock sqlmock.Sqlmock, id uuid.UUID) {
+				rows := sqlmock.NewRows([]string{"count"}).AddRow(1)
+
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `authors` WHERE id = \\? FOR SHARE").
+					WithArgs(id).
+					WillReturnRows(rows)
+				mock.ExpectCommit()
+			},
+			expectedExist: true,
+			expectedError: nil,
+		},
 	}
 
 	for _, test := range tests {
@@ -239,7 +391,16 @@ func TestAuthorRepository_Exists(t *testing.T) {
 
 			repo := author.NewAuthorRepository(db, zerolog.Nop())
 
-			exist, err := repo.Exists(context.Background(), test.authorID)
+			var exist bool
+			var err error
+			if test.inTransaction {
+				err = database.NewTxManager(db, zerolog.Nop()).WithinTransaction(context.Background(), func(ctx context.Context) error {
+					exist, err = repo.Exists(ctx, test.authorID)
+					return err
+				})
+			} else {
+				exist, err = repo.Exists(context.Background(), test.authorID)
+			}
 
 			if test.expectedError != nil {
 				assert.Error(t, err)
@@ -254,3 +415,148 @@ func TestAuthorRepository_Exists(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthorRepository_GetBooks(t *testing.T) {
+	authorID := uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626")
+
+	tests := []struct {
+		name          string
+		params        pagination.Params
+		configureMock func(sqlmock.Sqlmock)
+		expectedError error
+		expectedCount int
+		expectedTotal int64
+	}{
+		{
+			name:   "success get author books",
+			params: pagination.Params{Page: 2, PageSize: 1},
+			configureMock: func(mock sqlmock.Sqlmock) {
+				now := time.Now()
+
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `books` WHERE author_id = \\?").
+					WithArgs(authorID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "author_id", "created_at", "updated_at"}).
+					AddRow(uuid.New(), "Les Misérables", "Description1", authorID, now, now)
+
+				mock.ExpectQuery("SELECT \\* FROM `books` WHERE author_id = \\? ORDER BY created_at, id LIMIT \\? OFFSET \\?").
+					WithArgs(authorID, 1, 1).
+					WillReturnRows(rows)
+			},
+			expectedCount: 1,
+			expectedTotal: 3,
+		},
+		{
+			name:   "error database connection failed",
+			params: pagination.Params{Page: 1, PageSize: 20},
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `books` WHERE author_id = \\?").
+					WithArgs(authorID).
+					WillReturnError(gorm.ErrInvalidDB)
+			},
+			expectedError: gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := testutils.NewGormMySQL(t)
+			test.configureMock(mock)
+
+			repo := author.NewAuthorRepository(db, zerolog.Nop())
+
+			books, total, err := repo.GetBooks(context.Background(), authorID, test.params)
+
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Len(t, books, test.expectedCount)
+			assert.Equal(t, test.expectedTotal, total)
+
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthorRepository_GetStats(t *testing.T) {
+	authorID := uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626")
+	first := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	latest := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	db, mock := testutils.NewGormMySQL(t)
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) AS book_count, MIN\\(created_at\\) AS first_published_at, MAX\\(created_at\\) AS latest_published_at FROM `books` WHERE author_id = \\?").
+		WithArgs(authorID).
+		WillReturnRows(sqlmock.NewRows([]string{"book_count", "first_published_at", "latest_published_at"}).
+			AddRow(2, first, latest))
+
+	repo := author.NewAuthorRepository(db, zerolog.Nop())
+
+	stats, err := repo.GetStats(context.Background(), authorID)
+
+	require.NoError(t, err)
+	assert.Equal(t, &entity.AuthorStats{
+		BookCount:         2,
+		FirstPublishedAt:  &first,
+		LatestPublishedAt: &latest,
+	}, stats)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthorRepository_GetByIDs(t *testing.T) {
+	ids := []uuid.UUID{
+		uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626"),
+		uuid.MustParse("13867a7d-d1c4-4a06-aa60-42741a4fbbbd"),
+	}
+
+	tests := []struct {
+		name          string
+		configureMock func(sqlmock.Sqlmock)
+		expectedError error
+		expectedCount int
+	}{
+		{
+			name: "success get authors by ids",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				now := time.Now()
+
+				rows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
+					AddRow(ids[0], "Victor Hugo", now, now).
+					AddRow(ids[1], "Emile Zola", now, now)
+
+				mock.ExpectQuery("SELECT \\* FROM `authors` WHERE id IN \\(\\?,\\?\\)").
+					WithArgs(ids[0], ids[1]).
+					WillReturnRows(rows)
+			},
+			expectedCount: 2,
+		},
+		{
+			name: "error database connection failed",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `authors` WHERE id IN \\(\\?,\\?\\)").
+					WithArgs(ids[0], ids[1]).
+					WillReturnError(gorm.ErrInvalidDB)
+			},
+			expectedError: gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := testutils.NewGormMySQL(t)
+			test.configureMock(mock)
+
+			repo := author.NewAuthorRepository(db, zerolog.Nop())
+
+			authors, err := repo.GetByIDs(context.Background(), ids)
+
+			assert.ErrorIs(t, err, test.expectedError)
+			assert.Len(t, authors, test.expectedCount)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -8,12 +8,17 @@ import (
 
 	"go-boilerplate-rest-api-chi/internal/author/dto"
 	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/pagination"
 )
 
 //go:generate mockgen -destination=../mocks/mock_author_service.go -package=mocks go-boilerplate-rest-api-chi/internal/author AuthorService
 type AuthorService interface {
 	CreateAuthor(ctx context.Context, req *dto.CreateAuthorRequest) (*entity.Author, error)
-	GetAuthorByID(ctx context.Context, authorID uuid.UUID) (*entity.Author, error)
+	GetAuthorByID(ctx context.Context, authorID uuid.UUID, expand ...string) (*entity.Author, error)
+	GetAuthorsByIDs(ctx context.Context, authorIDs []uuid.UUID) ([]*entity.Author, error)
+	RenameAuthor(ctx context.Context, authorID uuid.UUID, req *dto.RenameAuthorRequest) (*entity.Author, error)
+	GetAuthorStats(ctx context.Context, authorID uuid.UUID) (*entity.AuthorStats, error)
+	GetAuthorBooks(ctx context.Context, authorID uuid.UUID, params pagination.Params) ([]entity.Book, int64, error)
 }
 
 type authorService struct {
@@ -36,11 +41,39 @@ func (s *authorService) CreateAuthor(ctx context.Context, req *dto.CreateAuthorR
 	return s.repository.Create(ctx, author)
 }
 
-func (s *authorService) GetAuthorByID(ctx context.Context, authorID uuid.UUID) (*entity.Author, error) {
-	author, err := s.repository.GetByID(ctx, authorID)
+func (s *authorService) GetAuthorByID(ctx context.Context, authorID uuid.UUID, expand ...string) (*entity.Author, error) {
+	author, err := s.repository.GetByID(ctx, authorID, expand...)
 	if err != nil {
 		return nil, err
 	}
 
 	return author, nil
 }
+
+func (s *authorService) GetAuthorsByIDs(ctx context.Context, authorIDs []uuid.UUID) ([]*entity.Author, error) {
+	if len(authorIDs) == 0 {
+		return nil, nil
+	}
+
+	return s.repository.GetByIDs(ctx, authorIDs)
+}
+
+func (s *authorService) RenameAuthor(ctx context.Context, authorID uuid.UUID, req *dto.RenameAuthorRequest) (*entity.Author, error) {
+	return s.repository.Rename(ctx, authorID, req.Name)
+}
+
+func (s *authorService) GetAuthorStats(ctx context.Context, authorID uuid.UUID) (*entity.AuthorStats, error) {
+	return s.repository.GetStats(ctx, authorID)
+}
+
+func (s *authorService) GetAuthorBooks(ctx context.Context, authorID uuid.UUID, params pagination.Params) ([]entity.Book, int64, error) {
+	exists, err := s.repository.Exists(ctx, authorID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !exists {
+		return nil, 0, ErrNotFound
+	}
+
+	return s.repository.GetBooks(ctx, authorID, params)
+}
@@ -14,6 +14,7 @@ import (
 	"go-boilerplate-rest-api-chi/internal/author/dto"
 	"go-boilerplate-rest-api-chi/internal/entity"
 	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/pagination"
 )
 
 func TestAuthorService_CreateAuthor(t *testing.T) {
@@ -186,3 +187,162 @@ func TestAuthorService_GetAuthorByID(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthorService_RenameAuthor(t *testing.T) {
+	authorID := uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626")
+
+	tests := []struct {
+		name             string
+		configureMock    func(*mocks.MockAuthorRepository)
+		expectedResponse *entity.Author
+		expectedError    error
+	}{
+		{
+			name: "success rename author",
+			configureMock: func(mockRepo *mocks.MockAuthorRepository) {
+				mockRepo.EXPECT().
+					Rename(gomock.Any(), authorID, "Joanne Rowling").
+					Return(&entity.Author{ID: authorID, Name: "Joanne Rowling"}, nil)
+			},
+			expectedResponse: &entity.Author{ID: authorID, Name: "Joanne Rowling"},
+		},
+		{
+			name: "error author not found",
+			configureMock: func(mockRepo *mocks.MockAuthorRepository) {
+				mockRepo.EXPECT().
+					Rename(gomock.Any(), authorID, "Joanne Rowling").
+					Return(nil, author.ErrNotFound)
+			},
+			expectedError: author.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+			authorRepoMock := mocks.NewMockAuthorRepository(ctrl)
+
+			test.configureMock(authorRepoMock)
+			service := author.NewAuthorService(authorRepoMock, zerolog.Nop())
+
+			result, err := service.RenameAuthor(context.Background(), authorID, &dto.RenameAuthorRequest{Name: "Joanne Rowling"})
+
+			assert.ErrorIs(t, err, test.expectedError)
+			assert.Equal(t, test.expectedResponse, result)
+		})
+	}
+}
+
+func TestAuthorService_GetAuthorBooks(t *testing.T) {
+	params := pagination.Params{Page: 1, PageSize: 20}
+
+	tests := []struct {
+		name          string
+		authorID      uuid.UUID
+		configureMock func(*mocks.MockAuthorRepository)
+		expectedBooks []entity.Book
+		expectedTotal int64
+		expectedError error
+	}{
+		{
+			name:     "success get author books",
+			authorID: uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626"),
+			configureMock: func(mockRepo *mocks.MockAuthorRepository) {
+				mockRepo.EXPECT().
+					Exists(gomock.Any(), uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626")).
+					Return(true, nil)
+				mockRepo.EXPECT().
+					GetBooks(gomock.Any(), uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626"), params).
+					Return([]entity.Book{{Title: "Harry Potter"}}, int64(1), nil)
+			},
+			expectedBooks: []entity.Book{{Title: "Harry Potter"}},
+			expectedTotal: 1,
+		},
+		{
+			name:     "error author not found",
+			authorID: uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626"),
+			configureMock: func(mockRepo *mocks.MockAuthorRepository) {
+				mockRepo.EXPECT().
+					Exists(gomock.Any(), uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626")).
+					Return(false, nil)
+			},
+			expectedError: author.ErrNotFound,
+		},
+		{
+			name:     "error database error",
+			authorID: uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626"),
+			configureMock: func(mockRepo *mocks.MockAuthorRepository) {
+				mockRepo.EXPECT().
+					Exists(gomock.Any(), uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626")).
+					Return(false, errors.New("database connection failed"))
+			},
+			expectedError: errors.New("database connection failed"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+			authorRepoMock := mocks.NewMockAuthorRepository(ctrl)
+
+			test.configureMock(authorRepoMock)
+			service := author.NewAuthorService(authorRepoMock, zerolog.Nop())
+
+			books, total, err := service.GetAuthorBooks(context.Background(), test.authorID, params)
+
+			if test.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, test.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedBooks, books)
+			assert.Equal(t, test.expectedTotal, total)
+		})
+	}
+}
+
+func TestAuthorService_GetAuthorsByIDs(t *testing.T) {
+	ids := []uuid.UUID{uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626")}
+
+	tests := []struct {
+		name             string
+		authorIDs        []uuid.UUID
+		configureMock    func(*mocks.MockAuthorRepository)
+		expectedResponse []*entity.Author
+	}{
+		{
+			name:      "success get authors by ids",
+			authorIDs: ids,
+			configureMock: func(mockRepo *mocks.MockAuthorRepository) {
+				mockRepo.EXPECT().
+					GetByIDs(gomock.Any(), ids).
+					Return([]*entity.Author{{ID: ids[0], Name: "Victor Hugo"}}, nil)
+			},
+			expectedResponse: []*entity.Author{{ID: ids[0], Name: "Victor Hugo"}},
+		},
+		{
+			name:          "no ids",
+			configureMock: func(*mocks.MockAuthorRepository) {},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+			authorRepoMock := mocks.NewMockAuthorRepository(ctrl)
+
+			test.configureMock(authorRepoMock)
+			service := author.NewAuthorService(authorRepoMock, zerolog.Nop())
+
+			authors, err := service.GetAuthorsByIDs(context.Background(), test.authorIDs)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedResponse, authors)
+		})
+	}
+}
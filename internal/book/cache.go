@@ -0,0 +1,100 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"go-boilerplate-rest-api-chi/internal/author"
+	"go-boilerplate-rest-api-chi/internal/cache"
+	"go-boilerplate-rest-api-chi/internal/database"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+)
+
+// cachedBookRepository serves GetByID from a cache. The books are cached without their author,
+// read from authors when expanded, so that renaming an author leaves no stale book behind.
+type cachedBookRepository struct {
+	BookRepository
+	authors author.AuthorRepository
+	cache   *cache.Cache
+}
+
+// NewCachedBookRepository decorates repository with c. The entries are invalidated by the
+// Create, Update and Delete of the decorator, whatever the replica.
+func NewCachedBookRepository(repository BookRepository, authors author.AuthorRepository, c *cache.Cache) BookRepository {
+	return &cachedBookRepository{
+		BookRepository: repository,
+		authors:        authors,
+		cache:          c,
+	}
+}
+
+func (r *cachedBookRepository) Create(ctx context.Context, book *entity.Book) (*entity.Book, error) {
+	created, err := r.BookRepository.Create(ctx, book)
+	if err == nil {
+		r.invalidate(ctx, created.ID)
+	}
+
+	return created, err
+}
+
+func (r *cachedBookRepository) GetByID(ctx context.Context, bookID uuid.UUID, expand ...string) (*entity.Book, error) {
+	key, ok := cacheKey(ctx, bookID)
+	if !ok {
+		return r.BookRepository.GetByID(ctx, bookID, expand...)
+	}
+
+	book, err := cache.Fetch(ctx, r.cache, key, func(ctx context.Context) (*entity.Book, error) {
+		return r.BookRepository.GetByID(ctx, bookID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(expand, "author") {
+		book.Author, err = r.authors.GetByID(ctx, book.AuthorID)
+		if err != nil && !errors.Is(err, author.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	return book, nil
+}
+
+func (r *cachedBookRepository) Update(ctx context.Context, bookID uuid.UUID, updates map[string]interface{}) error {
+	err := r.BookRepository.Update(ctx, bookID, updates)
+	r.invalidate(ctx, bookID)
+
+	return err
+}
+
+func (r *cachedBookRepository) Delete(ctx context.Context, bookID uuid.UUID) error {
+	err := r.BookRepository.Delete(ctx, bookID)
+	r.invalidate(ctx, bookID)
+
+	return err
+}
+
+func (r *cachedBookRepository) invalidate(ctx context.Context, bookID uuid.UUID) {
+	if tenantID, ok := tenant.FromContext(ctx); ok {
+		// The entry is dropped once committed, or a read of another request in between would
+		// cache the previous value again.
+		database.AfterCommit(ctx, func() {
+			r.cache.Invalidate(ctx, tenantID+":"+bookID.String())
+		})
+	}
+}
+
+// cacheKey names the entry of a book in its tenant. The reads of a transaction skip the cache,
+// which holds committed data only.
+func cacheKey(ctx context.Context, bookID uuid.UUID) (string, bool) {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok || database.InTransaction(ctx) {
+		return "", false
+	}
+
+	return tenantID + ":" + bookID.String(), true
+}
@@ -0,0 +1,197 @@
+package book_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"go-boilerplate-rest-api-chi/internal/author"
+	"go-boilerplate-rest-api-chi/internal/book"
+	"go-boilerplate-rest-api-chi/internal/cache"
+	"go-boilerplate-rest-api-chi/internal/database"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	"go-boilerplate-rest-api-chi/internal/test-utils"
+)
+
+func TestCachedBookRepository(t *testing.T) {
+	bookID := uuid.MustParse("f0b4a7f2-4a1c-4d6e-9b8a-2c3d4e5f6a7b")
+	authorID := uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626")
+	acme := tenant.WithID(context.Background(), "acme")
+	globex := tenant.WithID(context.Background(), "globex")
+	stored := &entity.Book{ID: bookID, TenantID: "acme", Title: "Les misérables", AuthorID: authorID}
+
+	tests := []struct {
+		name          string
+		configureMock func(*mocks.MockBookRepository, *mocks.MockAuthorRepository)
+		run           func(t *testing.T, repository book.BookRepository)
+	}{
+		{
+			name: "success lookups cached",
+			configureMock: func(mockRepo *mocks.MockBookRepository, _ *mocks.MockAuthorRepository) {
+				mockRepo.EXPECT().GetByID(gomock.Any(), bookID).Return(stored, nil).Times(1)
+			},
+			run: func(t *testing.T, repository book.BookRepository) {
+				for range 3 {
+					result, err := repository.GetByID(acme, bookID)
+					require.NoError(t, err)
+					assert.Equal(t, "Les misérables", result.Title)
+				}
+			},
+		},
+		{
+			name: "success author read apart",
+			configureMock: func(mockRepo *mocks.MockBookRepository, mockAuthors *mocks.MockAuthorRepository) {
+				mockRepo.EXPECT().GetByID(gomock.Any(), bookID).Return(stored, nil).Times(1)
+				gomock.InOrder(
+					mockAuthors.EXPECT().GetByID(gomock.Any(), authorID).Return(&entity.Author{ID: authorID, Name: "Victor Hugo"}, nil),
+					mockAuthors.EXPECT().GetByID(gomock.Any(), authorID).Return(&entity.Author{ID: authorID, Name: "V. Hugo"}, nil),
+				)
+			},
+			run: func(t *testing.T, repository book.BookRepository) {
+				result, err := repository.GetByID(acme, bookID, "author")
+				require.NoError(t, err)
+				assert.Equal(t, "Victor Hugo", result.Author.Name)
+
+				result, err = repository.GetByID(acme, bookID, "author")
+				require.NoError(t, err)
+				assert.Equal(t, "V. Hugo", result.Author.Name, "the rename of the author is seen")
+
+				result, err = repository.GetByID(acme, bookID)
+				require.NoError(t, err)
+				assert.Nil(t, result.Author)
+			},
+		},
+		{
+			name: "success entries apart per tenant",
+			configureMock: func(mockRepo *mocks.MockBookRepository, _ *mocks.MockAuthorRepository) {
+				mockRepo.EXPECT().GetByID(gomock.Any(), bookID).Return(stored, nil)
+				mockRepo.EXPECT().GetByID(gomock.Any(), bookID).Return(nil, book.ErrNotFound)
+			},
+			run: func(t *testing.T, repository book.BookRepository) {
+				_, err := repository.GetByID(acme, bookID)
+				require.NoError(t, err)
+
+				_, err = repository.GetByID(globex, bookID)
+				assert.Equal(t, book.ErrNotFound, err)
+			},
+		},
+		{
+			name: "success update invalidates",
+			configureMock: func(mockRepo *mocks.MockBookRepository, _ *mocks.MockAuthorRepository) {
+				gomock.InOrder(
+					mockRepo.EXPECT().GetByID(gomock.Any(), bookID).Return(stored, nil),
+					mockRepo.EXPECT().Update(gomock.Any(), bookID, map[string]interface{}{"description": "Jean Valjean"}).Return(nil),
+					mockRepo.EXPECT().GetByID(gomock.Any(), bookID).Return(&entity.Book{ID: bookID, Description: "Jean Valjean"}, nil),
+				)
+			},
+			run: func(t *testing.T, repository book.BookRepository) {
+				_, _ = repository.GetByID(acme, bookID)
+				require.NoError(t, repository.Update(acme, bookID, map[string]interface{}{"description": "Jean Valjean"}))
+
+				result, err := repository.GetByID(acme, bookID)
+				require.NoError(t, err)
+				assert.Equal(t, "Jean Valjean", result.Description)
+			},
+		},
+		{
+			name: "success delete invalidates",
+			configureMock: func(mockRepo *mocks.MockBookRepository, _ *mocks.MockAuthorRepository) {
+				gomock.InOrder(
+					mockRepo.EXPECT().GetByID(gomock.Any(), bookID).Return(stored, nil),
+					mockRepo.EXPECT().Delete(gomock.Any(), bookID).Return(nil),
+					mockRepo.EXPECT().GetByID(gomock.Any(), bookID).Return(nil, book.ErrNotFound),
+				)
+			},
+			run: func(t *testing.T, repository book.BookRepository) {
+				_, _ = repository.GetByID(acme, bookID)
+				require.NoError(t, repository.Delete(acme, bookID))
+
+				_, err := repository.GetByID(acme, bookID)
+				assert.Equal(t, book.ErrNotFound, err)
+			},
+		},
+		{
+			name: "success without tenant not cached",
+			configureMock: func(mockRepo *mocks.MockBookRepository, _ *mocks.MockAuthorRepository) {
+				mockRepo.EXPECT().GetByID(gomock.Any(), bookID, "author").Return(stored, nil).Times(2)
+			},
+			run: func(t *testing.T, repository book.BookRepository) {
+				for range 2 {
+					_, err := repository.GetByID(context.Background(), bookID, "author")
+					require.NoError(t, err)
+				}
+			},
+		},
+		{
+			name: "success missing author left out",
+			configureMock: func(mockRepo *mocks.MockBookRepository, mockAuthors *mocks.MockAuthorRepository) {
+				mockRepo.EXPECT().GetByID(gomock.Any(), bookID).Return(stored, nil)
+				mockAuthors.EXPECT().GetByID(gomock.Any(), authorID).Return(nil, author.ErrNotFound)
+			},
+			run: func(t *testing.T, repository book.BookRepository) {
+				result, err := repository.GetByID(acme, bookID, "author")
+				require.NoError(t, err)
+				assert.Nil(t, result.Author)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := mocks.NewMockBookRepository(ctrl)
+			mockAuthors := mocks.NewMockAuthorRepository(ctrl)
+			test.configureMock(mockRepo, mockAuthors)
+
+			c := cache.New("books", cache.NewMemoryStore(100), cache.NewLocalNotifier(), time.Minute, zerolog.Nop())
+			test.run(t, book.NewCachedBookRepository(mockRepo, mockAuthors, c))
+		})
+	}
+}
+
+func TestCachedBookRepository_Transaction(t *testing.T) {
+	bookID := uuid.MustParse("f0b4a7f2-4a1c-4d6e-9b8a-2c3d4e5f6a7b")
+	acme := tenant.WithID(context.Background(), "acme")
+	updates := map[string]interface{}{"description": "Jean Valjean"}
+
+	ctrl := gomock.NewController(t)
+	mockRepo := mocks.NewMockBookRepository(ctrl)
+	gomock.InOrder(
+		mockRepo.EXPECT().Update(gomock.Any(), bookID, updates).Return(nil),
+		mockRepo.EXPECT().GetByID(gomock.Any(), bookID).Return(&entity.Book{ID: bookID, Description: "Cosette"}, nil),
+		mockRepo.EXPECT().GetByID(gomock.Any(), bookID).Return(&entity.Book{ID: bookID, Description: "Jean Valjean"}, nil),
+	)
+
+	db, mock := testutils.NewGormMySQL(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	c := cache.New("books", cache.NewMemoryStore(100), cache.NewLocalNotifier(), time.Minute, zerolog.Nop())
+	repository := book.NewCachedBookRepository(mockRepo, mocks.NewMockAuthorRepository(ctrl), c)
+
+	err := database.NewTxManager(db, zerolog.Nop()).WithinTransaction(acme, func(ctx context.Context) error {
+		if err := repository.Update(ctx, bookID, updates); err != nil {
+			return err
+		}
+
+		// Another request reads the book before the commit and caches its previous value.
+		result, err := repository.GetByID(acme, bookID)
+		require.NoError(t, err)
+		assert.Equal(t, "Cosette", result.Description)
+		return nil
+	})
+	require.NoError(t, err)
+
+	result, err := repository.GetByID(acme, bookID)
+	require.NoError(t, err)
+	assert.Equal(t, "Jean Valjean", result.Description, "the entry is dropped on commit")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
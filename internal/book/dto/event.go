@@ -0,0 +1,20 @@
+package dto
+
+import "go-boilerplate-rest-api-chi/internal/entity"
+
+// BookEventData is the payload of the book.* events. Only the fields that changed are set.
+type BookEventData struct {
+	ID          string `json:"id"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	AuthorID    string `json:"author_id,omitempty"`
+}
+
+func ToBookEventData(book *entity.Book) BookEventData {
+	return BookEventData{
+		ID:          book.ID.String(),
+		Title:       book.Title,
+		Description: book.Description,
+		AuthorID:    book.AuthorID.String(),
+	}
+}
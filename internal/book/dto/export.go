@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/export"
+)
+
+type ExportJobResponse struct {
+	ID          string     `json:"id" xml:"id" example:"0d6f1b8e-8e0a-4a43-9a55-2f1c8b7b3e42"`
+	Format      string     `json:"format" xml:"format" example:"csv"`
+	Status      string     `json:"status" xml:"status" example:"completed"`
+	Error       string     `json:"error,omitempty" xml:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" xml:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" xml:"completed_at,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty" xml:"download_url,omitempty" example:"/api/books/exports/0d6f1b8e-8e0a-4a43-9a55-2f1c8b7b3e42/download"`
+}
+
+var BookExportHeader = []string{"id", "title", "description", "author_id", "author_name", "created_at", "updated_at"}
+
+func ToBookExportRow(book *entity.Book) []string {
+	authorName := ""
+	if book.Author != nil {
+		authorName = book.Author.Name
+	}
+
+	return []string{
+		book.ID.String(),
+		book.Title,
+		book.Description,
+		book.AuthorID.String(),
+		authorName,
+		book.CreatedAt.UTC().Format(time.RFC3339),
+		book.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func ToExportJobResponse(job export.Job) *ExportJobResponse {
+	return &ExportJobResponse{
+		ID:          job.ID.String(),
+		Format:      string(job.Format),
+		Status:      string(job.Status),
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+	}
+}
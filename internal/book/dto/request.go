@@ -1,11 +1,16 @@
 package dto
 
 type CreateBookRequest struct {
-	Title       string `json:"title" validate:"required"`
-	Description string `json:"description" validate:"required"`
-	AuthorID    string `json:"author_id" validate:"required"`
+	Title       string `json:"title" validate:"required,notblank,no_html"`
+	Description string `json:"description" validate:"required,notblank,no_html"`
+	AuthorID    string `json:"author_id" validate:"required,uuid" format:"uuid"`
 }
 
 type UpdateBookRequest struct {
-	Description string `json:"description" validate:"required"`
+	Description string `json:"description" validate:"required,notblank,no_html"`
+}
+
+type BookFilter struct {
+	Title    string `json:"title"`
+	AuthorID string `json:"author_id" validate:"omitempty,uuid"`
 }
@@ -6,22 +6,35 @@ import (
 )
 
 type BookResponse struct {
-	ID          string             `json:"id"`
-	Title       string             `json:"title"`
-	Description string             `json:"description"`
-	Author      dto.AuthorResponse `json:"author,omitempty"`
+	ID          string              `json:"id" xml:"id"`
+	Title       string              `json:"title" xml:"title"`
+	Description string              `json:"description" xml:"description"`
+	Author      *dto.AuthorResponse `json:"author,omitempty" xml:"author,omitempty"`
+}
+
+var BookCSVHeader = []string{"id", "title", "description", "author_id", "author_name"}
+
+func (b BookResponse) CSVRecord() []string {
+	record := []string{b.ID, b.Title, b.Description, "", ""}
+	if b.Author != nil {
+		record[3] = b.Author.ID
+		record[4] = b.Author.Name
+	}
+	return record
 }
 
 func ToBookResponse(book *entity.Book) *BookResponse {
-	return &BookResponse{
+	bookResponse := &BookResponse{
 		ID:          book.ID.String(),
 		Title:       book.Title,
 		Description: book.Description,
-		Author: dto.AuthorResponse{
-			ID:   book.Author.ID.String(),
-			Name: book.Author.Name,
-		},
 	}
+
+	if book.Author != nil {
+		bookResponse.Author = dto.ToAuthorResponse(book.Author)
+	}
+
+	return bookResponse
 }
 
 func ToBooksResponse(books []*entity.Book) []BookResponse {
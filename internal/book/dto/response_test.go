@@ -33,7 +33,7 @@ func TestToAuthorResponse(t *testing.T) {
 			ID:          "58411bf8-aa11-4553-9b13-4bdf58875d35",
 			Title:       "Book1",
 			Description: "Description1",
-			Author: authorDTO.AuthorResponse{
+			Author: &authorDTO.AuthorResponse{
 				ID:   "b846fc59-401a-450d-b3f1-3e9a953d7c22",
 				Name: "Author1",
 			},
@@ -97,7 +97,7 @@ func TestToBooksResponse(t *testing.T) {
 				ID:          "58411bf8-aa11-4553-9b13-4bdf58875d35",
 				Title:       "Book1",
 				Description: "Description1",
-				Author: authorDTO.AuthorResponse{
+				Author: &authorDTO.AuthorResponse{
 					ID:   "b846fc59-401a-450d-b3f1-3e9a953d7c22",
 					Name: "Author1",
 				},
@@ -106,7 +106,7 @@ func TestToBooksResponse(t *testing.T) {
 				ID:          "26dcbb76-e09d-45c5-8c97-f32ce3a2766a",
 				Title:       "Book2",
 				Description: "Description2",
-				Author: authorDTO.AuthorResponse{
+				Author: &authorDTO.AuthorResponse{
 					ID:   "b846fc59-401a-450d-b3f1-3e9a953d7c22",
 					Name: "Author1",
 				},
@@ -115,7 +115,7 @@ func TestToBooksResponse(t *testing.T) {
 				ID:          "2933e943-bde9-4743-8961-97828d166e11",
 				Title:       "Book3",
 				Description: "Description3",
-				Author: authorDTO.AuthorResponse{
+				Author: &authorDTO.AuthorResponse{
 					ID:   "b846fc59-401a-450d-b3f1-3e9a953d7c22",
 					Name: "Author1",
 				},
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"time"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+)
+
+// BookResponseV2 always nests the author and carries the timestamps of the book.
+type BookResponseV2 struct {
+	ID          string             `json:"id" xml:"id"`
+	Title       string             `json:"title" xml:"title"`
+	Description string             `json:"description" xml:"description"`
+	Author      BookAuthorResponse `json:"author" xml:"author"`
+	CreatedAt   time.Time          `json:"created_at" xml:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" xml:"updated_at"`
+}
+
+// BookAuthorResponse has no name when the author is not loaded.
+type BookAuthorResponse struct {
+	ID   string `json:"id" xml:"id"`
+	Name string `json:"name,omitempty" xml:"name,omitempty"`
+}
+
+var BookV2CSVHeader = []string{"id", "title", "description", "author_id", "author_name", "created_at", "updated_at"}
+
+func (b BookResponseV2) CSVRecord() []string {
+	return []string{
+		b.ID,
+		b.Title,
+		b.Description,
+		b.Author.ID,
+		b.Author.Name,
+		b.CreatedAt.Format(time.RFC3339),
+		b.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func ToBookResponseV2(book *entity.Book) *BookResponseV2 {
+	bookResponse := &BookResponseV2{
+		ID:          book.ID.String(),
+		Title:       book.Title,
+		Description: book.Description,
+		Author:      BookAuthorResponse{ID: book.AuthorID.String()},
+		CreatedAt:   book.CreatedAt,
+		UpdatedAt:   book.UpdatedAt,
+	}
+
+	if book.Author != nil {
+		bookResponse.Author.Name = book.Author.Name
+	}
+
+	return bookResponse
+}
+
+func ToBooksResponseV2(books []*entity.Book) []BookResponseV2 {
+	responses := make([]BookResponseV2, len(books))
+	for i, book := range books {
+		responses[i] = *ToBookResponseV2(book)
+	}
+	return responses
+}
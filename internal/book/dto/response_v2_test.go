@@ -0,0 +1,49 @@
+package dto_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"go-boilerplate-rest-api-chi/internal/book/dto"
+	"go-boilerplate-rest-api-chi/internal/entity"
+)
+
+func TestToBookResponseV2(t *testing.T) {
+	createdAt := time.Date(2026, 01, 12, 21, 45, 00, 00, time.UTC)
+	book := entity.Book{
+		ID:          uuid.MustParse("58411bf8-aa11-4553-9b13-4bdf58875d35"),
+		Title:       "Book1",
+		Description: "Description1",
+		AuthorID:    uuid.MustParse("b846fc59-401a-450d-b3f1-3e9a953d7c22"),
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+
+	t.Run("author not loaded", func(t *testing.T) {
+		assert.Equal(t, &dto.BookResponseV2{
+			ID:          "58411bf8-aa11-4553-9b13-4bdf58875d35",
+			Title:       "Book1",
+			Description: "Description1",
+			Author:      dto.BookAuthorResponse{ID: "b846fc59-401a-450d-b3f1-3e9a953d7c22"},
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+		}, dto.ToBookResponseV2(&book))
+	})
+
+	t.Run("author loaded", func(t *testing.T) {
+		withAuthor := book
+		withAuthor.Author = &entity.Author{ID: book.AuthorID, Name: "Author1"}
+
+		response := dto.ToBookResponseV2(&withAuthor)
+
+		assert.Equal(t, dto.BookAuthorResponse{ID: "b846fc59-401a-450d-b3f1-3e9a953d7c22", Name: "Author1"}, response.Author)
+		assert.Equal(t, []string{
+			"58411bf8-aa11-4553-9b13-4bdf58875d35", "Book1", "Description1",
+			"b846fc59-401a-450d-b3f1-3e9a953d7c22", "Author1",
+			"2026-01-12T21:45:00Z", "2026-01-12T21:45:00Z",
+		}, response.CSVRecord())
+	})
+}
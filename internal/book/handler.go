@@ -1,41 +1,90 @@
 package book
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 
+	"go-boilerplate-rest-api-chi/internal/auth"
 	"go-boilerplate-rest-api-chi/internal/author"
 	"go-boilerplate-rest-api-chi/internal/book/dto"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/export"
+	"go-boilerplate-rest-api-chi/internal/fieldset"
+	"go-boilerplate-rest-api-chi/internal/httpcache"
+	"go-boilerplate-rest-api-chi/internal/request"
 	"go-boilerplate-rest-api-chi/internal/response"
+	"go-boilerplate-rest-api-chi/internal/tenant"
 	internalValidator "go-boilerplate-rest-api-chi/internal/validator"
 )
 
+// bookFieldset keeps the author expanded by default so clients that send neither
+// ?fields= nor ?expand= get the same payload as before.
+var bookFieldset = fieldset.Spec{
+	Fields:        fieldset.Paths(dto.BookResponse{}),
+	Expand:        []string{"author"},
+	DefaultExpand: []string{"author"},
+}
+
 type BookSuccessResponse struct {
-	Status  string            `json:"status" example:"success"`
-	Message string            `json:"message" example:"Book retrieved successfully"`
-	Book    *dto.BookResponse `json:"book"`
+	Status  string            `json:"status" xml:"status" example:"success"`
+	Message string            `json:"message" xml:"message" example:"Book retrieved successfully"`
+	Book    *dto.BookResponse `json:"book" xml:"book"`
+}
+
+func (b BookSuccessResponse) CSVHeader() []string {
+	return dto.BookCSVHeader
+}
+
+func (b BookSuccessResponse) CSVRecords() [][]string {
+	if b.Book == nil {
+		return nil
+	}
+	return [][]string{b.Book.CSVRecord()}
 }
 
 type BooksSuccessResponse struct {
-	Status  string             `json:"status" example:"success"`
-	Message string             `json:"message" example:"Books retrieved successfully"`
-	Books   []dto.BookResponse `json:"books"`
+	Status  string             `json:"status" xml:"status" example:"success"`
+	Message string             `json:"message" xml:"message" example:"Books retrieved successfully"`
+	Books   []dto.BookResponse `json:"books" xml:"books>book"`
+}
+
+func (b BooksSuccessResponse) CSVHeader() []string {
+	return dto.BookCSVHeader
+}
+
+func (b BooksSuccessResponse) CSVRecords() [][]string {
+	records := make([][]string, len(b.Books))
+	for i, book := range b.Books {
+		records[i] = book.CSVRecord()
+	}
+	return records
+}
+
+type ExportJobSuccessResponse struct {
+	Status  string                 `json:"status" xml:"status" example:"success"`
+	Message string                 `json:"message" xml:"message" example:"Export job retrieved successfully"`
+	Job     *dto.ExportJobResponse `json:"job" xml:"job"`
 }
 
 type BookHandler struct {
 	service   BookService
+	exports   *export.Manager
 	validator *internalValidator.Validator
 	logger    zerolog.Logger
 }
 
-func NewBookHandler(service BookService, validator *internalValidator.Validator, logger zerolog.Logger) *BookHandler {
+func NewBookHandler(service BookService, exports *export.Manager, validator *internalValidator.Validator, logger zerolog.Logger) *BookHandler {
 	return &BookHandler{
 		service:   service,
+		exports:   exports,
 		validator: validator,
 		logger:    logger,
 	}
@@ -45,12 +94,16 @@ func (h *BookHandler) Routes() http.Handler {
 	r := chi.NewRouter()
 
 	// routes
-	r.Post("/", h.CreateBook)
-	r.Get("/", h.GetAllBooks)
-	r.Get("/{book_id}", h.GetBookByID)
+	r.With(response.Acceptable(BookSuccessResponse{})).Post("/", h.CreateBook)
+	r.With(httpcache.Collection).Get("/", h.GetAllBooks)
+	r.Get("/export", h.ExportBooks)
+	r.Post("/exports", h.CreateExportJob)
+	r.Get("/exports/{job_id}", h.GetExportJob)
+	r.Get("/exports/{job_id}/download", h.DownloadExport)
+	r.With(httpcache.Cacheable).Get("/{book_id}", h.GetBookByID)
 	r.Patch("/{book_id}", h.UpdateBook)
 	r.Delete("/{book_id}", h.DeleteBook)
-	r.Get("/secure", h.AuthTestRoute)
+	r.With(auth.Required).Get("/secure", h.AuthTestRoute)
 
 	return r
 }
@@ -62,22 +115,27 @@ func (h *BookHandler) Routes() http.Handler {
 //	@Tags			books
 //	@Accept			json
 //	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Produce		text/csv
 //	@Param			book	body		dto.CreateBookRequest	true	"Book data"
 //	@Success		201		{object}	BookSuccessResponse
 //	@Failure		400		{object}	response.ValidationErrorResponse
 //	@Failure		409		{object}	response.ErrorResponse
+//	@Failure		413		{object}	response.ValidationErrorResponse
+//	@Failure		415		{object}	response.ValidationErrorResponse
 //	@Failure		500		{object}	response.ErrorResponse
 //	@Router			/books [post]
 func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreateBookRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body")
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		request.WriteError(w, err)
 		return
 	}
 
 	if err := h.validator.Struct(&req); err != nil {
-		validationErrors := h.validator.FormatErrors(err)
+		validationErrors := h.validator.FormatErrors(r.Context(), err)
 		response.ValidationError(w, validationErrors)
 		return
 	}
@@ -88,7 +146,7 @@ func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.JSON(w, http.StatusCreated, BookSuccessResponse{
+	response.Render(w, r, http.StatusCreated, BookSuccessResponse{
 		Status:  "success",
 		Message: "Book created successfully",
 		Book:    dto.ToBookResponse(book),
@@ -101,35 +159,233 @@ func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 //	@Description	Get a list of all books
 //	@Tags			books
 //	@Produce		json
-//	@Success		200	{object}	BooksSuccessResponse
-//	@Failure		404	{object}	response.ErrorResponse
-//	@Failure		500	{object}	response.ErrorResponse
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Produce		text/csv
+//	@Param			title		query		string	false	"Filter on a part of the title"
+//	@Param			author_id	query		string	false	"Filter on the author ID"	format(uuid)
+//	@Param			fields		query		string	false	"Comma separated fields to return, e.g. id,title,author.name"
+//	@Param			expand		query		string	false	"Comma separated relationships to load"	Enums(author)
+//	@Success		200			{object}	BooksSuccessResponse
+//	@Failure		400			{object}	response.ValidationErrorResponse
+//	@Failure		404			{object}	response.ErrorResponse
+//	@Failure		500			{object}	response.ErrorResponse
 //	@Router			/books [get]
 func (h *BookHandler) GetAllBooks(w http.ResponseWriter, r *http.Request) {
-	books, err := h.service.GetAllBooks(r.Context())
+	filter, ok := h.parseFilter(w, r)
+	if !ok {
+		return
+	}
+
+	selection, errs := fieldset.Parse(r, bookFieldset)
+	if errs != nil {
+		response.ValidationError(w, errs)
+		return
+	}
+
+	books, err := h.service.GetAllBooks(r.Context(), filter, selection.Relations(bookFieldset)...)
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
-	response.JSON(w, http.StatusOK, BooksSuccessResponse{
+	bookResponses := dto.ToBooksResponse(books)
+	for i := range bookResponses {
+		selection.Apply(&bookResponses[i])
+	}
+
+	response.Render(w, r, http.StatusOK, selection.View(BooksSuccessResponse{
 		Status:  "success",
 		Message: "Books retrieved successfully",
-		Books:   dto.ToBooksResponse(books),
+		Books:   bookResponses,
+	}, "books"))
+}
+
+// ExportBooks godoc
+//
+//	@Summary		Export books
+//	@Description	Stream every book matching the filters as a downloadable file
+//	@Tags			exports
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			format		query		string	false	"Export format"	Enums(csv, ndjson, xlsx)	default(csv)
+//	@Param			title		query		string	false	"Filter on a part of the title"
+//	@Param			author_id	query		string	false	"Filter on the author ID"	format(uuid)
+//	@Success		200			{file}		file
+//	@Failure		400			{object}	response.ErrorResponse
+//	@Router			/books/export [get]
+func (h *BookHandler) ExportBooks(w http.ResponseWriter, r *http.Request) {
+	format, err := export.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	filter, ok := h.parseFilter(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", exportDisposition(format))
+	w.WriteHeader(http.StatusOK)
+
+	controller := http.NewResponseController(w)
+	flush := func() error {
+		if err := controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		return nil
+	}
+
+	// The status line is already sent, so a failure can only truncate the stream.
+	if err := h.writeExport(r.Context(), w, format, filter, flush); err != nil {
+		h.logger.Error().Err(err).Msg("books export interrupted")
+	}
+}
+
+// CreateExportJob godoc
+//
+//	@Summary		Create an export job
+//	@Description	Start an asynchronous export of the books matching the filters
+//	@Tags			exports
+//	@Produce		json
+//	@Param			format		query		string	false	"Export format"	Enums(csv, ndjson, xlsx)	default(csv)
+//	@Param			title		query		string	false	"Filter on a part of the title"
+//	@Param			author_id	query		string	false	"Filter on the author ID"	format(uuid)
+//	@Success		202			{object}	ExportJobSuccessResponse
+//	@Failure		400			{object}	response.ValidationErrorResponse
+//	@Failure		429			{object}	response.ErrorResponse
+//	@Router			/books/exports [post]
+func (h *BookHandler) CreateExportJob(w http.ResponseWriter, r *http.Request) {
+	format, err := export.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	filter, ok := h.parseFilter(w, r)
+	if !ok {
+		return
+	}
+
+	// The job outlives the request, only its tenant is carried over.
+	tenantID, _ := tenant.FromContext(r.Context())
+	job, err := h.exports.Submit(tenantID, format, func(ctx context.Context, w io.Writer) error {
+		return h.writeExport(tenant.WithID(ctx, tenantID), w, format, filter, func() error { return nil })
+	})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	jobPath := r.URL.Path + "/" + job.ID.String()
+
+	w.Header().Set("Location", jobPath)
+	response.JSON(w, http.StatusAccepted, ExportJobSuccessResponse{
+		Status:  "success",
+		Message: "Export job created successfully",
+		Job:     toExportJobResponse(jobPath, job),
+	})
+}
+
+// GetExportJob godoc
+//
+//	@Summary		Get an export job
+//	@Description	Get the status of an asynchronous export
+//	@Tags			exports
+//	@Produce		json
+//	@Param			job_id	path		string	true	"Export job ID"	format(uuid)
+//	@Success		200		{object}	ExportJobSuccessResponse
+//	@Failure		400		{object}	response.ErrorResponse
+//	@Failure		404		{object}	response.ErrorResponse
+//	@Router			/books/exports/{job_id} [get]
+func (h *BookHandler) GetExportJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.exportJob(w, r)
+	if !ok {
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ExportJobSuccessResponse{
+		Status:  "success",
+		Message: "Export job retrieved successfully",
+		Job:     toExportJobResponse(r.URL.Path, job),
 	})
 }
 
+// DownloadExport godoc
+//
+//	@Summary		Download an export
+//	@Description	Download the file produced by a completed export job
+//	@Tags			exports
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			job_id	path		string	true	"Export job ID"	format(uuid)
+//	@Success		200		{file}		file
+//	@Failure		400		{object}	response.ErrorResponse
+//	@Failure		404		{object}	response.ErrorResponse
+//	@Failure		409		{object}	response.ErrorResponse
+//	@Router			/books/exports/{job_id}/download [get]
+func (h *BookHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.exportJob(w, r)
+	if !ok {
+		return
+	}
+
+	file, job, err := h.exports.Open(job.ID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	w.Header().Set("Content-Type", job.Format.ContentType())
+	w.Header().Set("Content-Disposition", exportDisposition(job.Format))
+	http.ServeContent(w, r, "", *job.CompletedAt, file)
+}
+
+// exportJob reads the export job of the job_id URL parameter. The jobs of the other tenants
+// are answered as not found.
+func (h *BookHandler) exportJob(w http.ResponseWriter, r *http.Request) (export.Job, bool) {
+	jobID, err := uuid.Parse(chi.URLParam(r, "job_id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid uuid")
+		return export.Job{}, false
+	}
+
+	job, err := h.exports.Get(jobID)
+	if tenantID, _ := tenant.FromContext(r.Context()); err == nil && job.Tenant != tenantID {
+		err = export.ErrJobNotFound
+	}
+	if err != nil {
+		h.handleError(w, err)
+		return export.Job{}, false
+	}
+
+	return job, true
+}
+
 // GetBookByID godoc
 //
 //	@Summary		Get book by id
 //	@Description	Get a single book by its ID
 //	@Tags			books
 //	@Produce		json
-//	@Param			book_id	path		string	true	"Book ID"
-//	@Success		200		{object}	BookSuccessResponse
-//	@Failure		400		{object}	response.ErrorResponse
-//	@Failure		404		{object}	response.ErrorResponse
-//	@Failure		500		{object}	response.ErrorResponse
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Produce		text/csv
+//	@Param			book_id				path		string	true	"Book ID"	format(uuid)
+//	@Param			fields				query		string	false	"Comma separated fields to return, e.g. id,title,author.name"
+//	@Param			expand				query		string	false	"Comma separated relationships to load"	Enums(author)
+//	@Param			If-Modified-Since	header		string	false	"Date of the copy held, answered 304 when the book and its author have not changed since"
+//	@Success		200					{object}	BookSuccessResponse
+//	@Header			200					{string}	Last-Modified	"Date of the last change of the book or of its author"
+//	@Failure		400					{object}	response.ValidationErrorResponse
+//	@Failure		404					{object}	response.ErrorResponse
+//	@Failure		500					{object}	response.ErrorResponse
+//	@Success		304
 //	@Router			/books/{book_id} [get]
 func (h *BookHandler) GetBookByID(w http.ResponseWriter, r *http.Request) {
 	bookID, err := uuid.Parse(chi.URLParam(r, "book_id"))
@@ -138,17 +394,30 @@ func (h *BookHandler) GetBookByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	book, err := h.service.GetBookByID(r.Context(), bookID)
+	selection, errs := fieldset.Parse(r, bookFieldset)
+	if errs != nil {
+		response.ValidationError(w, errs)
+		return
+	}
+
+	book, err := h.service.GetBookByID(r.Context(), bookID, selection.Relations(bookFieldset)...)
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
-	response.JSON(w, http.StatusOK, BookSuccessResponse{
+	if httpcache.NotModified(w, r, lastModified(book)) {
+		return
+	}
+
+	bookResponse := dto.ToBookResponse(book)
+	selection.Apply(bookResponse)
+
+	response.Render(w, r, http.StatusOK, selection.View(BookSuccessResponse{
 		Status:  "success",
 		Message: "Book retrieved successfully",
-		Book:    dto.ToBookResponse(book),
-	})
+		Book:    bookResponse,
+	}, "book"))
 }
 
 // UpdateBook godoc
@@ -158,11 +427,13 @@ func (h *BookHandler) GetBookByID(w http.ResponseWriter, r *http.Request) {
 //	@Tags			books
 //	@Accept			json
 //	@Produce		json
-//	@Param			book_id	path		string					true	"Book ID"
+//	@Param			book_id	path		string					true	"Book ID"	format(uuid)
 //	@Param			book	body		dto.UpdateBookRequest	true	"Book data"
 //	@Success		200		{object}	response.SuccessResponse
 //	@Failure		400		{object}	response.ValidationErrorResponse
 //	@Failure		404		{object}	response.ErrorResponse
+//	@Failure		413		{object}	response.ValidationErrorResponse
+//	@Failure		415		{object}	response.ValidationErrorResponse
 //	@Failure		500		{object}	response.ErrorResponse
 //	@Router			/books/{book_id} [patch]
 func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
@@ -173,13 +444,13 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req dto.UpdateBookRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body")
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		request.WriteError(w, err)
 		return
 	}
 
 	if err := h.validator.Struct(&req); err != nil {
-		validationErrors := h.validator.FormatErrors(err)
+		validationErrors := h.validator.FormatErrors(r.Context(), err)
 		response.ValidationError(w, validationErrors)
 		return
 	}
@@ -199,7 +470,7 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 //	@Description	Delete a book by its ID
 //	@Tags			books
 //	@Produce		json
-//	@Param			book_id	path		string	true	"Book ID"
+//	@Param			book_id	path		string	true	"Book ID"	format(uuid)
 //	@Success		200		{object}	response.SuccessResponse
 //	@Failure		404		{object}	response.ErrorResponse
 //	@Failure		500		{object}	response.ErrorResponse
@@ -224,15 +495,82 @@ func (h *BookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
 //
 //	@Summary		Authenticated test route
 //	@Description	Authenticated test route
-//	@Tags			books
+//	@Tags			auth
 //	@Produce		json
 //	@Security		ApiKeyAuth
 //	@Success		200	{object}	response.SuccessResponse
+//	@Failure		401	{object}	response.ErrorResponse
 //	@Router			/books/secure [get]
 func (h *BookHandler) AuthTestRoute(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, "ok")
 }
 
+func (h *BookHandler) parseFilter(w http.ResponseWriter, r *http.Request) (*dto.BookFilter, bool) {
+	query := r.URL.Query()
+
+	filter := &dto.BookFilter{
+		Title:    query.Get("title"),
+		AuthorID: query.Get("author_id"),
+	}
+
+	if err := h.validator.Struct(filter); err != nil {
+		validationErrors := h.validator.FormatErrors(r.Context(), err)
+		response.ValidationError(w, validationErrors)
+		return nil, false
+	}
+
+	return filter, true
+}
+
+func (h *BookHandler) writeExport(ctx context.Context, w io.Writer, format export.Format, filter *dto.BookFilter, flush func() error) error {
+	writer, err := export.NewWriter(format, w, dto.BookExportHeader)
+	if err != nil {
+		return err
+	}
+
+	err = h.service.StreamBooks(ctx, filter, func(books []*entity.Book) error {
+		for _, book := range books {
+			if err := writer.WriteRow(dto.ToBookExportRow(book)); err != nil {
+				return err
+			}
+		}
+
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+func toExportJobResponse(jobPath string, job export.Job) *dto.ExportJobResponse {
+	jobResponse := dto.ToExportJobResponse(job)
+
+	if job.Status == export.JobCompleted {
+		jobResponse.DownloadURL = jobPath + "/download"
+	}
+
+	return jobResponse
+}
+
+func exportDisposition(format export.Format) string {
+	filename := "books-" + time.Now().UTC().Format("20060102T150405Z") + format.Extension()
+	return fmt.Sprintf(`attachment; filename="%s"`, filename)
+}
+
+// lastModified dates the response of a book, its author included when it is loaded.
+func lastModified(book *entity.Book) time.Time {
+	if book.Author != nil && book.Author.UpdatedAt.After(book.UpdatedAt) {
+		return book.Author.UpdatedAt
+	}
+	return book.UpdatedAt
+}
+
 func (h *BookHandler) handleError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, ErrNotFound):
@@ -243,6 +581,14 @@ func (h *BookHandler) handleError(w http.ResponseWriter, err error) {
 		response.Error(w, http.StatusBadRequest, "invalid author ID")
 	case errors.Is(err, author.ErrNotFound):
 		response.Error(w, http.StatusNotFound, "Author not found")
+	case errors.Is(err, export.ErrUnsupportedFormat):
+		response.Error(w, http.StatusBadRequest, "Unsupported export format")
+	case errors.Is(err, export.ErrJobNotFound):
+		response.Error(w, http.StatusNotFound, "Export job not found")
+	case errors.Is(err, export.ErrJobNotReady):
+		response.Error(w, http.StatusConflict, "Export job not ready")
+	case errors.Is(err, export.ErrTooManyJobs), errors.Is(err, export.ErrQueueFull):
+		response.Error(w, http.StatusTooManyRequests, "Too many export jobs")
 	default:
 		h.logger.Error().Err(err).Msg("unexpected error")
 		response.Error(w, http.StatusInternalServerError, "Internal server error")
@@ -2,12 +2,14 @@ package book_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -20,9 +22,12 @@ import (
 	authorDTO "go-boilerplate-rest-api-chi/internal/author/dto"
 	"go-boilerplate-rest-api-chi/internal/book"
 	"go-boilerplate-rest-api-chi/internal/book/dto"
+	"go-boilerplate-rest-api-chi/internal/config"
 	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/export"
 	"go-boilerplate-rest-api-chi/internal/mocks"
 	"go-boilerplate-rest-api-chi/internal/response"
+	"go-boilerplate-rest-api-chi/internal/tenant"
 	"go-boilerplate-rest-api-chi/internal/validator"
 )
 
@@ -68,7 +73,7 @@ func TestBookHandler_CreateBook(t *testing.T) {
 					ID:          "13867a7d-d1c4-4a06-aa60-42741a4fbbbd",
 					Title:       "Book1",
 					Description: "Description1",
-					Author: authorDTO.AuthorResponse{
+					Author: &authorDTO.AuthorResponse{
 						ID:   "24319e61-32d0-49f3-987f-019b734ed9c7",
 						Name: "Author1",
 					},
@@ -76,13 +81,18 @@ func TestBookHandler_CreateBook(t *testing.T) {
 			},
 		},
 		{
-			name:               "error invalid json",
+			name:               "error empty body",
 			requestBody:        nil,
 			configureMock:      func(service *mocks.MockBookService) {},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: &response.ErrorResponse{
+			expectedResponse: response.ValidationErrorResponse{
 				Status:  "error",
 				Message: "Invalid request body",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "body",
+					Message: "body must not be empty",
+					Tag:     "required",
+				}},
 			},
 		},
 		{
@@ -98,12 +108,14 @@ func TestBookHandler_CreateBook(t *testing.T) {
 				Status:  "error",
 				Message: "Validation failed",
 				Errors: []response.ValidationErrorDetail{{
-					Field:   "Title",
-					Message: "Title is required",
+					Field:   "title",
+					Message: "title is required",
+					Tag:     "required",
 				},
 					{
-						Field:   "Description",
-						Message: "Description is required",
+						Field:   "description",
+						Message: "description is required",
+						Tag:     "required",
 					}},
 			},
 		},
@@ -137,20 +149,16 @@ func TestBookHandler_CreateBook(t *testing.T) {
 				Description: "Description1",
 				AuthorID:    "invalid-uuid",
 			},
-			configureMock: func(mockService *mocks.MockBookService) {
-				input := &dto.CreateBookRequest{
-					Title:       "Book1",
-					Description: "Description1",
-					AuthorID:    "invalid-uuid",
-				}
-				mockService.EXPECT().
-					CreateBook(gomock.Any(), input).
-					Return(nil, book.ErrInvalidAuthorId)
-			},
+			configureMock:      func(mockService *mocks.MockBookService) {},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: &response.ErrorResponse{
+			expectedResponse: response.ValidationErrorResponse{
 				Status:  "error",
-				Message: "invalid author ID",
+				Message: "Validation failed",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "author_id",
+					Message: "author_id must be a valid UUID",
+					Tag:     "uuid",
+				}},
 			},
 		},
 		{
@@ -210,7 +218,7 @@ func TestBookHandler_CreateBook(t *testing.T) {
 			test.configureMock(mockService)
 
 			v := validator.New()
-			handler := book.NewBookHandler(mockService, v, zerolog.Nop())
+			handler := book.NewBookHandler(mockService, nil, v, zerolog.Nop())
 
 			var body *bytes.Buffer
 			if test.requestBody == nil {
@@ -241,6 +249,30 @@ func TestBookHandler_CreateBook(t *testing.T) {
 	}
 }
 
+func TestBookHandler_CreateBook_NotAcceptable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	// No CreateBook expected: the book is not created for a response the client refuses.
+	mockService := mocks.NewMockBookService(ctrl)
+	handler := book.NewBookHandler(mockService, nil, validator.New(), zerolog.Nop())
+
+	b, err := json.Marshal(dto.CreateBookRequest{Title: "Book1", AuthorID: "24319e61-32d0-49f3-987f-019b734ed9c7"})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/books", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "image/png")
+	w := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Mount("/books", handler.Routes())
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	assert.JSONEq(t, `{"status":"error","message":"Not acceptable"}`, w.Body.String())
+}
+
 func TestBookHandler_GetAllBooks(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -256,7 +288,7 @@ func TestBookHandler_GetAllBooks(t *testing.T) {
 					Name: "Author1",
 				}
 				mockService.EXPECT().
-					GetAllBooks(gomock.Any()).
+					GetAllBooks(gomock.Any(), gomock.Any(), "author").
 					Return([]*entity.Book{
 						{
 							ID:          uuid.MustParse("13867a7d-d1c4-4a06-aa60-42741a4fbbbd"),
@@ -287,7 +319,7 @@ func TestBookHandler_GetAllBooks(t *testing.T) {
 						ID:          "13867a7d-d1c4-4a06-aa60-42741a4fbbbd",
 						Title:       "Book1",
 						Description: "Description1",
-						Author: authorDTO.AuthorResponse{
+						Author: &authorDTO.AuthorResponse{
 							ID:   "24319e61-32d0-49f3-987f-019b734ed9c7",
 							Name: "Author1",
 						},
@@ -296,7 +328,7 @@ func TestBookHandler_GetAllBooks(t *testing.T) {
 						ID:          "66509608-3ca2-46d0-99d6-8ad989fe0061",
 						Title:       "Book2",
 						Description: "Description2",
-						Author: authorDTO.AuthorResponse{
+						Author: &authorDTO.AuthorResponse{
 							ID:   "24319e61-32d0-49f3-987f-019b734ed9c7",
 							Name: "Author1",
 						},
@@ -305,7 +337,7 @@ func TestBookHandler_GetAllBooks(t *testing.T) {
 						ID:          "d58905b0-1d21-47ee-805f-ccc92aba2453",
 						Title:       "Book3",
 						Description: "Description3",
-						Author: authorDTO.AuthorResponse{
+						Author: &authorDTO.AuthorResponse{
 							ID:   "24319e61-32d0-49f3-987f-019b734ed9c7",
 							Name: "Author1",
 						},
@@ -317,7 +349,7 @@ func TestBookHandler_GetAllBooks(t *testing.T) {
 			name: "error service internal error",
 			configureMock: func(mockService *mocks.MockBookService) {
 				mockService.EXPECT().
-					GetAllBooks(gomock.Any()).
+					GetAllBooks(gomock.Any(), gomock.Any(), "author").
 					Return(nil, errors.New("database connection failed"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
@@ -337,7 +369,7 @@ func TestBookHandler_GetAllBooks(t *testing.T) {
 			test.configureMock(mockService)
 
 			v := validator.New()
-			handler := book.NewBookHandler(mockService, v, zerolog.Nop())
+			handler := book.NewBookHandler(mockService, nil, v, zerolog.Nop())
 
 			req := httptest.NewRequest(http.MethodGet, "/books", nil)
 			req.Header.Set("Content-Type", "application/json")
@@ -363,6 +395,7 @@ func TestBookHandler_GetBookByID(t *testing.T) {
 	tests := []struct {
 		name               string
 		idUrlParam         string
+		query              string
 		configureMock      func(service *mocks.MockBookService)
 		expectedStatusCode int
 		expectedResponse   interface{}
@@ -372,7 +405,7 @@ func TestBookHandler_GetBookByID(t *testing.T) {
 			idUrlParam: "3a310074-b63f-455e-996f-63a5afffc227",
 			configureMock: func(mockService *mocks.MockBookService) {
 				mockService.EXPECT().
-					GetBookByID(gomock.Any(), uuid.MustParse("3a310074-b63f-455e-996f-63a5afffc227")).
+					GetBookByID(gomock.Any(), uuid.MustParse("3a310074-b63f-455e-996f-63a5afffc227"), "author").
 					Return(&entity.Book{
 						ID:          uuid.MustParse("3a310074-b63f-455e-996f-63a5afffc227"),
 						Title:       "Book1",
@@ -391,7 +424,7 @@ func TestBookHandler_GetBookByID(t *testing.T) {
 					ID:          "3a310074-b63f-455e-996f-63a5afffc227",
 					Title:       "Book1",
 					Description: "Description1",
-					Author: authorDTO.AuthorResponse{
+					Author: &authorDTO.AuthorResponse{
 						ID:   "88a49625-ee9d-456d-9541-e359454eb40c",
 						Name: "Author1",
 					},
@@ -399,11 +432,44 @@ func TestBookHandler_GetBookByID(t *testing.T) {
 			},
 		},
 		{
-			name:       "error book not found",
+			name:       "success get book with sparse fields",
 			idUrlParam: "3a310074-b63f-455e-996f-63a5afffc227",
+			query:      "?fields=id,title",
 			configureMock: func(mockService *mocks.MockBookService) {
 				mockService.EXPECT().
 					GetBookByID(gomock.Any(), uuid.MustParse("3a310074-b63f-455e-996f-63a5afffc227")).
+					Return(&entity.Book{
+						ID:          uuid.MustParse("3a310074-b63f-455e-996f-63a5afffc227"),
+						Title:       "Book1",
+						Description: "Description1",
+					}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: json.RawMessage(`{"status": "success", "message": "Book retrieved successfully", "book": {
+				"id": "3a310074-b63f-455e-996f-63a5afffc227", "title": "Book1"
+			}}`),
+		},
+		{
+			name:               "error unknown relationship",
+			idUrlParam:         "3a310074-b63f-455e-996f-63a5afffc227",
+			query:              "?expand=publisher",
+			configureMock:      func(mockService *mocks.MockBookService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ValidationErrorResponse{
+				Status:  "error",
+				Message: "Validation failed",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "expand",
+					Message: `unknown relationship "publisher"`,
+				}},
+			},
+		},
+		{
+			name:       "error book not found",
+			idUrlParam: "3a310074-b63f-455e-996f-63a5afffc227",
+			configureMock: func(mockService *mocks.MockBookService) {
+				mockService.EXPECT().
+					GetBookByID(gomock.Any(), uuid.MustParse("3a310074-b63f-455e-996f-63a5afffc227"), "author").
 					Return(nil, book.ErrNotFound)
 			},
 			expectedStatusCode: http.StatusNotFound,
@@ -427,7 +493,7 @@ func TestBookHandler_GetBookByID(t *testing.T) {
 			idUrlParam: "3a310074-b63f-455e-996f-63a5afffc227",
 			configureMock: func(mockService *mocks.MockBookService) {
 				mockService.EXPECT().
-					GetBookByID(gomock.Any(), uuid.MustParse("3a310074-b63f-455e-996f-63a5afffc227")).
+					GetBookByID(gomock.Any(), uuid.MustParse("3a310074-b63f-455e-996f-63a5afffc227"), "author").
 					Return(nil, errors.New("database connection failed"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
@@ -447,9 +513,9 @@ func TestBookHandler_GetBookByID(t *testing.T) {
 			test.configureMock(mockService)
 
 			v := validator.New()
-			handler := book.NewBookHandler(mockService, v, zerolog.Nop())
+			handler := book.NewBookHandler(mockService, nil, v, zerolog.Nop())
 
-			url := fmt.Sprintf("/books/%s", test.idUrlParam)
+			url := fmt.Sprintf("/books/%s%s", test.idUrlParam, test.query)
 			req := httptest.NewRequest(http.MethodGet, url, nil)
 			req.Header.Set("Content-Type", "application/json")
 			w := httptest.NewRecorder()
@@ -514,14 +580,19 @@ func TestBookHandler_UpdateBook(t *testing.T) {
 			},
 		},
 		{
-			name:               "error invalid json",
+			name:               "error empty body",
 			idUrlParam:         "3a310074-b63f-455e-996f-63a5afffc227",
 			requestBody:        nil,
 			configureMock:      func(mockService *mocks.MockBookService) {},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: &response.ErrorResponse{
+			expectedResponse: response.ValidationErrorResponse{
 				Status:  "error",
 				Message: "Invalid request body",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "body",
+					Message: "body must not be empty",
+					Tag:     "required",
+				}},
 			},
 		},
 		{
@@ -536,8 +607,9 @@ func TestBookHandler_UpdateBook(t *testing.T) {
 				Status:  "error",
 				Message: "Validation failed",
 				Errors: []response.ValidationErrorDetail{{
-					Field:   "Description",
-					Message: "Description is required",
+					Field:   "description",
+					Message: "description is required",
+					Tag:     "required",
 				}},
 			},
 		},
@@ -572,7 +644,7 @@ func TestBookHandler_UpdateBook(t *testing.T) {
 			test.configureMock(mockService)
 
 			v := validator.New()
-			handler := book.NewBookHandler(mockService, v, zerolog.Nop())
+			handler := book.NewBookHandler(mockService, nil, v, zerolog.Nop())
 
 			var body *bytes.Buffer
 			if test.requestBody == nil {
@@ -675,7 +747,7 @@ func TestBookHandler_DeleteBook(t *testing.T) {
 			test.configureMock(mockService)
 
 			v := validator.New()
-			handler := book.NewBookHandler(mockService, v, zerolog.Nop())
+			handler := book.NewBookHandler(mockService, nil, v, zerolog.Nop())
 
 			url := fmt.Sprintf("/books/%s", test.idUrlParam)
 			req := httptest.NewRequest(http.MethodDelete, url, nil)
@@ -697,3 +769,340 @@ func TestBookHandler_DeleteBook(t *testing.T) {
 		})
 	}
 }
+
+func TestBookHandler_ExportBooks(t *testing.T) {
+	createdAt := time.Date(2026, 1, 12, 21, 45, 0, 0, time.UTC)
+	books := []*entity.Book{
+		{
+			ID:          uuid.MustParse("13867a7d-d1c4-4a06-aa60-42741a4fbbbd"),
+			Title:       "Book1",
+			Description: "Description, with comma",
+			AuthorID:    uuid.MustParse("24319e61-32d0-49f3-987f-019b734ed9c7"),
+			Author: &entity.Author{
+				ID:   uuid.MustParse("24319e61-32d0-49f3-987f-019b734ed9c7"),
+				Name: "Author1",
+			},
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		},
+	}
+
+	tests := []struct {
+		name                string
+		query               string
+		configureMock       func(service *mocks.MockBookService)
+		expectedStatusCode  int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:  "success export csv",
+			query: "?format=csv&title=Book",
+			configureMock: func(mockService *mocks.MockBookService) {
+				mockService.EXPECT().
+					StreamBooks(gomock.Any(), &dto.BookFilter{Title: "Book"}, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ *dto.BookFilter, fn func([]*entity.Book) error) error {
+						return fn(books)
+					})
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody: "id,title,description,author_id,author_name,created_at,updated_at\n" +
+				"13867a7d-d1c4-4a06-aa60-42741a4fbbbd,Book1,\"Description, with comma\",24319e61-32d0-49f3-987f-019b734ed9c7,Author1,2026-01-12T21:45:00Z,2026-01-12T21:45:00Z\n",
+		},
+		{
+			name:  "success export ndjson",
+			query: "?format=ndjson",
+			configureMock: func(mockService *mocks.MockBookService) {
+				mockService.EXPECT().
+					StreamBooks(gomock.Any(), &dto.BookFilter{}, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ *dto.BookFilter, fn func([]*entity.Book) error) error {
+						return fn(books)
+					})
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody:        `{"author_id":"24319e61-32d0-49f3-987f-019b734ed9c7","author_name":"Author1","created_at":"2026-01-12T21:45:00Z","description":"Description, with comma","id":"13867a7d-d1c4-4a06-aa60-42741a4fbbbd","title":"Book1","updated_at":"2026-01-12T21:45:00Z"}` + "\n",
+		},
+		{
+			name:                "error unsupported format",
+			query:               "?format=pdf",
+			configureMock:       func(mockService *mocks.MockBookService) {},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        `{"status":"error","message":"Unsupported export format"}` + "\n",
+		},
+		{
+			name:                "error invalid author id filter",
+			query:               "?author_id=invalid",
+			configureMock:       func(mockService *mocks.MockBookService) {},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        `{"status":"error","message":"Validation failed","errors":[{"field":"author_id","message":"author_id must be a valid UUID","tag":"uuid"}]}` + "\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockService := mocks.NewMockBookService(ctrl)
+			test.configureMock(mockService)
+
+			v := validator.New()
+			handler := book.NewBookHandler(mockService, nil, v, zerolog.Nop())
+
+			req := httptest.NewRequest(http.MethodGet, "/books/export"+test.query, nil)
+			w := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Mount("/books", handler.Routes())
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, test.expectedBody, w.Body.String())
+			if test.expectedStatusCode == http.StatusOK {
+				assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment; filename=\"books-")
+			}
+		})
+	}
+}
+
+func TestBookHandler_ExportJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockService := mocks.NewMockBookService(ctrl)
+	mockService.EXPECT().
+		StreamBooks(gomock.Any(), &dto.BookFilter{}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *dto.BookFilter, fn func([]*entity.Book) error) error {
+			return fn([]*entity.Book{{
+				ID:       uuid.MustParse("13867a7d-d1c4-4a06-aa60-42741a4fbbbd"),
+				Title:    "Book1",
+				AuthorID: uuid.MustParse("24319e61-32d0-49f3-987f-019b734ed9c7"),
+			}})
+		})
+
+	exports := export.NewManager(t.TempDir(), config.ExportConfig{}, zerolog.Nop())
+	exports.Start(context.Background())
+	t.Cleanup(func() { _ = exports.Shutdown(context.Background()) })
+	handler := book.NewBookHandler(mockService, exports, validator.New(), zerolog.Nop())
+
+	r := chi.NewRouter()
+	// Stands for the tenant resolver.
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(tenant.WithID(r.Context(), r.Header.Get("X-Tenant-ID"))))
+		})
+	})
+	r.Mount("/books", handler.Routes())
+	call := func(method, target, tenantID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req.Header.Set("X-Tenant-ID", tenantID)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := call(http.MethodPost, "/books/exports?format=ndjson", "acme")
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	var created book.ExportJobSuccessResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "ndjson", created.Job.Format)
+	assert.Equal(t, "/books/exports/"+created.Job.ID, w.Header().Get("Location"))
+
+	var job book.ExportJobSuccessResponse
+	require.Eventually(t, func() bool {
+		w := call(http.MethodGet, "/books/exports/"+created.Job.ID, "acme")
+		if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &job) != nil {
+			return false
+		}
+		return job.Job.Status == "completed"
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "/books/exports/"+created.Job.ID+"/download", job.Job.DownloadURL)
+
+	w = call(http.MethodGet, job.Job.DownloadURL, "acme")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"title":"Book1"`)
+
+	// The job is hidden from the other tenants.
+	assert.Equal(t, http.StatusNotFound, call(http.MethodGet, "/books/exports/"+created.Job.ID, "globex").Code)
+	assert.Equal(t, http.StatusNotFound, call(http.MethodGet, job.Job.DownloadURL, "globex").Code)
+
+	assert.Equal(t, http.StatusNotFound, call(http.MethodGet, "/books/exports/"+uuid.NewString(), "acme").Code)
+}
+
+func TestBookHandler_CreateExportJob_TooManyJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	// Without workers, the first job stays pending.
+	exports := export.NewManager(t.TempDir(), config.ExportConfig{MaxPerTenant: 1}, zerolog.Nop())
+	handler := book.NewBookHandler(mocks.NewMockBookService(ctrl), exports, validator.New(), zerolog.Nop())
+
+	call := func(tenantID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/exports", nil)
+		w := httptest.NewRecorder()
+		handler.Routes().ServeHTTP(w, req.WithContext(tenant.WithID(req.Context(), tenantID)))
+		return w
+	}
+
+	assert.Equal(t, http.StatusAccepted, call("acme").Code)
+
+	w := call("acme")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, `{"status":"error","message":"Too many export jobs"}`+"\n", w.Body.String())
+
+	assert.Equal(t, http.StatusAccepted, call("globex").Code)
+}
+
+func TestBookHandler_GetBookByID_ContentNegotiation(t *testing.T) {
+	tests := []struct {
+		name                string
+		accept              string
+		expectedStatusCode  int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "xml",
+			accept:              "application/xml",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/xml",
+			expectedBody: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<response><status>success</status><message>Book retrieved successfully</message>` +
+				`<book><id>3a310074-b63f-455e-996f-63a5afffc227</id><title>Book1</title><description>Description1</description>` +
+				`<author><id>88a49625-ee9d-456d-9541-e359454eb40c</id><name>Author1</name></author></book></response>`,
+		},
+		{
+			name:                "csv",
+			accept:              "text/csv",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody: "id,title,description,author_id,author_name\n" +
+				"3a310074-b63f-455e-996f-63a5afffc227,Book1,Description1,88a49625-ee9d-456d-9541-e359454eb40c,Author1\n",
+		},
+		{
+			name:                "error not acceptable",
+			accept:              "image/png",
+			expectedStatusCode:  http.StatusNotAcceptable,
+			expectedContentType: "application/json",
+			expectedBody:        `{"status":"error","message":"Not acceptable"}` + "\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockService := mocks.NewMockBookService(ctrl)
+			mockService.EXPECT().
+				GetBookByID(gomock.Any(), uuid.MustParse("3a310074-b63f-455e-996f-63a5afffc227"), "author").
+				Return(&entity.Book{
+					ID:          uuid.MustParse("3a310074-b63f-455e-996f-63a5afffc227"),
+					Title:       "Book1",
+					Description: "Description1",
+					Author: &entity.Author{
+						ID:   uuid.MustParse("88a49625-ee9d-456d-9541-e359454eb40c"),
+						Name: "Author1",
+					},
+				}, nil)
+
+			handler := book.NewBookHandler(mockService, nil, validator.New(), zerolog.Nop())
+
+			req := httptest.NewRequest(http.MethodGet, "/books/3a310074-b63f-455e-996f-63a5afffc227", nil)
+			req.Header.Set("Accept", test.accept)
+			w := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Mount("/books", handler.Routes())
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, test.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestBookHandler_GetBookByID_LastModified(t *testing.T) {
+	updatedAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	renamedAt := time.Date(2026, 3, 2, 8, 30, 0, 500, time.UTC)
+
+	tests := []struct {
+		name                 string
+		ifModifiedSince      string
+		expectedStatusCode   int
+		expectedLastModified string
+	}{
+		{
+			name:                 "success dated by the rename of the author",
+			expectedStatusCode:   http.StatusOK,
+			expectedLastModified: "Mon, 02 Mar 2026 08:30:00 GMT",
+		},
+		{
+			name:                 "success not modified",
+			ifModifiedSince:      "Mon, 02 Mar 2026 08:30:00 GMT",
+			expectedStatusCode:   http.StatusNotModified,
+			expectedLastModified: "Mon, 02 Mar 2026 08:30:00 GMT",
+		},
+		{
+			name:                 "success modified since",
+			ifModifiedSince:      "Sun, 01 Mar 2026 12:00:00 GMT",
+			expectedStatusCode:   http.StatusOK,
+			expectedLastModified: "Mon, 02 Mar 2026 08:30:00 GMT",
+		},
+		{
+			name:                 "success malformed date ignored",
+			ifModifiedSince:      "yesterday",
+			expectedStatusCode:   http.StatusOK,
+			expectedLastModified: "Mon, 02 Mar 2026 08:30:00 GMT",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockService := mocks.NewMockBookService(ctrl)
+			mockService.EXPECT().
+				GetBookByID(gomock.Any(), uuid.MustParse("3a310074-b63f-455e-996f-63a5afffc227"), "author").
+				Return(&entity.Book{
+					ID:        uuid.MustParse("3a310074-b63f-455e-996f-63a5afffc227"),
+					Title:     "Book1",
+					UpdatedAt: updatedAt,
+					Author:    &entity.Author{Name: "Author1", UpdatedAt: renamedAt},
+				}, nil)
+
+			handler := book.NewBookHandler(mockService, nil, validator.New(), zerolog.Nop())
+
+			req := httptest.NewRequest(http.MethodGet, "/books/3a310074-b63f-455e-996f-63a5afffc227", nil)
+			if test.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", test.ifModifiedSince)
+			}
+			w := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Mount("/books", handler.Routes())
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedLastModified, w.Header().Get("Last-Modified"))
+			if test.expectedStatusCode == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
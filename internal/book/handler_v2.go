@@ -0,0 +1,294 @@
+package book
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/book/dto"
+	"go-boilerplate-rest-api-chi/internal/export"
+	"go-boilerplate-rest-api-chi/internal/httpcache"
+	"go-boilerplate-rest-api-chi/internal/pagination"
+	"go-boilerplate-rest-api-chi/internal/request"
+	"go-boilerplate-rest-api-chi/internal/response"
+	internalValidator "go-boilerplate-rest-api-chi/internal/validator"
+)
+
+type BookSuccessResponseV2 struct {
+	Status  string              `json:"status" xml:"status" example:"success"`
+	Message string              `json:"message" xml:"message" example:"Book retrieved successfully"`
+	Book    *dto.BookResponseV2 `json:"book" xml:"book"`
+}
+
+func (b BookSuccessResponseV2) CSVHeader() []string {
+	return dto.BookV2CSVHeader
+}
+
+func (b BookSuccessResponseV2) CSVRecords() [][]string {
+	if b.Book == nil {
+		return nil
+	}
+	return [][]string{b.Book.CSVRecord()}
+}
+
+type BooksSuccessResponseV2 struct {
+	Status     string               `json:"status" xml:"status" example:"success"`
+	Message    string               `json:"message" xml:"message" example:"Books retrieved successfully"`
+	Books      []dto.BookResponseV2 `json:"books" xml:"books>book"`
+	Pagination pagination.Meta      `json:"pagination" xml:"pagination"`
+}
+
+func (b BooksSuccessResponseV2) CSVHeader() []string {
+	return dto.BookV2CSVHeader
+}
+
+func (b BooksSuccessResponseV2) CSVRecords() [][]string {
+	records := make([][]string, len(b.Books))
+	for i, book := range b.Books {
+		records[i] = book.CSVRecord()
+	}
+	return records
+}
+
+// BookHandlerV2 serves the books in the v2 shape. The exports, whose format did not change,
+// are served by the v1 handler.
+type BookHandlerV2 struct {
+	*BookHandler
+}
+
+func NewBookHandlerV2(service BookService, exports *export.Manager, validator *internalValidator.Validator, logger zerolog.Logger) *BookHandlerV2 {
+	return &BookHandlerV2{
+		BookHandler: NewBookHandler(service, exports, validator, logger),
+	}
+}
+
+func (h *BookHandlerV2) Routes() http.Handler {
+	r := chi.NewRouter()
+
+	// routes
+	r.With(response.Acceptable(BookSuccessResponseV2{})).Post("/", h.CreateBook)
+	r.With(httpcache.Collection).Get("/", h.ListBooks)
+	r.Get("/export", h.ExportBooks)
+	r.Post("/exports", h.CreateExportJob)
+	r.Get("/exports/{job_id}", h.GetExportJob)
+	r.Get("/exports/{job_id}/download", h.DownloadExport)
+	r.With(httpcache.Cacheable).Get("/{book_id}", h.GetBookByID)
+	r.With(response.Acceptable(BookSuccessResponseV2{})).Patch("/{book_id}", h.UpdateBook)
+	r.Delete("/{book_id}", h.DeleteBook)
+	r.With(auth.Required).Get("/secure", h.AuthTestRoute)
+
+	return r
+}
+
+// CreateBook godoc
+//
+//	@Summary		Create a new book
+//	@Description	Create a new book with the provided data
+//	@Tags			books.v2
+//	@Accept			json
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Produce		text/csv
+//	@Param			book	body		dto.CreateBookRequest	true	"Book data"
+//	@Success		201		{object}	BookSuccessResponseV2
+//	@Failure		400		{object}	response.ValidationErrorResponse
+//	@Failure		404		{object}	response.ErrorResponse
+//	@Failure		409		{object}	response.ErrorResponse
+//	@Failure		413		{object}	response.ValidationErrorResponse
+//	@Failure		415		{object}	response.ValidationErrorResponse
+//	@Failure		500		{object}	response.ErrorResponse
+//	@Router			/books [post]
+func (h *BookHandlerV2) CreateBook(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateBookRequest
+
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.validator.FormatErrors(r.Context(), err)
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	book, err := h.service.CreateBook(r.Context(), &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Render(w, r, http.StatusCreated, BookSuccessResponseV2{
+		Status:  "success",
+		Message: "Book created successfully",
+		Book:    dto.ToBookResponseV2(book),
+	})
+}
+
+// ListBooks godoc
+//
+//	@Summary		List books
+//	@Description	Get a paginated list of the books, oldest first
+//	@Tags			books.v2
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Produce		text/csv
+//	@Param			title		query		string	false	"Filter on a part of the title"
+//	@Param			author_id	query		string	false	"Filter on the author ID"	format(uuid)
+//	@Param			page		query		int		false	"Page number"				minimum(1)	default(1)
+//	@Param			page_size	query		int		false	"Page size"					minimum(1)	maximum(100)	default(20)
+//	@Success		200			{object}	BooksSuccessResponseV2
+//	@Failure		400			{object}	response.ValidationErrorResponse
+//	@Failure		500			{object}	response.ErrorResponse
+//	@Router			/books [get]
+func (h *BookHandlerV2) ListBooks(w http.ResponseWriter, r *http.Request) {
+	filter, ok := h.parseFilter(w, r)
+	if !ok {
+		return
+	}
+
+	params, errs := pagination.Parse(r)
+	if errs != nil {
+		response.ValidationError(w, errs)
+		return
+	}
+
+	books, total, err := h.service.ListBooks(r.Context(), filter, params, "author")
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Render(w, r, http.StatusOK, BooksSuccessResponseV2{
+		Status:     "success",
+		Message:    "Books retrieved successfully",
+		Books:      dto.ToBooksResponseV2(books),
+		Pagination: pagination.NewMeta(params, total),
+	})
+}
+
+// GetBookByID godoc
+//
+//	@Summary		Get a book by ID
+//	@Description	Get a book with its author
+//	@Tags			books.v2
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Produce		text/csv
+//	@Param			book_id				path		string	true	"Book ID"	format(uuid)
+//	@Param			If-Modified-Since	header		string	false	"Date of the copy held, answered 304 when the book and its author have not changed since"
+//	@Success		200					{object}	BookSuccessResponseV2
+//	@Header			200					{string}	Last-Modified	"Date of the last change of the book or of its author"
+//	@Failure		400					{object}	response.ErrorResponse
+//	@Failure		404					{object}	response.ErrorResponse
+//	@Failure		500					{object}	response.ErrorResponse
+//	@Success		304
+//	@Router			/books/{book_id} [get]
+func (h *BookHandlerV2) GetBookByID(w http.ResponseWriter, r *http.Request) {
+	bookID, err := uuid.Parse(chi.URLParam(r, "book_id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid uuid")
+		return
+	}
+
+	book, err := h.service.GetBookByID(r.Context(), bookID, "author")
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if httpcache.NotModified(w, r, lastModified(book)) {
+		return
+	}
+
+	response.Render(w, r, http.StatusOK, BookSuccessResponseV2{
+		Status:  "success",
+		Message: "Book retrieved successfully",
+		Book:    dto.ToBookResponseV2(book),
+	})
+}
+
+// UpdateBook godoc
+//
+//	@Summary		Update a book
+//	@Description	Update a book with the provided data and return it
+//	@Tags			books.v2
+//	@Accept			json
+//	@Produce		json
+//	@Param			book_id	path		string					true	"Book ID"	format(uuid)
+//	@Param			book	body		dto.UpdateBookRequest	true	"Book data"
+//	@Success		200		{object}	BookSuccessResponseV2
+//	@Failure		400		{object}	response.ValidationErrorResponse
+//	@Failure		404		{object}	response.ErrorResponse
+//	@Failure		413		{object}	response.ValidationErrorResponse
+//	@Failure		415		{object}	response.ValidationErrorResponse
+//	@Failure		500		{object}	response.ErrorResponse
+//	@Router			/books/{book_id} [patch]
+func (h *BookHandlerV2) UpdateBook(w http.ResponseWriter, r *http.Request) {
+	bookID, err := uuid.Parse(chi.URLParam(r, "book_id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid uuid")
+		return
+	}
+
+	var req dto.UpdateBookRequest
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.validator.FormatErrors(r.Context(), err)
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	if err := h.service.UpdateBook(r.Context(), &req, bookID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	book, err := h.service.GetBookByID(r.Context(), bookID, "author")
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Render(w, r, http.StatusOK, BookSuccessResponseV2{
+		Status:  "success",
+		Message: "Book updated successfully",
+		Book:    dto.ToBookResponseV2(book),
+	})
+}
+
+// DeleteBook godoc
+//
+//	@Summary		Delete a book
+//	@Description	Delete a book by its ID
+//	@Tags			books.v2
+//	@Param			book_id	path	string	true	"Book ID"	format(uuid)
+//	@Success		204
+//	@Failure		400	{object}	response.ErrorResponse
+//	@Failure		404	{object}	response.ErrorResponse
+//	@Failure		500	{object}	response.ErrorResponse
+//	@Router			/books/{book_id} [delete]
+func (h *BookHandlerV2) DeleteBook(w http.ResponseWriter, r *http.Request) {
+	bookID, err := uuid.Parse(chi.URLParam(r, "book_id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid uuid")
+		return
+	}
+
+	if err := h.service.DeleteBook(r.Context(), bookID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
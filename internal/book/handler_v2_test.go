@@ -0,0 +1,200 @@
+package book_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"go-boilerplate-rest-api-chi/internal/book"
+	"go-boilerplate-rest-api-chi/internal/book/dto"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/pagination"
+	"go-boilerplate-rest-api-chi/internal/response"
+	"go-boilerplate-rest-api-chi/internal/validator"
+)
+
+func TestBookHandlerV2(t *testing.T) {
+	bookID := uuid.MustParse("13867a7d-d1c4-4a06-aa60-42741a4fbbbd")
+	authorID := uuid.MustParse("24319e61-32d0-49f3-987f-019b734ed9c7")
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	found := &entity.Book{
+		ID:          bookID,
+		Title:       "Book1",
+		Description: "Description1",
+		AuthorID:    authorID,
+		Author:      &entity.Author{ID: authorID, Name: "Author1"},
+		CreatedAt:   created,
+		UpdatedAt:   created,
+	}
+	expectedBook := &dto.BookResponseV2{
+		ID:          "13867a7d-d1c4-4a06-aa60-42741a4fbbbd",
+		Title:       "Book1",
+		Description: "Description1",
+		Author:      dto.BookAuthorResponse{ID: "24319e61-32d0-49f3-987f-019b734ed9c7", Name: "Author1"},
+		CreatedAt:   created,
+		UpdatedAt:   created,
+	}
+
+	tests := []struct {
+		name               string
+		method             string
+		url                string
+		requestBody        interface{}
+		configureMock      func(service *mocks.MockBookService)
+		expectedStatusCode int
+		expectedResponse   interface{}
+	}{
+		{
+			name:   "success list books",
+			method: http.MethodGet,
+			url:    "/books?page=2&page_size=1",
+			configureMock: func(mockService *mocks.MockBookService) {
+				mockService.EXPECT().
+					ListBooks(gomock.Any(), &dto.BookFilter{}, pagination.Params{Page: 2, PageSize: 1}, "author").
+					Return([]*entity.Book{found}, int64(3), nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: book.BooksSuccessResponseV2{
+				Status:     "success",
+				Message:    "Books retrieved successfully",
+				Books:      []dto.BookResponseV2{*expectedBook},
+				Pagination: pagination.Meta{Page: 2, PageSize: 1, TotalItems: 3, TotalPages: 3},
+			},
+		},
+		{
+			name:               "error list books invalid page",
+			method:             http.MethodGet,
+			url:                "/books?page=0",
+			configureMock:      func(service *mocks.MockBookService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ValidationErrorResponse{
+				Status:  "error",
+				Message: "Validation failed",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "page",
+					Message: "page must be a positive integer",
+				}},
+			},
+		},
+		{
+			name:   "success get book",
+			method: http.MethodGet,
+			url:    "/books/13867a7d-d1c4-4a06-aa60-42741a4fbbbd",
+			configureMock: func(mockService *mocks.MockBookService) {
+				mockService.EXPECT().GetBookByID(gomock.Any(), bookID, "author").Return(found, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: book.BookSuccessResponseV2{
+				Status:  "success",
+				Message: "Book retrieved successfully",
+				Book:    expectedBook,
+			},
+		},
+		{
+			name:   "error get book not found",
+			method: http.MethodGet,
+			url:    "/books/13867a7d-d1c4-4a06-aa60-42741a4fbbbd",
+			configureMock: func(mockService *mocks.MockBookService) {
+				mockService.EXPECT().GetBookByID(gomock.Any(), bookID, "author").Return(nil, book.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: response.ErrorResponse{
+				Status:  "error",
+				Message: "Book not found",
+			},
+		},
+		{
+			name:        "success update book",
+			method:      http.MethodPatch,
+			url:         "/books/13867a7d-d1c4-4a06-aa60-42741a4fbbbd",
+			requestBody: dto.UpdateBookRequest{Description: "Description1"},
+			configureMock: func(mockService *mocks.MockBookService) {
+				gomock.InOrder(
+					mockService.EXPECT().UpdateBook(gomock.Any(), &dto.UpdateBookRequest{Description: "Description1"}, bookID).Return(nil),
+					mockService.EXPECT().GetBookByID(gomock.Any(), bookID, "author").Return(found, nil),
+				)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: book.BookSuccessResponseV2{
+				Status:  "success",
+				Message: "Book updated successfully",
+				Book:    expectedBook,
+			},
+		},
+		{
+			name:        "error update book internal",
+			method:      http.MethodPatch,
+			url:         "/books/13867a7d-d1c4-4a06-aa60-42741a4fbbbd",
+			requestBody: dto.UpdateBookRequest{Description: "Description1"},
+			configureMock: func(mockService *mocks.MockBookService) {
+				mockService.EXPECT().UpdateBook(gomock.Any(), gomock.Any(), bookID).Return(errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: response.ErrorResponse{
+				Status:  "error",
+				Message: "Internal server error",
+			},
+		},
+		{
+			name:   "success delete book",
+			method: http.MethodDelete,
+			url:    "/books/13867a7d-d1c4-4a06-aa60-42741a4fbbbd",
+			configureMock: func(mockService *mocks.MockBookService) {
+				mockService.EXPECT().DeleteBook(gomock.Any(), bookID).Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockService := mocks.NewMockBookService(ctrl)
+			test.configureMock(mockService)
+
+			handler := book.NewBookHandlerV2(mockService, nil, validator.New(), zerolog.Nop())
+
+			body := bytes.NewBuffer([]byte{})
+			if test.requestBody != nil {
+				b, err := json.Marshal(test.requestBody)
+				require.NoError(t, err)
+				body = bytes.NewBuffer(b)
+			}
+
+			req := httptest.NewRequest(test.method, test.url, body)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Mount("/books", handler.Routes())
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+
+			if test.expectedResponse == nil {
+				assert.Empty(t, w.Body.String())
+				return
+			}
+
+			expectedJSON, err := json.Marshal(test.expectedResponse)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, string(expectedJSON), w.Body.String())
+		})
+	}
+}
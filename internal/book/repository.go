@@ -3,19 +3,35 @@ package book
 import (
 	"context"
 	"errors"
+	"maps"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
 
+	"go-boilerplate-rest-api-chi/internal/book/dto"
+	"go-boilerplate-rest-api-chi/internal/database"
 	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/outbox"
+	"go-boilerplate-rest-api-chi/internal/pagination"
 )
 
+const streamBatchSize = 500
+
+// relations maps the relationship names exposed by the API to the GORM associations.
+var relations = map[string]string{
+	"author": "Author",
+}
+
 //go:generate mockgen -destination=../mocks/mock_book_repository.go -package=mocks go-boilerplate-rest-api-chi/internal/book BookRepository
 type BookRepository interface {
 	Create(ctx context.Context, book *entity.Book) (*entity.Book, error)
-	GetAll(ctx context.Context) ([]*entity.Book, error)
-	GetByID(ctx context.Context, bookID uuid.UUID) (*entity.Book, error)
+	GetAll(ctx context.Context, filter *dto.BookFilter, expand ...string) ([]*entity.Book, error)
+	List(ctx context.Context, filter *dto.BookFilter, params pagination.Params, expand ...string) ([]*entity.Book, int64, error)
+	Stream(ctx context.Context, filter *dto.BookFilter, fn func(books []*entity.Book) error) error
+	GetByID(ctx context.Context, bookID uuid.UUID, expand ...string) (*entity.Book, error)
 	Update(ctx context.Context, bookID uuid.UUID, updates map[string]interface{}) error
 	Delete(ctx context.Context, bookID uuid.UUID) error
 }
@@ -32,8 +48,17 @@ func NewBookRepository(db *gorm.DB, logger zerolog.Logger) BookRepository {
 	}
 }
 
+// Create, Update and Delete record the matching book.* event in the outbox, in the transaction
+// of the change.
 func (r *bookRepository) Create(ctx context.Context, newBook *entity.Book) (*entity.Book, error) {
-	if err := r.db.WithContext(ctx).Create(newBook).Error; err != nil {
+	err := database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(newBook).Error; err != nil {
+			return err
+		}
+
+		return outbox.Add(tx, event.New(event.BookCreated, newBook.ID, dto.ToBookEventData(newBook)))
+	})
+	if err != nil {
 		if errors.Is(err, gorm.ErrDuplicatedKey) {
 			r.logger.Error().Err(err).Msg("record already exist in database")
 			return nil, ErrDuplicate
@@ -46,10 +71,10 @@ func (r *bookRepository) Create(ctx context.Context, newBook *entity.Book) (*ent
 	return newBook, nil
 }
 
-func (r *bookRepository) GetAll(ctx context.Context) ([]*entity.Book, error) {
+func (r *bookRepository) GetAll(ctx context.Context, filter *dto.BookFilter, expand ...string) ([]*entity.Book, error) {
 	var books []*entity.Book
 
-	if err := r.db.WithContext(ctx).Preload("Author").Find(&books).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Scopes(filterScope(filter), preloadScope(expand)).Find(&books).Error; err != nil {
 		r.logger.Error().Err(err).Msg("error when retreive books on database ")
 		return nil, err
 	}
@@ -57,10 +82,49 @@ func (r *bookRepository) GetAll(ctx context.Context) ([]*entity.Book, error) {
 	return books, nil
 }
 
-func (r *bookRepository) GetByID(ctx context.Context, bookID uuid.UUID) (*entity.Book, error) {
+// List returns a page of the books matching filter, with their total count.
+func (r *bookRepository) List(ctx context.Context, filter *dto.BookFilter, params pagination.Params, expand ...string) ([]*entity.Book, int64, error) {
+	var total int64
+	query := database.Conn(ctx, r.db).Model(&entity.Book{}).Scopes(filterScope(filter))
+
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, 0, err
+	}
+
+	var books []*entity.Book
+	if err := query.Scopes(preloadScope(expand)).Order("created_at, id").Offset(params.Offset()).Limit(params.PageSize).Find(&books).Error; err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, 0, err
+	}
+
+	return books, total, nil
+}
+
+// Stream walks the books matching filter in batches ordered by primary key, so the
+// whole table is never loaded in memory at once.
+func (r *bookRepository) Stream(ctx context.Context, filter *dto.BookFilter, fn func(books []*entity.Book) error) error {
+	var batch []*entity.Book
+
+	result := database.Conn(ctx, r.db).
+		Scopes(filterScope(filter)).
+		Preload("Author").
+		FindInBatches(&batch, streamBatchSize, func(_ *gorm.DB, _ int) error {
+			return fn(batch)
+		})
+
+	if result.Error != nil {
+		r.logger.Error().Err(result.Error).Msg("error when streaming books from database")
+		return result.Error
+	}
+
+	return nil
+}
+
+func (r *bookRepository) GetByID(ctx context.Context, bookID uuid.UUID, expand ...string) (*entity.Book, error) {
 	var book *entity.Book
 
-	if err := r.db.WithContext(ctx).Preload("Author").First(&book, "id = ?", bookID).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Scopes(preloadScope(expand)).First(&book, "id = ?", bookID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNotFound
 		}
@@ -70,30 +134,79 @@ func (r *bookRepository) GetByID(ctx context.Context, bookID uuid.UUID) (*entity
 	return book, nil
 }
 
+// The payload of book.updated holds the id and the updated columns.
 func (r *bookRepository) Update(ctx context.Context, bookID uuid.UUID, updates map[string]interface{}) error {
-	result := r.db.WithContext(ctx).Model(&entity.Book{ID: bookID}).Updates(updates)
+	return database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Book{ID: bookID}).Updates(updates)
 
-	if result.Error != nil {
-		return result.Error
-	}
+		if result.Error != nil {
+			return result.Error
+		}
 
-	if result.RowsAffected == 0 {
-		return ErrNotFound
-	}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
 
-	return nil
+		data := map[string]interface{}{"id": bookID.String()}
+		maps.Copy(data, updates)
+
+		return outbox.Add(tx, event.New(event.BookUpdated, bookID, data))
+	})
 }
 
 func (r *bookRepository) Delete(ctx context.Context, bookID uuid.UUID) error {
-	result := r.db.WithContext(ctx).Delete(&entity.Book{ID: bookID})
+	return database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&entity.Book{ID: bookID})
 
-	if result.Error != nil {
-		return result.Error
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		return outbox.Add(tx, event.New(event.BookDeleted, bookID, dto.BookEventData{ID: bookID.String()}))
+	})
+}
+
+func filterScope(filter *dto.BookFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter == nil {
+			return db
+		}
+
+		if filter.Title != "" {
+			db = db.Where("title LIKE ? ESCAPE "+escapeLiteral(db), "%"+likeEscaper.Replace(filter.Title)+"%")
+		}
+
+		if filter.AuthorID != "" {
+			db = db.Where("author_id = ?", filter.AuthorID)
+		}
+
+		return db
 	}
+}
 
-	if result.RowsAffected == 0 {
-		return ErrNotFound
+// likeEscaper escapes the wildcards of a LIKE pattern with a backslash.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLiteral is the backslash as a string literal, MySQL escaping it in the literals.
+func escapeLiteral(db *gorm.DB) string {
+	if db.Dialector.Name() == "mysql" {
+		return `'\\'`
 	}
+	return `'\'`
+}
 
-	return nil
+func preloadScope(expand []string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, relation := range expand {
+			if association, ok := relations[relation]; ok {
+				db = db.Preload(association)
+			}
+		}
+
+		return db
+	}
 }
@@ -2,6 +2,7 @@ package book_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -13,7 +14,9 @@ import (
 	"gorm.io/gorm"
 
 	"go-boilerplate-rest-api-chi/internal/book"
+	"go-boilerplate-rest-api-chi/internal/book/dto"
 	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/pagination"
 	"go-boilerplate-rest-api-chi/internal/test-utils"
 )
 
@@ -33,15 +36,19 @@ func TestBookRepository_Create(t *testing.T) {
 				AuthorID:    uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626"),
 			},
 			configureMock: func(mock sqlmock.Sqlmock, input *entity.Book) {
+				mock.ExpectBegin()
 				mock.ExpectExec("INSERT INTO `books`").
 					WithArgs(
 						sqlmock.AnyArg(),
+						entity.DefaultTenantID,
 						input.Title,
 						input.Description,
 						input.AuthorID,
 						sqlmock.AnyArg(), // CreatedAt
 						sqlmock.AnyArg(), // UpdatedAt
 					).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO `outbox`").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			expectedError: nil,
 			expectedResponse: &entity.Book{
@@ -58,15 +65,18 @@ func TestBookRepository_Create(t *testing.T) {
 				AuthorID:    uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626"),
 			},
 			configureMock: func(mock sqlmock.Sqlmock, input *entity.Book) {
+				mock.ExpectBegin()
 				mock.ExpectExec("INSERT INTO `books`").
 					WithArgs(
 						sqlmock.AnyArg(), // ID
+						entity.DefaultTenantID,
 						input.Title,
 						input.Description,
 						input.AuthorID,
 						sqlmock.AnyArg(), // CreatedAt
 						sqlmock.AnyArg(), // UpdatedAt
 					).WillReturnError(gorm.ErrDuplicatedKey)
+				mock.ExpectRollback()
 			},
 			expectedError:    book.ErrDuplicate,
 			expectedResponse: nil,
@@ -79,15 +89,18 @@ func TestBookRepository_Create(t *testing.T) {
 				AuthorID:    uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626"),
 			},
 			configureMock: func(mock sqlmock.Sqlmock, input *entity.Book) {
+				mock.ExpectBegin()
 				mock.ExpectExec("INSERT INTO `books`").
 					WithArgs(
 						sqlmock.AnyArg(), // ID
+						entity.DefaultTenantID,
 						input.Title,
 						input.Description,
 						input.AuthorID,
 						sqlmock.AnyArg(), // CreatedAt
 						sqlmock.AnyArg(), // UpdatedAt
 					).WillReturnError(gorm.ErrInvalidDB)
+				mock.ExpectRollback()
 			},
 			expectedError:    gorm.ErrInvalidDB,
 			expectedResponse: nil,
@@ -128,6 +141,7 @@ func TestBookRepository_Create(t *testing.T) {
 func TestBookRepository_GetAll(t *testing.T) {
 	tests := []struct {
 		name             string
+		filter           *dto.BookFilter
 		configureMock    func(sqlmock.Sqlmock)
 		expectedError    error
 		expectedResponse []*entity.Book
@@ -190,6 +204,44 @@ func TestBookRepository_GetAll(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "success get books with filter",
+			filter: &dto.BookFilter{
+				Title:    "One",
+				AuthorID: "eb21d07a-7ab3-40db-bfd3-448093bc5626",
+			},
+			configureMock: func(mock sqlmock.Sqlmock) {
+				now := time.Now()
+				authorID := uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626")
+
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "author_id", "created_at", "updated_at"}).
+					AddRow(uuid.MustParse("a1b2c3d4-e5f6-7890-1234-56789abcdef0"), "Book One", "Description One", authorID, now, now)
+
+				mock.ExpectQuery("SELECT \\* FROM `books` WHERE title LIKE \\? ESCAPE '\\\\\\\\' AND author_id = \\?").
+					WithArgs("%One%", "eb21d07a-7ab3-40db-bfd3-448093bc5626").
+					WillReturnRows(rows)
+
+				authorRows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
+					AddRow(authorID, "Victor Hugo", now, now)
+
+				mock.ExpectQuery("SELECT \\* FROM `authors` WHERE `authors`.`id` = \\?").
+					WithArgs(authorID).
+					WillReturnRows(authorRows)
+			},
+			expectedError: nil,
+			expectedResponse: []*entity.Book{
+				{
+					ID:          uuid.MustParse("a1b2c3d4-e5f6-7890-1234-56789abcdef0"),
+					Title:       "Book One",
+					Description: "Description One",
+					AuthorID:    uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626"),
+					Author: &entity.Author{
+						ID:   uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626"),
+						Name: "Victor Hugo",
+					},
+				},
+			},
+		},
 		{
 			name: "error database connection failed",
 			configureMock: func(mock sqlmock.Sqlmock) {
@@ -208,7 +260,7 @@ func TestBookRepository_GetAll(t *testing.T) {
 
 			repo := book.NewBookRepository(db, zerolog.Nop())
 
-			books, err := repo.GetAll(context.Background())
+			books, err := repo.GetAll(context.Background(), test.filter, "author")
 
 			if test.expectedError != nil {
 				assert.Error(t, err)
@@ -242,6 +294,160 @@ func TestBookRepository_GetAll(t *testing.T) {
 	}
 }
 
+func TestBookRepository_List(t *testing.T) {
+	tests := []struct {
+		name          string
+		filter        *dto.BookFilter
+		params        pagination.Params
+		configureMock func(sqlmock.Sqlmock)
+		expectedError error
+		expectedCount int
+		expectedTotal int64
+	}{
+		{
+			name:   "success list books with the wildcards of the title escaped",
+			filter: &dto.BookFilter{Title: `Mis_100%\`},
+			params: pagination.Window(5, 2),
+			configureMock: func(mock sqlmock.Sqlmock) {
+				now := time.Now()
+
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `books` WHERE title LIKE \\? ESCAPE '\\\\\\\\'").
+					WithArgs(`%Mis\_100\%\\%`).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(8))
+
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "author_id", "created_at", "updated_at"}).
+					AddRow(uuid.New(), "Les Misérables", "Description1", uuid.New(), now, now).
+					AddRow(uuid.New(), "Les Misérables II", "Description2", uuid.New(), now, now)
+
+				mock.ExpectQuery("SELECT \\* FROM `books` WHERE title LIKE \\? ESCAPE '\\\\\\\\' ORDER BY created_at, id LIMIT \\? OFFSET \\?").
+					WithArgs(`%Mis\_100\%\\%`, 2, 5).
+					WillReturnRows(rows)
+			},
+			expectedCount: 2,
+			expectedTotal: 8,
+		},
+		{
+			name:   "error database connection failed",
+			params: pagination.Params{Page: 1, PageSize: 20},
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `books`").
+					WillReturnError(gorm.ErrInvalidDB)
+			},
+			expectedError: gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := testutils.NewGormMySQL(t)
+			test.configureMock(mock)
+
+			repo := book.NewBookRepository(db, zerolog.Nop())
+
+			books, total, err := repo.List(context.Background(), test.filter, test.params)
+
+			assert.ErrorIs(t, err, test.expectedError)
+			assert.Len(t, books, test.expectedCount)
+			assert.Equal(t, test.expectedTotal, total)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestBookRepository_Stream(t *testing.T) {
+	tests := []struct {
+		name            string
+		configureMock   func(sqlmock.Sqlmock)
+		callbackError   error
+		expectedError   error
+		expectedBatches int
+	}{
+		{
+			name: "success stream books",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				now := time.Now()
+				authorID := uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626")
+
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "author_id", "created_at", "updated_at"}).
+					AddRow(uuid.MustParse("a1b2c3d4-e5f6-7890-1234-56789abcdef0"), "Book One", "Description One", authorID, now, now).
+					AddRow(uuid.MustParse("b1c2d3e4-f5a6-7890-1234-56789abcdef1"), "Book Two", "Description Two", authorID, now, now)
+
+				mock.ExpectQuery("SELECT \\* FROM `books` ORDER BY `books`.`id` LIMIT \\?").
+					WillReturnRows(rows)
+
+				authorRows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
+					AddRow(authorID, "Victor Hugo", now, now)
+
+				mock.ExpectQuery("SELECT \\* FROM `authors` WHERE `authors`.`id` = \\?").
+					WithArgs(authorID).
+					WillReturnRows(authorRows)
+			},
+			expectedError:   nil,
+			expectedBatches: 1,
+		},
+		{
+			name: "error callback aborts stream",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				now := time.Now()
+				authorID := uuid.MustParse("eb21d07a-7ab3-40db-bfd3-448093bc5626")
+
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "author_id", "created_at", "updated_at"}).
+					AddRow(uuid.MustParse("a1b2c3d4-e5f6-7890-1234-56789abcdef0"), "Book One", "Description One", authorID, now, now)
+
+				mock.ExpectQuery("SELECT \\* FROM `books` ORDER BY `books`.`id` LIMIT \\?").
+					WillReturnRows(rows)
+
+				authorRows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
+					AddRow(authorID, "Victor Hugo", now, now)
+
+				mock.ExpectQuery("SELECT \\* FROM `authors` WHERE `authors`.`id` = \\?").
+					WithArgs(authorID).
+					WillReturnRows(authorRows)
+			},
+			callbackError:   errors.New("client disconnected"),
+			expectedError:   errors.New("client disconnected"),
+			expectedBatches: 1,
+		},
+		{
+			name: "error database connection failed",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `books`").
+					WillReturnError(gorm.ErrInvalidDB)
+			},
+			expectedError:   gorm.ErrInvalidDB,
+			expectedBatches: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := testutils.NewGormMySQL(t)
+			test.configureMock(mock)
+
+			repo := book.NewBookRepository(db, zerolog.Nop())
+
+			batches := 0
+			err := repo.Stream(context.Background(), nil, func(books []*entity.Book) error {
+				batches++
+				for _, b := range books {
+					assert.NotNil(t, b.Author)
+				}
+				return test.callbackError
+			})
+
+			if test.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualError(t, err, test.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedBatches, batches)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestBookRepository_GetByID(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -316,7 +522,7 @@ func TestBookRepository_GetByID(t *testing.T) {
 
 			repo := book.NewBookRepository(db, zerolog.Nop())
 
-			book, err := repo.GetByID(context.Background(), test.bookID)
+			book, err := repo.GetByID(context.Background(), test.bookID, "author")
 
 			if test.expectedError != nil {
 				assert.Error(t, err)
@@ -357,6 +563,7 @@ func TestBookRepository_Update(t *testing.T) {
 				"description": "Updated description",
 			},
 			configureMock: func(mock sqlmock.Sqlmock, bookID uuid.UUID, updates map[string]interface{}) {
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE `books` SET `description`=\\?,`updated_at`=\\? WHERE `id` = \\?").
 					WithArgs(
 						updates["description"],
@@ -364,6 +571,8 @@ func TestBookRepository_Update(t *testing.T) {
 						bookID,
 					).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO `outbox`").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			expectedError: nil,
 		},
@@ -374,6 +583,7 @@ func TestBookRepository_Update(t *testing.T) {
 				"description": "Updated description",
 			},
 			configureMock: func(mock sqlmock.Sqlmock, bookID uuid.UUID, updates map[string]interface{}) {
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE `books` SET `description`=\\?,`updated_at`=\\? WHERE `id` = \\?").
 					WithArgs(
 						updates["description"],
@@ -381,6 +591,7 @@ func TestBookRepository_Update(t *testing.T) {
 						bookID,
 					).
 					WillReturnError(gorm.ErrInvalidDB)
+				mock.ExpectRollback()
 			},
 			expectedError: gorm.ErrInvalidDB,
 		},
@@ -391,6 +602,7 @@ func TestBookRepository_Update(t *testing.T) {
 				"description": "Updated description",
 			},
 			configureMock: func(mock sqlmock.Sqlmock, bookID uuid.UUID, updates map[string]interface{}) {
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE `books` SET `description`=\\?,`updated_at`=\\? WHERE `id` = \\?").
 					WithArgs(
 						updates["description"],
@@ -398,6 +610,7 @@ func TestBookRepository_Update(t *testing.T) {
 						bookID,
 					).
 					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
 			},
 			expectedError: book.ErrNotFound,
 		},
@@ -435,9 +648,12 @@ func TestBookRepository_Delete(t *testing.T) {
 			name:   "success delete book",
 			bookID: uuid.MustParse("a1b2c3d4-e5f6-7890-1234-56789abcdef0"),
 			configureMock: func(mock sqlmock.Sqlmock, bookID uuid.UUID) {
+				mock.ExpectBegin()
 				mock.ExpectExec("DELETE FROM `books` WHERE `books`.`id` = \\?").
 					WithArgs(bookID).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO `outbox`").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			expectedError: nil,
 		},
@@ -445,9 +661,11 @@ func TestBookRepository_Delete(t *testing.T) {
 			name:   "error book not found",
 			bookID: uuid.MustParse("a1b2c3d4-e5f6-7890-1234-56789abcdef0"),
 			configureMock: func(mock sqlmock.Sqlmock, bookID uuid.UUID) {
+				mock.ExpectBegin()
 				mock.ExpectExec("DELETE FROM `books` WHERE `books`.`id` = \\?").
 					WithArgs(bookID).
 					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
 			},
 			expectedError: book.ErrNotFound,
 		},
@@ -455,9 +673,11 @@ func TestBookRepository_Delete(t *testing.T) {
 			name:   "error database connection failed",
 			bookID: uuid.MustParse("a1b2c3d4-e5f6-7890-1234-56789abcdef0"),
 			configureMock: func(mock sqlmock.Sqlmock, bookID uuid.UUID) {
+				mock.ExpectBegin()
 				mock.ExpectExec("DELETE FROM `books` WHERE `books`.`id` = \\?").
 					WithArgs(bookID).
 					WillReturnError(gorm.ErrInvalidDB)
+				mock.ExpectRollback()
 			},
 			expectedError: gorm.ErrInvalidDB,
 		},
@@ -8,14 +8,18 @@ import (
 
 	"go-boilerplate-rest-api-chi/internal/author"
 	"go-boilerplate-rest-api-chi/internal/book/dto"
+	"go-boilerplate-rest-api-chi/internal/database"
 	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/pagination"
 )
 
 //go:generate mockgen -destination=../mocks/mock_book_service.go -package=mocks go-boilerplate-rest-api-chi/internal/book BookService
 type BookService interface {
 	CreateBook(ctx context.Context, req *dto.CreateBookRequest) (*entity.Book, error)
-	GetAllBooks(ctx context.Context) ([]*entity.Book, error)
-	GetBookByID(ctx context.Context, bookID uuid.UUID) (*entity.Book, error)
+	GetAllBooks(ctx context.Context, filter *dto.BookFilter, expand ...string) ([]*entity.Book, error)
+	ListBooks(ctx context.Context, filter *dto.BookFilter, params pagination.Params, expand ...string) ([]*entity.Book, int64, error)
+	StreamBooks(ctx context.Context, filter *dto.BookFilter, fn func(books []*entity.Book) error) error
+	GetBookByID(ctx context.Context, bookID uuid.UUID, expand ...string) (*entity.Book, error)
 	UpdateBook(ctx context.Context, req *dto.UpdateBookRequest, bookID uuid.UUID) error
 	DeleteBook(ctx context.Context, bookID uuid.UUID) error
 }
@@ -23,13 +27,15 @@ type BookService interface {
 type bookService struct {
 	repository       BookRepository
 	authorRepository author.AuthorRepository
+	txManager        database.TxManager
 	logger           zerolog.Logger
 }
 
-func NewBookService(repository BookRepository, authorRepository author.AuthorRepository, logger zerolog.Logger) BookService {
+func NewBookService(repository BookRepository, authorRepository author.AuthorRepository, txManager database.TxManager, logger zerolog.Logger) BookService {
 	return &bookService{
 		repository:       repository,
 		authorRepository: authorRepository,
+		txManager:        txManager,
 		logger:           logger,
 	}
 }
@@ -40,26 +46,36 @@ func (s *bookService) CreateBook(ctx context.Context, req *dto.CreateBookRequest
 		return nil, ErrInvalidAuthorId
 	}
 
-	exists, err := s.authorRepository.Exists(ctx, authorID)
+	var book *entity.Book
+
+	// The author check and the insert share a transaction, so the author cannot be deleted
+	// in between.
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		exists, err := s.authorRepository.Exists(ctx, authorID)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return author.ErrNotFound
+		}
+
+		book, err = s.repository.Create(ctx, &entity.Book{
+			Title:       req.Title,
+			Description: req.Description,
+			AuthorID:    authorID,
+		})
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if !exists {
-		return nil, author.ErrNotFound
-	}
-
-	book := &entity.Book{
-		Title:       req.Title,
-		Description: req.Description,
-		AuthorID:    authorID,
-	}
-
-	return s.repository.Create(ctx, book)
+	return book, nil
 }
 
-func (s *bookService) GetAllBooks(ctx context.Context) ([]*entity.Book, error) {
-	books, err := s.repository.GetAll(ctx)
+func (s *bookService) GetAllBooks(ctx context.Context, filter *dto.BookFilter, expand ...string) ([]*entity.Book, error) {
+	books, err := s.repository.GetAll(ctx, filter, expand...)
 	if err != nil {
 		return nil, err
 	}
@@ -70,8 +86,17 @@ func (s *bookService) GetAllBooks(ctx context.Context) ([]*entity.Book, error) {
 	return books, nil
 }
 
-func (s *bookService) GetBookByID(ctx context.Context, bookID uuid.UUID) (*entity.Book, error) {
-	book, err := s.repository.GetByID(ctx, bookID)
+// ListBooks, unlike GetAllBooks, returns an empty page rather than ErrNotFound.
+func (s *bookService) ListBooks(ctx context.Context, filter *dto.BookFilter, params pagination.Params, expand ...string) ([]*entity.Book, int64, error) {
+	return s.repository.List(ctx, filter, params, expand...)
+}
+
+func (s *bookService) StreamBooks(ctx context.Context, filter *dto.BookFilter, fn func(books []*entity.Book) error) error {
+	return s.repository.Stream(ctx, filter, fn)
+}
+
+func (s *bookService) GetBookByID(ctx context.Context, bookID uuid.UUID, expand ...string) (*entity.Book, error) {
+	book, err := s.repository.GetByID(ctx, bookID, expand...)
 	if err != nil {
 		return nil, err
 	}
@@ -15,6 +15,7 @@ import (
 	"go-boilerplate-rest-api-chi/internal/book/dto"
 	"go-boilerplate-rest-api-chi/internal/entity"
 	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/pagination"
 )
 
 func TestBookService_CreateBook(t *testing.T) {
@@ -160,8 +161,16 @@ func TestBookService_CreateBook(t *testing.T) {
 			authorRepoMock := mocks.NewMockAuthorRepository(ctrl)
 			bookRepoMock := mocks.NewMockBookRepository(ctrl)
 
+			txManagerMock := mocks.NewMockTxManager(ctrl)
+			txManagerMock.EXPECT().
+				WithinTransaction(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+					return fn(ctx)
+				}).
+				MaxTimes(1)
+
 			test.configureMock(bookRepoMock, authorRepoMock)
-			service := book.NewBookService(bookRepoMock, authorRepoMock, zerolog.Nop())
+			service := book.NewBookService(bookRepoMock, authorRepoMock, txManagerMock, zerolog.Nop())
 
 			result, err := service.CreateBook(context.Background(), test.input)
 
@@ -198,7 +207,7 @@ func TestBookService_GetAllBooks(t *testing.T) {
 				authorID := uuid.MustParse("779404e4-2660-4c80-b958-cfa72515e7d4")
 
 				bookRepository.EXPECT().
-					GetAll(gomock.Any()).
+					GetAll(gomock.Any(), gomock.Any()).
 					Return([]*entity.Book{
 						{
 							ID:          uuid.MustParse("619c69fb-9bcb-451e-b825-29b81697a531"),
@@ -264,7 +273,7 @@ func TestBookService_GetAllBooks(t *testing.T) {
 			name: "error no book found",
 			configureMock: func(bookRepository *mocks.MockBookRepository) {
 				bookRepository.EXPECT().
-					GetAll(gomock.Any()).
+					GetAll(gomock.Any(), gomock.Any()).
 					Return([]*entity.Book{}, nil)
 			},
 			expectedResponse: nil,
@@ -274,7 +283,7 @@ func TestBookService_GetAllBooks(t *testing.T) {
 			name: "error database connection failed",
 			configureMock: func(bookRepository *mocks.MockBookRepository) {
 				bookRepository.EXPECT().
-					GetAll(gomock.Any()).
+					GetAll(gomock.Any(), gomock.Any()).
 					Return(nil, gorm.ErrInvalidDB)
 			},
 			expectedResponse: nil,
@@ -291,9 +300,9 @@ func TestBookService_GetAllBooks(t *testing.T) {
 			bookRepoMock := mocks.NewMockBookRepository(ctrl)
 
 			test.configureMock(bookRepoMock)
-			service := book.NewBookService(bookRepoMock, authorRepoMock, zerolog.Nop())
+			service := book.NewBookService(bookRepoMock, authorRepoMock, mocks.NewMockTxManager(ctrl), zerolog.Nop())
 
-			books, err := service.GetAllBooks(context.Background())
+			books, err := service.GetAllBooks(context.Background(), &dto.BookFilter{})
 
 			if test.expectedError != nil {
 				assert.Error(t, err)
@@ -386,7 +395,7 @@ func TestBookService_GetBookByID(t *testing.T) {
 			bookRepoMock := mocks.NewMockBookRepository(ctrl)
 
 			test.configureMock(bookRepoMock)
-			service := book.NewBookService(bookRepoMock, authorRepoMock, zerolog.Nop())
+			service := book.NewBookService(bookRepoMock, authorRepoMock, mocks.NewMockTxManager(ctrl), zerolog.Nop())
 
 			books, err := service.GetBookByID(context.Background(), test.bookID)
 
@@ -478,7 +487,7 @@ func TestBookService_UpdateBook(t *testing.T) {
 			bookRepoMock := mocks.NewMockBookRepository(ctrl)
 
 			test.configureMock(bookRepoMock)
-			service := book.NewBookService(bookRepoMock, authorRepoMock, zerolog.Nop())
+			service := book.NewBookService(bookRepoMock, authorRepoMock, mocks.NewMockTxManager(ctrl), zerolog.Nop())
 
 			err := service.UpdateBook(context.Background(), test.input, test.bookID)
 
@@ -545,7 +554,7 @@ func TestBookService_DeleteBook(t *testing.T) {
 			bookRepoMock := mocks.NewMockBookRepository(ctrl)
 
 			test.configureMock(bookRepoMock)
-			service := book.NewBookService(bookRepoMock, authorRepoMock, zerolog.Nop())
+			service := book.NewBookService(bookRepoMock, authorRepoMock, mocks.NewMockTxManager(ctrl), zerolog.Nop())
 
 			err := service.DeleteBook(context.Background(), test.bookID)
 
@@ -558,3 +567,78 @@ func TestBookService_DeleteBook(t *testing.T) {
 		})
 	}
 }
+
+func TestBookService_StreamBooks(t *testing.T) {
+	tests := []struct {
+		name          string
+		filter        *dto.BookFilter
+		configureMock func(*mocks.MockBookRepository)
+		expectedError error
+	}{
+		{
+			name:   "success stream books",
+			filter: &dto.BookFilter{Title: "Book"},
+			configureMock: func(bookRepository *mocks.MockBookRepository) {
+				bookRepository.EXPECT().
+					Stream(gomock.Any(), &dto.BookFilter{Title: "Book"}, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ *dto.BookFilter, fn func([]*entity.Book) error) error {
+						return fn([]*entity.Book{{Title: "Book1"}})
+					})
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "error database connection failed",
+			filter: &dto.BookFilter{},
+			configureMock: func(bookRepository *mocks.MockBookRepository) {
+				bookRepository.EXPECT().
+					Stream(gomock.Any(), &dto.BookFilter{}, gomock.Any()).
+					Return(gorm.ErrInvalidDB)
+			},
+			expectedError: gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+			authorRepoMock := mocks.NewMockAuthorRepository(ctrl)
+			bookRepoMock := mocks.NewMockBookRepository(ctrl)
+
+			test.configureMock(bookRepoMock)
+			service := book.NewBookService(bookRepoMock, authorRepoMock, mocks.NewMockTxManager(ctrl), zerolog.Nop())
+
+			err := service.StreamBooks(context.Background(), test.filter, func(books []*entity.Book) error {
+				return nil
+			})
+
+			if test.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, test.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBookService_ListBooks(t *testing.T) {
+	params := pagination.Params{Page: 1, PageSize: 20}
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	bookRepoMock := mocks.NewMockBookRepository(ctrl)
+
+	bookRepoMock.EXPECT().
+		List(gomock.Any(), &dto.BookFilter{Title: "Book"}, params).
+		Return([]*entity.Book{}, int64(0), nil)
+
+	service := book.NewBookService(bookRepoMock, mocks.NewMockAuthorRepository(ctrl), mocks.NewMockTxManager(ctrl), zerolog.Nop())
+
+	books, total, err := service.ListBooks(context.Background(), &dto.BookFilter{Title: "Book"}, params)
+
+	assert.NoError(t, err)
+	assert.Empty(t, books)
+	assert.Zero(t, total)
+}
@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/singleflight"
+)
+
+// Store keeps the encoded entries of the caches. The replicas share their entries when they
+// share the store.
+type Store interface {
+	// Get returns the entry of key, ok being false when it is missing or expired.
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Stats counts the lookups of a cache since it was created.
+type Stats struct {
+	Hits   int64 `json:"hits" example:"1250"`
+	Misses int64 `json:"misses" example:"84"`
+}
+
+// Cache is a read-through cache: Fetch loads the missing entries once, however many callers
+// miss them at the same time. The store is never required: an unreachable store is a miss.
+type Cache struct {
+	name     string
+	store    Store
+	notifier Notifier
+	ttl      time.Duration
+	logger   zerolog.Logger
+
+	group singleflight.Group
+	// generation changes on every invalidation, so that a load started before it is not
+	// stored after it with the value it replaced.
+	generation atomic.Uint64
+	hits       atomic.Int64
+	misses     atomic.Int64
+}
+
+// New builds the cache name, whose keys are prefixed with the name in store. The invalidations
+// are shared with the other replicas through notifier.
+func New(name string, store Store, notifier Notifier, ttl time.Duration, logger zerolog.Logger) *Cache {
+	return &Cache{
+		name:     name,
+		store:    store,
+		notifier: notifier,
+		ttl:      ttl,
+		logger:   logger.With().Str("cache", name).Logger(),
+	}
+}
+
+func (c *Cache) Name() string {
+	return c.name
+}
+
+// Start applies the invalidations shared by the replicas until ctx is cancelled.
+func (c *Cache) Start(ctx context.Context) error {
+	prefix := c.name + ":"
+	unsubscribe, err := c.notifier.Subscribe(func(keys []string) {
+		var own []string
+		for _, key := range keys {
+			if strings.HasPrefix(key, prefix) {
+				own = append(own, key)
+			}
+		}
+		if len(own) > 0 {
+			c.forget(context.Background(), own)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+	}()
+
+	return nil
+}
+
+// Fetch returns the entry of key, loaded with load and stored on a miss. Every caller gets its
+// own copy of the entry, decoded from its JSON encoding.
+func Fetch[T any](ctx context.Context, c *Cache, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+	key = c.key(key)
+
+	if data, ok, err := c.store.Get(ctx, key); err != nil {
+		c.logger.Warn().Err(err).Str("key", key).Msg("cache read failed")
+	} else if ok {
+		if err := json.Unmarshal(data, &value); err == nil {
+			c.hits.Add(1)
+			return value, nil
+		}
+		c.logger.Warn().Str("key", key).Msg("cache entry unreadable, reloaded")
+	}
+	c.misses.Add(1)
+
+	data, err, _ := c.group.Do(key, func() (any, error) {
+		generation := c.generation.Load()
+
+		// The load is shared by the callers, so it is not cancelled with the first of them.
+		ctx := context.WithoutCancel(ctx)
+		loaded, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(loaded)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.store.Set(ctx, key, data, c.ttl); err != nil {
+			c.logger.Warn().Err(err).Str("key", key).Msg("cache write failed")
+		}
+		// An invalidation during the load may have been applied before the write.
+		if c.generation.Load() != generation {
+			if err := c.store.Delete(ctx, key); err != nil {
+				c.logger.Warn().Err(err).Str("key", key).Msg("cache invalidation failed")
+			}
+		}
+
+		return data, nil
+	})
+	if err != nil {
+		return value, err
+	}
+
+	err = json.Unmarshal(data.([]byte), &value)
+	return value, err
+}
+
+// Invalidate drops the entries of keys, in this replica and in the others.
+func (c *Cache) Invalidate(ctx context.Context, keys ...string) {
+	keys = slices.Clone(keys)
+	for i, key := range keys {
+		keys[i] = c.key(key)
+	}
+
+	c.forget(ctx, keys)
+	if err := c.notifier.Publish(ctx, keys); err != nil {
+		c.logger.Error().Err(err).Strs("keys", keys).Msg("failed to share the invalidation with the other replicas")
+	}
+}
+
+func (c *Cache) Stats() Stats {
+	return Stats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
+
+func (c *Cache) forget(ctx context.Context, keys []string) {
+	c.generation.Add(1)
+	for _, key := range keys {
+		c.group.Forget(key)
+	}
+
+	if err := c.store.Delete(ctx, keys...); err != nil {
+		c.logger.Error().Err(err).Strs("keys", keys).Msg("cache invalidation failed")
+	}
+}
+
+func (c *Cache) key(key string) string {
+	return c.name + ":" + key
+}
@@ -0,0 +1,197 @@
+package cache_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/cache"
+)
+
+type book struct {
+	Title string
+}
+
+func newCache(t *testing.T, notifier cache.Notifier) *cache.Cache {
+	t.Helper()
+
+	c := cache.New("books", cache.NewMemoryStore(100), notifier, time.Minute, zerolog.Nop())
+	require.NoError(t, c.Start(t.Context()))
+	return c
+}
+
+// counted counts the loads of a book.
+func counted(loads *atomic.Int32, title string) func(context.Context) (*book, error) {
+	return func(context.Context) (*book, error) {
+		loads.Add(1)
+		return &book{Title: title}, nil
+	}
+}
+
+func TestFetch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success loaded once then hit", func(t *testing.T) {
+		c := newCache(t, cache.NewLocalNotifier())
+		var loads atomic.Int32
+
+		first, err := cache.Fetch(ctx, c, "1", counted(&loads, "Les misérables"))
+		require.NoError(t, err)
+		first.Title = "changed by the caller"
+
+		second, err := cache.Fetch(ctx, c, "1", counted(&loads, "Les misérables"))
+		require.NoError(t, err)
+
+		assert.Equal(t, "Les misérables", second.Title, "every caller gets its own copy")
+		assert.Equal(t, int32(1), loads.Load())
+		assert.Equal(t, cache.Stats{Hits: 1, Misses: 1}, c.Stats())
+	})
+
+	t.Run("success concurrent misses loaded once", func(t *testing.T) {
+		c := newCache(t, cache.NewLocalNotifier())
+		var loads atomic.Int32
+		release := make(chan struct{})
+
+		var wg sync.WaitGroup
+		titles := make([]string, 10)
+		for i := range titles {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				b, err := cache.Fetch(ctx, c, "1", func(context.Context) (*book, error) {
+					loads.Add(1)
+					<-release
+					return &book{Title: "Les misérables"}, nil
+				})
+				if err == nil {
+					titles[i] = b.Title
+				}
+			}()
+		}
+		// Let the callers reach the load before it completes.
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), loads.Load())
+		for _, title := range titles {
+			assert.Equal(t, "Les misérables", title)
+		}
+		assert.Equal(t, cache.Stats{Misses: 10}, c.Stats())
+	})
+
+	t.Run("error not cached", func(t *testing.T) {
+		c := newCache(t, cache.NewLocalNotifier())
+		errNotFound := errors.New("not found")
+
+		_, err := cache.Fetch(ctx, c, "1", func(context.Context) (*book, error) { return nil, errNotFound })
+		assert.Equal(t, errNotFound, err)
+
+		var loads atomic.Int32
+		b, err := cache.Fetch(ctx, c, "1", counted(&loads, "Les misérables"))
+		require.NoError(t, err)
+		assert.Equal(t, "Les misérables", b.Title)
+		assert.Equal(t, int32(1), loads.Load())
+	})
+
+	t.Run("success store unreachable", func(t *testing.T) {
+		c := cache.New("books", failingStore{}, cache.NewLocalNotifier(), time.Minute, zerolog.Nop())
+		var loads atomic.Int32
+
+		for range 2 {
+			b, err := cache.Fetch(ctx, c, "1", counted(&loads, "Les misérables"))
+			require.NoError(t, err)
+			assert.Equal(t, "Les misérables", b.Title)
+		}
+		assert.Equal(t, int32(2), loads.Load())
+	})
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success entry reloaded", func(t *testing.T) {
+		c := newCache(t, cache.NewLocalNotifier())
+		var loads atomic.Int32
+
+		_, _ = cache.Fetch(ctx, c, "1", counted(&loads, "Les misérables"))
+		c.Invalidate(ctx, "1")
+		b, err := cache.Fetch(ctx, c, "1", counted(&loads, "Les contemplations"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "Les contemplations", b.Title)
+		assert.Equal(t, int32(2), loads.Load())
+	})
+
+	t.Run("success other replicas invalidated", func(t *testing.T) {
+		notifier := cache.NewLocalNotifier()
+		replica1 := newCache(t, notifier)
+		replica2 := newCache(t, notifier)
+		authors := cache.New("authors", cache.NewMemoryStore(100), notifier, time.Minute, zerolog.Nop())
+		require.NoError(t, authors.Start(t.Context()))
+		var loads atomic.Int32
+
+		_, _ = cache.Fetch(ctx, replica2, "1", counted(&loads, "Les misérables"))
+		_, _ = cache.Fetch(ctx, authors, "1", counted(&loads, "Victor Hugo"))
+		replica1.Invalidate(ctx, "1")
+
+		b, _ := cache.Fetch(ctx, replica2, "1", counted(&loads, "Les contemplations"))
+		assert.Equal(t, "Les contemplations", b.Title)
+		a, _ := cache.Fetch(ctx, authors, "1", counted(&loads, "George Sand"))
+		assert.Equal(t, "Victor Hugo", a.Title, "the other caches keep their entries")
+	})
+
+	t.Run("success load overtaken by an invalidation not stored", func(t *testing.T) {
+		c := newCache(t, cache.NewLocalNotifier())
+		var loads atomic.Int32
+
+		_, err := cache.Fetch(ctx, c, "1", func(context.Context) (*book, error) {
+			c.Invalidate(ctx, "1")
+			return &book{Title: "stale"}, nil
+		})
+		require.NoError(t, err)
+
+		b, _ := cache.Fetch(ctx, c, "1", counted(&loads, "Les misérables"))
+		assert.Equal(t, "Les misérables", b.Title)
+	})
+}
+
+func TestStatsHandler(t *testing.T) {
+	books := newCache(t, cache.NewLocalNotifier())
+	var loads atomic.Int32
+	for range 3 {
+		_, _ = cache.Fetch(context.Background(), books, "1", counted(&loads, "Les misérables"))
+	}
+
+	w := httptest.NewRecorder()
+	cache.StatsHandler(books)(w, httptest.NewRequest(http.MethodGet, "/cache/stats", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body cache.StatsSuccessResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, map[string]cache.Stats{"books": {Hits: 2, Misses: 1}}, body.Caches)
+}
+
+type failingStore struct{}
+
+func (failingStore) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+
+func (failingStore) Set(context.Context, string, []byte, time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func (failingStore) Delete(context.Context, ...string) error {
+	return errors.New("connection refused")
+}
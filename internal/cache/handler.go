@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"net/http"
+
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+// AdminScope grants access to the statistics of the caches, shared by every tenant.
+const AdminScope = "cache:admin"
+
+type StatsSuccessResponse struct {
+	Status  string           `json:"status" example:"success"`
+	Message string           `json:"message" example:"Cache statistics retrieved successfully"`
+	Caches  map[string]Stats `json:"caches"`
+}
+
+// StatsHandler reports the hits and misses of caches, by name.
+func StatsHandler(caches ...*Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		stats := make(map[string]Stats, len(caches))
+		for _, c := range caches {
+			stats[c.Name()] = c.Stats()
+		}
+
+		response.JSON(w, http.StatusOK, StatsSuccessResponse{
+			Status:  "success",
+			Message: "Cache statistics retrieved successfully",
+			Caches:  stats,
+		})
+	}
+}
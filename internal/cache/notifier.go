@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+// Notifier carries the invalidated keys between the caches of the replicas. Every cache
+// receives every invalidation, its own included.
+type Notifier interface {
+	Publish(ctx context.Context, keys []string) error
+	Subscribe(handler func(keys []string)) (unsubscribe func(), err error)
+}
+
+// LocalNotifier delivers the invalidations in process, for a single replica.
+type LocalNotifier struct {
+	mu       sync.RWMutex
+	next     int
+	handlers map[int]func([]string)
+}
+
+func NewLocalNotifier() *LocalNotifier {
+	return &LocalNotifier{handlers: make(map[int]func([]string))}
+}
+
+func (n *LocalNotifier) Publish(_ context.Context, keys []string) error {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	for _, handler := range n.handlers {
+		handler(keys)
+	}
+
+	return nil
+}
+
+func (n *LocalNotifier) Subscribe(handler func([]string)) (func(), error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	id := n.next
+	n.next++
+	n.handlers[id] = handler
+
+	return func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.handlers, id)
+	}, nil
+}
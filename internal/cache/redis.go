@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+// redisPrefix keeps the entries apart from the other keys of the Redis database.
+const redisPrefix = "cache:"
+
+// ConnectRedis connects to url, such as redis://localhost:6379/0. The client is closed when
+// ctx is cancelled.
+func ConnectRedis(ctx context.Context, url string) (*redis.Client, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	client := redis.NewClient(options)
+	go func() {
+		<-ctx.Done()
+		_ = client.Close()
+	}()
+
+	return client, nil
+}
+
+// RedisStore shares the entries of the replicas in Redis, which expires them.
+type RedisStore struct {
+	client redis.Cmdable
+}
+
+func NewRedisStore(client redis.Cmdable) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, redisPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, redisPrefix+key, value, ttl).Err()
+}
+
+func (s *RedisStore) Delete(ctx context.Context, keys ...string) error {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = redisPrefix + key
+	}
+
+	return s.client.Del(ctx, prefixed...).Err()
+}
+
+// RedisNotifier shares the invalidations of the replicas through a Redis channel. An
+// invalidation lost while Redis is unreachable is only recovered by the expiry of the entry.
+type RedisNotifier struct {
+	client  *redis.Client
+	channel string
+	logger  zerolog.Logger
+}
+
+func NewRedisNotifier(client *redis.Client, channel string, logger zerolog.Logger) *RedisNotifier {
+	return &RedisNotifier{
+		client:  client,
+		channel: channel,
+		logger:  logger,
+	}
+}
+
+func (n *RedisNotifier) Publish(ctx context.Context, keys []string) error {
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+
+	return n.client.Publish(ctx, n.channel, data).Err()
+}
+
+// Subscribe returns once the subscription is active, so that no later invalidation is missed.
+func (n *RedisNotifier) Subscribe(handler func([]string)) (func(), error) {
+	ctx := context.Background()
+
+	pubsub := n.client.Subscribe(ctx, n.channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, err
+	}
+
+	go func() {
+		for message := range pubsub.Channel() {
+			var keys []string
+			if err := json.Unmarshal([]byte(message.Payload), &keys); err != nil {
+				n.logger.Warn().Err(err).Msg("invalid cache invalidation dropped")
+				continue
+			}
+			handler(keys)
+		}
+	}()
+
+	return func() { _ = pubsub.Close() }, nil
+}
@@ -0,0 +1,114 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/cache"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("least recently used evicted", func(t *testing.T) {
+		store := cache.NewMemoryStore(2)
+
+		require.NoError(t, store.Set(ctx, "a", []byte("1"), time.Minute))
+		require.NoError(t, store.Set(ctx, "b", []byte("2"), time.Minute))
+		_, ok, _ := store.Get(ctx, "a")
+		require.True(t, ok)
+		require.NoError(t, store.Set(ctx, "c", []byte("3"), time.Minute))
+
+		_, ok, _ = store.Get(ctx, "b")
+		assert.False(t, ok, "b was the least recently used")
+		value, ok, _ := store.Get(ctx, "a")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("1"), value)
+		assert.Equal(t, 2, store.Len())
+	})
+
+	t.Run("expired entry missed", func(t *testing.T) {
+		store := cache.NewMemoryStore(10)
+
+		require.NoError(t, store.Set(ctx, "a", []byte("1"), 20*time.Millisecond))
+		time.Sleep(30 * time.Millisecond)
+
+		_, ok, err := store.Get(ctx, "a")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, 0, store.Len())
+	})
+
+	t.Run("entries replaced and deleted", func(t *testing.T) {
+		store := cache.NewMemoryStore(10)
+
+		require.NoError(t, store.Set(ctx, "a", []byte("1"), time.Minute))
+		require.NoError(t, store.Set(ctx, "a", []byte("2"), time.Minute))
+		require.NoError(t, store.Set(ctx, "b", []byte("3"), time.Minute))
+		value, _, _ := store.Get(ctx, "a")
+		assert.Equal(t, []byte("2"), value)
+
+		require.NoError(t, store.Delete(ctx, "a", "b", "unknown"))
+		assert.Equal(t, 0, store.Len())
+	})
+}
+
+func TestRedisStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	store := cache.NewRedisStore(client)
+	ctx := context.Background()
+
+	_, ok, err := store.Get(ctx, "books:default:1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, store.Set(ctx, "books:default:1", []byte(`{"Title":"Les misérables"}`), time.Minute))
+	value, ok, err := store.Get(ctx, "books:default:1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte(`{"Title":"Les misérables"}`), value)
+	assert.True(t, server.Exists("cache:books:default:1"))
+	assert.Equal(t, time.Minute, server.TTL("cache:books:default:1"))
+
+	require.NoError(t, store.Delete(ctx, "books:default:1"))
+	_, ok, _ = store.Get(ctx, "books:default:1")
+	assert.False(t, ok)
+
+	server.Close()
+	_, _, err = store.Get(ctx, "books:default:1")
+	assert.Error(t, err)
+}
+
+func TestRedisNotifier(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	// Two replicas subscribe to the same channel.
+	replica1 := cache.NewRedisNotifier(client, "library.cache", zerolog.Nop())
+	replica2 := cache.NewRedisNotifier(client, "library.cache", zerolog.Nop())
+
+	received := make(chan []string, 1)
+	unsubscribe, err := replica2.Subscribe(func(keys []string) { received <- keys })
+	require.NoError(t, err)
+	t.Cleanup(unsubscribe)
+
+	require.NoError(t, replica1.Publish(context.Background(), []string{"books:default:1", "books:default:2"}))
+
+	select {
+	case keys := <-received:
+		assert.Equal(t, []string{"books:default:1", "books:default:2"}, keys)
+	case <-time.After(time.Second):
+		t.Fatal("invalidation not received")
+	}
+}
@@ -1,19 +1,106 @@
 package config
 
 import (
+	"reflect"
+	"time"
+
 	"github.com/caarlos0/env/v11"
 )
 
 type Config struct {
 	Api      ApiConfig      `envPrefix:"API_"`
+	HTTP     HTTPConfig     `envPrefix:"HTTP_"`
 	Log      LogConfig      `envPrefix:"LOG_"`
 	Database DatabaseConfig `envPrefix:"DATABASE_"`
+	Webhook  WebhookConfig  `envPrefix:"WEBHOOK_"`
+	Outbox   OutboxConfig   `envPrefix:"OUTBOX_"`
+	Events   EventsConfig   `envPrefix:"EVENTS_"`
+	Auth     AuthConfig     `envPrefix:"AUTH_"`
+	Realtime RealtimeConfig `envPrefix:"REALTIME_"`
+	GraphQL  GraphQLConfig  `envPrefix:"GRAPHQL_"`
+	GRPC     GRPCConfig     `envPrefix:"GRPC_"`
+	Redis    RedisConfig    `envPrefix:"REDIS_"`
+	Tenancy  TenancyConfig  `envPrefix:"TENANCY_"`
+	Cache    CacheConfig    `envPrefix:"CACHE_"`
+	Export   ExportConfig   `envPrefix:"EXPORT_"`
 }
 
 type ApiConfig struct {
 	Environment string `env:"ENVIRONMENT,required,notEmpty"`
 	Host        string `env:"HOST,required,notEmpty"`
 	Port        int    `env:"PORT,required,notEmpty"`
+	// DocsEnabled serves the documentation, behind authentication, outside of development.
+	DocsEnabled bool `env:"DOCS_ENABLED" envDefault:"false"`
+	// ValidateRequests checks the requests against the OpenAPI document before the handlers.
+	// ValidateResponses also checks the responses, to catch the drift of the contract in
+	// development and in the tests.
+	ValidateRequests  bool `env:"VALIDATE_REQUESTS" envDefault:"false"`
+	ValidateResponses bool `env:"VALIDATE_RESPONSES" envDefault:"false"`
+	// MaxBodySize bounds, in bytes, the JSON bodies of the requests.
+	MaxBodySize int64 `env:"MAX_BODY_SIZE" envDefault:"1048576"`
+	// The v1 routes announce their deprecation and removal dates, v2 being their successor.
+	V1DeprecatedAt time.Time `env:"V1_DEPRECATED_AT" envDefault:"2026-11-01T00:00:00Z"`
+	V1SunsetAt     time.Time `env:"V1_SUNSET_AT" envDefault:"2027-05-01T00:00:00Z"`
+}
+
+// HTTPConfig drives the HTTP server. The zero durations and limits disable the matching
+// protection, as for http.Server.
+type HTTPConfig struct {
+	ReadTimeout       time.Duration `env:"READ_TIMEOUT" envDefault:"15s"`
+	ReadHeaderTimeout time.Duration `env:"READ_HEADER_TIMEOUT" envDefault:"10s"`
+	// WriteTimeout is disabled by default: the SSE streams set their own write deadlines and
+	// RequestTimeout bounds the other routes.
+	WriteTimeout   time.Duration `env:"WRITE_TIMEOUT" envDefault:"0s"`
+	IdleTimeout    time.Duration `env:"IDLE_TIMEOUT" envDefault:"120s"`
+	MaxHeaderBytes int           `env:"MAX_HEADER_BYTES" envDefault:"1048576"`
+	// RequestTimeout cancels the context of the requests, and MaxInFlight bounds the requests
+	// handled at once, the streams excepted.
+	RequestTimeout time.Duration   `env:"REQUEST_TIMEOUT" envDefault:"10s"`
+	MaxInFlight    int             `env:"MAX_IN_FLIGHT" envDefault:"100"`
+	CORS           CORSConfig      `envPrefix:"CORS_"`
+	RateLimit      RateLimitConfig `envPrefix:"RATE_LIMIT_"`
+	Cache          HTTPCacheConfig `envPrefix:"CACHE_"`
+}
+
+// CORSConfig is the CORS policy. An origin may hold one wildcard, such as
+// https://*.example.com. The header of TENANCY_HEADER is allowed in multi-tenant mode even
+// when missing from AllowedHeaders. ExposedHeaders are the response headers the browsers
+// let the scripts read.
+type CORSConfig struct {
+	AllowedOrigins   []string      `env:"ALLOWED_ORIGINS" envDefault:"*"`
+	AllowedMethods   []string      `env:"ALLOWED_METHODS" envDefault:"GET,POST,PUT,PATCH,DELETE"`
+	AllowedHeaders   []string      `env:"ALLOWED_HEADERS" envDefault:"Authorization,Content-Type,Accept-Language,API-Version,X-API-Key,X-Tenant-ID,If-None-Match,If-Modified-Since"`
+	ExposedHeaders   []string      `env:"EXPOSED_HEADERS" envDefault:"RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,RateLimit-Policy,Retry-After,Deprecation,Sunset,Link,ETag,Location"`
+	AllowCredentials bool          `env:"ALLOW_CREDENTIALS" envDefault:"false"`
+	MaxAge           time.Duration `env:"MAX_AGE" envDefault:"12h"`
+}
+
+// RateLimitConfig limits the requests per client, identified by the first of Keys found in
+// the request: api_key (ID of the API key authenticated with), user (authenticated subject)
+// or ip. Routes adds
+// limits to the resources mounted in every API version, keyed by their path:
+// /books=20/1m,/graphql=60/1m. The Clients of a plan, such as user:42=pro, get its quota
+// instead of the global one, and the PlanRoutes of the plan instead of the route limits,
+// keyed by plan and route: pro:/graphql=600/1m. The route limits still apply to the routes
+// their plan does not name. The redis store, using REDIS_URL, shares the counters of the
+// replicas.
+type RateLimitConfig struct {
+	Store      string               `env:"STORE" envDefault:"memory"`
+	Keys       []string             `env:"KEYS" envDefault:"api_key,user,ip"`
+	Global     RateLimit            `env:"GLOBAL" envDefault:"100/1m"`
+	Routes     map[string]RateLimit `env:"ROUTES" envKeyValSeparator:"="`
+	Plans      map[string]RateLimit `env:"PLANS" envKeyValSeparator:"="`
+	PlanRoutes map[string]RateLimit `env:"PLAN_ROUTES" envKeyValSeparator:"="`
+	Clients    map[string]string    `env:"CLIENTS" envKeyValSeparator:"="`
+}
+
+// HTTPCacheConfig sets the Cache-Control of the successful reads of the catalogue routes,
+// keyed by their path as for the rate limits: /books=1m/30s. The responses to the
+// authenticated callers are private. MemorySize, when positive, keeps that many responses of
+// the collections in process for the anonymous callers, until their max age.
+type HTTPCacheConfig struct {
+	Routes     map[string]CachePolicy `env:"ROUTES" envKeyValSeparator:"=" envDefault:"/books=1m/30s,/authors=1m/30s"`
+	MemorySize int                    `env:"MEMORY_SIZE" envDefault:"0"`
 }
 
 type LogConfig struct {
@@ -30,10 +117,161 @@ type DatabaseConfig struct {
 	LogLevel string `env:"LOG_LEVEL,required,notEmpty"`
 }
 
+type WebhookConfig struct {
+	MaxAttempts  int           `env:"MAX_ATTEMPTS" envDefault:"8"`
+	BaseDelay    time.Duration `env:"BASE_DELAY" envDefault:"10s"`
+	MaxDelay     time.Duration `env:"MAX_DELAY" envDefault:"1h"`
+	Timeout      time.Duration `env:"TIMEOUT" envDefault:"10s"`
+	PollInterval time.Duration `env:"POLL_INTERVAL" envDefault:"5s"`
+}
+
+// ExportConfig sizes the asynchronous exports: Workers run the jobs waiting in a queue of
+// QueueSize, and a tenant has at most MaxPerTenant jobs pending or running. The files are
+// deleted TTL after their job completed, looked for every PurgeInterval.
+type ExportConfig struct {
+	Workers       int           `env:"WORKERS" envDefault:"2"`
+	QueueSize     int           `env:"QUEUE_SIZE" envDefault:"32"`
+	MaxPerTenant  int           `env:"MAX_PER_TENANT" envDefault:"2"`
+	TTL           time.Duration `env:"TTL" envDefault:"1h"`
+	PurgeInterval time.Duration `env:"PURGE_INTERVAL" envDefault:"5m"`
+}
+
+// OutboxConfig drives the relay of the outbox. NATS is enabled by setting NATSURL; KafkaLocal
+// relays to the in-memory Kafka stand-in until a real producer is plugged in. A failed message
+// is retried with an exponential backoff from BaseDelay to MaxDelay and given up after
+// MaxAttempts. Lease bounds how long a relay holds the messages it claimed.
+//
+// Every replica follows the outbox, relay or not, to hand the events to its own subscribers.
+// GapTimeout bounds how long it waits for a message missing from the sequence of IDs, which
+// may belong to a transaction not committed yet, before skipping it.
+type OutboxConfig struct {
+	RelayEnabled bool          `env:"RELAY_ENABLED" envDefault:"true"`
+	PollInterval time.Duration `env:"POLL_INTERVAL" envDefault:"1s"`
+	BatchSize    int           `env:"BATCH_SIZE" envDefault:"100"`
+	Retention    time.Duration `env:"RETENTION" envDefault:"168h"`
+	MaxAttempts  int           `env:"MAX_ATTEMPTS" envDefault:"10"`
+	BaseDelay    time.Duration `env:"BASE_DELAY" envDefault:"1s"`
+	MaxDelay     time.Duration `env:"MAX_DELAY" envDefault:"5m"`
+	Lease        time.Duration `env:"LEASE" envDefault:"1m"`
+	GapTimeout   time.Duration `env:"GAP_TIMEOUT" envDefault:"5s"`
+	NATSURL      string        `env:"NATS_URL"`
+	NATSSubject  string        `env:"NATS_SUBJECT" envDefault:"library.events"`
+	KafkaLocal   bool          `env:"KAFKA_LOCAL" envDefault:"false"`
+	KafkaTopic   string        `env:"KAFKA_TOPIC" envDefault:"library.events"`
+}
+
+// EventsConfig sizes the Server-Sent Events change feed.
+type EventsConfig struct {
+	ReplaySize        int           `env:"REPLAY_SIZE" envDefault:"1000"`
+	ClientBuffer      int           `env:"CLIENT_BUFFER" envDefault:"64"`
+	HeartbeatInterval time.Duration `env:"HEARTBEAT_INTERVAL" envDefault:"15s"`
+}
+
+// AuthConfig verifies the bearer tokens. In the jwt mode they are HS256 tokens signed with
+// JWTSecret: without a secret no token is accepted and the protected routes answer 401. In
+// the oidc mode they are issued by the OpenID provider described by OIDC.
+type AuthConfig struct {
+	Mode        string     `env:"MODE" envDefault:"jwt"`
+	JWTSecret   string     `env:"JWT_SECRET"`
+	JWTIssuer   string     `env:"JWT_ISSUER"`
+	JWTAudience string     `env:"JWT_AUDIENCE"`
+	OIDC        OIDCConfig `envPrefix:"OIDC_"`
+}
+
+// OIDCConfig points to an OpenID provider such as Keycloak or Auth0, whose signing keys are
+// discovered from the issuer. The keys are fetched again after JWKSCacheTTL, or when a token
+// is signed by an unknown key, at most once per JWKSMinRefresh.
+//
+// The scopes of a caller are read from ScopeClaims, space separated strings or arrays. The
+// roles and groups read from RoleClaims, such as realm_access.roles, grant the scopes of
+// RoleScopes: admin=api_keys:admin webhooks:admin books:write,librarian=books:write.
+type OIDCConfig struct {
+	Issuer         string            `env:"ISSUER"`
+	Audience       string            `env:"AUDIENCE"`
+	JWKSCacheTTL   time.Duration     `env:"JWKS_CACHE_TTL" envDefault:"1h"`
+	JWKSMinRefresh time.Duration     `env:"JWKS_MIN_REFRESH" envDefault:"1m"`
+	ScopeClaims    []string          `env:"SCOPE_CLAIMS" envDefault:"scope,scp"`
+	RoleClaims     []string          `env:"ROLE_CLAIMS" envDefault:"roles,groups,realm_access.roles"`
+	RoleScopes     map[string]string `env:"ROLE_SCOPES" envKeyValSeparator:"="`
+	// TenantClaim binds the caller to a tenant, see TenancyConfig.
+	TenantClaim string `env:"TENANT_CLAIM" envDefault:"tenant"`
+}
+
+// RealtimeConfig drives the WebSocket hub. Presence and notifications only reach the other
+// replicas when NATSURL is set.
+type RealtimeConfig struct {
+	NATSURL          string        `env:"NATS_URL"`
+	NATSSubject      string        `env:"NATS_SUBJECT" envDefault:"library.realtime"`
+	PresenceInterval time.Duration `env:"PRESENCE_INTERVAL" envDefault:"15s"`
+	PingInterval     time.Duration `env:"PING_INTERVAL" envDefault:"30s"`
+	WriteTimeout     time.Duration `env:"WRITE_TIMEOUT" envDefault:"10s"`
+	ClientBuffer     int           `env:"CLIENT_BUFFER" envDefault:"32"`
+	OriginPatterns   []string      `env:"ORIGIN_PATTERNS" envDefault:"*"`
+}
+
+// GraphQLConfig bounds the queries of the GraphQL endpoint. The complexity counts one per
+// field, multiplied by the page size for the fields of a connection.
+type GraphQLConfig struct {
+	MaxDepth      int `env:"MAX_DEPTH" envDefault:"10"`
+	MaxComplexity int `env:"MAX_COMPLEXITY" envDefault:"1000"`
+}
+
+// GRPCConfig drives the gRPC server, listening on API_HOST next to the HTTP server.
+type GRPCConfig struct {
+	Port       int  `env:"PORT" envDefault:"9090"`
+	Reflection bool `env:"REFLECTION" envDefault:"true"`
+}
+
+// TenancyConfig hosts several catalogues in one deployment. Disabled, every request uses the
+// default tenant. Enabled, the tenant of a request is the tenant claim of its token, else
+// the Header, else the subdomain of BaseDomain: acme.library.example.com names acme.
+//
+// The callers without a tenant claim, the anonymous ones included, may name any tenant, the
+// catalogues being public. RequireAuth refuses the anonymous callers instead.
+type TenancyConfig struct {
+	Enabled     bool   `env:"ENABLED" envDefault:"false"`
+	Header      string `env:"HEADER" envDefault:"X-Tenant-ID"`
+	BaseDomain  string `env:"BASE_DOMAIN"`
+	RequireAuth bool   `env:"REQUIRE_AUTH" envDefault:"false"`
+}
+
+// CacheConfig drives the read-through cache of the book, author and tenant lookups: none,
+// memory or redis. The memory store keeps up to Size entries per cache in each replica, which
+// share their invalidations on Channel when REDIS_URL is set.
+type CacheConfig struct {
+	Store   string        `env:"STORE" envDefault:"memory"`
+	TTL     time.Duration `env:"TTL" envDefault:"1m"`
+	Size    int           `env:"SIZE" envDefault:"10000"`
+	Channel string        `env:"CHANNEL" envDefault:"library.cache"`
+}
+
+// RedisConfig connects to Redis, such as redis://localhost:6379/0.
+type RedisConfig struct {
+	URL string `env:"URL"`
+}
+
 func LoadConfig() (Config, error) {
 	var cfg Config
 
-	if err := env.Parse(&cfg); err != nil {
+	options := env.Options{
+		FuncMap: map[reflect.Type]env.ParserFunc{
+			reflect.TypeOf(RateLimit{}): func(value string) (any, error) {
+				var limit RateLimit
+				err := limit.UnmarshalText([]byte(value))
+				return limit, err
+			},
+			reflect.TypeOf(CachePolicy{}): func(value string) (any, error) {
+				var policy CachePolicy
+				err := policy.UnmarshalText([]byte(value))
+				return policy, err
+			},
+		},
+	}
+	if err := env.ParseWithOptions(&cfg, options); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
 		return Config{}, err
 	}
 
@@ -2,8 +2,10 @@ package config_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go-boilerplate-rest-api-chi/internal/config"
 )
@@ -37,3 +39,273 @@ func TestLoadConfig(t *testing.T) {
 		assert.Equal(t, config.Config{}, newCfg)
 	})
 }
+
+func TestLoadConfig_HTTP(t *testing.T) {
+	setRequired := func(t *testing.T) {
+		t.Setenv("API_ENVIRONMENT", "test")
+		t.Setenv("API_HOST", "localhost")
+		t.Setenv("API_PORT", "8080")
+		t.Setenv("LOG_LEVEL", "info")
+		t.Setenv("LOG_FORMAT", "json")
+		t.Setenv("DATABASE_HOST", "localhost")
+		t.Setenv("DATABASE_PORT", "5432")
+		t.Setenv("DATABASE_USER", "testuser")
+		t.Setenv("DATABASE_PASSWORD", "testpass")
+		t.Setenv("DATABASE_NAME", "testdb")
+		t.Setenv("DATABASE_LOG_LEVEL", "warn")
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		setRequired(t)
+
+		cfg, err := config.LoadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"GET", "POST", "PUT", "PATCH", "DELETE"}, cfg.HTTP.CORS.AllowedMethods)
+		assert.Equal(t, 12*time.Hour, cfg.HTTP.CORS.MaxAge)
+		assert.Equal(t, config.RateLimit{Requests: 100, Window: time.Minute}, cfg.HTTP.RateLimit.Global)
+		assert.Equal(t, "memory", cfg.HTTP.RateLimit.Store)
+		assert.Equal(t, []string{"api_key", "user", "ip"}, cfg.HTTP.RateLimit.Keys)
+		assert.Equal(t, 10*time.Second, cfg.HTTP.RequestTimeout)
+		assert.Equal(t, "jwt", cfg.Auth.Mode)
+		assert.Equal(t, []string{"roles", "groups", "realm_access.roles"}, cfg.Auth.OIDC.RoleClaims)
+		assert.Equal(t, config.TenancyConfig{Header: "X-Tenant-ID"}, cfg.Tenancy)
+		assert.Equal(t, config.CacheConfig{Store: "memory", TTL: time.Minute, Size: 10000, Channel: "library.cache"}, cfg.Cache)
+		assert.Equal(t, config.HTTPCacheConfig{Routes: map[string]config.CachePolicy{
+			"/books":   {MaxAge: time.Minute, StaleWhileRevalidate: 30 * time.Second},
+			"/authors": {MaxAge: time.Minute, StaleWhileRevalidate: 30 * time.Second},
+		}}, cfg.HTTP.Cache)
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("HTTP_CORS_ALLOWED_ORIGINS", "https://*.example.com,https://example.com")
+		t.Setenv("HTTP_CORS_ALLOW_CREDENTIALS", "true")
+		t.Setenv("HTTP_RATE_LIMIT_GLOBAL", "1000/1h")
+		t.Setenv("HTTP_RATE_LIMIT_ROUTES", "/books=20/1m,/graphql=60/30s")
+		t.Setenv("HTTP_WRITE_TIMEOUT", "30s")
+		t.Setenv("HTTP_RATE_LIMIT_STORE", "redis")
+		t.Setenv("HTTP_RATE_LIMIT_PLANS", "free=100/1h,pro=5000/1h")
+		t.Setenv("HTTP_RATE_LIMIT_PLAN_ROUTES", "pro:/graphql=600/1m")
+		t.Setenv("HTTP_RATE_LIMIT_CLIENTS", "user:42=pro,ip:192.0.2.1=free")
+		t.Setenv("REDIS_URL", "redis://localhost:6379/0")
+		t.Setenv("AUTH_MODE", "oidc")
+		t.Setenv("AUTH_OIDC_ISSUER", "https://auth.example.com/realms/library")
+		t.Setenv("AUTH_OIDC_ROLE_SCOPES", "admin=api_keys:admin books:write,librarian=books:write")
+		t.Setenv("TENANCY_ENABLED", "true")
+		t.Setenv("TENANCY_BASE_DOMAIN", "library.example.com")
+		t.Setenv("HTTP_CACHE_ROUTES", "/books=5m/1m,/authors=1h")
+		t.Setenv("HTTP_CACHE_MEMORY_SIZE", "500")
+
+		cfg, err := config.LoadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://*.example.com", "https://example.com"}, cfg.HTTP.CORS.AllowedOrigins)
+		assert.Equal(t, config.RateLimit{Requests: 1000, Window: time.Hour}, cfg.HTTP.RateLimit.Global)
+		assert.Equal(t, map[string]config.RateLimit{
+			"/books":   {Requests: 20, Window: time.Minute},
+			"/graphql": {Requests: 60, Window: 30 * time.Second},
+		}, cfg.HTTP.RateLimit.Routes)
+		assert.Equal(t, 30*time.Second, cfg.HTTP.WriteTimeout)
+		assert.Equal(t, "redis", cfg.HTTP.RateLimit.Store)
+		assert.Equal(t, config.RateLimit{Requests: 5000, Window: time.Hour}, cfg.HTTP.RateLimit.Plans["pro"])
+		assert.Equal(t, map[string]config.RateLimit{"pro:/graphql": {Requests: 600, Window: time.Minute}}, cfg.HTTP.RateLimit.PlanRoutes)
+		assert.Equal(t, map[string]string{"user:42": "pro", "ip:192.0.2.1": "free"}, cfg.HTTP.RateLimit.Clients)
+		assert.Equal(t, "redis://localhost:6379/0", cfg.Redis.URL)
+		assert.Equal(t, "https://auth.example.com/realms/library", cfg.Auth.OIDC.Issuer)
+		assert.Equal(t, map[string]string{"admin": "api_keys:admin books:write", "librarian": "books:write"}, cfg.Auth.OIDC.RoleScopes)
+		assert.Equal(t, config.TenancyConfig{Enabled: true, Header: "X-Tenant-ID", BaseDomain: "library.example.com"}, cfg.Tenancy)
+		assert.Equal(t, config.HTTPCacheConfig{
+			Routes: map[string]config.CachePolicy{
+				"/books":   {MaxAge: 5 * time.Minute, StaleWhileRevalidate: time.Minute},
+				"/authors": {MaxAge: time.Hour},
+			},
+			MemorySize: 500,
+		}, cfg.HTTP.Cache)
+	})
+
+	t.Run("error malformed rate limit", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("HTTP_RATE_LIMIT_ROUTES", "/books=20")
+
+		_, err := config.LoadConfig()
+
+		assert.ErrorContains(t, err, `rate limit "20" should be in "requests/window" format`)
+	})
+
+	t.Run("error malformed cache policy", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("HTTP_CACHE_ROUTES", "/books=1m/soon")
+
+		_, err := config.LoadConfig()
+
+		assert.ErrorContains(t, err, `cache policy "1m/soon": invalid stale-while-revalidate`)
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() config.Config {
+		return config.Config{HTTP: config.HTTPConfig{
+			CORS: config.CORSConfig{
+				AllowedOrigins: []string{"https://*.example.com"},
+				AllowedMethods: []string{"GET", "PATCH"},
+				AllowedHeaders: []string{"Authorization"},
+				MaxAge:         time.Hour,
+			},
+			RateLimit: config.RateLimitConfig{
+				Global: config.RateLimit{Requests: 100, Window: time.Minute},
+				Routes: map[string]config.RateLimit{"/books": {Requests: 10, Window: time.Minute}},
+			},
+		}}
+	}
+
+	tests := []struct {
+		name          string
+		configure     func(cfg *config.Config)
+		expectedError string
+	}{
+		{
+			name:      "success",
+			configure: func(*config.Config) {},
+		},
+		{
+			name:      "success zero values",
+			configure: func(cfg *config.Config) { cfg.HTTP = config.HTTPConfig{} },
+		},
+		{
+			name: "error wildcard with credentials",
+			configure: func(cfg *config.Config) {
+				cfg.HTTP.CORS.AllowedOrigins = []string{"*"}
+				cfg.HTTP.CORS.AllowCredentials = true
+			},
+			expectedError: "HTTP_CORS_ALLOWED_ORIGINS: * cannot be used with HTTP_CORS_ALLOW_CREDENTIALS",
+		},
+		{
+			name:          "error origin with path",
+			configure:     func(cfg *config.Config) { cfg.HTTP.CORS.AllowedOrigins = []string{"https://example.com/app"} },
+			expectedError: `origin "https://example.com/app" must be a scheme and a host`,
+		},
+		{
+			name:          "error origin with two wildcards",
+			configure:     func(cfg *config.Config) { cfg.HTTP.CORS.AllowedOrigins = []string{"https://*.*.example.com"} },
+			expectedError: `origin "https://*.*.example.com" may hold one wildcard only`,
+		},
+		{
+			name:          "error unknown method",
+			configure:     func(cfg *config.Config) { cfg.HTTP.CORS.AllowedMethods = []string{"patch"} },
+			expectedError: `HTTP_CORS_ALLOWED_METHODS: unknown method "patch"`,
+		},
+		{
+			name:          "error negative max age",
+			configure:     func(cfg *config.Config) { cfg.HTTP.CORS.MaxAge = -time.Second },
+			expectedError: "HTTP_CORS_MAX_AGE must not be negative, got -1s",
+		},
+		{
+			name:          "error negative timeout",
+			configure:     func(cfg *config.Config) { cfg.HTTP.ReadTimeout = -time.Second },
+			expectedError: "HTTP_READ_TIMEOUT must not be negative, got -1s",
+		},
+		{
+			name:          "error rate limit without window",
+			configure:     func(cfg *config.Config) { cfg.HTTP.RateLimit.Global.Window = 0 },
+			expectedError: "HTTP_RATE_LIMIT_GLOBAL: window must be positive, got 0s",
+		},
+		{
+			name: "error route without slash",
+			configure: func(cfg *config.Config) {
+				cfg.HTTP.RateLimit.Routes = map[string]config.RateLimit{"books": {Requests: 10, Window: time.Minute}}
+			},
+			expectedError: `HTTP_RATE_LIMIT_ROUTES: route "books" must start with /`,
+		},
+		{
+			name:          "error unknown rate limit key",
+			configure:     func(cfg *config.Config) { cfg.HTTP.RateLimit.Keys = []string{"token"} },
+			expectedError: `HTTP_RATE_LIMIT_KEYS: unknown key "token"`,
+		},
+		{
+			name:          "error redis store without url",
+			configure:     func(cfg *config.Config) { cfg.HTTP.RateLimit.Store = "redis" },
+			expectedError: "HTTP_RATE_LIMIT_STORE: the redis store needs REDIS_URL",
+		},
+		{
+			name:          "error client of an unknown plan",
+			configure:     func(cfg *config.Config) { cfg.HTTP.RateLimit.Clients = map[string]string{"user:42": "gold"} },
+			expectedError: `HTTP_RATE_LIMIT_CLIENTS: client "user:42" has the unknown plan "gold"`,
+		},
+		{
+			name: "error plan route of an unknown plan",
+			configure: func(cfg *config.Config) {
+				cfg.HTTP.RateLimit.PlanRoutes = map[string]config.RateLimit{"gold:/books": {Requests: 100, Window: time.Minute}}
+			},
+			expectedError: `HTTP_RATE_LIMIT_PLAN_ROUTES: "gold:/books" names the unknown plan "gold"`,
+		},
+		{
+			name: "error plan route without route limit",
+			configure: func(cfg *config.Config) {
+				cfg.HTTP.RateLimit.Plans = map[string]config.RateLimit{"pro": {Requests: 5000, Window: time.Hour}}
+				cfg.HTTP.RateLimit.PlanRoutes = map[string]config.RateLimit{"pro:/authors": {Requests: 100, Window: time.Minute}}
+			},
+			expectedError: `HTTP_RATE_LIMIT_PLAN_ROUTES: "pro:/authors" names the route "/authors", missing from HTTP_RATE_LIMIT_ROUTES`,
+		},
+		{
+			name: "error cache policy without max age",
+			configure: func(cfg *config.Config) {
+				cfg.HTTP.Cache.Routes = map[string]config.CachePolicy{"/books": {StaleWhileRevalidate: time.Minute}}
+			},
+			expectedError: "HTTP_CACHE_ROUTES /books: max age must be positive, got 0s",
+		},
+		{
+			name:          "error negative memory size",
+			configure:     func(cfg *config.Config) { cfg.HTTP.Cache.MemorySize = -1 },
+			expectedError: "HTTP_CACHE_MEMORY_SIZE must not be negative, got -1",
+		},
+		{
+			name: "success oidc mode",
+			configure: func(cfg *config.Config) {
+				cfg.Auth = config.AuthConfig{Mode: "oidc", OIDC: config.OIDCConfig{Issuer: "https://auth.example.com", JWKSCacheTTL: time.Hour}}
+			},
+		},
+		{
+			name:          "error unknown auth mode",
+			configure:     func(cfg *config.Config) { cfg.Auth.Mode = "saml" },
+			expectedError: `AUTH_MODE: unknown mode "saml"`,
+		},
+		{
+			name: "error oidc mode without issuer",
+			configure: func(cfg *config.Config) {
+				cfg.Auth = config.AuthConfig{Mode: "oidc", OIDC: config.OIDCConfig{JWKSCacheTTL: time.Hour}}
+			},
+			expectedError: `AUTH_OIDC_ISSUER must be an absolute URL in the oidc mode, got ""`,
+		},
+		{
+			name:          "error unknown cache store",
+			configure:     func(cfg *config.Config) { cfg.Cache.Store = "memcached" },
+			expectedError: `CACHE_STORE: unknown store "memcached"`,
+		},
+		{
+			name:          "error cache without ttl",
+			configure:     func(cfg *config.Config) { cfg.Cache = config.CacheConfig{Store: "memory", Size: 100} },
+			expectedError: "CACHE_TTL must be positive, got 0s",
+		},
+		{
+			name:          "error redis cache without redis",
+			configure:     func(cfg *config.Config) { cfg.Cache = config.CacheConfig{Store: "redis", TTL: time.Minute} },
+			expectedError: "CACHE_STORE: the redis store needs REDIS_URL",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := valid()
+			test.configure(&cfg)
+
+			err := cfg.Validate()
+
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, test.expectedError)
+		})
+	}
+}
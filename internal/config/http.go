@@ -0,0 +1,281 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit allows Requests per Window, written requests/window as in 100/1m.
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+func (l *RateLimit) UnmarshalText(text []byte) error {
+	requests, window, found := strings.Cut(string(text), "/")
+	if !found {
+		return fmt.Errorf("rate limit %q should be in \"requests/window\" format", text)
+	}
+
+	var err error
+	if l.Requests, err = strconv.Atoi(requests); err != nil {
+		return fmt.Errorf("rate limit %q: invalid requests: %w", text, err)
+	}
+	if l.Window, err = time.ParseDuration(window); err != nil {
+		return fmt.Errorf("rate limit %q: invalid window: %w", text, err)
+	}
+
+	return nil
+}
+
+func (l RateLimit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Window)
+}
+
+// CachePolicy lets the caches keep a response MaxAge, then serve it stale while they
+// revalidate it for StaleWhileRevalidate, written maxAge[/staleWhileRevalidate] as in 1m/30s.
+type CachePolicy struct {
+	MaxAge               time.Duration
+	StaleWhileRevalidate time.Duration
+}
+
+func (p *CachePolicy) UnmarshalText(text []byte) error {
+	maxAge, stale, found := strings.Cut(string(text), "/")
+
+	var err error
+	if p.MaxAge, err = time.ParseDuration(maxAge); err != nil {
+		return fmt.Errorf("cache policy %q: invalid max age: %w", text, err)
+	}
+	p.StaleWhileRevalidate = 0
+	if found {
+		if p.StaleWhileRevalidate, err = time.ParseDuration(stale); err != nil {
+			return fmt.Errorf("cache policy %q: invalid stale-while-revalidate: %w", text, err)
+		}
+	}
+
+	return nil
+}
+
+func (p CachePolicy) String() string {
+	if p.StaleWhileRevalidate == 0 {
+		return p.MaxAge.String()
+	}
+	return fmt.Sprintf("%s/%s", p.MaxAge, p.StaleWhileRevalidate)
+}
+
+var (
+	corsMethods     = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	rateLimitKeys   = []string{"api_key", "user", "ip"}
+	rateLimitStores = []string{"memory", "redis"}
+	authModes       = []string{"jwt", "oidc"}
+	cacheStores     = []string{"none", "memory", "redis"}
+)
+
+// Validate reports the invalid settings of the configuration, all at once.
+func (c Config) Validate() error {
+	errs := []error{c.HTTP.validate()}
+	if c.HTTP.RateLimit.Store == "redis" && c.Redis.URL == "" {
+		errs = append(errs, errors.New("HTTP_RATE_LIMIT_STORE: the redis store needs REDIS_URL"))
+	}
+	errs = append(errs, c.Auth.validate(), c.Cache.validate())
+	if c.Cache.Store == "redis" && c.Redis.URL == "" {
+		errs = append(errs, errors.New("CACHE_STORE: the redis store needs REDIS_URL"))
+	}
+	return errors.Join(errs...)
+}
+
+func (c CacheConfig) validate() error {
+	switch c.Store {
+	case "", "none":
+		return nil
+	case "memory", "redis":
+	default:
+		return fmt.Errorf("CACHE_STORE: unknown store %q, expected one of %s", c.Store, strings.Join(cacheStores, ", "))
+	}
+
+	var errs []error
+	if c.TTL <= 0 {
+		errs = append(errs, fmt.Errorf("CACHE_TTL must be positive, got %s", c.TTL))
+	}
+	if c.Store == "memory" && c.Size <= 0 {
+		errs = append(errs, fmt.Errorf("CACHE_SIZE must be positive, got %d", c.Size))
+	}
+	return errors.Join(errs...)
+}
+
+func (c AuthConfig) validate() error {
+	switch c.Mode {
+	case "", "jwt":
+		return nil
+	case "oidc":
+	default:
+		return fmt.Errorf("AUTH_MODE: unknown mode %q, expected one of %s", c.Mode, strings.Join(authModes, ", "))
+	}
+
+	var errs []error
+	if u, err := url.Parse(c.OIDC.Issuer); err != nil || u.Scheme == "" || u.Host == "" {
+		errs = append(errs, fmt.Errorf("AUTH_OIDC_ISSUER must be an absolute URL in the oidc mode, got %q", c.OIDC.Issuer))
+	}
+	if c.OIDC.JWKSCacheTTL <= 0 {
+		errs = append(errs, fmt.Errorf("AUTH_OIDC_JWKS_CACHE_TTL must be positive, got %s", c.OIDC.JWKSCacheTTL))
+	}
+	if c.OIDC.JWKSMinRefresh < 0 {
+		errs = append(errs, fmt.Errorf("AUTH_OIDC_JWKS_MIN_REFRESH must not be negative, got %s", c.OIDC.JWKSMinRefresh))
+	}
+	return errors.Join(errs...)
+}
+
+func (c HTTPConfig) validate() error {
+	var errs []error
+
+	durations := map[string]time.Duration{
+		"HTTP_READ_TIMEOUT":        c.ReadTimeout,
+		"HTTP_READ_HEADER_TIMEOUT": c.ReadHeaderTimeout,
+		"HTTP_WRITE_TIMEOUT":       c.WriteTimeout,
+		"HTTP_IDLE_TIMEOUT":        c.IdleTimeout,
+		"HTTP_REQUEST_TIMEOUT":     c.RequestTimeout,
+		"HTTP_CORS_MAX_AGE":        c.CORS.MaxAge,
+	}
+	for name, d := range durations {
+		if d < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative, got %s", name, d))
+		}
+	}
+	if c.MaxHeaderBytes < 0 {
+		errs = append(errs, fmt.Errorf("HTTP_MAX_HEADER_BYTES must not be negative, got %d", c.MaxHeaderBytes))
+	}
+	if c.MaxInFlight < 0 {
+		errs = append(errs, fmt.Errorf("HTTP_MAX_IN_FLIGHT must not be negative, got %d", c.MaxInFlight))
+	}
+
+	errs = append(errs, c.CORS.validate()...)
+
+	errs = append(errs, c.RateLimit.validate()...)
+
+	errs = append(errs, c.Cache.validate()...)
+
+	return errors.Join(errs...)
+}
+
+func (c HTTPCacheConfig) validate() []error {
+	var errs []error
+
+	for route, policy := range c.Routes {
+		if !strings.HasPrefix(route, "/") {
+			errs = append(errs, fmt.Errorf("HTTP_CACHE_ROUTES: route %q must start with /", route))
+		}
+		if policy.MaxAge <= 0 {
+			errs = append(errs, fmt.Errorf("HTTP_CACHE_ROUTES %s: max age must be positive, got %s", route, policy.MaxAge))
+		}
+		if policy.StaleWhileRevalidate < 0 {
+			errs = append(errs, fmt.Errorf("HTTP_CACHE_ROUTES %s: stale-while-revalidate must not be negative, got %s", route, policy.StaleWhileRevalidate))
+		}
+	}
+	if c.MemorySize < 0 {
+		errs = append(errs, fmt.Errorf("HTTP_CACHE_MEMORY_SIZE must not be negative, got %d", c.MemorySize))
+	}
+
+	return errs
+}
+
+func (c RateLimitConfig) validate() []error {
+	var errs []error
+
+	if c.Store != "" && !slices.Contains(rateLimitStores, c.Store) {
+		errs = append(errs, fmt.Errorf("HTTP_RATE_LIMIT_STORE: unknown store %q, expected one of %s", c.Store, strings.Join(rateLimitStores, ", ")))
+	}
+	for _, key := range c.Keys {
+		if !slices.Contains(rateLimitKeys, key) {
+			errs = append(errs, fmt.Errorf("HTTP_RATE_LIMIT_KEYS: unknown key %q, expected one of %s", key, strings.Join(rateLimitKeys, ", ")))
+		}
+	}
+
+	errs = append(errs, c.Global.validate("HTTP_RATE_LIMIT_GLOBAL"))
+	for route, limit := range c.Routes {
+		if !strings.HasPrefix(route, "/") {
+			errs = append(errs, fmt.Errorf("HTTP_RATE_LIMIT_ROUTES: route %q must start with /", route))
+		}
+		errs = append(errs, limit.validate("HTTP_RATE_LIMIT_ROUTES "+route))
+	}
+	for plan, limit := range c.Plans {
+		errs = append(errs, limit.validate("HTTP_RATE_LIMIT_PLANS "+plan))
+	}
+	for key, limit := range c.PlanRoutes {
+		plan, route, _ := strings.Cut(key, ":")
+		if _, ok := c.Plans[plan]; !ok {
+			errs = append(errs, fmt.Errorf("HTTP_RATE_LIMIT_PLAN_ROUTES: %q names the unknown plan %q", key, plan))
+		}
+		// The route limiter is only mounted on the routes of HTTP_RATE_LIMIT_ROUTES.
+		if _, ok := c.Routes[route]; !ok {
+			errs = append(errs, fmt.Errorf("HTTP_RATE_LIMIT_PLAN_ROUTES: %q names the route %q, missing from HTTP_RATE_LIMIT_ROUTES", key, route))
+		}
+		errs = append(errs, limit.validate("HTTP_RATE_LIMIT_PLAN_ROUTES "+key))
+	}
+	for client, plan := range c.Clients {
+		if _, ok := c.Plans[plan]; !ok {
+			errs = append(errs, fmt.Errorf("HTTP_RATE_LIMIT_CLIENTS: client %q has the unknown plan %q", client, plan))
+		}
+	}
+
+	return errs
+}
+
+func (c CORSConfig) validate() []error {
+	var errs []error
+
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			if c.AllowCredentials {
+				errs = append(errs, errors.New("HTTP_CORS_ALLOWED_ORIGINS: * cannot be used with HTTP_CORS_ALLOW_CREDENTIALS"))
+			}
+			continue
+		}
+		if err := validateOrigin(origin); err != nil {
+			errs = append(errs, fmt.Errorf("HTTP_CORS_ALLOWED_ORIGINS: %w", err))
+		}
+	}
+
+	for _, method := range c.AllowedMethods {
+		if !slices.Contains(corsMethods, method) {
+			errs = append(errs, fmt.Errorf("HTTP_CORS_ALLOWED_METHODS: unknown method %q, expected one of %s", method, strings.Join(corsMethods, ", ")))
+		}
+	}
+
+	for _, header := range c.AllowedHeaders {
+		if header == "" || strings.ContainsAny(header, " \t:") {
+			errs = append(errs, fmt.Errorf("HTTP_CORS_ALLOWED_HEADERS: invalid header name %q", header))
+		}
+	}
+
+	return errs
+}
+
+// validateOrigin accepts a scheme and a host, the host holding at most one wildcard.
+func validateOrigin(origin string) error {
+	if strings.Count(origin, "*") > 1 {
+		return fmt.Errorf("origin %q may hold one wildcard only", origin)
+	}
+
+	u, err := url.Parse(strings.Replace(origin, "*", "wildcard", 1))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" || u.RawQuery != "" {
+		return fmt.Errorf("origin %q must be a scheme and a host, such as https://*.example.com", origin)
+	}
+
+	return nil
+}
+
+func (l RateLimit) validate(name string) error {
+	switch {
+	case l.Requests < 0:
+		return fmt.Errorf("%s: requests must not be negative, got %d", name, l.Requests)
+	case l.Requests > 0 && l.Window <= 0:
+		return fmt.Errorf("%s: window must be positive, got %s", name, l.Window)
+	default:
+		return nil
+	}
+}
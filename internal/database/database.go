@@ -11,6 +11,7 @@ import (
 
 	"go-boilerplate-rest-api-chi/internal/config"
 	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/tenant"
 )
 
 type Database struct {
@@ -46,21 +47,94 @@ func Init(cfg config.Config, logger zerolog.Logger) (*Database, error) {
 	sqlDB.SetMaxIdleConns(10)
 	sqlDB.SetMaxOpenConns(10)
 
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		logger.Error().Err(err).Msg("Failed to register the tenant plugin")
+		return nil, err
+	}
+
+	if err := renameTenantColumns(db); err != nil {
+		logger.Error().Err(err).Msg("tenant column migration failed")
+		return nil, err
+	}
+
 	if err := db.AutoMigrate(
 		// Models
+		&entity.Tenant{},
 		&entity.Book{},
 		&entity.Author{},
+		&entity.WebhookSubscription{},
+		&entity.WebhookDelivery{},
+		&entity.OutboxMessage{},
+		&entity.APIKey{},
 	); err != nil {
 		logger.Error().Err(err).Msg("auto-migration failed")
 		return nil, err
 	}
 
+	if err := migrateTenants(db); err != nil {
+		logger.Error().Err(err).Msg("tenant migration failed")
+		return nil, err
+	}
+
 	return &Database{
 		Gorm:  db,
 		sqlDB: sqlDB,
 	}, nil
 }
 
+// migrateTenants creates the default tenant, owning the catalogue created before the tenants,
+// drops the unique indexes of the titles and of the author names over every tenant, now
+// unique per tenant, and the indexes of the renamed tenant columns of the webhooks.
+func migrateTenants(db *gorm.DB) error {
+	err := db.Where(entity.Tenant{ID: entity.DefaultTenantID}).
+		Attrs(entity.Tenant{Name: "Default"}).
+		FirstOrCreate(&entity.Tenant{}).Error
+	if err != nil {
+		return err
+	}
+
+	migrator := db.Migrator()
+	for model, indexes := range map[any][]string{
+		&entity.Book{}:                {"idx_books_title"},
+		&entity.Author{}:              {"uni_authors_name", "name"},
+		&entity.WebhookSubscription{}: {"idx_webhook_subscriptions_tenant"},
+		&entity.WebhookDelivery{}:     {"idx_webhook_deliveries_tenant"},
+	} {
+		for _, index := range indexes {
+			if !migrator.HasIndex(model, index) {
+				continue
+			}
+			if err := migrator.DropIndex(model, index); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// renameTenantColumns renames the tenant columns of the webhooks, the outbox and the API keys
+// to tenant_id, now scoped by tenant.Plugin. It runs before the auto-migration, which would
+// add an empty tenant_id column next to them.
+func renameTenantColumns(db *gorm.DB) error {
+	migrator := db.Migrator()
+	for _, model := range []any{
+		&entity.WebhookSubscription{},
+		&entity.WebhookDelivery{},
+		&entity.OutboxMessage{},
+		&entity.APIKey{},
+	} {
+		if !migrator.HasColumn(model, "tenant") {
+			continue
+		}
+		if err := migrator.RenameColumn(model, "tenant", "TenantID"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (d *Database) Close() error {
 	if d.sqlDB != nil {
 		return d.sqlDB.Close()
@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+const (
+	maxTxAttempts = 3
+	txRetryDelay  = 20 * time.Millisecond
+
+	mysqlLockWaitTimeout = 1205
+	mysqlDeadlock        = 1213
+)
+
+type (
+	txKey          struct{}
+	afterCommitKey struct{}
+)
+
+// afterCommit holds the hooks registered within a transaction.
+type afterCommit struct {
+	hooks []func()
+}
+
+// TxManager is the unit of work of the services: the repositories called with the ctx given
+// to fn share one transaction, see Conn.
+//
+//go:generate mockgen -destination=../mocks/mock_tx_manager.go -package=mocks go-boilerplate-rest-api-chi/internal/database TxManager
+type TxManager interface {
+	// WithinTransaction commits when fn returns nil and rolls back otherwise. Called within
+	// another transaction, it only rolls back to a savepoint. The outermost transaction is
+	// run again on deadlock or serialization failure, so fn must not have side effects
+	// outside the database: it defers them with AfterCommit.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type gormTxManager struct {
+	db     *gorm.DB
+	logger zerolog.Logger
+}
+
+func NewTxManager(db *gorm.DB, logger zerolog.Logger) TxManager {
+	return &gormTxManager{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (m *gormTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	var hooks *afterCommit
+	run := func(tx *gorm.DB) error {
+		// Every attempt registers its own hooks.
+		hooks = &afterCommit{}
+		ctx := context.WithValue(ctx, txKey{}, tx)
+		return fn(context.WithValue(ctx, afterCommitKey{}, hooks))
+	}
+
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		if err := tx.WithContext(ctx).Transaction(run); err != nil {
+			return err
+		}
+		// The hooks of a savepoint wait for the outermost transaction.
+		if parent, ok := ctx.Value(afterCommitKey{}).(*afterCommit); ok {
+			parent.hooks = append(parent.hooks, hooks.hooks...)
+		}
+		return nil
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := m.db.WithContext(ctx).Transaction(run)
+		if err == nil {
+			for _, hook := range hooks.hooks {
+				hook()
+			}
+			return nil
+		}
+		if !IsRetryable(err) || attempt == maxTxAttempts {
+			return err
+		}
+
+		m.logger.Warn().Err(err).Int("attempt", attempt).Msg("transaction aborted, retrying")
+
+		delay := time.Duration(attempt)*txRetryDelay + rand.N(txRetryDelay)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// Conn returns the transaction carried by ctx, or db outside of a transaction. Repositories
+// use it in place of db.WithContext(ctx) to take part in the unit of work.
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
+
+// InTransaction reports whether ctx carries a transaction of a TxManager.
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return ok
+}
+
+// AfterCommit runs fn once the transaction carried by ctx commits, and drops it when the
+// transaction, or the savepoint it was registered in, rolls back. Outside of a transaction, fn
+// runs immediately.
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(afterCommitKey{}).(*afterCommit)
+	if !ok {
+		fn()
+		return
+	}
+
+	hooks.hooks = append(hooks.hooks, fn)
+}
+
+// IsRetryable reports whether err aborted the transaction because of a deadlock, a lock wait
+// timeout or a serialization failure, after which the whole transaction can be run again.
+func IsRetryable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+
+	return mysqlErr.Number == mysqlDeadlock ||
+		mysqlErr.Number == mysqlLockWaitTimeout ||
+		mysqlErr.SQLState == [5]byte{'4', '0', '0', '0', '1'}
+}
@@ -0,0 +1,260 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"go-boilerplate-rest-api-chi/internal/database"
+	"go-boilerplate-rest-api-chi/internal/test-utils"
+)
+
+var errBoom = errors.New("boom")
+
+func TestTxManager_WithinTransaction(t *testing.T) {
+	deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+
+	tests := []struct {
+		name             string
+		configureMock    func(sqlmock.Sqlmock)
+		fn               func(db *gorm.DB) func(ctx context.Context) error
+		expectedError    error
+		expectedAttempts int
+	}{
+		{
+			name: "success commit",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM books").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(db *gorm.DB) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					return database.Conn(ctx, db).Exec("DELETE FROM books").Error
+				}
+			},
+			expectedAttempts: 1,
+		},
+		{
+			name: "error rollback",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM books").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectRollback()
+			},
+			fn: func(db *gorm.DB) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					if err := database.Conn(ctx, db).Exec("DELETE FROM books").Error; err != nil {
+						return err
+					}
+					return errBoom
+				}
+			},
+			expectedError:    errBoom,
+			expectedAttempts: 1,
+		},
+		{
+			name: "success nested transaction rolls back to its savepoint",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM authors").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("ROLLBACK TO SAVEPOINT sp").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM books").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(db *gorm.DB) func(ctx context.Context) error {
+				txManager := database.NewTxManager(db, zerolog.Nop())
+
+				return func(ctx context.Context) error {
+					err := txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+						if err := database.Conn(ctx, db).Exec("DELETE FROM authors").Error; err != nil {
+							return err
+						}
+						return errBoom
+					})
+					if !errors.Is(err, errBoom) {
+						return fmt.Errorf("unexpected nested error: %w", err)
+					}
+
+					return database.Conn(ctx, db).Exec("DELETE FROM books").Error
+				}
+			},
+			expectedAttempts: 1,
+		},
+		{
+			name: "success retried after a deadlock",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM books").WillReturnError(deadlock)
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM books").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(db *gorm.DB) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					return database.Conn(ctx, db).Exec("DELETE FROM books").Error
+				}
+			},
+			expectedAttempts: 2,
+		},
+		{
+			name: "error deadlock on every attempt",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				for range 3 {
+					mock.ExpectBegin()
+					mock.ExpectExec("DELETE FROM books").WillReturnError(deadlock)
+					mock.ExpectRollback()
+				}
+			},
+			fn: func(db *gorm.DB) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					return database.Conn(ctx, db).Exec("DELETE FROM books").Error
+				}
+			},
+			expectedError:    deadlock,
+			expectedAttempts: 3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := testutils.NewGormMySQL(t)
+			test.configureMock(mock)
+
+			txManager := database.NewTxManager(db, zerolog.Nop())
+			fn := test.fn(db)
+
+			attempts := 0
+			err := txManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+				attempts++
+				assert.True(t, database.InTransaction(ctx))
+				return fn(ctx)
+			})
+
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedAttempts, attempts)
+
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAfterCommit(t *testing.T) {
+	tests := []struct {
+		name          string
+		configureMock func(sqlmock.Sqlmock)
+		fn            func(txManager database.TxManager, record func(string)) func(ctx context.Context) error
+		expectedHooks []string
+	}{
+		{
+			name: "success run after the commit",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			fn: func(_ database.TxManager, record func(string)) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					database.AfterCommit(ctx, func() { record("invalidate") })
+					record("write")
+					return nil
+				}
+			},
+			expectedHooks: []string{"write", "invalidate"},
+		},
+		{
+			name: "success dropped on rollback",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			fn: func(_ database.TxManager, record func(string)) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					database.AfterCommit(ctx, func() { record("invalidate") })
+					return errBoom
+				}
+			},
+		},
+		{
+			name: "success nested hooks wait for the outermost commit",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("SAVEPOINT sp").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ROLLBACK TO SAVEPOINT sp").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			fn: func(txManager database.TxManager, record func(string)) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					_ = txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+						database.AfterCommit(ctx, func() { record("committed savepoint") })
+						return nil
+					})
+					_ = txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+						database.AfterCommit(ctx, func() { record("rolled back savepoint") })
+						return errBoom
+					})
+					record("write")
+					return nil
+				}
+			},
+			expectedHooks: []string{"write", "committed savepoint"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := testutils.NewGormMySQL(t)
+			test.configureMock(mock)
+
+			var hooks []string
+			txManager := database.NewTxManager(db, zerolog.Nop())
+			_ = txManager.WithinTransaction(context.Background(), test.fn(txManager, func(hook string) {
+				hooks = append(hooks, hook)
+			}))
+
+			assert.Equal(t, test.expectedHooks, hooks)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("success run immediately outside of a transaction", func(t *testing.T) {
+		run := false
+		database.AfterCommit(context.Background(), func() { run = true })
+		assert.True(t, run)
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "deadlock", err: &mysql.MySQLError{Number: 1213}, expected: true},
+		{name: "lock wait timeout", err: &mysql.MySQLError{Number: 1205}, expected: true},
+		{name: "serialization failure", err: &mysql.MySQLError{Number: 3101, SQLState: [5]byte{'4', '0', '0', '0', '1'}}, expected: true},
+		{name: "wrapped deadlock", err: fmt.Errorf("create book: %w", &mysql.MySQLError{Number: 1213}), expected: true},
+		{name: "duplicate entry", err: &mysql.MySQLError{Number: 1062}, expected: false},
+		{name: "other error", err: gorm.ErrInvalidDB, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, database.IsRetryable(test.err))
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKey authenticates a machine client. Only the hash of the key is stored; its prefix, part
+// of the key, finds it and stays visible to tell the keys apart.
+//
+// TenantID binds the key to the catalogue of a tenant, the keys without one serving the whole
+// deployment. The keys are looked up across the tenants, before the tenant of the request is
+// known, see tenant.AllTenants.
+type APIKey struct {
+	ID         uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	Name       string    `gorm:"not null"`
+	Prefix     string    `gorm:"type:varchar(32);not null;uniqueIndex"`
+	Hash       string    `gorm:"type:char(64);not null"`
+	Scopes     string    `gorm:"not null"` // space separated, as the scope claim of the tokens
+	TenantID   string    `gorm:"size:63"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (k *APIKey) BeforeCreate(_ *gorm.DB) error {
+	k.ID = uuid.New()
+	return nil
+}
@@ -9,7 +9,8 @@ import (
 
 type Author struct {
 	ID        uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
-	Name      string    `gorm:"not null;unique"`
+	TenantID  string    `gorm:"type:varchar(63);not null;default:default;uniqueIndex:idx_authors_tenant_name"`
+	Name      string    `gorm:"size:191;not null;uniqueIndex:idx_authors_tenant_name"`
 	Book      []Book
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -19,3 +20,10 @@ func (a *Author) BeforeCreate(_ *gorm.DB) error {
 	a.ID = uuid.New()
 	return nil
 }
+
+// AuthorStats is computed from the books of an author and is not stored.
+type AuthorStats struct {
+	BookCount         int64
+	FirstPublishedAt  *time.Time
+	LatestPublishedAt *time.Time
+}
@@ -9,7 +9,8 @@ import (
 
 type Book struct {
 	ID          uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
-	Title       string    `gorm:"not null;uniqueIndex"`
+	TenantID    string    `gorm:"type:varchar(63);not null;default:default;uniqueIndex:idx_books_tenant_title"`
+	Title       string    `gorm:"not null;uniqueIndex:idx_books_tenant_title"`
 	Description string    `gorm:"not null"`
 	AuthorID    uuid.UUID `gorm:"type:char(36);not null"`
 	Author      *Author   `gorm:"foreignKey:AuthorID"`
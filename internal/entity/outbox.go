@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxMessage is a domain event recorded in the transaction of the change it describes,
+// waiting to be relayed. The auto-incremented ID gives the order of the events.
+//
+// DeliveredSinks names, comma-separated, the sinks that already accepted the message, so a
+// retry skips them. A relay claims the message until LockedUntil; once the relay gives up
+// the message is dead and no longer holds back the later ones of its aggregate.
+type OutboxMessage struct {
+	ID             uint64     `gorm:"primaryKey;autoIncrement"`
+	EventID        uuid.UUID  `gorm:"type:char(36);not null;uniqueIndex"`
+	EventType      string     `gorm:"not null"`
+	AggregateID    uuid.UUID  `gorm:"type:char(36);not null;index"`
+	Payload        []byte     `gorm:"not null"`
+	OccurredAt     time.Time  `gorm:"not null"`
+	Actor          string     `gorm:"size:255"`
+	TenantID       string     `gorm:"size:63"`
+	PublishedAt    *time.Time `gorm:"index"`
+	DeadAt         *time.Time `gorm:"index"`
+	DeliveredSinks string     `gorm:"size:255"`
+	Attempts       int        `gorm:"not null;default:0"`
+	NextAttemptAt  *time.Time
+	LockedUntil    *time.Time
+	LastError      string `gorm:"type:text"`
+	CreatedAt      time.Time
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox"
+}
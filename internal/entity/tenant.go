@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// DefaultTenantID is the tenant of the catalogue created before the tenants, and of every
+// request while multi-tenancy is disabled.
+const DefaultTenantID = "default"
+
+// Tenant is a library catalogue hosted by the deployment. Its ID is a slug, the subdomain or
+// the X-Tenant-ID header naming it.
+type Tenant struct {
+	ID         string `gorm:"type:varchar(63);not null;primaryKey"`
+	Name       string `gorm:"not null"`
+	DisabledAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WebhookSubscription receives the events of the catalogue of its tenant. The deliveries of
+// every tenant are sent by the same background dispatcher, see tenant.AllTenants.
+type WebhookSubscription struct {
+	ID         uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	TenantID   string    `gorm:"size:63;not null;default:default;index"`
+	URL        string    `gorm:"not null"`
+	Secret     string    `gorm:"not null"`
+	EventTypes string    `gorm:"not null"` // comma separated, wildcards such as "author.*" allowed
+	Active     bool      `gorm:"not null;default:true"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (w *WebhookSubscription) BeforeCreate(_ *gorm.DB) error {
+	w.ID = uuid.New()
+	return nil
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryDead marks the dead-letter queue: every attempt failed.
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+type WebhookDelivery struct {
+	ID             uuid.UUID             `gorm:"type:char(36);not null;primaryKey"`
+	TenantID       string                `gorm:"size:63;not null;default:default;index"` // the one of the subscription
+	SubscriptionID uuid.UUID             `gorm:"type:char(36);not null;index"`
+	Subscription   *WebhookSubscription  `gorm:"foreignKey:SubscriptionID;constraint:OnDelete:CASCADE"`
+	EventID        uuid.UUID             `gorm:"type:char(36);not null"`
+	EventType      string                `gorm:"not null"`
+	Payload        []byte                `gorm:"not null"`
+	Status         WebhookDeliveryStatus `gorm:"type:varchar(16);not null;index:idx_webhook_deliveries_due,priority:1"`
+	Attempts       int                   `gorm:"not null"`
+	NextAttemptAt  time.Time             `gorm:"not null;index:idx_webhook_deliveries_due,priority:2"`
+	LastStatusCode int
+	LastError      string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (w *WebhookDelivery) BeforeCreate(_ *gorm.DB) error {
+	w.ID = uuid.New()
+	return nil
+}
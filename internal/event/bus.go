@@ -0,0 +1,65 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+type Handler func(ctx context.Context, event Event) error
+
+// Bus delivers events to the in-process subscribers whose pattern matches their type.
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions []*subscription
+}
+
+type subscription struct {
+	pattern string
+	handler Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers handler for the event types selected by pattern (see Match) and
+// returns the function removing it.
+func (b *Bus) Subscribe(pattern string, handler Handler) func() {
+	s := &subscription{pattern: pattern, handler: handler}
+
+	b.mu.Lock()
+	b.subscriptions = append(b.subscriptions, s)
+	b.mu.Unlock()
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		b.subscriptions = slices.DeleteFunc(b.subscriptions, func(other *subscription) bool {
+			return other == s
+		})
+	}
+}
+
+// Publish calls the matching handlers in subscription order. Every handler is called even
+// when one fails, and their errors are joined.
+func (b *Bus) Publish(ctx context.Context, e Event) error {
+	b.mu.RLock()
+	subscriptions := slices.Clone(b.subscriptions)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, s := range subscriptions {
+		if !Match(s.pattern, e.Type) {
+			continue
+		}
+
+		if err := s.handler(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,41 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"go-boilerplate-rest-api-chi/internal/event"
+)
+
+func TestBus_Publish(t *testing.T) {
+	bus := event.NewBus()
+
+	var received []string
+	record := func(name string, err error) event.Handler {
+		return func(_ context.Context, e event.Event) error {
+			received = append(received, name+":"+string(e.Type))
+			return err
+		}
+	}
+
+	bus.Subscribe("book.*", record("books", nil))
+	bus.Subscribe("*", record("all", errors.New("boom")))
+	unsubscribe := bus.Subscribe("author.created", record("authors", nil))
+
+	err := bus.Publish(context.Background(), event.New(event.BookCreated, uuid.New(), nil))
+
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, []string{"books:book.created", "all:book.created"}, received)
+
+	received = nil
+	unsubscribe()
+
+	err = bus.Publish(context.Background(), event.New(event.AuthorCreated, uuid.New(), nil))
+
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, []string{"all:author.created"}, received)
+}
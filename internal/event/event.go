@@ -0,0 +1,77 @@
+package event
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Type string
+
+const (
+	BookCreated   Type = "book.created"
+	BookUpdated   Type = "book.updated"
+	BookDeleted   Type = "book.deleted"
+	AuthorCreated Type = "author.created"
+	AuthorRenamed Type = "author.renamed"
+)
+
+// Types lists every event type recorded in the outbox.
+var Types = []Type{BookCreated, BookUpdated, BookDeleted, AuthorCreated, AuthorRenamed}
+
+type Event struct {
+	ID          uuid.UUID `json:"id"`
+	Type        Type      `json:"type"`
+	AggregateID uuid.UUID `json:"aggregate_id"`
+	OccurredAt  time.Time `json:"occurred_at"`
+	// Actor is the subject of the principal behind the change, if any.
+	Actor string `json:"actor,omitempty"`
+	// Tenant is the tenant of the catalogue changed.
+	Tenant string `json:"tenant,omitempty"`
+	Data   any    `json:"data"`
+}
+
+func New(eventType Type, aggregateID uuid.UUID, data any) Event {
+	return Event{
+		ID:          uuid.New(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		OccurredAt:  time.Now().UTC(),
+		Data:        data,
+	}
+}
+
+// Match reports whether eventType is selected by pattern, which is either an exact type,
+// "*" or a prefix wildcard such as "author.*".
+func Match(pattern string, eventType Type) bool {
+	if pattern == "*" || pattern == string(eventType) {
+		return true
+	}
+
+	if prefix, ok := strings.CutSuffix(pattern, ".*"); ok {
+		return strings.HasPrefix(string(eventType), prefix+".")
+	}
+
+	return false
+}
+
+// ValidPattern reports whether pattern selects at least one known event type.
+func ValidPattern(pattern string) bool {
+	return slices.ContainsFunc(Types, func(eventType Type) bool {
+		return Match(pattern, eventType)
+	})
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// NopPublisher drops every event.
+type NopPublisher struct{}
+
+func (NopPublisher) Publish(context.Context, Event) error {
+	return nil
+}
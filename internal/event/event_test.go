@@ -0,0 +1,38 @@
+package event_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go-boilerplate-rest-api-chi/internal/event"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		eventType event.Type
+		expected  bool
+	}{
+		{pattern: "book.created", eventType: event.BookCreated, expected: true},
+		{pattern: "book.created", eventType: event.BookDeleted, expected: false},
+		{pattern: "author.*", eventType: event.AuthorCreated, expected: true},
+		{pattern: "author.*", eventType: event.AuthorRenamed, expected: true},
+		{pattern: "author.*", eventType: event.BookCreated, expected: false},
+		{pattern: "*", eventType: event.BookUpdated, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern+" "+string(test.eventType), func(t *testing.T) {
+			assert.Equal(t, test.expected, event.Match(test.pattern, test.eventType))
+		})
+	}
+}
+
+func TestValidPattern(t *testing.T) {
+	assert.True(t, event.ValidPattern("book.*"))
+	assert.True(t, event.ValidPattern("author.renamed"))
+	assert.True(t, event.ValidPattern("*"))
+	assert.False(t, event.ValidPattern("genre.*"))
+	assert.False(t, event.ValidPattern("book.published"))
+}
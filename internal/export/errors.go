@@ -0,0 +1,11 @@
+package export
+
+import "errors"
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported export format")
+	ErrJobNotFound       = errors.New("export job not found")
+	ErrJobNotReady       = errors.New("export job not ready")
+	ErrTooManyJobs       = errors.New("too many export jobs for the tenant")
+	ErrQueueFull         = errors.New("export queue full")
+)
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"io"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+	FormatXLSX   Format = "xlsx"
+)
+
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(value)) {
+	case FormatCSV, "":
+		return FormatCSV, nil
+	case FormatNDJSON:
+		return FormatNDJSON, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// Writer encodes rows one by one so exports never hold the whole dataset in memory.
+type Writer interface {
+	WriteRow(values []string) error
+	Flush() error
+	Close() error
+}
+
+// NewWriter returns a Writer for the given format and writes the header straight away.
+func NewWriter(format Format, w io.Writer, header []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, header)
+	case FormatNDJSON:
+		return newNDJSONWriter(w, header), nil
+	case FormatXLSX:
+		return newXLSXWriter(w, header)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
@@ -0,0 +1,125 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/export"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          string
+		expectedFormat export.Format
+		expectedError  error
+	}{
+		{name: "default to csv", value: "", expectedFormat: export.FormatCSV},
+		{name: "csv", value: "csv", expectedFormat: export.FormatCSV},
+		{name: "ndjson case insensitive", value: "NDJSON", expectedFormat: export.FormatNDJSON},
+		{name: "xlsx", value: "xlsx", expectedFormat: export.FormatXLSX},
+		{name: "error unsupported format", value: "pdf", expectedError: export.ErrUnsupportedFormat},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			format, err := export.ParseFormat(test.value)
+
+			assert.ErrorIs(t, err, test.expectedError)
+			assert.Equal(t, test.expectedFormat, format)
+		})
+	}
+}
+
+func TestNewWriter(t *testing.T) {
+	header := []string{"id", "title"}
+	rows := [][]string{
+		{"1", "Les Misérables"},
+		{"2", "Notre-Dame <de> Paris"},
+	}
+
+	tests := []struct {
+		name         string
+		format       export.Format
+		expectedBody string
+	}{
+		{
+			name:         "csv",
+			format:       export.FormatCSV,
+			expectedBody: "id,title\n1,Les Misérables\n2,Notre-Dame <de> Paris\n",
+		},
+		{
+			name:         "ndjson",
+			format:       export.FormatNDJSON,
+			expectedBody: "{\"id\":\"1\",\"title\":\"Les Misérables\"}\n{\"id\":\"2\",\"title\":\"Notre-Dame \\u003cde\\u003e Paris\"}\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+
+			writer, err := export.NewWriter(test.format, &buffer, header)
+			require.NoError(t, err)
+
+			for _, row := range rows {
+				require.NoError(t, writer.WriteRow(row))
+			}
+			require.NoError(t, writer.Close())
+
+			assert.Equal(t, test.expectedBody, buffer.String())
+		})
+	}
+
+	t.Run("xlsx", func(t *testing.T) {
+		var buffer bytes.Buffer
+
+		writer, err := export.NewWriter(export.FormatXLSX, &buffer, header)
+		require.NoError(t, err)
+
+		for _, row := range rows {
+			require.NoError(t, writer.WriteRow(row))
+		}
+		require.NoError(t, writer.Close())
+
+		archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		require.NoError(t, err)
+
+		names := make([]string, 0, len(archive.File))
+		var sheet string
+		for _, file := range archive.File {
+			names = append(names, file.Name)
+			if file.Name == "xl/worksheets/sheet1.xml" {
+				reader, err := file.Open()
+				require.NoError(t, err)
+				content, err := io.ReadAll(reader)
+				require.NoError(t, err)
+				sheet = string(content)
+			}
+		}
+
+		assert.ElementsMatch(t, []string{
+			"[Content_Types].xml",
+			"_rels/.rels",
+			"xl/workbook.xml",
+			"xl/_rels/workbook.xml.rels",
+			"xl/worksheets/sheet1.xml",
+		}, names)
+		assert.Contains(t, sheet, `<row r="1"><c t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`)
+		assert.Contains(t, sheet, `<row r="3">`)
+		assert.Contains(t, sheet, "Notre-Dame &lt;de&gt; Paris")
+		assert.True(t, bytes.HasSuffix([]byte(sheet), []byte("</sheetData></worksheet>")))
+	})
+
+	t.Run("error unsupported format", func(t *testing.T) {
+		writer, err := export.NewWriter("pdf", io.Discard, header)
+
+		assert.ErrorIs(t, err, export.ErrUnsupportedFormat)
+		assert.Nil(t, writer)
+	})
+}
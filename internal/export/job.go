@@ -0,0 +1,307 @@
+package export
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/config"
+)
+
+const (
+	defaultWorkers       = 2
+	defaultQueueSize     = 32
+	defaultMaxPerTenant  = 2
+	defaultTTL           = time.Hour
+	defaultPurgeInterval = 5 * time.Minute
+)
+
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+)
+
+// Job exports the catalogue of Tenant, the only tenant it is shown to.
+type Job struct {
+	ID          uuid.UUID
+	Tenant      string
+	Format      Format
+	Status      JobStatus
+	Error       string
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+	path        string
+}
+
+// RunFunc produces the export content into w. It runs detached from the request
+// that submitted the job, with a context cancelled when the Manager shuts down.
+type RunFunc func(ctx context.Context, w io.Writer) error
+
+// Manager runs the exports on a bounded pool of workers and keeps the produced files on
+// disk until they expire.
+type Manager struct {
+	mu     sync.RWMutex
+	jobs   map[uuid.UUID]*Job
+	active map[string]int
+	queue  chan queuedJob
+	dir    string
+	cfg    config.ExportConfig
+	logger zerolog.Logger
+
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+}
+
+type queuedJob struct {
+	id     uuid.UUID
+	format Format
+	run    RunFunc
+}
+
+// NewManager uses the defaults of config.ExportConfig for the zero values of cfg. The jobs
+// wait in the queue until Start.
+func NewManager(dir string, cfg config.ExportConfig, logger zerolog.Logger) *Manager {
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWorkers
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	if cfg.MaxPerTenant <= 0 {
+		cfg.MaxPerTenant = defaultMaxPerTenant
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultTTL
+	}
+	if cfg.PurgeInterval <= 0 {
+		cfg.PurgeInterval = defaultPurgeInterval
+	}
+
+	return &Manager{
+		jobs:   make(map[uuid.UUID]*Job),
+		active: make(map[string]int),
+		queue:  make(chan queuedJob, cfg.QueueSize),
+		dir:    dir,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Start runs the workers and the purge of the expired files until ctx is cancelled or
+// Shutdown is called. The jobs run with a context derived from ctx.
+func (m *Manager) Start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(ctx)
+
+	for range m.cfg.Workers {
+		m.workers.Add(1)
+		go func() {
+			defer m.workers.Done()
+			m.work(ctx)
+		}()
+	}
+
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+		m.purge(ctx)
+	}()
+}
+
+// Shutdown cancels the running jobs and waits for the workers, until ctx is done. The files
+// are then removed, the jobs being only known to this process.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	if m.cancel != nil {
+		m.cancel()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		m.workers.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, job := range m.jobs {
+		m.remove(id, job)
+	}
+
+	return nil
+}
+
+// Submit queues an export of the catalogue of tenantID. It fails with ErrTooManyJobs when
+// the tenant already has MaxPerTenant jobs pending or running, and with ErrQueueFull when
+// QueueSize jobs are waiting for a worker.
+func (m *Manager) Submit(tenantID string, format Format, run RunFunc) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.active[tenantID] >= m.cfg.MaxPerTenant {
+		return Job{}, ErrTooManyJobs
+	}
+
+	job := &Job{
+		ID:        uuid.New(),
+		Tenant:    tenantID,
+		Format:    format,
+		Status:    JobPending,
+		CreatedAt: time.Now(),
+	}
+
+	select {
+	case m.queue <- queuedJob{id: job.ID, format: format, run: run}:
+	default:
+		return Job{}, ErrQueueFull
+	}
+
+	m.jobs[job.ID] = job
+	m.active[tenantID]++
+
+	return *job, nil
+}
+
+func (m *Manager) Get(jobID uuid.UUID) (Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	job, ok := m.jobs[jobID]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+
+	return *job, nil
+}
+
+// Open returns the produced file of a completed job. The caller must close it.
+func (m *Manager) Open(jobID uuid.UUID) (*os.File, Job, error) {
+	job, err := m.Get(jobID)
+	if err != nil {
+		return nil, Job{}, err
+	}
+
+	if job.Status != JobCompleted {
+		return nil, job, ErrJobNotReady
+	}
+
+	file, err := os.Open(job.path)
+	if err != nil {
+		return nil, job, err
+	}
+
+	return file, job, nil
+}
+
+func (m *Manager) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case queued := <-m.queue:
+			m.run(ctx, queued.id, queued.format, queued.run)
+		}
+	}
+}
+
+func (m *Manager) run(ctx context.Context, jobID uuid.UUID, format Format, run RunFunc) {
+	m.update(jobID, func(job *Job) { job.Status = JobRunning })
+
+	file, err := os.CreateTemp(m.dir, "export-*"+format.Extension())
+	if err != nil {
+		m.fail(jobID, err)
+		return
+	}
+
+	runErr := run(ctx, file)
+	closeErr := file.Close()
+
+	if runErr != nil || closeErr != nil {
+		_ = os.Remove(file.Name())
+		if runErr == nil {
+			runErr = closeErr
+		}
+		m.fail(jobID, runErr)
+		return
+	}
+
+	m.update(jobID, func(job *Job) {
+		now := time.Now()
+		job.Status = JobCompleted
+		job.CompletedAt = &now
+		job.path = file.Name()
+		m.active[job.Tenant]--
+	})
+}
+
+func (m *Manager) fail(jobID uuid.UUID, err error) {
+	m.logger.Error().Err(err).Str("job_id", jobID.String()).Msg("export job failed")
+
+	m.update(jobID, func(job *Job) {
+		now := time.Now()
+		job.Status = JobFailed
+		job.Error = "export failed"
+		job.CompletedAt = &now
+		m.active[job.Tenant]--
+	})
+}
+
+func (m *Manager) update(jobID uuid.UUID, fn func(job *Job)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if job, ok := m.jobs[jobID]; ok {
+		fn(job)
+	}
+}
+
+func (m *Manager) purge(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.purgeExpired()
+		}
+	}
+}
+
+func (m *Manager) purgeExpired() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, job := range m.jobs {
+		if job.CompletedAt == nil || time.Since(*job.CompletedAt) < m.cfg.TTL {
+			continue
+		}
+		m.remove(id, job)
+	}
+}
+
+// remove forgets the job and deletes its file. The caller holds m.mu.
+func (m *Manager) remove(id uuid.UUID, job *Job) {
+	if job.path != "" {
+		if err := os.Remove(job.path); err != nil && !os.IsNotExist(err) {
+			m.logger.Warn().Err(err).Str("job_id", id.String()).Msg("failed to remove export file")
+		}
+	}
+
+	delete(m.jobs, id)
+}
@@ -0,0 +1,163 @@
+package export_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/export"
+)
+
+func waitForJob(t *testing.T, manager *export.Manager, jobID uuid.UUID) export.Job {
+	t.Helper()
+
+	var job export.Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = manager.Get(jobID)
+		require.NoError(t, err)
+		return job.Status == export.JobCompleted || job.Status == export.JobFailed
+	}, time.Second, 5*time.Millisecond)
+
+	return job
+}
+
+// startManager starts a manager shut down at the end of the test.
+func startManager(t *testing.T, cfg config.ExportConfig) *export.Manager {
+	t.Helper()
+
+	manager := export.NewManager(t.TempDir(), cfg, zerolog.Nop())
+	manager.Start(context.Background())
+	t.Cleanup(func() { _ = manager.Shutdown(context.Background()) })
+
+	return manager
+}
+
+func TestManager(t *testing.T) {
+	t.Run("nominal", func(t *testing.T) {
+		manager := startManager(t, config.ExportConfig{})
+
+		submitted, err := manager.Submit("acme", export.FormatCSV, func(_ context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, "id\n1\n")
+			return err
+		})
+		require.NoError(t, err)
+		assert.Equal(t, export.FormatCSV, submitted.Format)
+
+		job := waitForJob(t, manager, submitted.ID)
+		assert.Equal(t, export.JobCompleted, job.Status)
+		assert.NotNil(t, job.CompletedAt)
+
+		file, _, err := manager.Open(submitted.ID)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = file.Close() })
+
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Equal(t, "id\n1\n", string(content))
+	})
+
+	t.Run("error job failed", func(t *testing.T) {
+		manager := startManager(t, config.ExportConfig{})
+
+		submitted, err := manager.Submit("acme", export.FormatCSV, func(_ context.Context, _ io.Writer) error {
+			return errors.New("database connection failed")
+		})
+		require.NoError(t, err)
+
+		job := waitForJob(t, manager, submitted.ID)
+		assert.Equal(t, export.JobFailed, job.Status)
+		assert.Equal(t, "export failed", job.Error)
+
+		_, _, err = manager.Open(submitted.ID)
+		assert.ErrorIs(t, err, export.ErrJobNotReady)
+	})
+
+	t.Run("error job not found", func(t *testing.T) {
+		manager := startManager(t, config.ExportConfig{})
+
+		_, err := manager.Get(uuid.New())
+		assert.ErrorIs(t, err, export.ErrJobNotFound)
+	})
+
+	t.Run("error too many jobs for the tenant", func(t *testing.T) {
+		// Without workers, the jobs stay pending.
+		manager := export.NewManager(t.TempDir(), config.ExportConfig{MaxPerTenant: 1}, zerolog.Nop())
+		noop := func(_ context.Context, _ io.Writer) error { return nil }
+
+		_, err := manager.Submit("acme", export.FormatCSV, noop)
+		require.NoError(t, err)
+
+		_, err = manager.Submit("acme", export.FormatCSV, noop)
+		assert.ErrorIs(t, err, export.ErrTooManyJobs)
+
+		_, err = manager.Submit("globex", export.FormatCSV, noop)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error queue full", func(t *testing.T) {
+		manager := export.NewManager(t.TempDir(), config.ExportConfig{QueueSize: 1}, zerolog.Nop())
+		noop := func(_ context.Context, _ io.Writer) error { return nil }
+
+		_, err := manager.Submit("acme", export.FormatCSV, noop)
+		require.NoError(t, err)
+
+		_, err = manager.Submit("globex", export.FormatCSV, noop)
+		assert.ErrorIs(t, err, export.ErrQueueFull)
+	})
+
+	t.Run("completed jobs free their slot of the tenant", func(t *testing.T) {
+		manager := startManager(t, config.ExportConfig{MaxPerTenant: 1})
+		noop := func(_ context.Context, _ io.Writer) error { return nil }
+
+		first, err := manager.Submit("acme", export.FormatCSV, noop)
+		require.NoError(t, err)
+		waitForJob(t, manager, first.ID)
+
+		_, err = manager.Submit("acme", export.FormatCSV, noop)
+		assert.NoError(t, err)
+	})
+
+	t.Run("shutdown cancels the running jobs", func(t *testing.T) {
+		manager := export.NewManager(t.TempDir(), config.ExportConfig{}, zerolog.Nop())
+		manager.Start(context.Background())
+
+		started := make(chan struct{})
+		submitted, err := manager.Submit("acme", export.FormatCSV, func(ctx context.Context, _ io.Writer) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		require.NoError(t, err)
+		<-started
+
+		require.NoError(t, manager.Shutdown(context.Background()))
+
+		// The jobs are forgotten with their files.
+		_, err = manager.Get(submitted.ID)
+		assert.ErrorIs(t, err, export.ErrJobNotFound)
+	})
+
+	t.Run("expired jobs are purged", func(t *testing.T) {
+		manager := startManager(t, config.ExportConfig{TTL: time.Nanosecond, PurgeInterval: 10 * time.Millisecond})
+
+		submitted, err := manager.Submit("acme", export.FormatCSV, func(_ context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, "id\n")
+			return err
+		})
+		require.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			_, err := manager.Get(submitted.ID)
+			return errors.Is(err, export.ErrJobNotFound)
+		}, time.Second, 5*time.Millisecond)
+	})
+}
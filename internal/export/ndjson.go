@@ -0,0 +1,42 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+type ndjsonWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+	header  []string
+}
+
+func newNDJSONWriter(w io.Writer, header []string) *ndjsonWriter {
+	buffer := bufio.NewWriter(w)
+
+	return &ndjsonWriter{
+		buffer:  buffer,
+		encoder: json.NewEncoder(buffer),
+		header:  header,
+	}
+}
+
+func (n *ndjsonWriter) WriteRow(values []string) error {
+	record := make(map[string]string, len(n.header))
+	for i, key := range n.header {
+		if i < len(values) {
+			record[key] = values[i]
+		}
+	}
+
+	return n.encoder.Encode(record)
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return n.buffer.Flush()
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.Flush()
+}
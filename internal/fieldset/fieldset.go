@@ -0,0 +1,504 @@
+package fieldset
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+// Spec describes what a read endpoint accepts in ?fields= and ?expand= (or ?include=).
+type Spec struct {
+	// Fields are the selectable dotted JSON paths, usually built with Paths.
+	Fields []string
+	// Expand are the relationships that can be loaded on demand.
+	Expand []string
+	// DefaultExpand is used when the request selects neither fields nor relationships.
+	DefaultExpand []string
+}
+
+type Selection struct {
+	fields []string
+	expand []string
+	// root is the JSON path the fields are prefixed with, set by View.
+	root string
+}
+
+// Parse reads the sparse fieldset and the expanded relationships requested by r.
+// Unknown names are reported in the ValidationErrorDetail format.
+func Parse(r *http.Request, spec Spec) (Selection, []response.ValidationErrorDetail) {
+	query := r.URL.Query()
+
+	fields := splitList(query.Get("fields"))
+	expand := splitList(query.Get("expand"))
+	if len(expand) == 0 {
+		expand = splitList(query.Get("include"))
+	}
+
+	var errs []response.ValidationErrorDetail
+
+	for _, field := range fields {
+		if !slices.Contains(spec.Fields, field) {
+			errs = append(errs, response.ValidationErrorDetail{
+				Field:   "fields",
+				Message: fmt.Sprintf("unknown field %q", field),
+			})
+		}
+	}
+
+	for _, relation := range expand {
+		if !slices.Contains(spec.Expand, relation) {
+			errs = append(errs, response.ValidationErrorDetail{
+				Field:   "expand",
+				Message: fmt.Sprintf("unknown relationship %q", relation),
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return Selection{}, errs
+	}
+
+	if len(fields) == 0 && len(expand) == 0 {
+		expand = spec.DefaultExpand
+	}
+
+	return Selection{fields: fields, expand: expand}, nil
+}
+
+// Expanded reports whether relation must be loaded, either because it was expanded
+// explicitly or because one of its fields was selected.
+func (s Selection) Expanded(relation string) bool {
+	if slices.Contains(s.expand, relation) {
+		return true
+	}
+
+	for _, field := range s.fields {
+		if field == relation || strings.HasPrefix(field, relation+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Includes reports whether path ends up in the output, so the handler can skip loading
+// data nobody asked for.
+func (s Selection) Includes(path string) bool {
+	if len(s.fields) == 0 {
+		return true
+	}
+
+	return slices.Contains(s.fields, path) || s.hasChild(path)
+}
+
+// Relations returns every relationship of spec that must be loaded.
+func (s Selection) Relations(spec Spec) []string {
+	var relations []string
+
+	for _, relation := range spec.Expand {
+		if s.Expanded(relation) {
+			relations = append(relations, relation)
+		}
+	}
+
+	return relations
+}
+
+// Apply zeroes the fields of v that were not selected, so the formats keeping every column,
+// such as CSV, leave them empty. v must be a pointer to a struct. View drops them from the
+// other formats.
+func (s Selection) Apply(v any) {
+	if len(s.fields) == 0 {
+		return
+	}
+
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return
+	}
+
+	s.apply(value.Elem(), "")
+}
+
+func (s Selection) apply(value reflect.Value, prefix string) {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if !value.IsNil() {
+			s.apply(value.Elem(), prefix)
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			s.apply(value.Index(i), prefix)
+		}
+	case reflect.Struct:
+		for _, field := range fieldsOf(value.Type()) {
+			path := prefix + field.name
+			switch {
+			case slices.Contains(s.fields, path):
+			case s.hasChild(path):
+				s.apply(value.FieldByIndex(field.index), path+".")
+			default:
+				value.FieldByIndex(field.index).SetZero()
+			}
+		}
+	}
+}
+
+// View returns the payload v rendered with only the selected fields of the value found at
+// the JSON path at, the whole payload when at is empty: the other fields are dropped, whether
+// they are tagged omitempty or not. The fields of v outside at are kept, and so is the CSV
+// rendering of v. The structs inlined in v must be exported.
+func (s Selection) View(v any, at string) any {
+	if len(s.fields) == 0 {
+		return v
+	}
+
+	scoped := Selection{root: at}
+	for _, field := range s.fields {
+		if at != "" {
+			field = at + "." + field
+		}
+		scoped.fields = append(scoped.fields, field)
+	}
+
+	value := reflect.ValueOf(v)
+	view := view{value: scoped.cachedPlan(value.Type()).fill(value).Interface()}
+	if tabular, ok := v.(response.Tabular); ok {
+		return tabularView{view: view, Tabular: tabular}
+	}
+
+	return view
+}
+
+// projection is a type holding only the selected fields of another, built with
+// reflect.StructOf, and where its fields come from.
+type projection struct {
+	typ    reflect.Type
+	elem   *projection
+	fields []projectedField
+}
+
+type projectedField struct {
+	index []int
+	// projection is nil for the fields copied as they are.
+	projection *projection
+}
+
+// maxPlans bounds the plans kept by cachedPlan, the selections of the clients being countless.
+const maxPlans = 1024
+
+var (
+	plansMu sync.RWMutex
+	plans   = make(map[planKey]*projection)
+)
+
+type planKey struct {
+	typ    reflect.Type
+	root   string
+	fields string
+}
+
+// cachedPlan returns the plan of t for s, built once per selection since reflect.StructOf
+// is slow and never frees the types it creates. The cache is emptied once it holds maxPlans.
+func (s Selection) cachedPlan(t reflect.Type) *projection {
+	fields := slices.Compact(slices.Sorted(slices.Values(s.fields)))
+	key := planKey{typ: t, root: s.root, fields: strings.Join(fields, ",")}
+
+	plansMu.RLock()
+	p, ok := plans[key]
+	plansMu.RUnlock()
+	if ok {
+		return p
+	}
+
+	p = s.plan(t, "")
+
+	plansMu.Lock()
+	if len(plans) >= maxPlans {
+		clear(plans)
+	}
+	plans[key] = p
+	plansMu.Unlock()
+
+	return p
+}
+
+func (s Selection) plan(t reflect.Type, prefix string) *projection {
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem := s.plan(t.Elem(), prefix)
+		return &projection{typ: reflect.PointerTo(elem.typ), elem: elem}
+	case reflect.Slice:
+		elem := s.plan(t.Elem(), prefix)
+		return &projection{typ: reflect.SliceOf(elem.typ), elem: elem}
+	case reflect.Struct:
+		p := &projection{}
+		p.typ = reflect.StructOf(s.planFields(t, prefix, p))
+		return p
+	default:
+		return &projection{typ: t}
+	}
+}
+
+// planFields returns the fields of t kept in the output, flattening the inlined structs as
+// encoding/json promotes their fields. The fields of distinct inlined structs may share a Go
+// name, which reflect.StructOf refuses: the later ones are renamed, their tags keeping the
+// names they are rendered with.
+func (s Selection) planFields(t reflect.Type, prefix string, p *projection) []reflect.StructField {
+	var fields []reflect.StructField
+	names := make(map[string]bool)
+
+	for _, field := range fieldsOf(t) {
+		path := prefix + field.name
+		kept := reflect.StructField{Name: field.Name, Type: field.Type, Tag: field.Tag}
+		switch {
+		case slices.Contains(s.fields, path), !s.covers(path):
+			p.fields = append(p.fields, projectedField{index: field.index})
+		case s.hasChild(path):
+			nested := s.plan(field.Type, path+".")
+			kept.Type = nested.typ
+			p.fields = append(p.fields, projectedField{index: field.index, projection: nested})
+		default:
+			continue
+		}
+
+		if names[kept.Name] {
+			kept.Name = fmt.Sprintf("%s%d", field.Name, len(fields))
+			kept.Tag = withNames(field.Tag, field.Name)
+		}
+		names[kept.Name] = true
+		fields = append(fields, kept)
+	}
+
+	return fields
+}
+
+// withNames adds to tag the json, xml and msgpack keys it lacks, set to name.
+func withNames(tag reflect.StructTag, name string) reflect.StructTag {
+	for _, key := range []string{"json", "xml", "msgpack"} {
+		if _, ok := tag.Lookup(key); !ok {
+			tag = reflect.StructTag(strings.TrimSpace(fmt.Sprintf("%s:%q %s", key, name, tag)))
+		}
+	}
+	return tag
+}
+
+// covers reports whether path is below the value the selection applies to.
+func (s Selection) covers(path string) bool {
+	return s.root == "" || path == s.root || strings.HasPrefix(path, s.root+".")
+}
+
+func (p *projection) fill(src reflect.Value) reflect.Value {
+	dst := reflect.New(p.typ).Elem()
+
+	switch src.Kind() {
+	case reflect.Pointer:
+		if !src.IsNil() {
+			dst.Set(p.elem.fill(src.Elem()).Addr())
+		}
+	case reflect.Slice:
+		if !src.IsNil() {
+			dst.Set(reflect.MakeSlice(p.typ, src.Len(), src.Len()))
+			for i := 0; i < src.Len(); i++ {
+				dst.Index(i).Set(p.elem.fill(src.Index(i)))
+			}
+		}
+	case reflect.Struct:
+		for i, field := range p.fields {
+			value := src.FieldByIndex(field.index)
+			if field.projection != nil {
+				value = field.projection.fill(value)
+			}
+			dst.Field(i).Set(value)
+		}
+	default:
+		dst.Set(src)
+	}
+
+	return dst
+}
+
+// view renders its projected value in every format of the response package.
+type view struct {
+	value any
+}
+
+func (v view) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.value)
+}
+
+func (v view) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(v.value, start)
+}
+
+func (v view) EncodeMsgpack(e *msgpack.Encoder) error {
+	return e.Encode(v.value)
+}
+
+type tabularView struct {
+	view
+	response.Tabular
+}
+
+func (s Selection) hasChild(path string) bool {
+	for _, field := range s.fields {
+		if strings.HasPrefix(field, path+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Paths lists the dotted JSON paths of the struct v, descending into nested structs
+// and slices of structs.
+func Paths(v any) []string {
+	return paths(reflect.TypeOf(v), "")
+}
+
+func paths(t reflect.Type, prefix string) []string {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var result []string
+	for _, field := range fieldsOf(t) {
+		path := prefix + field.name
+		result = append(result, path)
+		result = append(result, paths(field.Type, path+".")...)
+	}
+
+	return result
+}
+
+// field is a field of a struct as encoding/json sees it, the inlined structs being flattened.
+type field struct {
+	reflect.StructField
+	// name is the JSON name of the field.
+	name  string
+	index []int
+}
+
+var fieldsCache sync.Map
+
+// fieldsOf returns the fields encoding/json renders for the struct t, in order. Of the fields
+// sharing a name, the shallowest one wins, then the one with a JSON tag, and the fields still
+// tied are all dropped.
+func fieldsOf(t reflect.Type) []field {
+	if cached, ok := fieldsCache.Load(t); ok {
+		return cached.([]field)
+	}
+
+	var all []field
+	collectFields(t, nil, &all)
+
+	byName := make(map[string][]int)
+	for i, f := range all {
+		byName[f.name] = append(byName[f.name], i)
+	}
+
+	var fields []field
+	for i, f := range all {
+		if dominant(all, byName[f.name]) == i {
+			fields = append(fields, f)
+		}
+	}
+
+	fieldsCache.Store(t, fields)
+	return fields
+}
+
+func collectFields(t reflect.Type, index []int, all *[]field) {
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		fieldIndex := append(slices.Clone(index), i)
+		if isInlined(structField) {
+			collectFields(structField.Type, fieldIndex, all)
+			continue
+		}
+
+		name := jsonName(structField)
+		if name == "" {
+			continue
+		}
+
+		*all = append(*all, field{StructField: structField, name: name, index: fieldIndex})
+	}
+}
+
+// dominant returns the position in all of the field encoding/json keeps among candidates,
+// the fields sharing a name, or -1 when none wins.
+func dominant(all []field, candidates []int) int {
+	best, tied := -1, false
+	for _, candidate := range candidates {
+		if best == -1 {
+			best = candidate
+			continue
+		}
+
+		current, other := all[best], all[candidate]
+		switch {
+		case len(other.index) < len(current.index),
+			len(other.index) == len(current.index) && isTagged(other.StructField) && !isTagged(current.StructField):
+			best, tied = candidate, false
+		case len(other.index) == len(current.index) && isTagged(other.StructField) == isTagged(current.StructField):
+			tied = true
+		}
+	}
+
+	if tied {
+		return -1
+	}
+	return best
+}
+
+// isInlined reports whether field is an embedded struct whose fields encoding/json promotes.
+func isInlined(field reflect.StructField) bool {
+	return field.Anonymous && field.Tag.Get("json") == "" && field.Type.Kind() == reflect.Struct
+}
+
+func isTagged(field reflect.StructField) bool {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return name != ""
+}
+
+func jsonName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
+
+func splitList(value string) []string {
+	var items []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
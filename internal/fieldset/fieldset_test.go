@@ -0,0 +1,346 @@
+package fieldset_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go-boilerplate-rest-api-chi/internal/fieldset"
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+type owner struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type chapter struct {
+	Number int    `json:"number,omitempty"`
+	Title  string `json:"title,omitempty"`
+}
+
+type base struct {
+	ID string `json:"id,omitempty"`
+}
+
+type document struct {
+	base
+	Title    string    `json:"title,omitempty"`
+	Owner    *owner    `json:"owner,omitempty"`
+	Chapters []chapter `json:"chapters,omitempty"`
+	internal string
+}
+
+var spec = fieldset.Spec{
+	Fields:        fieldset.Paths(document{}),
+	Expand:        []string{"owner", "chapters"},
+	DefaultExpand: []string{"owner"},
+}
+
+func TestPaths(t *testing.T) {
+	assert.Equal(t, []string{
+		"id",
+		"title",
+		"owner",
+		"owner.id",
+		"owner.name",
+		"chapters",
+		"chapters.number",
+		"chapters.title",
+	}, fieldset.Paths(document{}))
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name              string
+		query             string
+		expectedRelations []string
+		expectedErrors    []response.ValidationErrorDetail
+	}{
+		{
+			name:              "default expand",
+			query:             "",
+			expectedRelations: []string{"owner"},
+		},
+		{
+			name:              "fields without relationship",
+			query:             "?fields=id,title",
+			expectedRelations: nil,
+		},
+		{
+			name:              "nested field implies expand",
+			query:             "?fields=id,%20owner.name",
+			expectedRelations: []string{"owner"},
+		},
+		{
+			name:              "explicit expand",
+			query:             "?expand=chapters",
+			expectedRelations: []string{"chapters"},
+		},
+		{
+			name:              "include alias",
+			query:             "?include=owner,chapters",
+			expectedRelations: []string{"owner", "chapters"},
+		},
+		{
+			name:  "error unknown field and relationship",
+			query: "?fields=id,isbn&expand=reviews",
+			expectedErrors: []response.ValidationErrorDetail{
+				{Field: "fields", Message: `unknown field "isbn"`},
+				{Field: "expand", Message: `unknown relationship "reviews"`},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/"+test.query, nil)
+
+			selection, errs := fieldset.Parse(req, spec)
+
+			assert.Equal(t, test.expectedErrors, errs)
+			if test.expectedErrors == nil {
+				assert.Equal(t, test.expectedRelations, selection.Relations(spec))
+			}
+		})
+	}
+}
+
+func TestSelection_Apply(t *testing.T) {
+	newDocument := func() *document {
+		return &document{
+			base:  base{ID: "1"},
+			Title: "Title",
+			Owner: &owner{ID: "2", Name: "Owner"},
+			Chapters: []chapter{
+				{Number: 1, Title: "Chapter 1"},
+				{Number: 2, Title: "Chapter 2"},
+			},
+			internal: "kept",
+		}
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected *document
+	}{
+		{
+			name:     "no fields keeps everything",
+			query:    "",
+			expected: newDocument(),
+		},
+		{
+			name:  "top level fields",
+			query: "?fields=id,title",
+			expected: &document{
+				base:     base{ID: "1"},
+				Title:    "Title",
+				internal: "kept",
+			},
+		},
+		{
+			name:  "nested fields",
+			query: "?fields=owner.name,chapters.title",
+			expected: &document{
+				Owner: &owner{Name: "Owner"},
+				Chapters: []chapter{
+					{Title: "Chapter 1"},
+					{Title: "Chapter 2"},
+				},
+				internal: "kept",
+			},
+		},
+		{
+			name:  "whole relationship",
+			query: "?fields=owner",
+			expected: &document{
+				Owner:    &owner{ID: "2", Name: "Owner"},
+				internal: "kept",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/"+test.query, nil)
+			selection, errs := fieldset.Parse(req, spec)
+			assert.Nil(t, errs)
+
+			doc := newDocument()
+			selection.Apply(doc)
+
+			assert.Equal(t, test.expected, doc)
+		})
+	}
+}
+
+type Record struct {
+	ID string `json:"id" xml:"id"`
+}
+
+type article struct {
+	Record
+	Title  string  `json:"title" xml:"title"`
+	Owner  *owner  `json:"owner" xml:"owner"`
+	Labels []owner `json:"labels" xml:"labels>label"`
+}
+
+type articlesResponse struct {
+	Status   string    `json:"status" xml:"status"`
+	Articles []article `json:"articles" xml:"articles>article"`
+}
+
+func (a articlesResponse) CSVHeader() []string {
+	return []string{"id", "title"}
+}
+
+func (a articlesResponse) CSVRecords() [][]string {
+	records := make([][]string, len(a.Articles))
+	for i, article := range a.Articles {
+		records[i] = []string{article.ID, article.Title}
+	}
+	return records
+}
+
+func TestSelection_View(t *testing.T) {
+	articleSpec := fieldset.Spec{Fields: fieldset.Paths(article{})}
+	payload := articlesResponse{
+		Status: "success",
+		Articles: []article{{
+			Record: Record{ID: "1"},
+			Title:  "Title",
+			Owner:  &owner{ID: "2", Name: "Owner"},
+			Labels: []owner{{ID: "3", Name: "Label"}},
+		}},
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		renderer     response.Renderer
+		expectedBody string
+	}{
+		{
+			name:         "json without fields",
+			query:        "",
+			renderer:     response.JSONRenderer{},
+			expectedBody: `{"status":"success","articles":[{"id":"1","title":"Title","owner":{"id":"2","name":"Owner"},"labels":[{"id":"3","name":"Label"}]}]}` + "\n",
+		},
+		{
+			name:         "json with fields",
+			query:        "?fields=id,owner.name,labels",
+			renderer:     response.JSONRenderer{},
+			expectedBody: `{"status":"success","articles":[{"id":"1","owner":{"name":"Owner"},"labels":[{"id":"3","name":"Label"}]}]}` + "\n",
+		},
+		{
+			name:     "xml with fields",
+			query:    "?fields=title",
+			renderer: response.XMLRenderer{},
+			expectedBody: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<response><status>success</status><articles><article><title>Title</title></article></articles></response>`,
+		},
+		{
+			name:         "csv with fields",
+			query:        "?fields=title",
+			renderer:     response.CSVRenderer{},
+			expectedBody: "id,title\n1,Title\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/"+test.query, nil)
+			selection, errs := fieldset.Parse(req, articleSpec)
+			assert.Nil(t, errs)
+
+			var body bytes.Buffer
+			err := test.renderer.Render(&body, selection.View(payload, "articles"))
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedBody, body.String())
+		})
+	}
+}
+
+type Left struct {
+	Ref  string
+	Name string `json:"name"`
+}
+
+type Right struct {
+	Ref   string
+	Label string `json:"label"`
+}
+
+type Shadowed struct {
+	Title string `json:"title"`
+}
+
+type Extra struct {
+	Code string `json:"extra_code"`
+}
+
+// collision inlines fields sharing a JSON name, or a Go name, with other fields.
+type collision struct {
+	Left
+	Right
+	Shadowed
+	Extra
+	Title string `json:"title"`
+	Code  string `json:"code"`
+}
+
+func TestSelection_View_Collisions(t *testing.T) {
+	payload := collision{
+		Left:     Left{Ref: "1", Name: "Name"},
+		Right:    Right{Ref: "2", Label: "Label"},
+		Shadowed: Shadowed{Title: "Shadowed"},
+		Extra:    Extra{Code: "Extra"},
+		Title:    "Title",
+		Code:     "Code",
+	}
+
+	// The refs of the same depth are dropped and the outer title hides the inlined one, as
+	// encoding/json does.
+	assert.Equal(t, []string{"name", "label", "extra_code", "title", "code"}, fieldset.Paths(collision{}))
+
+	tests := []struct {
+		name         string
+		query        string
+		expectedBody string
+	}{
+		{
+			name:         "shallowest field wins",
+			query:        "?fields=title,label",
+			expectedBody: `{"label":"Label","title":"Title"}` + "\n",
+		},
+		{
+			name:         "fields sharing a Go name are both kept",
+			query:        "?fields=code,extra_code",
+			expectedBody: `{"extra_code":"Extra","code":"Code"}` + "\n",
+		},
+		{
+			name:         "same selection in another order",
+			query:        "?fields=extra_code,code",
+			expectedBody: `{"extra_code":"Extra","code":"Code"}` + "\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/"+test.query, nil)
+			selection, errs := fieldset.Parse(req, fieldset.Spec{Fields: fieldset.Paths(collision{})})
+			assert.Nil(t, errs)
+
+			var body bytes.Buffer
+			err := response.JSONRenderer{}.Render(&body, selection.View(payload, ""))
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedBody, body.String())
+		})
+	}
+}
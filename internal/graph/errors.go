@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"errors"
+
+	"go-boilerplate-rest-api-chi/internal/author"
+	"go-boilerplate-rest-api-chi/internal/book"
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+// Codes reported in the "code" extension of the errors.
+const (
+	CodeBadUserInput    = "BAD_USER_INPUT"
+	CodeNotFound        = "NOT_FOUND"
+	CodeConflict        = "CONFLICT"
+	CodeInternal        = "INTERNAL_SERVER_ERROR"
+	CodeQueryTooDeep    = "QUERY_TOO_DEEP"
+	CodeQueryTooComplex = "QUERY_TOO_COMPLEX"
+)
+
+// Error is a GraphQL error with its code and, for invalid inputs, the field errors in the
+// format of the REST API.
+type Error struct {
+	Message string
+	Code    string
+	Fields  []response.ValidationErrorDetail
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Extensions() map[string]any {
+	extensions := map[string]any{"code": e.Code}
+	if len(e.Fields) > 0 {
+		extensions["fields"] = e.Fields
+	}
+	return extensions
+}
+
+// toError maps the domain errors like the REST handlers do. Unexpected errors are logged
+// and hidden from the client.
+func (s *schemaBuilder) toError(err error) error {
+	var graphErr *Error
+
+	switch {
+	case errors.As(err, &graphErr):
+		return graphErr
+	case errors.Is(err, book.ErrNotFound):
+		return &Error{Message: "Book not found", Code: CodeNotFound}
+	case errors.Is(err, book.ErrDuplicate):
+		return &Error{Message: "Book with this name already exists", Code: CodeConflict}
+	case errors.Is(err, book.ErrInvalidAuthorId):
+		return &Error{Message: "invalid author ID", Code: CodeBadUserInput}
+	case errors.Is(err, author.ErrNotFound):
+		return &Error{Message: "Author not found", Code: CodeNotFound}
+	case errors.Is(err, author.ErrDuplicate):
+		return &Error{Message: "Author with this name already exists", Code: CodeConflict}
+	default:
+		s.logger.Error().Err(err).Msg("unexpected error")
+		return &Error{Message: "Internal server error", Code: CodeInternal}
+	}
+}
@@ -0,0 +1,223 @@
+package graph
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/author"
+	"go-boilerplate-rest-api-chi/internal/book"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/response"
+	internalValidator "go-boilerplate-rest-api-chi/internal/validator"
+)
+
+const maxRequestSize = 1 << 20
+
+// Request is the body of a GraphQL request sent with POST.
+type Request struct {
+	Query         string         `json:"query" example:"{ books(first: 10) { nodes { title author { name } } } }"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+type Handler struct {
+	schema        graphql.Schema
+	authorService author.AuthorService
+	cfg           config.GraphQLConfig
+	logger        zerolog.Logger
+}
+
+// NewHandler panics when the schema is invalid, which is a programming error.
+func NewHandler(bookService book.BookService, authorService author.AuthorService, validator *internalValidator.Validator, cfg config.GraphQLConfig, logger zerolog.Logger) *Handler {
+	builder := &schemaBuilder{
+		bookService:   bookService,
+		authorService: authorService,
+		validator:     validator,
+		logger:        logger,
+	}
+
+	schema, err := builder.build()
+	if err != nil {
+		panic(err)
+	}
+
+	return &Handler{
+		schema:        schema,
+		authorService: authorService,
+		cfg:           cfg,
+		logger:        logger,
+	}
+}
+
+func (h *Handler) Routes() http.Handler {
+	r := chi.NewRouter()
+
+	// routes
+	r.Get("/", h.Query)
+	r.Post("/", h.Query)
+
+	return r
+}
+
+// Query godoc
+//
+//	@Summary		Execute a GraphQL query
+//	@Description	Executes a query or a mutation of the GraphQL schema over the books and authors. Queries can also be sent with GET in the query, operationName and variables parameters. Errors are returned in "errors" with their code in "extensions".
+//	@Tags			graphql
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		graph.Request	true	"GraphQL request"
+//	@Success		200		{object}	graphql.Result
+//	@Failure		400		{object}	graphql.Result
+//	@Failure		405		{object}	graphql.Result
+//	@Router			/graphql [post]
+func (h *Handler) Query(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeRequest(w, r)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err)
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if validation := graphql.ValidateDocument(&h.schema, doc, nil); !validation.IsValid {
+		response.JSON(w, http.StatusOK, &graphql.Result{Errors: validation.Errors})
+		return
+	}
+
+	op := operation(doc, req.OperationName)
+	if op == nil {
+		writeErrors(w, http.StatusBadRequest, errors.New("unknown operation, operationName must name one of the operations of the query"))
+		return
+	}
+
+	// GET requests must not have side effects.
+	if r.Method == http.MethodGet && op.Operation != ast.OperationTypeQuery {
+		w.Header().Set("Allow", http.MethodPost)
+		writeErrors(w, http.StatusMethodNotAllowed, errors.New("mutations must be sent with POST"))
+		return
+	}
+
+	if err := checkLimits(doc, op, req.Variables, h.cfg); err != nil {
+		writeErrors(w, http.StatusOK, err)
+		return
+	}
+
+	ctx := withLoaders(r.Context(), newLoaders(h.authorService))
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+	restoreExtensions(result.Errors)
+
+	response.JSON(w, http.StatusOK, result)
+}
+
+// Playground serves GraphiQL, to explore the schema from a browser.
+func (h *Handler) Playground(endpoint string) http.HandlerFunc {
+	page := []byte(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>GraphiQL - Boilerplate - rest API - CHI</title>
+  <style>body { margin: 0; height: 100vh; } #graphiql { height: 100vh; }</style>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css">
+</head>
+<body>
+  <div id="graphiql"></div>
+  <script crossorigin src="https://unpkg.com/react@18/umd/react.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/graphiql@3/graphiql.min.js"></script>
+  <script>
+    const fetcher = GraphiQL.createFetcher({ url: "` + endpoint + `" });
+    ReactDOM.createRoot(document.getElementById("graphiql")).render(React.createElement(GraphiQL, { fetcher }));
+  </script>
+</body>
+</html>
+`)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(page); err != nil {
+			h.logger.Error().Err(err).Msg("failed to write the playground")
+		}
+	}
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request) (*Request, error) {
+	req := &Request{}
+
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return nil, errors.New("variables must be a JSON object")
+			}
+		}
+	} else {
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			return nil, errors.New("invalid request body")
+		}
+	}
+
+	if req.Query == "" {
+		return nil, errors.New("query is required")
+	}
+
+	return req, nil
+}
+
+func writeErrors(w http.ResponseWriter, status int, err error) {
+	errs := gqlerrors.FormatErrors(err)
+	restoreExtensions(errs)
+	response.JSON(w, status, &graphql.Result{Errors: errs})
+}
+
+// restoreExtensions puts back the extensions of the errors returned by the thunks, which
+// the executor drops.
+func restoreExtensions(errs []gqlerrors.FormattedError) {
+	for i := range errs {
+		if errs[i].Extensions != nil {
+			continue
+		}
+
+		err := errs[i].OriginalError()
+		for err != nil {
+			if extended, ok := err.(gqlerrors.ExtendedError); ok {
+				errs[i].Extensions = extended.Extensions()
+				break
+			}
+
+			switch wrapped := err.(type) {
+			case gqlerrors.FormattedError:
+				err = wrapped.OriginalError()
+			case *gqlerrors.Error:
+				err = wrapped.OriginalError
+			default:
+				err = nil
+			}
+		}
+	}
+}
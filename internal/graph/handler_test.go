@@ -0,0 +1,280 @@
+package graph_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"go-boilerplate-rest-api-chi/internal/author"
+	authorDto "go-boilerplate-rest-api-chi/internal/author/dto"
+	"go-boilerplate-rest-api-chi/internal/book"
+	bookDto "go-boilerplate-rest-api-chi/internal/book/dto"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/graph"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/pagination"
+	"go-boilerplate-rest-api-chi/internal/validator"
+)
+
+var (
+	hugoID    = uuid.MustParse("aeca0955-bae4-47e9-9f85-6818dc68ca51")
+	zolaID    = uuid.MustParse("0b8cfbd4-1c5e-4d5b-9d3c-0e0b8b0e0f11")
+	miserable = uuid.MustParse("f3c1b7a2-6d0e-4a8b-9c5d-2e7f1a3b4c5d")
+	published = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+)
+
+func serve(t *testing.T, configure func(*mocks.MockBookService, *mocks.MockAuthorService), method string, body string) (int, map[string]any) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	bookService := mocks.NewMockBookService(ctrl)
+	authorService := mocks.NewMockAuthorService(ctrl)
+	if configure != nil {
+		configure(bookService, authorService)
+	}
+
+	handler := graph.NewHandler(bookService, authorService, validator.New(), config.GraphQLConfig{MaxDepth: 6, MaxComplexity: 200}, zerolog.Nop())
+
+	var req *http.Request
+	if method == http.MethodGet {
+		req = httptest.NewRequest(http.MethodGet, "/?query="+url.QueryEscape(body), nil)
+	} else {
+		req = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+	}
+	rec := httptest.NewRecorder()
+	handler.Routes().ServeHTTP(rec, req)
+
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+
+	return rec.Code, result
+}
+
+func query(t *testing.T, q string, variables map[string]any) string {
+	t.Helper()
+
+	body, err := json.Marshal(graph.Request{Query: q, Variables: variables})
+	require.NoError(t, err)
+	return string(body)
+}
+
+func errorCodes(result map[string]any) []any {
+	var codes []any
+	errs, _ := result["errors"].([]any)
+	for _, e := range errs {
+		extensions, _ := e.(map[string]any)["extensions"].(map[string]any)
+		codes = append(codes, extensions["code"])
+	}
+	return codes
+}
+
+func TestHandler_Books(t *testing.T) {
+	books := []*entity.Book{
+		{ID: miserable, Title: "Les Misérables", Description: "Jean Valjean", AuthorID: hugoID, CreatedAt: published, UpdatedAt: published},
+		{ID: uuid.New(), Title: "Germinal", Description: "La mine", AuthorID: zolaID, CreatedAt: published, UpdatedAt: published},
+		{ID: uuid.New(), Title: "Notre-Dame de Paris", Description: "Quasimodo", AuthorID: hugoID, CreatedAt: published, UpdatedAt: published},
+	}
+
+	code, result := serve(t, func(bookService *mocks.MockBookService, authorService *mocks.MockAuthorService) {
+		bookService.EXPECT().
+			ListBooks(gomock.Any(), &bookDto.BookFilter{}, pagination.Window(2, 3)).
+			Return(books, int64(8), nil)
+
+		// The three books share two authors, loaded in a single call.
+		authorService.EXPECT().
+			GetAuthorsByIDs(gomock.Any(), gomock.InAnyOrder([]uuid.UUID{hugoID, zolaID})).
+			Return([]*entity.Author{{ID: hugoID, Name: "Victor Hugo"}, {ID: zolaID, Name: "Émile Zola"}}, nil).
+			Times(1)
+	}, http.MethodPost, query(t, `query ($after: String) {
+		books(first: 3, after: $after) {
+			totalCount
+			pageInfo { hasNextPage hasPreviousPage startCursor endCursor }
+			edges { cursor node { id title createdAt author { name } } }
+		}
+	}`, map[string]any{"after": "b2Zmc2V0OjE="}))
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, result["errors"])
+	assert.Equal(t, map[string]any{
+		"books": map[string]any{
+			"totalCount": float64(8),
+			"pageInfo": map[string]any{
+				"hasNextPage":     true,
+				"hasPreviousPage": true,
+				"startCursor":     "b2Zmc2V0OjI=",
+				"endCursor":       "b2Zmc2V0OjQ=",
+			},
+			"edges": []any{
+				map[string]any{"cursor": "b2Zmc2V0OjI=", "node": map[string]any{
+					"id": miserable.String(), "title": "Les Misérables", "createdAt": "2024-01-02T03:04:05Z",
+					"author": map[string]any{"name": "Victor Hugo"},
+				}},
+				map[string]any{"cursor": "b2Zmc2V0OjM=", "node": map[string]any{
+					"id": books[1].ID.String(), "title": "Germinal", "createdAt": "2024-01-02T03:04:05Z",
+					"author": map[string]any{"name": "Émile Zola"},
+				}},
+				map[string]any{"cursor": "b2Zmc2V0OjQ=", "node": map[string]any{
+					"id": books[2].ID.String(), "title": "Notre-Dame de Paris", "createdAt": "2024-01-02T03:04:05Z",
+					"author": map[string]any{"name": "Victor Hugo"},
+				}},
+			},
+		},
+	}, result["data"])
+}
+
+func TestHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		configureMock      func(*mocks.MockBookService, *mocks.MockAuthorService)
+		expectedStatusCode int
+		expectedCodes      []any
+	}{
+		{
+			name:   "error book not found",
+			method: http.MethodPost,
+			body:   query(t, `{ book(id: "`+miserable.String()+`") { title } }`, nil),
+			configureMock: func(bookService *mocks.MockBookService, _ *mocks.MockAuthorService) {
+				bookService.EXPECT().GetBookByID(gomock.Any(), miserable).Return(nil, book.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCodes:      []any{graph.CodeNotFound},
+		},
+		{
+			name:               "error invalid id",
+			method:             http.MethodPost,
+			body:               query(t, `{ author(id: "42") { name } }`, nil),
+			expectedStatusCode: http.StatusOK,
+			expectedCodes:      []any{graph.CodeBadUserInput},
+		},
+		{
+			name:               "error invalid cursor",
+			method:             http.MethodPost,
+			body:               query(t, `{ books(after: "nope") { totalCount } }`, nil),
+			expectedStatusCode: http.StatusOK,
+			expectedCodes:      []any{graph.CodeBadUserInput},
+		},
+		{
+			name:   "error duplicate author",
+			method: http.MethodPost,
+			body:   query(t, `mutation { createAuthor(input: {name: "Victor Hugo"}) { id } }`, nil),
+			configureMock: func(_ *mocks.MockBookService, authorService *mocks.MockAuthorService) {
+				authorService.EXPECT().
+					CreateAuthor(gomock.Any(), &authorDto.CreateAuthorRequest{Name: "Victor Hugo"}).
+					Return(nil, author.ErrDuplicate)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCodes:      []any{graph.CodeConflict},
+		},
+		{
+			name:               "error validation fails",
+			method:             http.MethodPost,
+			body:               query(t, `mutation { createAuthor(input: {name: ""}) { id } }`, nil),
+			expectedStatusCode: http.StatusOK,
+			expectedCodes:      []any{graph.CodeBadUserInput},
+		},
+		{
+			name:   "error internal hidden",
+			method: http.MethodPost,
+			body:   query(t, `{ book(id: "`+miserable.String()+`") { author { name } } }`, nil),
+			configureMock: func(bookService *mocks.MockBookService, authorService *mocks.MockAuthorService) {
+				bookService.EXPECT().GetBookByID(gomock.Any(), miserable).Return(&entity.Book{ID: miserable, AuthorID: hugoID}, nil)
+				authorService.EXPECT().GetAuthorsByIDs(gomock.Any(), []uuid.UUID{hugoID}).Return(nil, errors.New("connection refused"))
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCodes:      []any{graph.CodeInternal},
+		},
+		{
+			name:               "error query too deep",
+			method:             http.MethodPost,
+			body:               query(t, `{ author(id: "x") { books { nodes { author { books { nodes { title } } } } } } }`, nil),
+			expectedStatusCode: http.StatusOK,
+			expectedCodes:      []any{graph.CodeQueryTooDeep},
+		},
+		{
+			name:               "error query too complex",
+			method:             http.MethodPost,
+			body:               query(t, `query ($first: Int) { books(first: $first) { nodes { id title description createdAt updatedAt } } }`, map[string]any{"first": 50}),
+			expectedStatusCode: http.StatusOK,
+			expectedCodes:      []any{graph.CodeQueryTooComplex},
+		},
+		{
+			name:               "error mutation over GET",
+			method:             http.MethodGet,
+			body:               `mutation { deleteBook(id: "` + miserable.String() + `") }`,
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedCodes:      []any{nil},
+		},
+		{
+			name:               "error invalid JSON",
+			method:             http.MethodPost,
+			body:               "{",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCodes:      []any{nil},
+		},
+		{
+			name:               "error syntax",
+			method:             http.MethodPost,
+			body:               query(t, `{ books {`, nil),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCodes:      []any{nil},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, result := serve(t, test.configureMock, test.method, test.body)
+
+			assert.Equal(t, test.expectedStatusCode, code)
+			assert.Equal(t, test.expectedCodes, errorCodes(result))
+		})
+	}
+}
+
+func TestHandler_Mutations(t *testing.T) {
+	code, result := serve(t, func(bookService *mocks.MockBookService, _ *mocks.MockAuthorService) {
+		req := &bookDto.UpdateBookRequest{Description: "Jean Valjean et Cosette"}
+		gomock.InOrder(
+			bookService.EXPECT().UpdateBook(gomock.Any(), req, miserable).Return(nil),
+			bookService.EXPECT().GetBookByID(gomock.Any(), miserable).Return(&entity.Book{
+				ID:          miserable,
+				Description: "Jean Valjean et Cosette",
+				Author:      &entity.Author{ID: hugoID, Name: "Victor Hugo"},
+			}, nil),
+		)
+	}, http.MethodPost, query(t, `mutation ($id: ID!) {
+		updateBook(id: $id, input: {description: "Jean Valjean et Cosette"}) { description author { name } }
+	}`, map[string]any{"id": miserable.String()}))
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]any{
+		"updateBook": map[string]any{
+			"description": "Jean Valjean et Cosette",
+			"author":      map[string]any{"name": "Victor Hugo"},
+		},
+	}, result["data"])
+}
+
+func TestHandler_ValidationFields(t *testing.T) {
+	_, result := serve(t, nil, http.MethodPost, query(t, `{ books(authorId: "hugo") { totalCount } }`, nil))
+
+	errs := result["errors"].([]any)
+	require.Len(t, errs, 1)
+	assert.Equal(t, map[string]any{
+		"code":   graph.CodeBadUserInput,
+		"fields": []any{map[string]any{"field": "author_id", "message": "author_id must be a valid UUID", "tag": "uuid"}},
+	}, errs[0].(map[string]any)["extensions"])
+}
@@ -0,0 +1,153 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/pagination"
+)
+
+// operation returns the operation of doc to execute, nil when operationName matches none or
+// when it is required to pick one of several operations.
+func operation(doc *ast.Document, operationName string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+
+	for _, definition := range doc.Definitions {
+		op, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		if operationName == "" {
+			if found != nil {
+				return nil
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == operationName {
+			return op
+		}
+	}
+
+	return found
+}
+
+// limits measures a query before its execution. Introspection fields are not counted, so
+// the playground keeps working with low limits.
+type limits struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+	// visiting guards against the fragments spreading themselves, which the validation of
+	// the document reports.
+	visiting map[string]bool
+}
+
+func checkLimits(doc *ast.Document, op *ast.OperationDefinition, variables map[string]any, cfg config.GraphQLConfig) error {
+	l := &limits{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+		visiting:  make(map[string]bool),
+	}
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			l.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	if depth := l.depth(op.SelectionSet); cfg.MaxDepth > 0 && depth > cfg.MaxDepth {
+		return &Error{
+			Message: fmt.Sprintf("query depth %d exceeds the maximum of %d", depth, cfg.MaxDepth),
+			Code:    CodeQueryTooDeep,
+		}
+	}
+
+	if complexity := l.complexity(op.SelectionSet); cfg.MaxComplexity > 0 && complexity > cfg.MaxComplexity {
+		return &Error{
+			Message: fmt.Sprintf("query complexity %d exceeds the maximum of %d", complexity, cfg.MaxComplexity),
+			Code:    CodeQueryTooComplex,
+		}
+	}
+
+	return nil
+}
+
+func (l *limits) depth(set *ast.SelectionSet) int {
+	deepest := 0
+
+	l.each(set, func(field *ast.Field) {
+		deepest = max(deepest, 1+l.depth(field.SelectionSet))
+	})
+
+	return deepest
+}
+
+// complexity costs one per field. The selection of a connection is multiplied by the
+// number of items requested.
+func (l *limits) complexity(set *ast.SelectionSet) int {
+	total := 0
+
+	l.each(set, func(field *ast.Field) {
+		total += 1 + l.pageSize(field)*l.complexity(field.SelectionSet)
+	})
+
+	return total
+}
+
+// each calls fn for the fields of set, looking into the fragments.
+func (l *limits) each(set *ast.SelectionSet, fn func(field *ast.Field)) {
+	if set == nil {
+		return
+	}
+
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if !strings.HasPrefix(selection.Name.Value, "__") {
+				fn(selection)
+			}
+		case *ast.InlineFragment:
+			l.each(selection.SelectionSet, fn)
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := l.fragments[name]
+			if !ok || l.visiting[name] {
+				continue
+			}
+
+			l.visiting[name] = true
+			l.each(fragment.SelectionSet, fn)
+			l.visiting[name] = false
+		}
+	}
+}
+
+// pageSize is the first argument of a connection field, 1 for the other fields.
+func (l *limits) pageSize(field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+
+		switch value := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(value.Value); err == nil && n > 0 {
+				return n
+			}
+		case *ast.Variable:
+			// Variables are decoded from JSON.
+			if n, ok := l.variables[value.Name.Value].(float64); ok && n > 0 {
+				return int(n)
+			}
+		}
+		return pagination.DefaultPageSize
+	}
+
+	if field.SelectionSet != nil && field.Name.Value == "books" {
+		return pagination.DefaultPageSize
+	}
+
+	return 1
+}
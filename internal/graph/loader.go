@@ -0,0 +1,107 @@
+package graph
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"go-boilerplate-rest-api-chi/internal/author"
+	"go-boilerplate-rest-api-chi/internal/entity"
+)
+
+// Loader batches the loads of a request. The resolvers get a thunk per key; the executor
+// calls the thunks once a whole level of the query is resolved, and the first call fetches
+// every key requested so far in one go. Keys are loaded at most once per request.
+type Loader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	current *batch[K, V]
+	thunks  map[K]func() (V, error)
+}
+
+type batch[K comparable, V any] struct {
+	keys   []K
+	once   sync.Once
+	values map[K]V
+	err    error
+}
+
+func NewLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:  fetch,
+		thunks: make(map[K]func() (V, error)),
+	}
+}
+
+// Load returns the thunk of key. A key missing from the fetched values resolves to the zero
+// value of V.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) func() (V, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if thunk, ok := l.thunks[key]; ok {
+		return thunk
+	}
+
+	if l.current == nil {
+		l.current = &batch[K, V]{}
+	}
+	b := l.current
+	b.keys = append(b.keys, key)
+
+	thunk := func() (V, error) {
+		b.once.Do(func() {
+			// The keys requested from now on go to the next batch.
+			l.mu.Lock()
+			if l.current == b {
+				l.current = nil
+			}
+			l.mu.Unlock()
+
+			b.values, b.err = l.fetch(ctx, b.keys)
+		})
+
+		if b.err != nil {
+			var zero V
+			return zero, b.err
+		}
+		return b.values[key], nil
+	}
+	l.thunks[key] = thunk
+
+	return thunk
+}
+
+// loaders are created for every request, so nothing is cached across requests.
+type loaders struct {
+	authors *Loader[uuid.UUID, *entity.Author]
+}
+
+func newLoaders(authorService author.AuthorService) *loaders {
+	return &loaders{
+		authors: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*entity.Author, error) {
+			authors, err := authorService.GetAuthorsByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			byID := make(map[uuid.UUID]*entity.Author, len(authors))
+			for _, a := range authors {
+				byID[a.ID] = a
+			}
+			return byID, nil
+		}),
+	}
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
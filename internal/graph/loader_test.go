@@ -0,0 +1,56 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/graph"
+)
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+
+	var batches [][]int
+	loader := graph.NewLoader(func(_ context.Context, keys []int) (map[int]string, error) {
+		batches = append(batches, keys)
+		if keys[0] == 0 {
+			return nil, errors.New("boom")
+		}
+
+		values := make(map[int]string)
+		for _, key := range keys {
+			if key != 3 {
+				values[key] = string(rune('a' + key - 1))
+			}
+		}
+		return values, nil
+	})
+
+	first := loader.Load(ctx, 1)
+	second := loader.Load(ctx, 2)
+	missing := loader.Load(ctx, 3)
+	again := loader.Load(ctx, 1)
+
+	value, err := second()
+	require.NoError(t, err)
+	assert.Equal(t, "b", value)
+	value, err = first()
+	require.NoError(t, err)
+	assert.Equal(t, "a", value)
+	value, err = again()
+	require.NoError(t, err)
+	assert.Equal(t, "a", value)
+	value, err = missing()
+	require.NoError(t, err)
+	assert.Empty(t, value)
+
+	// Once a batch is fetched, the new keys go to the next one.
+	_, err = loader.Load(ctx, 0)()
+	assert.EqualError(t, err, "boom")
+
+	assert.Equal(t, [][]int{{1, 2, 3}, {0}}, batches)
+}
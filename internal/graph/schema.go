@@ -0,0 +1,445 @@
+package graph
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/author"
+	authorDto "go-boilerplate-rest-api-chi/internal/author/dto"
+	"go-boilerplate-rest-api-chi/internal/book"
+	bookDto "go-boilerplate-rest-api-chi/internal/book/dto"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/pagination"
+	internalValidator "go-boilerplate-rest-api-chi/internal/validator"
+)
+
+const cursorPrefix = "offset:"
+
+type schemaBuilder struct {
+	bookService   book.BookService
+	authorService author.AuthorService
+	validator     *internalValidator.Validator
+	logger        zerolog.Logger
+}
+
+// connection is the page of books returned by the list fields, following the Relay
+// connection spec.
+type connection struct {
+	Edges      []edge
+	Nodes      []*entity.Book
+	PageInfo   pageInfo
+	TotalCount int64
+}
+
+type edge struct {
+	Cursor string
+	Node   *entity.Book
+}
+
+type pageInfo struct {
+	HasNextPage     bool
+	HasPreviousPage bool
+	StartCursor     *string
+	EndCursor       *string
+}
+
+func (s *schemaBuilder) build() (graphql.Schema, error) {
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage":     &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"hasPreviousPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"startCursor":     &graphql.Field{Type: graphql.String},
+			"endCursor":       &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	authorStatsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "AuthorStats",
+		Fields: graphql.Fields{
+			"bookCount":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"firstPublishedAt":  &graphql.Field{Type: graphql.DateTime},
+			"latestPublishedAt": &graphql.Field{Type: graphql.DateTime},
+		},
+	})
+
+	authorType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Author",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"name":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"updatedAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"stats": &graphql.Field{
+				Type: graphql.NewNonNull(authorStatsType),
+				Resolve: s.resolve(func(p graphql.ResolveParams) (any, error) {
+					return s.authorService.GetAuthorStats(p.Context, p.Source.(*entity.Author).ID)
+				}),
+			},
+		},
+	})
+
+	bookType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Book",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"title":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"description": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"createdAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"updatedAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"author": &graphql.Field{
+				Type:    graphql.NewNonNull(authorType),
+				Resolve: s.resolveBookAuthor,
+			},
+		},
+	})
+
+	edgeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "BookEdge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"node":   &graphql.Field{Type: graphql.NewNonNull(bookType)},
+		},
+	})
+
+	connectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "BookConnection",
+		Fields: graphql.Fields{
+			"edges":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edgeType)))},
+			"nodes":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(bookType)))},
+			"pageInfo":   &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+			"totalCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	connectionArgs := graphql.FieldConfigArgument{
+		"first": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: pagination.DefaultPageSize},
+		"after": &graphql.ArgumentConfig{Type: graphql.String},
+	}
+
+	authorType.AddFieldConfig("books", &graphql.Field{
+		Type: graphql.NewNonNull(connectionType),
+		Args: connectionArgs,
+		Resolve: s.resolve(func(p graphql.ResolveParams) (any, error) {
+			return s.connection(p, func(params pagination.Params) ([]*entity.Book, int64, error) {
+				books, total, err := s.authorService.GetAuthorBooks(p.Context, p.Source.(*entity.Author).ID, params)
+				if err != nil {
+					return nil, 0, err
+				}
+
+				nodes := make([]*entity.Book, len(books))
+				for i := range books {
+					nodes[i] = &books[i]
+				}
+				return nodes, total, nil
+			})
+		}),
+	})
+
+	idArgs := graphql.FieldConfigArgument{
+		"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+	}
+
+	booksArgs := graphql.FieldConfigArgument{
+		"title":    &graphql.ArgumentConfig{Type: graphql.String},
+		"authorId": &graphql.ArgumentConfig{Type: graphql.ID},
+	}
+	for name, arg := range connectionArgs {
+		booksArgs[name] = arg
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"book": &graphql.Field{
+				Type: bookType,
+				Args: idArgs,
+				Resolve: s.resolve(func(p graphql.ResolveParams) (any, error) {
+					id, err := idArg(p)
+					if err != nil {
+						return nil, err
+					}
+					return s.bookService.GetBookByID(p.Context, id)
+				}),
+			},
+			"books": &graphql.Field{
+				Type: graphql.NewNonNull(connectionType),
+				Args: booksArgs,
+				Resolve: s.resolve(func(p graphql.ResolveParams) (any, error) {
+					filter := &bookDto.BookFilter{}
+					filter.Title, _ = p.Args["title"].(string)
+					filter.AuthorID, _ = p.Args["authorId"].(string)
+					if err := s.validate(p.Context, filter); err != nil {
+						return nil, err
+					}
+
+					return s.connection(p, func(params pagination.Params) ([]*entity.Book, int64, error) {
+						return s.bookService.ListBooks(p.Context, filter, params)
+					})
+				}),
+			},
+			"author": &graphql.Field{
+				Type: authorType,
+				Args: idArgs,
+				Resolve: s.resolve(func(p graphql.ResolveParams) (any, error) {
+					id, err := idArg(p)
+					if err != nil {
+						return nil, err
+					}
+					return s.authorService.GetAuthorByID(p.Context, id)
+				}),
+			},
+		},
+	})
+
+	createBookInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateBookInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":       &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"description": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"authorId":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.ID)},
+		},
+	})
+
+	updateBookInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "UpdateBookInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"description": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	authorInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "AuthorInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createBook": &graphql.Field{
+				Type: graphql.NewNonNull(bookType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createBookInput)},
+				},
+				Resolve: s.resolve(func(p graphql.ResolveParams) (any, error) {
+					input := p.Args["input"].(map[string]any)
+					req := &bookDto.CreateBookRequest{
+						Title:       input["title"].(string),
+						Description: input["description"].(string),
+						AuthorID:    input["authorId"].(string),
+					}
+					if err := s.validate(p.Context, req); err != nil {
+						return nil, err
+					}
+					return s.bookService.CreateBook(p.Context, req)
+				}),
+			},
+			"updateBook": &graphql.Field{
+				Type: graphql.NewNonNull(bookType),
+				Args: graphql.FieldConfigArgument{
+					"id":    idArgs["id"],
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(updateBookInput)},
+				},
+				Resolve: s.resolve(func(p graphql.ResolveParams) (any, error) {
+					id, err := idArg(p)
+					if err != nil {
+						return nil, err
+					}
+
+					input := p.Args["input"].(map[string]any)
+					req := &bookDto.UpdateBookRequest{Description: input["description"].(string)}
+					if err := s.validate(p.Context, req); err != nil {
+						return nil, err
+					}
+
+					if err := s.bookService.UpdateBook(p.Context, req, id); err != nil {
+						return nil, err
+					}
+					return s.bookService.GetBookByID(p.Context, id)
+				}),
+			},
+			"deleteBook": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: idArgs,
+				Resolve: s.resolve(func(p graphql.ResolveParams) (any, error) {
+					id, err := idArg(p)
+					if err != nil {
+						return nil, err
+					}
+					if err := s.bookService.DeleteBook(p.Context, id); err != nil {
+						return nil, err
+					}
+					return true, nil
+				}),
+			},
+			"createAuthor": &graphql.Field{
+				Type: graphql.NewNonNull(authorType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(authorInput)},
+				},
+				Resolve: s.resolve(func(p graphql.ResolveParams) (any, error) {
+					input := p.Args["input"].(map[string]any)
+					req := &authorDto.CreateAuthorRequest{Name: input["name"].(string)}
+					if err := s.validate(p.Context, req); err != nil {
+						return nil, err
+					}
+					return s.authorService.CreateAuthor(p.Context, req)
+				}),
+			},
+			"renameAuthor": &graphql.Field{
+				Type: graphql.NewNonNull(authorType),
+				Args: graphql.FieldConfigArgument{
+					"id":    idArgs["id"],
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(authorInput)},
+				},
+				Resolve: s.resolve(func(p graphql.ResolveParams) (any, error) {
+					id, err := idArg(p)
+					if err != nil {
+						return nil, err
+					}
+
+					input := p.Args["input"].(map[string]any)
+					req := &authorDto.RenameAuthorRequest{Name: input["name"].(string)}
+					if err := s.validate(p.Context, req); err != nil {
+						return nil, err
+					}
+					return s.authorService.RenameAuthor(p.Context, id, req)
+				}),
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
+
+// resolve maps the errors of fn to GraphQL errors.
+func (s *schemaBuilder) resolve(fn graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		result, err := fn(p)
+		if err != nil {
+			return nil, s.toError(err)
+		}
+		return result, nil
+	}
+}
+
+// resolveBookAuthor defers to the author loader, so the authors of a list of books are
+// fetched in a single query.
+func (s *schemaBuilder) resolveBookAuthor(p graphql.ResolveParams) (any, error) {
+	b := p.Source.(*entity.Book)
+	if b.Author != nil {
+		return b.Author, nil
+	}
+
+	thunk := loadersFrom(p.Context).authors.Load(p.Context, b.AuthorID)
+
+	return func() (any, error) {
+		a, err := thunk()
+		if err != nil {
+			return nil, s.toError(err)
+		}
+		if a == nil {
+			return nil, s.toError(author.ErrNotFound)
+		}
+		return a, nil
+	}, nil
+}
+
+func (s *schemaBuilder) validate(ctx context.Context, req any) error {
+	if err := s.validator.Struct(req); err != nil {
+		return &Error{
+			Message: "Validation failed",
+			Code:    CodeBadUserInput,
+			Fields:  s.validator.FormatErrors(ctx, err),
+		}
+	}
+	return nil
+}
+
+// connection reads the first and after arguments of p and returns the matching page.
+func (s *schemaBuilder) connection(p graphql.ResolveParams, list func(params pagination.Params) ([]*entity.Book, int64, error)) (*connection, error) {
+	first, _ := p.Args["first"].(int)
+	if first < 1 || first > pagination.MaxPageSize {
+		return nil, &Error{
+			Message: fmt.Sprintf("first must be between 1 and %d", pagination.MaxPageSize),
+			Code:    CodeBadUserInput,
+		}
+	}
+
+	offset := 0
+	if after, ok := p.Args["after"].(string); ok {
+		decoded, err := decodeCursor(after)
+		if err != nil {
+			return nil, &Error{Message: "invalid cursor", Code: CodeBadUserInput}
+		}
+		offset = decoded + 1
+	}
+
+	books, total, err := list(pagination.Window(offset, first))
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &connection{
+		Edges:      make([]edge, len(books)),
+		Nodes:      books,
+		TotalCount: total,
+		PageInfo: pageInfo{
+			HasNextPage:     int64(offset+len(books)) < total,
+			HasPreviousPage: offset > 0,
+		},
+	}
+	for i, b := range books {
+		conn.Edges[i] = edge{Cursor: encodeCursor(offset + i), Node: b}
+	}
+	if len(books) > 0 {
+		conn.PageInfo.StartCursor = &conn.Edges[0].Cursor
+		conn.PageInfo.EndCursor = &conn.Edges[len(books)-1].Cursor
+	}
+
+	return conn, nil
+}
+
+func idArg(p graphql.ResolveParams) (uuid.UUID, error) {
+	id, err := uuid.Parse(p.Args["id"].(string))
+	if err != nil {
+		return uuid.Nil, &Error{Message: "invalid id", Code: CodeBadUserInput}
+	}
+	return id, nil
+}
+
+// Cursors are opaque to the clients, they only carry the offset of the item.
+func encodeCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	decoded, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	raw, ok := strings.CutPrefix(string(decoded), cursorPrefix)
+	if !ok {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+
+	offset, err := strconv.Atoi(raw)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+
+	return offset, nil
+}
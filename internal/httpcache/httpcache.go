@@ -0,0 +1,165 @@
+package httpcache
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/config"
+)
+
+// Rules are the caching rules of a route. Its reads marked Cacheable follow them.
+type Rules struct {
+	Policy config.CachePolicy
+	// Vary names the request headers the responses depend on, beside the Accept header of
+	// the negotiation.
+	Vary []string
+	// Memory, when set, keeps the responses of the collections of the route.
+	Memory *Memory
+}
+
+type contextKey struct{}
+
+// Middleware attaches the rules to the requests of the route.
+func (rules Rules) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, rules)))
+	})
+}
+
+func rulesFromContext(ctx context.Context) (Rules, bool) {
+	rules, ok := ctx.Value(contextKey{}).(Rules)
+	return rules, ok
+}
+
+// Cacheable sets the Cache-Control of the successful reads of the route, private for the
+// authenticated callers, and the Vary telling their variants apart. Without rules the
+// responses are left as they are.
+func Cacheable(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rules, ok := rulesFromContext(r.Context())
+		if !ok || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		_, authenticated := auth.FromContext(r.Context())
+		next.ServeHTTP(&controlWriter{
+			ResponseWriter: w,
+			cacheControl:   cacheControl(rules.Policy, authenticated),
+			vary:           rules.Vary,
+		}, r)
+	})
+}
+
+// Collection is Cacheable, the responses to the anonymous callers being also kept in the
+// memory of the rules when there is one. The items are left to the lookup cache.
+func Collection(next http.Handler) http.Handler {
+	// The memory sits behind Cacheable, to keep the responses with their Cache-Control.
+	return Cacheable(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rules, ok := rulesFromContext(r.Context()); ok && rules.Memory != nil {
+			rules.Memory.serve(w, r, rules.Policy, rules.Vary, next)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}))
+}
+
+func cacheControl(policy config.CachePolicy, authenticated bool) string {
+	directives := []string{"public"}
+	if authenticated {
+		directives[0] = "private"
+	}
+	directives = append(directives, fmt.Sprintf("max-age=%d", int(policy.MaxAge.Seconds())))
+	if policy.StaleWhileRevalidate > 0 {
+		directives = append(directives, fmt.Sprintf("stale-while-revalidate=%d", int(policy.StaleWhileRevalidate.Seconds())))
+	}
+
+	return strings.Join(directives, ", ")
+}
+
+// NotModified sets the Last-Modified of the response to modified and, when the If-Modified-Since
+// of the request is not older, answers 304 and returns true.
+func NotModified(w http.ResponseWriter, r *http.Request, modified time.Time) bool {
+	if modified.IsZero() {
+		return false
+	}
+
+	// The HTTP dates hold seconds only.
+	modified = modified.UTC().Truncate(time.Second)
+	w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || modified.After(since) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// controlWriter adds the caching headers once the status of the response is known.
+type controlWriter struct {
+	http.ResponseWriter
+	cacheControl string
+	vary         []string
+	wroteHeader  bool
+}
+
+func (w *controlWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+
+		header := w.Header()
+		if statusCode == http.StatusOK || statusCode == http.StatusNotModified {
+			header.Set("Cache-Control", w.cacheControl)
+		}
+		header.Set("Vary", strings.Join(mergeVary(header.Values("Vary"), w.vary), ", "))
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *controlWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *controlWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// mergeVary lists the headers of the Vary values and of vary once each, Accept first as the
+// renderers vary with it.
+func mergeVary(values []string, vary []string) []string {
+	merged := []string{"Accept"}
+	seen := map[string]bool{"accept": true}
+
+	add := func(name string) {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			return
+		}
+		seen[strings.ToLower(name)] = true
+		merged = append(merged, name)
+	}
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			add(name)
+		}
+	}
+	for _, name := range vary {
+		add(name)
+	}
+
+	return merged
+}
@@ -0,0 +1,162 @@
+package httpcache_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/httpcache"
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+var rules = httpcache.Rules{
+	Policy: config.CachePolicy{MaxAge: time.Minute, StaleWhileRevalidate: 30 * time.Second},
+	Vary:   []string{"Authorization", "API-Version"},
+}
+
+func TestCacheable(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response.Render(w, r, http.StatusOK, map[string]string{"status": "success"})
+	})
+
+	tests := []struct {
+		name                 string
+		rules                *httpcache.Rules
+		principal            *auth.Principal
+		handler              http.Handler
+		expectedCacheControl string
+		expectedVary         string
+	}{
+		{
+			name:                 "success public",
+			rules:                &rules,
+			handler:              ok,
+			expectedCacheControl: "public, max-age=60, stale-while-revalidate=30",
+			expectedVary:         "Accept, Authorization, API-Version",
+		},
+		{
+			name:                 "success private when authenticated",
+			rules:                &rules,
+			principal:            &auth.Principal{Subject: "42"},
+			handler:              ok,
+			expectedCacheControl: "private, max-age=60, stale-while-revalidate=30",
+			expectedVary:         "Accept, Authorization, API-Version",
+		},
+		{
+			name:                 "success without stale-while-revalidate",
+			rules:                &httpcache.Rules{Policy: config.CachePolicy{MaxAge: time.Hour}},
+			handler:              ok,
+			expectedCacheControl: "public, max-age=3600",
+			expectedVary:         "Accept",
+		},
+		{
+			name:  "success errors not cached",
+			rules: &rules,
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response.Error(w, http.StatusNotFound, "Book not found")
+			}),
+			expectedVary: "Accept, Authorization, API-Version",
+		},
+		{
+			name:    "success without rules",
+			handler: ok,
+			// The Vary of the negotiation only.
+			expectedVary: "Accept",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := httpcache.Cacheable(test.handler)
+			if test.rules != nil {
+				handler = test.rules.Middleware(handler)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/books", nil)
+			if test.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), test.principal))
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedCacheControl, w.Header().Get("Cache-Control"))
+			assert.Equal(t, test.expectedVary, w.Header().Get("Vary"))
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	modified := time.Date(2026, 3, 2, 8, 30, 0, 999, time.UTC)
+
+	tests := []struct {
+		name               string
+		method             string
+		ifModifiedSince    string
+		modified           time.Time
+		expectedNotMod     bool
+		expectedStatusCode int
+		expectedHeader     string
+	}{
+		{
+			name:               "success no condition",
+			method:             http.MethodGet,
+			modified:           modified,
+			expectedStatusCode: http.StatusOK,
+			expectedHeader:     "Mon, 02 Mar 2026 08:30:00 GMT",
+		},
+		{
+			name:               "success same second",
+			method:             http.MethodGet,
+			ifModifiedSince:    "Mon, 02 Mar 2026 08:30:00 GMT",
+			modified:           modified,
+			expectedNotMod:     true,
+			expectedStatusCode: http.StatusNotModified,
+			expectedHeader:     "Mon, 02 Mar 2026 08:30:00 GMT",
+		},
+		{
+			name:               "success later copy",
+			method:             http.MethodHead,
+			ifModifiedSince:    "Tue, 03 Mar 2026 00:00:00 GMT",
+			modified:           modified,
+			expectedNotMod:     true,
+			expectedStatusCode: http.StatusNotModified,
+			expectedHeader:     "Mon, 02 Mar 2026 08:30:00 GMT",
+		},
+		{
+			name:               "success older copy",
+			method:             http.MethodGet,
+			ifModifiedSince:    "Mon, 02 Mar 2026 08:29:59 GMT",
+			modified:           modified,
+			expectedStatusCode: http.StatusOK,
+			expectedHeader:     "Mon, 02 Mar 2026 08:30:00 GMT",
+		},
+		{
+			name:               "success unknown date",
+			method:             http.MethodGet,
+			ifModifiedSince:    "Mon, 02 Mar 2026 08:30:00 GMT",
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/books/1", nil)
+			if test.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", test.ifModifiedSince)
+			}
+			w := httptest.NewRecorder()
+
+			notModified := httpcache.NotModified(w, req, test.modified)
+
+			assert.Equal(t, test.expectedNotMod, notModified)
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedHeader, w.Header().Get("Last-Modified"))
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package httpcache
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/cache"
+	"go-boilerplate-rest-api-chi/internal/config"
+)
+
+// Memory keeps whole responses in process for their max age, so the hot collections are not
+// read again from the database by every anonymous caller.
+type Memory struct {
+	store      *cache.MemoryStore
+	generation atomic.Uint64
+	logger     zerolog.Logger
+}
+
+// NewMemory keeps at most size responses, the least recently used being evicted first.
+func NewMemory(size int, logger zerolog.Logger) *Memory {
+	return &Memory{
+		store:  cache.NewMemoryStore(size),
+		logger: logger,
+	}
+}
+
+// Purge drops every response kept, once the catalogue changes.
+func (m *Memory) Purge() {
+	m.generation.Add(1)
+}
+
+type storedResponse struct {
+	Header   http.Header
+	Body     []byte
+	StoredAt time.Time
+}
+
+// serve answers from memory, or through next keeping its response. Only the successful
+// responses to the anonymous GET requests are kept.
+func (m *Memory) serve(w http.ResponseWriter, r *http.Request, policy config.CachePolicy, vary []string, next http.Handler) {
+	if _, authenticated := auth.FromContext(r.Context()); authenticated || r.Method != http.MethodGet {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	key := m.key(r, vary)
+	if value, ok, _ := m.store.Get(r.Context(), key); ok {
+		var stored storedResponse
+		if err := json.Unmarshal(value, &stored); err == nil {
+			header := w.Header()
+			for name, values := range stored.Header {
+				header[name] = values
+			}
+			header.Set("Age", strconv.Itoa(int(time.Since(stored.StoredAt).Seconds())))
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(stored.Body)
+			return
+		}
+	}
+
+	// The headers already set, such as the RateLimit ones, belong to the caller.
+	before := w.Header().Clone()
+	recorder := &recordingWriter{ResponseWriter: w}
+	next.ServeHTTP(recorder, r)
+	if recorder.statusCode != http.StatusOK {
+		return
+	}
+
+	header := make(http.Header)
+	for name, values := range w.Header() {
+		if !slices.Equal(before[name], values) {
+			header[name] = values
+		}
+	}
+	value, err := json.Marshal(storedResponse{Header: header, Body: recorder.body.Bytes(), StoredAt: time.Now()})
+	if err != nil {
+		m.logger.Error().Err(err).Str("url", r.URL.String()).Msg("failed to keep the response in memory")
+		return
+	}
+	_ = m.store.Set(r.Context(), key, value, policy.MaxAge)
+}
+
+// key tells apart the responses of the URL, by the host naming the tenant and by the
+// headers they vary with.
+func (m *Memory) key(r *http.Request, vary []string) string {
+	parts := []string{strconv.FormatUint(m.generation.Load(), 10), r.Host, r.URL.RequestURI(), r.Header.Get("Accept")}
+	for _, name := range vary {
+		parts = append(parts, r.Header.Get(name))
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// recordingWriter copies the response written through it.
+type recordingWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
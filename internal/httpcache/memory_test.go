@@ -0,0 +1,115 @@
+package httpcache_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/httpcache"
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+func TestCollection_Memory(t *testing.T) {
+	var calls atomic.Int32
+	books := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Query().Get("page") == "0" {
+			response.Error(w, http.StatusBadRequest, "Invalid page")
+			return
+		}
+		w.Header().Set("X-Total-Count", "2")
+		response.Render(w, r, http.StatusOK, map[string]int32{"calls": calls.Load()})
+	})
+
+	newHandler := func() (http.Handler, *httpcache.Memory) {
+		memory := httpcache.NewMemory(10, zerolog.Nop())
+		withMemory := rules
+		withMemory.Memory = memory
+		return withMemory.Middleware(httpcache.Collection(books)), memory
+	}
+	get := func(handler http.Handler, target string, configure func(*http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if configure != nil {
+			configure(req)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("success served from memory", func(t *testing.T) {
+		calls.Store(0)
+		handler, _ := newHandler()
+
+		first := get(handler, "/books", nil)
+		second := get(handler, "/books", func(r *http.Request) { r.Header.Set("Accept", "application/json") })
+		third := get(handler, "/books", nil)
+
+		assert.Equal(t, int32(2), calls.Load(), "the Accept header makes another variant")
+		assert.Equal(t, first.Body.String(), third.Body.String())
+		assert.Equal(t, "2", third.Header().Get("X-Total-Count"))
+		assert.Equal(t, "public, max-age=60, stale-while-revalidate=30", third.Header().Get("Cache-Control"))
+		assert.Equal(t, "Accept, Authorization, API-Version", third.Header().Get("Vary"))
+		assert.Equal(t, "0", third.Header().Get("Age"))
+		assert.Empty(t, first.Header().Get("Age"))
+		assert.Equal(t, http.StatusOK, second.Code)
+	})
+
+	t.Run("success caller headers not kept", func(t *testing.T) {
+		calls.Store(0)
+		route, _ := newHandler()
+		// Stands for the rate limiter, setting its headers before the route.
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("RateLimit-Remaining", r.Header.Get("X-Remaining"))
+			route.ServeHTTP(w, r)
+		})
+		limited := func(remaining string) *httptest.ResponseRecorder {
+			return get(handler, "/books", func(r *http.Request) { r.Header.Set("X-Remaining", remaining) })
+		}
+
+		assert.Equal(t, "9", limited("9").Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "8", limited("8").Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("success authenticated callers not served from memory", func(t *testing.T) {
+		calls.Store(0)
+		handler, _ := newHandler()
+		authenticated := func(r *http.Request) {
+			*r = *r.WithContext(auth.WithPrincipal(r.Context(), &auth.Principal{Subject: "42"}))
+		}
+
+		get(handler, "/books", authenticated)
+		w := get(handler, "/books", authenticated)
+
+		assert.Equal(t, int32(2), calls.Load())
+		assert.Equal(t, "private, max-age=60, stale-while-revalidate=30", w.Header().Get("Cache-Control"))
+	})
+
+	t.Run("success purged", func(t *testing.T) {
+		calls.Store(0)
+		handler, memory := newHandler()
+
+		get(handler, "/books", nil)
+		memory.Purge()
+		get(handler, "/books", nil)
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("success errors not kept", func(t *testing.T) {
+		calls.Store(0)
+		handler, _ := newHandler()
+
+		get(handler, "/books?page=0", nil)
+		w := get(handler, "/books?page=0", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
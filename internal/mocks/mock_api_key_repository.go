@@ -0,0 +1,146 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: go-boilerplate-rest-api-chi/internal/apikey (interfaces: APIKeyRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/mock_api_key_repository.go -package=mocks go-boilerplate-rest-api-chi/internal/apikey APIKeyRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	entity "go-boilerplate-rest-api-chi/internal/entity"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAPIKeyRepository is a mock of APIKeyRepository interface.
+type MockAPIKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIKeyRepositoryMockRecorder is the mock recorder for MockAPIKeyRepository.
+type MockAPIKeyRepositoryMockRecorder struct {
+	mock *MockAPIKeyRepository
+}
+
+// NewMockAPIKeyRepository creates a new mock instance.
+func NewMockAPIKeyRepository(ctrl *gomock.Controller) *MockAPIKeyRepository {
+	mock := &MockAPIKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyRepository) EXPECT() *MockAPIKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateKey mocks base method.
+func (m *MockAPIKeyRepository) CreateKey(ctx context.Context, key *entity.APIKey) (*entity.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKey", ctx, key)
+	ret0, _ := ret[0].(*entity.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateKey indicates an expected call of CreateKey.
+func (mr *MockAPIKeyRepositoryMockRecorder) CreateKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKey", reflect.TypeOf((*MockAPIKeyRepository)(nil).CreateKey), ctx, key)
+}
+
+// GetKeyByID mocks base method.
+func (m *MockAPIKeyRepository) GetKeyByID(ctx context.Context, keyID uuid.UUID) (*entity.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyByID", ctx, keyID)
+	ret0, _ := ret[0].(*entity.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyByID indicates an expected call of GetKeyByID.
+func (mr *MockAPIKeyRepositoryMockRecorder) GetKeyByID(ctx, keyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyByID", reflect.TypeOf((*MockAPIKeyRepository)(nil).GetKeyByID), ctx, keyID)
+}
+
+// GetKeyByPrefix mocks base method.
+func (m *MockAPIKeyRepository) GetKeyByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyByPrefix", ctx, prefix)
+	ret0, _ := ret[0].(*entity.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyByPrefix indicates an expected call of GetKeyByPrefix.
+func (mr *MockAPIKeyRepositoryMockRecorder) GetKeyByPrefix(ctx, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyByPrefix", reflect.TypeOf((*MockAPIKeyRepository)(nil).GetKeyByPrefix), ctx, prefix)
+}
+
+// GetKeys mocks base method.
+func (m *MockAPIKeyRepository) GetKeys(ctx context.Context) ([]*entity.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeys", ctx)
+	ret0, _ := ret[0].([]*entity.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeys indicates an expected call of GetKeys.
+func (mr *MockAPIKeyRepositoryMockRecorder) GetKeys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeys", reflect.TypeOf((*MockAPIKeyRepository)(nil).GetKeys), ctx)
+}
+
+// RevokeKey mocks base method.
+func (m *MockAPIKeyRepository) RevokeKey(ctx context.Context, keyID uuid.UUID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeKey", ctx, keyID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeKey indicates an expected call of RevokeKey.
+func (mr *MockAPIKeyRepositoryMockRecorder) RevokeKey(ctx, keyID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeKey", reflect.TypeOf((*MockAPIKeyRepository)(nil).RevokeKey), ctx, keyID, at)
+}
+
+// TouchKey mocks base method.
+func (m *MockAPIKeyRepository) TouchKey(ctx context.Context, keyID uuid.UUID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchKey", ctx, keyID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchKey indicates an expected call of TouchKey.
+func (mr *MockAPIKeyRepositoryMockRecorder) TouchKey(ctx, keyID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchKey", reflect.TypeOf((*MockAPIKeyRepository)(nil).TouchKey), ctx, keyID, at)
+}
+
+// UpdateSecret mocks base method.
+func (m *MockAPIKeyRepository) UpdateSecret(ctx context.Context, keyID uuid.UUID, prefix, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSecret", ctx, keyID, prefix, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSecret indicates an expected call of UpdateSecret.
+func (mr *MockAPIKeyRepositoryMockRecorder) UpdateSecret(ctx, keyID, prefix, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecret", reflect.TypeOf((*MockAPIKeyRepository)(nil).UpdateSecret), ctx, keyID, prefix, hash)
+}
@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	entity "go-boilerplate-rest-api-chi/internal/entity"
+	pagination "go-boilerplate-rest-api-chi/internal/pagination"
 	reflect "reflect"
 
 	uuid "github.com/google/uuid"
@@ -72,17 +73,83 @@ func (mr *MockAuthorRepositoryMockRecorder) Exists(ctx, authorID any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockAuthorRepository)(nil).Exists), ctx, authorID)
 }
 
+// GetBooks mocks base method.
+func (m *MockAuthorRepository) GetBooks(ctx context.Context, authorID uuid.UUID, params pagination.Params) ([]entity.Book, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBooks", ctx, authorID, params)
+	ret0, _ := ret[0].([]entity.Book)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBooks indicates an expected call of GetBooks.
+func (mr *MockAuthorRepositoryMockRecorder) GetBooks(ctx, authorID, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockAuthorRepository)(nil).GetBooks), ctx, authorID, params)
+}
+
 // GetByID mocks base method.
-func (m *MockAuthorRepository) GetByID(ctx context.Context, authorID uuid.UUID) (*entity.Author, error) {
+func (m *MockAuthorRepository) GetByID(ctx context.Context, authorID uuid.UUID, expand ...string) (*entity.Author, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", ctx, authorID)
+	varargs := []any{ctx, authorID}
+	for _, a := range expand {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetByID", varargs...)
 	ret0, _ := ret[0].(*entity.Author)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockAuthorRepositoryMockRecorder) GetByID(ctx, authorID any) *gomock.Call {
+func (mr *MockAuthorRepositoryMockRecorder) GetByID(ctx, authorID any, expand ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, authorID}, expand...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAuthorRepository)(nil).GetByID), varargs...)
+}
+
+// GetByIDs mocks base method.
+func (m *MockAuthorRepository) GetByIDs(ctx context.Context, authorIDs []uuid.UUID) ([]*entity.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, authorIDs)
+	ret0, _ := ret[0].([]*entity.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockAuthorRepositoryMockRecorder) GetByIDs(ctx, authorIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockAuthorRepository)(nil).GetByIDs), ctx, authorIDs)
+}
+
+// GetStats mocks base method.
+func (m *MockAuthorRepository) GetStats(ctx context.Context, authorID uuid.UUID) (*entity.AuthorStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStats", ctx, authorID)
+	ret0, _ := ret[0].(*entity.AuthorStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockAuthorRepositoryMockRecorder) GetStats(ctx, authorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockAuthorRepository)(nil).GetStats), ctx, authorID)
+}
+
+// Rename mocks base method.
+func (m *MockAuthorRepository) Rename(ctx context.Context, authorID uuid.UUID, name string) (*entity.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", ctx, authorID, name)
+	ret0, _ := ret[0].(*entity.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockAuthorRepositoryMockRecorder) Rename(ctx, authorID, name any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAuthorRepository)(nil).GetByID), ctx, authorID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockAuthorRepository)(nil).Rename), ctx, authorID, name)
 }
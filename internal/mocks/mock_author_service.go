@@ -13,6 +13,7 @@ import (
 	context "context"
 	dto "go-boilerplate-rest-api-chi/internal/author/dto"
 	entity "go-boilerplate-rest-api-chi/internal/entity"
+	pagination "go-boilerplate-rest-api-chi/internal/pagination"
 	reflect "reflect"
 
 	uuid "github.com/google/uuid"
@@ -58,17 +59,83 @@ func (mr *MockAuthorServiceMockRecorder) CreateAuthor(ctx, req any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthor", reflect.TypeOf((*MockAuthorService)(nil).CreateAuthor), ctx, req)
 }
 
+// GetAuthorBooks mocks base method.
+func (m *MockAuthorService) GetAuthorBooks(ctx context.Context, authorID uuid.UUID, params pagination.Params) ([]entity.Book, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorBooks", ctx, authorID, params)
+	ret0, _ := ret[0].([]entity.Book)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAuthorBooks indicates an expected call of GetAuthorBooks.
+func (mr *MockAuthorServiceMockRecorder) GetAuthorBooks(ctx, authorID, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorBooks", reflect.TypeOf((*MockAuthorService)(nil).GetAuthorBooks), ctx, authorID, params)
+}
+
 // GetAuthorByID mocks base method.
-func (m *MockAuthorService) GetAuthorByID(ctx context.Context, authorID uuid.UUID) (*entity.Author, error) {
+func (m *MockAuthorService) GetAuthorByID(ctx context.Context, authorID uuid.UUID, expand ...string) (*entity.Author, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAuthorByID", ctx, authorID)
+	varargs := []any{ctx, authorID}
+	for _, a := range expand {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAuthorByID", varargs...)
 	ret0, _ := ret[0].(*entity.Author)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAuthorByID indicates an expected call of GetAuthorByID.
-func (mr *MockAuthorServiceMockRecorder) GetAuthorByID(ctx, authorID any) *gomock.Call {
+func (mr *MockAuthorServiceMockRecorder) GetAuthorByID(ctx, authorID any, expand ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, authorID}, expand...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorByID", reflect.TypeOf((*MockAuthorService)(nil).GetAuthorByID), varargs...)
+}
+
+// GetAuthorStats mocks base method.
+func (m *MockAuthorService) GetAuthorStats(ctx context.Context, authorID uuid.UUID) (*entity.AuthorStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorStats", ctx, authorID)
+	ret0, _ := ret[0].(*entity.AuthorStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorStats indicates an expected call of GetAuthorStats.
+func (mr *MockAuthorServiceMockRecorder) GetAuthorStats(ctx, authorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorStats", reflect.TypeOf((*MockAuthorService)(nil).GetAuthorStats), ctx, authorID)
+}
+
+// GetAuthorsByIDs mocks base method.
+func (m *MockAuthorService) GetAuthorsByIDs(ctx context.Context, authorIDs []uuid.UUID) ([]*entity.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorsByIDs", ctx, authorIDs)
+	ret0, _ := ret[0].([]*entity.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorsByIDs indicates an expected call of GetAuthorsByIDs.
+func (mr *MockAuthorServiceMockRecorder) GetAuthorsByIDs(ctx, authorIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorsByIDs", reflect.TypeOf((*MockAuthorService)(nil).GetAuthorsByIDs), ctx, authorIDs)
+}
+
+// RenameAuthor mocks base method.
+func (m *MockAuthorService) RenameAuthor(ctx context.Context, authorID uuid.UUID, req *dto.RenameAuthorRequest) (*entity.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameAuthor", ctx, authorID, req)
+	ret0, _ := ret[0].(*entity.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameAuthor indicates an expected call of RenameAuthor.
+func (mr *MockAuthorServiceMockRecorder) RenameAuthor(ctx, authorID, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorByID", reflect.TypeOf((*MockAuthorService)(nil).GetAuthorByID), ctx, authorID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameAuthor", reflect.TypeOf((*MockAuthorService)(nil).RenameAuthor), ctx, authorID, req)
 }
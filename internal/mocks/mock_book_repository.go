@@ -11,7 +11,9 @@ package mocks
 
 import (
 	context "context"
+	dto "go-boilerplate-rest-api-chi/internal/book/dto"
 	entity "go-boilerplate-rest-api-chi/internal/entity"
+	pagination "go-boilerplate-rest-api-chi/internal/pagination"
 	reflect "reflect"
 
 	uuid "github.com/google/uuid"
@@ -72,33 +74,78 @@ func (mr *MockBookRepositoryMockRecorder) Delete(ctx, bookID any) *gomock.Call {
 }
 
 // GetAll mocks base method.
-func (m *MockBookRepository) GetAll(ctx context.Context) ([]*entity.Book, error) {
+func (m *MockBookRepository) GetAll(ctx context.Context, filter *dto.BookFilter, expand ...string) ([]*entity.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", ctx)
+	varargs := []any{ctx, filter}
+	for _, a := range expand {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAll", varargs...)
 	ret0, _ := ret[0].([]*entity.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockBookRepositoryMockRecorder) GetAll(ctx any) *gomock.Call {
+func (mr *MockBookRepositoryMockRecorder) GetAll(ctx, filter any, expand ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBookRepository)(nil).GetAll), ctx)
+	varargs := append([]any{ctx, filter}, expand...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBookRepository)(nil).GetAll), varargs...)
 }
 
 // GetByID mocks base method.
-func (m *MockBookRepository) GetByID(ctx context.Context, bookID uuid.UUID) (*entity.Book, error) {
+func (m *MockBookRepository) GetByID(ctx context.Context, bookID uuid.UUID, expand ...string) (*entity.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", ctx, bookID)
+	varargs := []any{ctx, bookID}
+	for _, a := range expand {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetByID", varargs...)
 	ret0, _ := ret[0].(*entity.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockBookRepositoryMockRecorder) GetByID(ctx, bookID any) *gomock.Call {
+func (mr *MockBookRepositoryMockRecorder) GetByID(ctx, bookID any, expand ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBookRepository)(nil).GetByID), ctx, bookID)
+	varargs := append([]any{ctx, bookID}, expand...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBookRepository)(nil).GetByID), varargs...)
+}
+
+// List mocks base method.
+func (m *MockBookRepository) List(ctx context.Context, filter *dto.BookFilter, params pagination.Params, expand ...string) ([]*entity.Book, int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, filter, params}
+	for _, a := range expand {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "List", varargs...)
+	ret0, _ := ret[0].([]*entity.Book)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockBookRepositoryMockRecorder) List(ctx, filter, params any, expand ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, filter, params}, expand...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBookRepository)(nil).List), varargs...)
+}
+
+// Stream mocks base method.
+func (m *MockBookRepository) Stream(ctx context.Context, filter *dto.BookFilter, fn func([]*entity.Book) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockBookRepositoryMockRecorder) Stream(ctx, filter, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockBookRepository)(nil).Stream), ctx, filter, fn)
 }
 
 // Update mocks base method.
@@ -13,6 +13,7 @@ import (
 	context "context"
 	dto "go-boilerplate-rest-api-chi/internal/book/dto"
 	entity "go-boilerplate-rest-api-chi/internal/entity"
+	pagination "go-boilerplate-rest-api-chi/internal/pagination"
 	reflect "reflect"
 
 	uuid "github.com/google/uuid"
@@ -73,33 +74,78 @@ func (mr *MockBookServiceMockRecorder) DeleteBook(ctx, bookID any) *gomock.Call
 }
 
 // GetAllBooks mocks base method.
-func (m *MockBookService) GetAllBooks(ctx context.Context) ([]*entity.Book, error) {
+func (m *MockBookService) GetAllBooks(ctx context.Context, filter *dto.BookFilter, expand ...string) ([]*entity.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllBooks", ctx)
+	varargs := []any{ctx, filter}
+	for _, a := range expand {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAllBooks", varargs...)
 	ret0, _ := ret[0].([]*entity.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllBooks indicates an expected call of GetAllBooks.
-func (mr *MockBookServiceMockRecorder) GetAllBooks(ctx any) *gomock.Call {
+func (mr *MockBookServiceMockRecorder) GetAllBooks(ctx, filter any, expand ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllBooks", reflect.TypeOf((*MockBookService)(nil).GetAllBooks), ctx)
+	varargs := append([]any{ctx, filter}, expand...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllBooks", reflect.TypeOf((*MockBookService)(nil).GetAllBooks), varargs...)
 }
 
 // GetBookByID mocks base method.
-func (m *MockBookService) GetBookByID(ctx context.Context, bookID uuid.UUID) (*entity.Book, error) {
+func (m *MockBookService) GetBookByID(ctx context.Context, bookID uuid.UUID, expand ...string) (*entity.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBookByID", ctx, bookID)
+	varargs := []any{ctx, bookID}
+	for _, a := range expand {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetBookByID", varargs...)
 	ret0, _ := ret[0].(*entity.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBookByID indicates an expected call of GetBookByID.
-func (mr *MockBookServiceMockRecorder) GetBookByID(ctx, bookID any) *gomock.Call {
+func (mr *MockBookServiceMockRecorder) GetBookByID(ctx, bookID any, expand ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByID", reflect.TypeOf((*MockBookService)(nil).GetBookByID), ctx, bookID)
+	varargs := append([]any{ctx, bookID}, expand...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByID", reflect.TypeOf((*MockBookService)(nil).GetBookByID), varargs...)
+}
+
+// ListBooks mocks base method.
+func (m *MockBookService) ListBooks(ctx context.Context, filter *dto.BookFilter, params pagination.Params, expand ...string) ([]*entity.Book, int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, filter, params}
+	for _, a := range expand {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListBooks", varargs...)
+	ret0, _ := ret[0].([]*entity.Book)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListBooks indicates an expected call of ListBooks.
+func (mr *MockBookServiceMockRecorder) ListBooks(ctx, filter, params any, expand ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, filter, params}, expand...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBooks", reflect.TypeOf((*MockBookService)(nil).ListBooks), varargs...)
+}
+
+// StreamBooks mocks base method.
+func (m *MockBookService) StreamBooks(ctx context.Context, filter *dto.BookFilter, fn func([]*entity.Book) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamBooks", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamBooks indicates an expected call of StreamBooks.
+func (mr *MockBookServiceMockRecorder) StreamBooks(ctx, filter, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamBooks", reflect.TypeOf((*MockBookService)(nil).StreamBooks), ctx, filter, fn)
 }
 
 // UpdateBook mocks base method.
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: go-boilerplate-rest-api-chi/internal/tenant (interfaces: TenantRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/mock_tenant_repository.go -package=mocks go-boilerplate-rest-api-chi/internal/tenant TenantRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	entity "go-boilerplate-rest-api-chi/internal/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTenantRepository is a mock of TenantRepository interface.
+type MockTenantRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenantRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTenantRepositoryMockRecorder is the mock recorder for MockTenantRepository.
+type MockTenantRepositoryMockRecorder struct {
+	mock *MockTenantRepository
+}
+
+// NewMockTenantRepository creates a new mock instance.
+func NewMockTenantRepository(ctrl *gomock.Controller) *MockTenantRepository {
+	mock := &MockTenantRepository{ctrl: ctrl}
+	mock.recorder = &MockTenantRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTenantRepository) EXPECT() *MockTenantRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateTenant mocks base method.
+func (m *MockTenantRepository) CreateTenant(ctx context.Context, tenant *entity.Tenant) (*entity.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTenant", ctx, tenant)
+	ret0, _ := ret[0].(*entity.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTenant indicates an expected call of CreateTenant.
+func (mr *MockTenantRepositoryMockRecorder) CreateTenant(ctx, tenant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTenant", reflect.TypeOf((*MockTenantRepository)(nil).CreateTenant), ctx, tenant)
+}
+
+// GetTenantByID mocks base method.
+func (m *MockTenantRepository) GetTenantByID(ctx context.Context, tenantID string) (*entity.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenantByID", ctx, tenantID)
+	ret0, _ := ret[0].(*entity.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTenantByID indicates an expected call of GetTenantByID.
+func (mr *MockTenantRepositoryMockRecorder) GetTenantByID(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenantByID", reflect.TypeOf((*MockTenantRepository)(nil).GetTenantByID), ctx, tenantID)
+}
+
+// GetTenants mocks base method.
+func (m *MockTenantRepository) GetTenants(ctx context.Context) ([]*entity.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenants", ctx)
+	ret0, _ := ret[0].([]*entity.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTenants indicates an expected call of GetTenants.
+func (mr *MockTenantRepositoryMockRecorder) GetTenants(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenants", reflect.TypeOf((*MockTenantRepository)(nil).GetTenants), ctx)
+}
+
+// UpdateTenant mocks base method.
+func (m *MockTenantRepository) UpdateTenant(ctx context.Context, tenantID string, updates map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTenant", ctx, tenantID, updates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTenant indicates an expected call of UpdateTenant.
+func (mr *MockTenantRepositoryMockRecorder) UpdateTenant(ctx, tenantID, updates any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTenant", reflect.TypeOf((*MockTenantRepository)(nil).UpdateTenant), ctx, tenantID, updates)
+}
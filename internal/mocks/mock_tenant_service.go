@@ -0,0 +1,103 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: go-boilerplate-rest-api-chi/internal/tenant (interfaces: TenantService)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/mock_tenant_service.go -package=mocks go-boilerplate-rest-api-chi/internal/tenant TenantService
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	entity "go-boilerplate-rest-api-chi/internal/entity"
+	dto "go-boilerplate-rest-api-chi/internal/tenant/dto"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTenantService is a mock of TenantService interface.
+type MockTenantService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenantServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockTenantServiceMockRecorder is the mock recorder for MockTenantService.
+type MockTenantServiceMockRecorder struct {
+	mock *MockTenantService
+}
+
+// NewMockTenantService creates a new mock instance.
+func NewMockTenantService(ctrl *gomock.Controller) *MockTenantService {
+	mock := &MockTenantService{ctrl: ctrl}
+	mock.recorder = &MockTenantServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTenantService) EXPECT() *MockTenantServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateTenant mocks base method.
+func (m *MockTenantService) CreateTenant(ctx context.Context, req *dto.CreateTenantRequest) (*entity.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTenant", ctx, req)
+	ret0, _ := ret[0].(*entity.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTenant indicates an expected call of CreateTenant.
+func (mr *MockTenantServiceMockRecorder) CreateTenant(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTenant", reflect.TypeOf((*MockTenantService)(nil).CreateTenant), ctx, req)
+}
+
+// GetTenant mocks base method.
+func (m *MockTenantService) GetTenant(ctx context.Context, tenantID string) (*entity.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenant", ctx, tenantID)
+	ret0, _ := ret[0].(*entity.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTenant indicates an expected call of GetTenant.
+func (mr *MockTenantServiceMockRecorder) GetTenant(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenant", reflect.TypeOf((*MockTenantService)(nil).GetTenant), ctx, tenantID)
+}
+
+// GetTenants mocks base method.
+func (m *MockTenantService) GetTenants(ctx context.Context) ([]*entity.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenants", ctx)
+	ret0, _ := ret[0].([]*entity.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTenants indicates an expected call of GetTenants.
+func (mr *MockTenantServiceMockRecorder) GetTenants(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenants", reflect.TypeOf((*MockTenantService)(nil).GetTenants), ctx)
+}
+
+// UpdateTenant mocks base method.
+func (m *MockTenantService) UpdateTenant(ctx context.Context, tenantID string, req *dto.UpdateTenantRequest) (*entity.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTenant", ctx, tenantID, req)
+	ret0, _ := ret[0].(*entity.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTenant indicates an expected call of UpdateTenant.
+func (mr *MockTenantServiceMockRecorder) UpdateTenant(ctx, tenantID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTenant", reflect.TypeOf((*MockTenantService)(nil).UpdateTenant), ctx, tenantID, req)
+}
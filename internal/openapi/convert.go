@@ -0,0 +1,121 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Version is the OpenAPI version of the documents served by the API.
+const Version = "3.1.0"
+
+// Convert turns the Swagger 2.0 document generated by swag into an OpenAPI 3.1 document.
+func Convert(swagger []byte) (*openapi3.T, error) {
+	var doc2 openapi2.T
+	if err := json.Unmarshal(swagger, &doc2); err != nil {
+		return nil, fmt.Errorf("decode swagger document: %w", err)
+	}
+
+	doc, err := openapi2conv.ToV3(&doc2)
+	if err != nil {
+		return nil, fmt.Errorf("convert swagger document: %w", err)
+	}
+
+	// Without a host the base path is lost in the conversion, while a relative server keeps it.
+	if len(doc.Servers) == 0 && doc2.BasePath != "" {
+		doc.AddServer(&openapi3.Server{URL: doc2.BasePath})
+	}
+
+	upgrade(doc)
+
+	return doc, nil
+}
+
+// upgrade rewrites the OpenAPI 3.0 constructs that 3.1 dropped in favour of JSON Schema.
+func upgrade(doc *openapi3.T) {
+	doc.OpenAPI = Version
+
+	visited := make(map[*openapi3.Schema]bool)
+	walk := func(ref *openapi3.SchemaRef) { walkSchema(ref, visited) }
+
+	if doc.Components != nil {
+		for _, schema := range doc.Components.Schemas {
+			walk(schema)
+		}
+		for _, parameter := range doc.Components.Parameters {
+			walkParameter(parameter, walk)
+		}
+		for _, body := range doc.Components.RequestBodies {
+			walkContent(body.Value.Content, walk)
+		}
+		for _, resp := range doc.Components.Responses {
+			walkContent(resp.Value.Content, walk)
+		}
+	}
+
+	for _, item := range doc.Paths.Map() {
+		for _, parameter := range item.Parameters {
+			walkParameter(parameter, walk)
+		}
+		for _, operation := range item.Operations() {
+			for _, parameter := range operation.Parameters {
+				walkParameter(parameter, walk)
+			}
+			if operation.RequestBody != nil && operation.RequestBody.Value != nil {
+				walkContent(operation.RequestBody.Value.Content, walk)
+			}
+			for _, resp := range operation.Responses.Map() {
+				if resp.Value != nil {
+					walkContent(resp.Value.Content, walk)
+				}
+			}
+		}
+	}
+}
+
+func walkParameter(parameter *openapi3.ParameterRef, walk func(*openapi3.SchemaRef)) {
+	if parameter.Value != nil {
+		walk(parameter.Value.Schema)
+		walkContent(parameter.Value.Content, walk)
+	}
+}
+
+func walkContent(content openapi3.Content, walk func(*openapi3.SchemaRef)) {
+	for _, mediaType := range content {
+		walk(mediaType.Schema)
+	}
+}
+
+// walkSchema replaces nullable with a "null" type. Referenced schemas are upgraded where they
+// are defined.
+func walkSchema(ref *openapi3.SchemaRef, visited map[*openapi3.Schema]bool) {
+	if ref == nil || ref.Ref != "" || ref.Value == nil || visited[ref.Value] {
+		return
+	}
+	schema := ref.Value
+	visited[schema] = true
+
+	if schema.Nullable {
+		schema.Nullable = false
+		if schema.Type != nil && !slices.Contains(*schema.Type, openapi3.TypeNull) {
+			types := append(*schema.Type, openapi3.TypeNull)
+			schema.Type = &types
+		}
+	}
+
+	for _, property := range schema.Properties {
+		walkSchema(property, visited)
+	}
+	for _, refs := range []openapi3.SchemaRefs{schema.AllOf, schema.AnyOf, schema.OneOf} {
+		for _, item := range refs {
+			walkSchema(item, visited)
+		}
+	}
+	walkSchema(schema.Items, visited)
+	walkSchema(schema.Not, visited)
+	walkSchema(schema.AdditionalProperties.Schema, visited)
+}
@@ -0,0 +1,85 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/openapi"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name          string
+		swagger       string
+		expectedError string
+		assertDoc     func(t *testing.T, doc map[string]any)
+	}{
+		{
+			name: "success converts to openapi 3.1",
+			swagger: `{
+				"swagger": "2.0",
+				"info": {"title": "library", "version": "1.0"},
+				"basePath": "/api/v1",
+				"paths": {
+					"/books/{book_id}": {
+						"get": {
+							"produces": ["application/json"],
+							"parameters": [{"name": "book_id", "in": "path", "required": true, "type": "string"}],
+							"responses": {"200": {"description": "OK", "schema": {"$ref": "#/definitions/Book"}}}
+						}
+					}
+				},
+				"definitions": {
+					"Book": {
+						"type": "object",
+						"properties": {
+							"title": {"type": "string"},
+							"subtitle": {"type": "string", "x-nullable": true}
+						}
+					}
+				}
+			}`,
+			assertDoc: func(t *testing.T, doc map[string]any) {
+				assert.Equal(t, "3.1.0", doc["openapi"])
+				assert.Equal(t, []any{map[string]any{"url": "/api/v1"}}, doc["servers"])
+
+				book := doc["components"].(map[string]any)["schemas"].(map[string]any)["Book"].(map[string]any)
+				properties := book["properties"].(map[string]any)
+				assert.Equal(t, map[string]any{"type": []any{"string", "null"}}, properties["subtitle"])
+				assert.Equal(t, map[string]any{"type": "string"}, properties["title"])
+
+				get := doc["paths"].(map[string]any)["/books/{book_id}"].(map[string]any)["get"].(map[string]any)
+				schema := get["responses"].(map[string]any)["200"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"]
+				assert.Equal(t, map[string]any{"$ref": "#/components/schemas/Book"}, schema)
+			},
+		},
+		{
+			name:          "error invalid document",
+			swagger:       `{"swagger": `,
+			expectedError: "decode swagger document",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := openapi.Convert([]byte(test.swagger))
+
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			content, err := json.Marshal(doc)
+			require.NoError(t, err)
+
+			var decoded map[string]any
+			require.NoError(t, json.Unmarshal(content, &decoded))
+			test.assertDoc(t, decoded)
+		})
+	}
+}
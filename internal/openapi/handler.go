@@ -0,0 +1,74 @@
+package openapi
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+
+	"github.com/MarceloPetrucio/go-scalar-api-reference"
+	"github.com/rs/zerolog"
+)
+
+// Handler serves an OpenAPI document embedded at build time, in JSON and YAML, and its
+// reference page.
+type Handler struct {
+	json      []byte
+	yaml      []byte
+	reference []byte
+	logger    zerolog.Logger
+}
+
+// NewHandler reads name.json and name.yaml from fsys. It panics when they are missing, which
+// only happens when the documentation was not generated.
+func NewHandler(fsys fs.FS, name string, title string, logger zerolog.Logger) *Handler {
+	jsonContent, err := fs.ReadFile(fsys, name+".json")
+	if err != nil {
+		panic(fmt.Sprintf("openapi: %v", err))
+	}
+	yamlContent, err := fs.ReadFile(fsys, name+".yaml")
+	if err != nil {
+		panic(fmt.Sprintf("openapi: %v", err))
+	}
+
+	reference, err := scalar.ApiReferenceHTML(&scalar.Options{
+		SpecContent: string(jsonContent),
+		CustomOptions: scalar.CustomOptions{
+			PageTitle: title,
+		},
+		DarkMode:   true,
+		IsEditable: false,
+		Theme:      scalar.ThemeBluePlanet,
+		HideModels: true,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("openapi: %v", err))
+	}
+
+	return &Handler{
+		json:      jsonContent,
+		yaml:      yamlContent,
+		reference: []byte(reference),
+		logger:    logger,
+	}
+}
+
+func (h *Handler) JSON(w http.ResponseWriter, r *http.Request) {
+	h.write(w, "application/json", h.json)
+}
+
+func (h *Handler) YAML(w http.ResponseWriter, r *http.Request) {
+	h.write(w, "application/yaml", h.yaml)
+}
+
+// Reference serves the Scalar page documenting the API.
+func (h *Handler) Reference(w http.ResponseWriter, r *http.Request) {
+	h.write(w, "text/html; charset=utf-8", h.reference)
+}
+
+func (h *Handler) write(w http.ResponseWriter, contentType string, content []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(content); err != nil {
+		h.logger.Error().Err(err).Msg("failed to write the documentation")
+	}
+}
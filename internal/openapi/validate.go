@@ -0,0 +1,251 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/request"
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+// The parameters are only checked against the formats defined globally. uuid.Parse accepts
+// every version, where the pattern shipped by kin-openapi stops at the version 5.
+func init() {
+	openapi3.DefineStringFormatValidator("uuid", openapi3.NewCallbackValidator(func(value string) error {
+		_, err := uuid.Parse(value)
+		return err
+	}))
+}
+
+// Validator checks the requests against an OpenAPI document before they reach the handlers,
+// and optionally the responses, so that a drift of the contract fails in development and in
+// the tests.
+type Validator struct {
+	router            routers.Router
+	validateResponses bool
+	logger            zerolog.Logger
+}
+
+// NewValidator loads name.json from fsys. It panics when the document is missing or invalid,
+// which only happens when the documentation was not generated.
+func NewValidator(fsys fs.FS, name string, validateResponses bool, logger zerolog.Logger) *Validator {
+	content, err := fs.ReadFile(fsys, name+".json")
+	if err != nil {
+		panic(fmt.Sprintf("openapi: %v", err))
+	}
+
+	doc, err := openapi3.NewLoader().LoadFromData(content)
+	if err != nil {
+		panic(fmt.Sprintf("openapi: %v", err))
+	}
+
+	// The routes are matched on the path below the version router, whatever the prefix it is
+	// mounted on.
+	doc.Servers = nil
+
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		panic(fmt.Sprintf("openapi: %v", err))
+	}
+
+	return &Validator{
+		router:            router,
+		validateResponses: validateResponses,
+		logger:            logger,
+	}
+}
+
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(routedRequest(r))
+		if err != nil {
+			// The routes missing from the document are left to the router, which answers 404
+			// or 405.
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				MultiError:          true,
+				SkipSettingDefaults: true,
+				// The authentication is checked by the auth middlewares.
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			// A body over the limit of request.MaxBodySize is refused as DecodeJSON does.
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				request.WriteError(w, request.TooLarge(maxBytesErr.Limit))
+				return
+			}
+			response.ValidationError(w, details(err, "body"))
+			return
+		}
+
+		if !v.validateResponses {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+		v.checkResponse(r.Context(), w, input, recorder)
+	})
+}
+
+func (v *Validator) checkResponse(ctx context.Context, w http.ResponseWriter, input *openapi3filter.RequestValidationInput, recorder *responseRecorder) {
+	if recorder.passthrough {
+		return
+	}
+
+	status := recorder.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	err := openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 status,
+		Header:                 w.Header(),
+		Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+		Options: &openapi3filter.Options{
+			MultiError:            true,
+			IncludeResponseStatus: true,
+		},
+	})
+	if err != nil {
+		v.logger.Error().Err(err).
+			Str("method", input.Request.Method).
+			Str("path", input.Request.URL.Path).
+			Int("status", status).
+			Msg("response does not match the OpenAPI document")
+
+		response.JSON(w, http.StatusInternalServerError, response.ValidationErrorResponse{
+			Status:  "error",
+			Message: "Response does not match the OpenAPI document",
+			Errors:  details(err, "body"),
+		})
+		return
+	}
+
+	w.WriteHeader(status)
+	if _, err := w.Write(recorder.body.Bytes()); err != nil {
+		v.logger.Error().Err(err).Msg("failed to write the response")
+	}
+}
+
+// routedRequest shallow copies r with the path left to route by the version router.
+func routedRequest(r *http.Request) *http.Request {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.RoutePath == "" {
+		return r
+	}
+
+	routed := r.Clone(r.Context())
+	routed.URL.Path = rctx.RoutePath
+	return routed
+}
+
+// details flattens the validation errors of kin-openapi in the ValidationErrorDetail format.
+// field names the value the errors without a location of their own are about.
+func details(err error, field string) []response.ValidationErrorDetail {
+	switch err := err.(type) {
+	case openapi3.MultiError:
+		var all []response.ValidationErrorDetail
+		for _, e := range err {
+			all = append(all, details(e, field)...)
+		}
+		return all
+	case *openapi3filter.RequestError:
+		if err.Parameter != nil {
+			field = err.Parameter.Name
+		}
+		if err.Err == nil {
+			return []response.ValidationErrorDetail{{Field: field, Message: err.Reason}}
+		}
+		return details(err.Err, field)
+	case *openapi3filter.ResponseError:
+		if err.Err == nil {
+			return []response.ValidationErrorDetail{{Field: "status", Message: err.Reason}}
+		}
+		return details(err.Err, field)
+	case *openapi3filter.ParseError:
+		return []response.ValidationErrorDetail{{Field: field, Message: fmt.Sprintf("%s is malformed", field)}}
+	case *openapi3.SchemaError:
+		if pointer := err.JSONPointer(); len(pointer) > 0 {
+			field = strings.Join(pointer, ".")
+		}
+		switch err.SchemaField {
+		case "required":
+			return []response.ValidationErrorDetail{{Field: field, Message: fmt.Sprintf("%s is required", field)}}
+		case "format":
+			return []response.ValidationErrorDetail{{Field: field, Message: fmt.Sprintf("%s must be a valid %s", field, err.Schema.Format)}}
+		}
+		return []response.ValidationErrorDetail{{Field: field, Message: err.Reason}}
+	}
+
+	if err == openapi3filter.ErrInvalidRequired {
+		return []response.ValidationErrorDetail{{Field: field, Message: fmt.Sprintf("%s is required", field)}}
+	}
+	return []response.ValidationErrorDetail{{Field: field, Message: err.Error()}}
+}
+
+// responseRecorder holds back the JSON responses until they are validated, and lets the
+// others, such as the streamed exports, through.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	passthrough bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status != 0 {
+		return
+	}
+	r.status = status
+
+	contentType := r.Header().Get("Content-Type")
+	if mediaType, _, _ := mime.ParseMediaType(contentType); contentType != "" && mediaType != "application/json" {
+		r.passthrough = true
+		r.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	if r.passthrough {
+		return r.ResponseWriter.Write(p)
+	}
+	return r.body.Write(p)
+}
+
+// FlushError lets the streamed responses be flushed through http.ResponseController.
+func (r *responseRecorder) FlushError() error {
+	if !r.passthrough {
+		return nil
+	}
+	return http.NewResponseController(r.ResponseWriter).Flush()
+}
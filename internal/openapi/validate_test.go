@@ -0,0 +1,197 @@
+package openapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"go-boilerplate-rest-api-chi/internal/openapi"
+	"go-boilerplate-rest-api-chi/internal/request"
+)
+
+const spec = `{
+	"openapi": "3.1.0",
+	"info": {"title": "library", "version": "1.0"},
+	"servers": [{"url": "/api/v1"}],
+	"paths": {
+		"/books": {
+			"post": {
+				"requestBody": {
+					"required": true,
+					"content": {"application/json": {"schema": {
+						"type": "object",
+						"required": ["title", "author_id"],
+						"properties": {
+							"title": {"type": "string"},
+							"author_id": {"type": "string", "format": "uuid"}
+						}
+					}}}
+				},
+				"responses": {"201": {"description": "Created", "content": {"application/json": {"schema": {
+					"type": "object",
+					"required": ["id"],
+					"properties": {"id": {"type": "string"}}
+				}}}}}
+			}
+		},
+		"/books/{book_id}": {
+			"get": {
+				"parameters": [{"name": "book_id", "in": "path", "required": true, "schema": {"type": "string", "format": "uuid"}}],
+				"responses": {"200": {"description": "OK", "content": {"text/csv": {"schema": {"type": "string"}}}}}
+			}
+		}
+	}
+}`
+
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		url                string
+		requestBody        string
+		handler            http.HandlerFunc
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:        "success valid request and response",
+			method:      http.MethodPost,
+			url:         "/api/v1/books",
+			requestBody: `{"title": "Les Misérables", "author_id": "aeca0955-bae4-47e9-9f85-6818dc68ca51"}`,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id": "1"}`))
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedBody:       `{"id": "1"}`,
+		},
+		{
+			name:               "error invalid body",
+			method:             http.MethodPost,
+			url:                "/api/v1/books",
+			requestBody:        `{"author_id": "42"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody: `{"status": "error", "message": "Validation failed", "errors": [
+				{"field": "author_id", "message": "author_id must be a valid uuid"},
+				{"field": "title", "message": "title is required"}
+			]}`,
+		},
+		{
+			name:               "error malformed body",
+			method:             http.MethodPost,
+			url:                "/api/v1/books",
+			requestBody:        `{"title": `,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"status": "error", "message": "Validation failed", "errors": [{"field": "body", "message": "body is malformed"}]}`,
+		},
+		{
+			name:               "error body too large",
+			method:             http.MethodPost,
+			url:                "/api/v1/books",
+			requestBody:        `{"title": "` + strings.Repeat("Les Misérables ", 10) + `", "author_id": "aeca0955-bae4-47e9-9f85-6818dc68ca51"}`,
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+			expectedBody: `{"status": "error", "message": "Request body too large", "errors": [
+				{"field": "body", "message": "body must not exceed 128 bytes", "tag": "max_size", "param": "128"}
+			]}`,
+		},
+		{
+			name:               "error invalid path parameter",
+			method:             http.MethodGet,
+			url:                "/api/v1/books/42",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"status": "error", "message": "Validation failed", "errors": [{"field": "book_id", "message": "book_id must be a valid uuid"}]}`,
+		},
+		{
+			name:        "error response drift",
+			method:      http.MethodPost,
+			url:         "/api/v1/books",
+			requestBody: `{"title": "Les Misérables", "author_id": "aeca0955-bae4-47e9-9f85-6818dc68ca51"}`,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"uuid": "1"}`))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody: `{"status": "error", "message": "Response does not match the OpenAPI document", "errors": [
+				{"field": "id", "message": "id is required"}
+			]}`,
+		},
+		{
+			name:   "error undocumented status",
+			method: http.MethodGet,
+			url:    "/api/v1/books/aeca0955-bae4-47e9-9f85-6818dc68ca51",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusTeapot)
+				_, _ = w.Write([]byte(`{}`))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody: `{"status": "error", "message": "Response does not match the OpenAPI document", "errors": [
+				{"field": "status", "message": "status is not supported"}
+			]}`,
+		},
+		{
+			name:   "success streamed response let through",
+			method: http.MethodGet,
+			url:    "/api/v1/books/aeca0955-bae4-47e9-9f85-6818dc68ca51",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/csv")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte("id\n1\n"))
+				_ = http.NewResponseController(w).Flush()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "id\n1\n",
+		},
+		{
+			name:   "success undocumented route let through",
+			method: http.MethodGet,
+			url:    "/api/v1/authors",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			validator := openapi.NewValidator(fstest.MapFS{"openapi.json": {Data: []byte(spec)}}, "openapi", true, zerolog.Nop())
+
+			handler := test.handler
+			if handler == nil {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					t.Fatal("the handler must not be called")
+				}
+			}
+
+			v1 := chi.NewRouter()
+			v1.Use(validator.Middleware)
+			v1.Handle("/*", handler)
+
+			r := chi.NewRouter()
+			r.Use(request.MaxBodySize(128))
+			r.Mount("/api/v1", v1)
+
+			req := httptest.NewRequest(test.method, test.url, strings.NewReader(test.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			if strings.HasPrefix(test.expectedBody, "{") {
+				assert.JSONEq(t, test.expectedBody, w.Body.String())
+			} else {
+				assert.Equal(t, test.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
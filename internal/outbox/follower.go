@@ -0,0 +1,131 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+)
+
+const defaultGapTimeout = 5 * time.Second
+
+// Follower hands every message of the outbox to the in-process bus of its replica, in the order
+// they were recorded and whichever replica relays them. Unlike the relay, which publishes each
+// message once for the whole deployment, every replica runs a follower, for the subscribers
+// serving its own clients: the change feed and the cached responses of each replica see the
+// changes made through the others.
+//
+// The delivery is at most once: the follower starts after the last message recorded when it
+// starts, and does not retry the subscribers that fail.
+type Follower struct {
+	repository OutboxRepository
+	bus        *event.Bus
+	cfg        config.OutboxConfig
+	logger     zerolog.Logger
+	now        func() time.Time
+
+	started  bool
+	lastID   uint64
+	gapSince time.Time
+}
+
+// NewFollower uses the defaults of config.OutboxConfig for the zero values of cfg.
+func NewFollower(repository OutboxRepository, bus *event.Bus, cfg config.OutboxConfig, logger zerolog.Logger) *Follower {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.GapTimeout <= 0 {
+		cfg.GapTimeout = defaultGapTimeout
+	}
+
+	return &Follower{
+		repository: repository,
+		bus:        bus,
+		cfg:        cfg,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Run follows the outbox until ctx is cancelled.
+func (f *Follower) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			more, err := f.FollowPending(ctx)
+			if err != nil && ctx.Err() == nil {
+				f.logger.Error().Err(err).Msg("failed to follow the outbox")
+			}
+			if err != nil || !more {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// FollowPending publishes a batch of the messages recorded since the last one published. The
+// first call only records where the outbox ends. A message missing from the sequence of IDs
+// holds the following ones for GapTimeout, its transaction possibly not being committed yet,
+// and is then skipped as rolled back. more reports whether a full batch was published. The
+// messages of every tenant are followed.
+func (f *Follower) FollowPending(ctx context.Context) (more bool, err error) {
+	ctx = tenant.AllTenants(ctx)
+	if !f.started {
+		lastID, err := f.repository.LastID(ctx)
+		if err != nil {
+			return false, err
+		}
+		f.lastID = lastID
+		f.started = true
+		return false, nil
+	}
+
+	messages, err := f.repository.ListAfter(ctx, f.lastID, f.cfg.BatchSize)
+	if err != nil {
+		return false, err
+	}
+
+	for _, message := range messages {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+
+		if message.ID != f.lastID+1 && !f.gapExpired() {
+			return false, nil
+		}
+
+		if err := f.bus.Publish(ctx, ToEvent(message)); err != nil {
+			f.logger.Warn().Err(err).
+				Str("event_id", message.EventID.String()).
+				Msg("failed to hand an outbox message to the bus")
+		}
+		f.lastID = message.ID
+		f.gapSince = time.Time{}
+	}
+
+	return len(messages) == f.cfg.BatchSize, nil
+}
+
+// gapExpired reports whether the gap before the next message was awaited for GapTimeout.
+func (f *Follower) gapExpired() bool {
+	now := f.now()
+	if f.gapSince.IsZero() {
+		f.gapSince = now
+	}
+	return now.Sub(f.gapSince) >= f.cfg.GapTimeout
+}
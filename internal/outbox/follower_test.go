@@ -0,0 +1,131 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/outbox"
+)
+
+func TestFollower_FollowPending(t *testing.T) {
+	message := func(id uint64, eventType string) *entity.OutboxMessage {
+		return &entity.OutboxMessage{ID: id, EventID: uuid.New(), EventType: eventType, AggregateID: uuid.New(), Payload: []byte(`{}`)}
+	}
+
+	tests := []struct {
+		name          string
+		configureMock func(*mocks.MockOutboxRepository)
+		// calls is the number of calls to FollowPending after the one finding the end of the outbox.
+		calls          int
+		expectedEvents []string
+		expectedMore   bool
+		expectedError  error
+	}{
+		{
+			name: "success messages recorded after the start are published in order",
+			configureMock: func(repository *mocks.MockOutboxRepository) {
+				repository.EXPECT().LastID(gomock.Any()).Return(uint64(10), nil)
+				repository.EXPECT().ListAfter(gomock.Any(), uint64(10), 2).
+					Return([]*entity.OutboxMessage{message(11, "book.created"), message(12, "author.renamed")}, nil)
+			},
+			calls:          1,
+			expectedEvents: []string{"book.created", "author.renamed"},
+			expectedMore:   true,
+		},
+		{
+			name: "success following calls start after the last published message",
+			configureMock: func(repository *mocks.MockOutboxRepository) {
+				repository.EXPECT().LastID(gomock.Any()).Return(uint64(0), nil)
+				gomock.InOrder(
+					repository.EXPECT().ListAfter(gomock.Any(), uint64(0), 2).Return([]*entity.OutboxMessage{message(1, "book.created")}, nil),
+					repository.EXPECT().ListAfter(gomock.Any(), uint64(1), 2).Return(nil, nil),
+				)
+			},
+			calls:          2,
+			expectedEvents: []string{"book.created"},
+		},
+		{
+			name: "success a gap holds the following messages",
+			configureMock: func(repository *mocks.MockOutboxRepository) {
+				repository.EXPECT().LastID(gomock.Any()).Return(uint64(10), nil)
+				repository.EXPECT().ListAfter(gomock.Any(), uint64(10), 2).
+					Return([]*entity.OutboxMessage{message(12, "book.updated")}, nil)
+			},
+			calls: 1,
+		},
+		{
+			name: "success a gap is skipped after the gap timeout",
+			configureMock: func(repository *mocks.MockOutboxRepository) {
+				repository.EXPECT().LastID(gomock.Any()).Return(uint64(10), nil)
+				repository.EXPECT().ListAfter(gomock.Any(), uint64(10), 2).
+					Return([]*entity.OutboxMessage{message(12, "book.updated")}, nil).Times(2)
+			},
+			calls:          2,
+			expectedEvents: []string{"book.updated"},
+		},
+		{
+			name: "error end of the outbox cannot be found",
+			configureMock: func(repository *mocks.MockOutboxRepository) {
+				repository.EXPECT().LastID(gomock.Any()).Return(uint64(0), errors.New("database connection failed"))
+			},
+			expectedError: errors.New("database connection failed"),
+		},
+		{
+			name: "error messages cannot be listed",
+			configureMock: func(repository *mocks.MockOutboxRepository) {
+				repository.EXPECT().LastID(gomock.Any()).Return(uint64(10), nil)
+				repository.EXPECT().ListAfter(gomock.Any(), uint64(10), 2).Return(nil, errors.New("database connection failed"))
+			},
+			calls:         1,
+			expectedError: errors.New("database connection failed"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			repository := mocks.NewMockOutboxRepository(ctrl)
+			test.configureMock(repository)
+
+			bus := event.NewBus()
+			var published []string
+			bus.Subscribe("*", func(_ context.Context, e event.Event) error {
+				published = append(published, string(e.Type))
+				return nil
+			})
+
+			follower := outbox.NewFollower(repository, bus, config.OutboxConfig{BatchSize: 2, GapTimeout: 20 * time.Millisecond}, zerolog.Nop())
+
+			more, err := follower.FollowPending(context.Background())
+			for i := 0; i < test.calls && err == nil; i++ {
+				if i > 0 {
+					time.Sleep(30 * time.Millisecond)
+				}
+				more, err = follower.FollowPending(context.Background())
+			}
+
+			if test.expectedError != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expectedError.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedMore, more)
+			assert.Equal(t, test.expectedEvents, published)
+		})
+	}
+}
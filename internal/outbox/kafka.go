@@ -0,0 +1,115 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"sync"
+
+	"go-boilerplate-rest-api-chi/internal/event"
+)
+
+type KafkaRecord struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
+// KafkaProducer writes a record and returns once the broker acknowledged it. Records with the
+// same key must go to the same partition, which keeps the events of an aggregate in order.
+type KafkaProducer interface {
+	Produce(ctx context.Context, record KafkaRecord) error
+}
+
+// KafkaSink produces the events to topic, keyed by aggregate ID.
+type KafkaSink struct {
+	producer KafkaProducer
+	topic    string
+}
+
+func NewKafkaSink(producer KafkaProducer, topic string) *KafkaSink {
+	return &KafkaSink{
+		producer: producer,
+		topic:    topic,
+	}
+}
+
+func (s *KafkaSink) Name() string {
+	return "kafka"
+}
+
+func (s *KafkaSink) Publish(ctx context.Context, e event.Event) error {
+	value, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	return s.producer.Produce(ctx, KafkaRecord{
+		Topic: s.topic,
+		Key:   []byte(e.AggregateID.String()),
+		Value: value,
+		Headers: map[string]string{
+			"event_id":   e.ID.String(),
+			"event_type": string(e.Type),
+		},
+	})
+}
+
+// MemoryKafka is a local stand-in for a Kafka cluster, for development and tests. Records are
+// appended to in-memory partitions chosen by key hash, like the default Kafka partitioner.
+type MemoryKafka struct {
+	mu         sync.RWMutex
+	partitions int
+	topics     map[string][][]KafkaRecord
+}
+
+func NewMemoryKafka(partitions int) *MemoryKafka {
+	if partitions <= 0 {
+		partitions = 1
+	}
+
+	return &MemoryKafka{
+		partitions: partitions,
+		topics:     make(map[string][][]KafkaRecord),
+	}
+}
+
+func (k *MemoryKafka) Produce(ctx context.Context, record KafkaRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	partitions, ok := k.topics[record.Topic]
+	if !ok {
+		partitions = make([][]KafkaRecord, k.partitions)
+		k.topics[record.Topic] = partitions
+	}
+
+	partition := k.Partition(record.Key)
+	partitions[partition] = append(partitions[partition], record)
+
+	return nil
+}
+
+func (k *MemoryKafka) Partition(key []byte) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write(key)
+	return int(hash.Sum32() % uint32(k.partitions))
+}
+
+// Records returns a copy of the records of a partition, oldest first.
+func (k *MemoryKafka) Records(topic string, partition int) []KafkaRecord {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	partitions, ok := k.topics[topic]
+	if !ok || partition < 0 || partition >= len(partitions) {
+		return nil
+	}
+
+	return append([]KafkaRecord(nil), partitions[partition]...)
+}
@@ -0,0 +1,42 @@
+package outbox_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/outbox"
+)
+
+func TestKafkaSink_Publish(t *testing.T) {
+	kafka := outbox.NewMemoryKafka(4)
+	sink := outbox.NewKafkaSink(kafka, "library.events")
+
+	bookID := uuid.New()
+	events := []event.Event{
+		event.New(event.BookCreated, bookID, nil),
+		event.New(event.AuthorCreated, uuid.New(), nil),
+		event.New(event.BookUpdated, bookID, nil),
+		event.New(event.BookDeleted, bookID, nil),
+	}
+
+	for _, e := range events {
+		require.NoError(t, sink.Publish(context.Background(), e))
+	}
+
+	key := []byte(bookID.String())
+	records := kafka.Records("library.events", kafka.Partition(key))
+
+	var types []string
+	for _, record := range records {
+		if string(record.Key) == string(key) {
+			types = append(types, record.Headers["event_type"])
+		}
+	}
+
+	assert.Equal(t, []string{"book.created", "book.updated", "book.deleted"}, types)
+}
@@ -0,0 +1,90 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/event"
+)
+
+// JetStreamPublisher is the part of jetstream.JetStream used by NATSSink.
+type JetStreamPublisher interface {
+	PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error)
+}
+
+// NATSSink publishes the events to JetStream on "<subject>.<event type>" and waits for the
+// acknowledgement. The event ID is sent as the message ID, so the stream drops the copies
+// published again after a relay failure.
+type NATSSink struct {
+	js      JetStreamPublisher
+	subject string
+}
+
+func NewNATSSink(js JetStreamPublisher, subject string) *NATSSink {
+	return &NATSSink{
+		js:      js,
+		subject: subject,
+	}
+}
+
+// ConnectNATS connects to url and makes sure a stream captures the subjects under subject.
+// The connection keeps retrying in the background and is drained when ctx is cancelled.
+func ConnectNATS(ctx context.Context, url, subject string, logger zerolog.Logger) (*NATSSink, error) {
+	conn, err := nats.Connect(url,
+		nats.Name("go-boilerplate-rest-api-chi-outbox"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	streamCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err = js.CreateOrUpdateStream(streamCtx, jetstream.StreamConfig{
+		Name:     strings.ToUpper(strings.ReplaceAll(subject, ".", "_")),
+		Subjects: []string{subject + ".>"},
+	})
+	if err != nil {
+		// The relay retries until the stream is reachable, nothing is lost meanwhile.
+		logger.Warn().Err(err).Str("subject", subject).Msg("failed to set up the NATS outbox stream")
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = conn.Drain()
+	}()
+
+	return NewNATSSink(js, subject), nil
+}
+
+func (s *NATSSink) Name() string {
+	return "nats"
+}
+
+func (s *NATSSink) Publish(ctx context.Context, e event.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(s.subject + "." + string(e.Type))
+	msg.Data = payload
+	msg.Header.Set(jetstream.MsgIDHeader, e.ID.String())
+
+	_, err = s.js.PublishMsg(ctx, msg)
+	return err
+}
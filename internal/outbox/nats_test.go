@@ -0,0 +1,47 @@
+package outbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/outbox"
+)
+
+type jetStreamStub struct {
+	subject string
+	payload []byte
+	msgID   string
+}
+
+func (s *jetStreamStub) PublishMsg(_ context.Context, msg *nats.Msg, _ ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	s.subject = msg.Subject
+	s.payload = msg.Data
+	s.msgID = msg.Header.Get(jetstream.MsgIDHeader)
+
+	return &jetstream.PubAck{Stream: "LIBRARY_EVENTS"}, nil
+}
+
+func TestNATSSink_Publish(t *testing.T) {
+	stub := &jetStreamStub{}
+	sink := outbox.NewNATSSink(stub, "library.events")
+
+	e := event.New(event.AuthorRenamed, uuid.New(), map[string]string{"name": "Victor Hugo"})
+
+	require.NoError(t, sink.Publish(context.Background(), e))
+
+	assert.Equal(t, "library.events.author.renamed", stub.subject)
+	assert.Equal(t, e.ID.String(), stub.msgID)
+
+	var published event.Event
+	require.NoError(t, json.Unmarshal(stub.payload, &published))
+	assert.Equal(t, e.ID, published.ID)
+	assert.Equal(t, e.AggregateID, published.AggregateID)
+}
@@ -0,0 +1,61 @@
+package outbox
+
+import (
+	"cmp"
+	"encoding/json"
+
+	"gorm.io/gorm"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+)
+
+// Add records events in the outbox through tx, which must be the transaction of the change
+// they describe: either both are committed or none is. The events without an actor get the
+// principal of the context of tx, and the events without a tenant its tenant.
+func Add(tx *gorm.DB, events ...event.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var actor string
+	if principal, ok := auth.FromContext(tx.Statement.Context); ok {
+		actor = principal.Subject
+	}
+	tenantID, _ := tenant.FromContext(tx.Statement.Context)
+
+	messages := make([]*entity.OutboxMessage, len(events))
+	for i, e := range events {
+		payload, err := json.Marshal(e.Data)
+		if err != nil {
+			return err
+		}
+
+		messages[i] = &entity.OutboxMessage{
+			EventID:     e.ID,
+			EventType:   string(e.Type),
+			AggregateID: e.AggregateID,
+			Payload:     payload,
+			OccurredAt:  e.OccurredAt,
+			Actor:       cmp.Or(e.Actor, actor),
+			TenantID:    cmp.Or(e.Tenant, tenantID),
+		}
+	}
+
+	return tx.Create(&messages).Error
+}
+
+// ToEvent rebuilds the event of a message, with its data left as raw JSON.
+func ToEvent(message *entity.OutboxMessage) event.Event {
+	return event.Event{
+		ID:          message.EventID,
+		Type:        event.Type(message.EventType),
+		AggregateID: message.AggregateID,
+		OccurredAt:  message.OccurredAt,
+		Actor:       message.Actor,
+		Tenant:      message.TenantID,
+		Data:        json.RawMessage(message.Payload),
+	}
+}
@@ -0,0 +1,75 @@
+package outbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/outbox"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	"go-boilerplate-rest-api-chi/internal/test-utils"
+)
+
+func TestAdd(t *testing.T) {
+	db, mock := testutils.NewGormMySQL(t)
+
+	e := event.New(event.BookCreated, uuid.New(), map[string]string{"title": "Les miserables"})
+
+	mock.ExpectExec("INSERT INTO `outbox`").
+		WithArgs(e.ID, "book.created", e.AggregateID, []byte(`{"title":"Les miserables"}`), e.OccurredAt, "", "", nil, nil, "", 0, nil, nil, "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	require.NoError(t, outbox.Add(db, e))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAdd_Actor(t *testing.T) {
+	db, mock := testutils.NewGormMySQL(t)
+
+	e := event.New(event.BookDeleted, uuid.New(), nil)
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "user-1"})
+	ctx = tenant.WithID(ctx, "acme")
+
+	mock.ExpectExec("INSERT INTO `outbox`").
+		WithArgs(e.ID, "book.deleted", e.AggregateID, []byte(`null`), e.OccurredAt, "user-1", "acme", nil, nil, "", 0, nil, nil, "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	require.NoError(t, outbox.Add(db.WithContext(ctx), e))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestToEvent(t *testing.T) {
+	message := &entity.OutboxMessage{
+		EventID:     uuid.New(),
+		EventType:   "author.renamed",
+		AggregateID: uuid.New(),
+		Payload:     []byte(`{"name":"Victor Hugo"}`),
+		OccurredAt:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Actor:       "user-1",
+		TenantID:    "acme",
+	}
+
+	e := outbox.ToEvent(message)
+
+	body, err := json.Marshal(e)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"id": "`+message.EventID.String()+`",
+		"type": "author.renamed",
+		"aggregate_id": "`+message.AggregateID.String()+`",
+		"occurred_at": "2024-01-02T03:04:05Z",
+		"actor": "user-1",
+		"tenant": "acme",
+		"data": {"name": "Victor Hugo"}
+	}`, string(body))
+}
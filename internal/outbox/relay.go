@@ -0,0 +1,200 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/retry"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 100
+	defaultRetention    = 7 * 24 * time.Hour
+	defaultMaxAttempts  = 10
+	defaultBaseDelay    = time.Second
+	defaultMaxDelay     = 5 * time.Minute
+	defaultLease        = time.Minute
+
+	purgeInterval = time.Hour
+	// maxErrorLength bounds the sink error kept in LastError.
+	maxErrorLength = 512
+)
+
+// Relay publishes the outbox messages to every sink, at least once and in the order they were
+// recorded for a given aggregate. The sinks that accepted a message are recorded, so a retry
+// only goes to the ones that failed; a sink may still receive a message again after a crash.
+//
+// Several relays may share a database: each claims the messages it publishes, see
+// OutboxRepository.ClaimPending.
+type Relay struct {
+	repository OutboxRepository
+	sinks      []Sink
+	cfg        config.OutboxConfig
+	logger     zerolog.Logger
+	now        func() time.Time
+}
+
+// NewRelay uses the defaults of config.OutboxConfig for the zero values of cfg.
+func NewRelay(repository OutboxRepository, sinks []Sink, cfg config.OutboxConfig, logger zerolog.Logger) *Relay {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = defaultRetention
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = defaultBaseDelay
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = defaultMaxDelay
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = defaultLease
+	}
+
+	return &Relay{
+		repository: repository,
+		sinks:      sinks,
+		cfg:        cfg,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Run relays the pending messages until ctx is cancelled, and deletes the published ones
+// once they are older than the retention.
+func (r *Relay) Run(ctx context.Context) {
+	// The relay purges the messages of every tenant.
+	ctx = tenant.AllTenants(ctx)
+
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	purge := time.NewTicker(purgeInterval)
+	defer purge.Stop()
+
+	for {
+		for {
+			more, err := r.RelayPending(ctx)
+			if err != nil && ctx.Err() == nil {
+				r.logger.Error().Err(err).Msg("failed to relay outbox messages")
+			}
+			if err != nil || !more {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-purge.C:
+			if _, err := r.repository.DeletePublished(ctx, r.now().Add(-r.cfg.Retention)); err != nil && ctx.Err() == nil {
+				r.logger.Error().Err(err).Msg("failed to purge the outbox")
+			}
+		}
+	}
+}
+
+// RelayPending makes one attempt for a batch of due messages. A failed message is retried
+// after a backoff, and the following ones of its aggregate wait for it until it is published
+// or given up after MaxAttempts. more reports whether the batch published anything, which may
+// have made later messages due. The messages of every tenant are relayed.
+func (r *Relay) RelayPending(ctx context.Context) (more bool, err error) {
+	ctx = tenant.AllTenants(ctx)
+	messages, err := r.repository.ClaimPending(ctx, r.now(), r.cfg.Lease, r.cfg.BatchSize)
+	if err != nil {
+		return false, err
+	}
+
+	for _, message := range messages {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+
+		if err := r.publish(ctx, message); err != nil {
+			if err := r.fail(ctx, message, err); err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		if err := r.repository.MarkPublished(ctx, message.ID, r.now()); err != nil {
+			return false, err
+		}
+		more = true
+	}
+
+	return more, nil
+}
+
+// publish sends the message to the sinks that have not accepted it yet, recording each one
+// that does in DeliveredSinks.
+func (r *Relay) publish(ctx context.Context, message *entity.OutboxMessage) error {
+	e := ToEvent(message)
+
+	var delivered []string
+	if message.DeliveredSinks != "" {
+		delivered = strings.Split(message.DeliveredSinks, ",")
+	}
+
+	for _, sink := range r.sinks {
+		if slices.Contains(delivered, sink.Name()) {
+			continue
+		}
+		if err := sink.Publish(ctx, e); err != nil {
+			return fmt.Errorf("%s: %w", sink.Name(), err)
+		}
+		delivered = append(delivered, sink.Name())
+		message.DeliveredSinks = strings.Join(delivered, ",")
+	}
+
+	return nil
+}
+
+// fail records the failed attempt: the next one is scheduled, or the message is dead once
+// MaxAttempts is reached.
+func (r *Relay) fail(ctx context.Context, message *entity.OutboxMessage, publishErr error) error {
+	message.Attempts++
+	message.LastError = truncate(publishErr.Error())
+
+	now := r.now()
+	if message.Attempts >= r.cfg.MaxAttempts {
+		message.DeadAt = &now
+		r.logger.Error().Err(publishErr).
+			Str("event_id", message.EventID.String()).
+			Int("attempts", message.Attempts).
+			Msg("outbox message given up")
+	} else {
+		next := now.Add(retry.Backoff(message.Attempts, r.cfg.BaseDelay, r.cfg.MaxDelay))
+		message.NextAttemptAt = &next
+		r.logger.Warn().Err(publishErr).
+			Str("event_id", message.EventID.String()).
+			Int("attempts", message.Attempts).
+			Msg("failed to relay outbox message")
+	}
+
+	return r.repository.MarkFailed(ctx, message)
+}
+
+func truncate(s string) string {
+	if len(s) > maxErrorLength {
+		return s[:maxErrorLength]
+	}
+	return s
+}
@@ -0,0 +1,139 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/outbox"
+)
+
+func TestRelay_RelayPending(t *testing.T) {
+	bookID := uuid.New()
+	authorID := uuid.New()
+
+	created := &entity.OutboxMessage{ID: 1, EventID: uuid.New(), EventType: "book.created", AggregateID: bookID, Payload: []byte(`{"id":"1"}`)}
+	renamed := &entity.OutboxMessage{ID: 2, EventID: uuid.New(), EventType: "author.renamed", AggregateID: authorID, Payload: []byte(`{}`)}
+	updated := &entity.OutboxMessage{ID: 3, EventID: uuid.New(), EventType: "book.updated", AggregateID: bookID, Payload: []byte(`{}`)}
+
+	start := time.Now()
+
+	// copyOf lets every test mutate its own messages.
+	copyOf := func(message *entity.OutboxMessage, configure func(*entity.OutboxMessage)) *entity.OutboxMessage {
+		c := *message
+		if configure != nil {
+			configure(&c)
+		}
+		return &c
+	}
+
+	tests := []struct {
+		name          string
+		configureMock func(*mocks.MockOutboxRepository, *mocks.MockSink, *mocks.MockSink)
+		expectedMore  bool
+		expectedError error
+	}{
+		{
+			name: "success every message is published in order",
+			configureMock: func(repository *mocks.MockOutboxRepository, bus *mocks.MockSink, nats *mocks.MockSink) {
+				repository.EXPECT().ClaimPending(gomock.Any(), gomock.Any(), time.Minute, 3).
+					Return([]*entity.OutboxMessage{copyOf(created, nil), copyOf(renamed, nil)}, nil)
+				gomock.InOrder(
+					bus.EXPECT().Publish(gomock.Any(), eventOf(created)).Return(nil),
+					nats.EXPECT().Publish(gomock.Any(), eventOf(created)).Return(nil),
+					repository.EXPECT().MarkPublished(gomock.Any(), uint64(1), gomock.Any()).Return(nil),
+					bus.EXPECT().Publish(gomock.Any(), eventOf(renamed)).Return(nil),
+					nats.EXPECT().Publish(gomock.Any(), eventOf(renamed)).Return(nil),
+					repository.EXPECT().MarkPublished(gomock.Any(), uint64(2), gomock.Any()).Return(nil),
+				)
+			},
+			expectedMore: true,
+		},
+		{
+			name: "success sinks that already accepted the message are skipped",
+			configureMock: func(repository *mocks.MockOutboxRepository, bus *mocks.MockSink, nats *mocks.MockSink) {
+				repository.EXPECT().ClaimPending(gomock.Any(), gomock.Any(), time.Minute, 3).
+					Return([]*entity.OutboxMessage{copyOf(updated, func(m *entity.OutboxMessage) { m.DeliveredSinks = "bus" })}, nil)
+				nats.EXPECT().Publish(gomock.Any(), eventOf(updated)).Return(nil)
+				repository.EXPECT().MarkPublished(gomock.Any(), uint64(3), gomock.Any()).Return(nil)
+			},
+			expectedMore: true,
+		},
+		{
+			name: "error failed message is retried after a backoff",
+			configureMock: func(repository *mocks.MockOutboxRepository, bus *mocks.MockSink, nats *mocks.MockSink) {
+				repository.EXPECT().ClaimPending(gomock.Any(), gomock.Any(), time.Minute, 3).
+					Return([]*entity.OutboxMessage{copyOf(created, func(m *entity.OutboxMessage) { m.Attempts = 2 })}, nil)
+				bus.EXPECT().Publish(gomock.Any(), eventOf(created)).Return(nil)
+				nats.EXPECT().Publish(gomock.Any(), eventOf(created)).Return(errors.New("unreachable"))
+				repository.EXPECT().MarkFailed(gomock.Any(), gomock.Cond(func(m *entity.OutboxMessage) bool {
+					// The third attempt waits between 2 and 4 minutes.
+					return m.ID == 1 && m.Attempts == 3 && m.DeliveredSinks == "bus" && m.LastError == "nats: unreachable" &&
+						m.DeadAt == nil && m.NextAttemptAt != nil &&
+						!m.NextAttemptAt.Before(start.Add(2*time.Minute)) && !m.NextAttemptAt.After(time.Now().Add(4*time.Minute))
+				})).Return(nil)
+			},
+		},
+		{
+			name: "error failed message is given up after max attempts",
+			configureMock: func(repository *mocks.MockOutboxRepository, bus *mocks.MockSink, nats *mocks.MockSink) {
+				repository.EXPECT().ClaimPending(gomock.Any(), gomock.Any(), time.Minute, 3).
+					Return([]*entity.OutboxMessage{copyOf(created, func(m *entity.OutboxMessage) { m.Attempts = 4 })}, nil)
+				bus.EXPECT().Publish(gomock.Any(), eventOf(created)).Return(errors.New("unreachable"))
+				repository.EXPECT().MarkFailed(gomock.Any(), gomock.Cond(func(m *entity.OutboxMessage) bool {
+					return m.Attempts == 5 && m.DeliveredSinks == "" && m.DeadAt != nil && m.NextAttemptAt == nil
+				})).Return(nil)
+			},
+		},
+		{
+			name: "error due messages cannot be claimed",
+			configureMock: func(repository *mocks.MockOutboxRepository, bus *mocks.MockSink, nats *mocks.MockSink) {
+				repository.EXPECT().ClaimPending(gomock.Any(), gomock.Any(), time.Minute, 3).Return(nil, errors.New("database connection failed"))
+			},
+			expectedError: errors.New("database connection failed"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			repository := mocks.NewMockOutboxRepository(ctrl)
+			bus := mocks.NewMockSink(ctrl)
+			bus.EXPECT().Name().Return("bus").AnyTimes()
+			nats := mocks.NewMockSink(ctrl)
+			nats.EXPECT().Name().Return("nats").AnyTimes()
+			test.configureMock(repository, bus, nats)
+
+			relay := outbox.NewRelay(repository, []outbox.Sink{bus, nats}, config.OutboxConfig{BatchSize: 3, MaxAttempts: 5, BaseDelay: time.Minute, Lease: time.Minute}, zerolog.Nop())
+
+			more, err := relay.RelayPending(context.Background())
+
+			if test.expectedError != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expectedError.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedMore, more)
+		})
+	}
+}
+
+func eventOf(message *entity.OutboxMessage) gomock.Matcher {
+	return gomock.Cond(func(e event.Event) bool {
+		return e.ID == message.EventID && string(e.Type) == message.EventType && e.AggregateID == message.AggregateID
+	})
+}
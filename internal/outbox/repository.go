@@ -0,0 +1,152 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+)
+
+//go:generate mockgen -destination=../mocks/mock_outbox_repository.go -package=mocks go-boilerplate-rest-api-chi/internal/outbox OutboxRepository
+type OutboxRepository interface {
+	ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.OutboxMessage, error)
+	MarkPublished(ctx context.Context, messageID uint64, at time.Time) error
+	MarkFailed(ctx context.Context, message *entity.OutboxMessage) error
+	DeletePublished(ctx context.Context, before time.Time) (int64, error)
+	LastID(ctx context.Context) (uint64, error)
+	ListAfter(ctx context.Context, afterID uint64, limit int) ([]*entity.OutboxMessage, error)
+}
+
+type outboxRepository struct {
+	db     *gorm.DB
+	logger zerolog.Logger
+}
+
+func NewOutboxRepository(db *gorm.DB, logger zerolog.Logger) OutboxRepository {
+	return &outboxRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ClaimPending returns the messages due at now, in the order they were recorded, and holds
+// them for lease so the other relays skip them. Only the earliest undelivered message of an
+// aggregate is due, so its events keep their order whatever the number of relays.
+func (r *outboxRepository) ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.OutboxMessage, error) {
+	var messages []*entity.OutboxMessage
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND dead_at IS NULL").
+			Where("next_attempt_at IS NULL OR next_attempt_at <= ?", now).
+			Where("locked_until IS NULL OR locked_until <= ?", now).
+			Where("NOT EXISTS (SELECT 1 FROM outbox earlier WHERE earlier.aggregate_id = outbox.aggregate_id AND earlier.id < outbox.id AND earlier.published_at IS NULL AND earlier.dead_at IS NULL)").
+			Order("id").
+			Limit(limit).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		ids := make([]uint64, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+		until := now.Add(lease)
+		if err := tx.Model(&entity.OutboxMessage{}).Where("id IN ?", ids).Update("locked_until", until).Error; err != nil {
+			return err
+		}
+		for _, message := range messages {
+			message.LockedUntil = &until
+		}
+
+		return nil
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, messageID uint64, at time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&entity.OutboxMessage{ID: messageID}).
+		Updates(map[string]interface{}{
+			"published_at": at,
+			"locked_until": nil,
+		}).Error
+	if err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+	}
+
+	return err
+}
+
+// MarkFailed records the outcome of a failed attempt and releases the claim on message.
+func (r *outboxRepository) MarkFailed(ctx context.Context, message *entity.OutboxMessage) error {
+	err := r.db.WithContext(ctx).
+		Model(&entity.OutboxMessage{ID: message.ID}).
+		Updates(map[string]interface{}{
+			"attempts":        message.Attempts,
+			"delivered_sinks": message.DeliveredSinks,
+			"next_attempt_at": message.NextAttemptAt,
+			"dead_at":         message.DeadAt,
+			"last_error":      message.LastError,
+			"locked_until":    nil,
+		}).Error
+	if err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+	}
+
+	return err
+}
+
+func (r *outboxRepository) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("published_at IS NOT NULL AND published_at < ?", before).
+		Delete(&entity.OutboxMessage{})
+	if result.Error != nil {
+		r.logger.Error().Err(result.Error).Msg("database error")
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// LastID returns the ID of the last recorded message, 0 when the outbox is empty.
+func (r *outboxRepository) LastID(ctx context.Context) (uint64, error) {
+	var lastID uint64
+	err := r.db.WithContext(ctx).
+		Model(&entity.OutboxMessage{}).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&lastID).Error
+	if err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return 0, err
+	}
+
+	return lastID, nil
+}
+
+// ListAfter returns the messages recorded after afterID, in order and whatever their state.
+func (r *outboxRepository) ListAfter(ctx context.Context, afterID uint64, limit int) ([]*entity.OutboxMessage, error) {
+	var messages []*entity.OutboxMessage
+	err := r.db.WithContext(ctx).
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,157 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/outbox"
+	"go-boilerplate-rest-api-chi/internal/test-utils"
+)
+
+func TestOutboxRepository_ClaimPending(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		configureMock func(sqlmock.Sqlmock)
+		expectedIDs   []uint64
+		expectedError error
+	}{
+		{
+			name: "success due messages claimed in order",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT \\* FROM `outbox` WHERE \\(published_at IS NULL AND dead_at IS NULL\\) AND \\(next_attempt_at IS NULL OR next_attempt_at <= \\?\\) AND \\(locked_until IS NULL OR locked_until <= \\?\\) AND \\(NOT EXISTS \\(SELECT 1 FROM outbox earlier WHERE earlier.aggregate_id = outbox.aggregate_id AND earlier.id < outbox.id AND earlier.published_at IS NULL AND earlier.dead_at IS NULL\\)\\) ORDER BY id LIMIT \\? FOR UPDATE SKIP LOCKED").
+					WithArgs(now, now, 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "event_type"}).
+						AddRow(4, uuid.New(), "book.created").
+						AddRow(7, uuid.New(), "author.renamed"))
+				mock.ExpectExec("UPDATE `outbox` SET `locked_until`=\\? WHERE id IN \\(\\?,\\?\\)").
+					WithArgs(now.Add(time.Minute), 4, 7).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			expectedIDs: []uint64{4, 7},
+		},
+		{
+			name: "success nothing due",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT \\* FROM `outbox`").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error database connection failed",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT \\* FROM `outbox`").
+					WillReturnError(errors.New("database connection failed"))
+				mock.ExpectRollback()
+			},
+			expectedError: errors.New("database connection failed"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := testutils.NewGormMySQL(t)
+			test.configureMock(mock)
+
+			repo := outbox.NewOutboxRepository(db, zerolog.Nop())
+
+			messages, err := repo.ClaimPending(context.Background(), now, time.Minute, 10)
+
+			if test.expectedError != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expectedError.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			var ids []uint64
+			for _, message := range messages {
+				ids = append(ids, message.ID)
+				assert.Equal(t, now.Add(time.Minute), *message.LockedUntil)
+			}
+			assert.Equal(t, test.expectedIDs, ids)
+
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOutboxRepository_MarkFailed(t *testing.T) {
+	db, mock := testutils.NewGormMySQL(t)
+
+	next := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock.ExpectExec("UPDATE `outbox` SET `attempts`=\\?,`dead_at`=\\?,`delivered_sinks`=\\?,`last_error`=\\?,`locked_until`=\\?,`next_attempt_at`=\\? WHERE `id` = \\?").
+		WithArgs(2, nil, "bus", "nats: timeout", nil, &next, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := outbox.NewOutboxRepository(db, zerolog.Nop())
+
+	message := &entity.OutboxMessage{ID: 3, Attempts: 2, DeliveredSinks: "bus", NextAttemptAt: &next, LastError: "nats: timeout"}
+	require.NoError(t, repo.MarkFailed(context.Background(), message))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepository_DeletePublished(t *testing.T) {
+	db, mock := testutils.NewGormMySQL(t)
+
+	before := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock.ExpectExec("DELETE FROM `outbox` WHERE published_at IS NOT NULL AND published_at < \\?").
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 12))
+
+	repo := outbox.NewOutboxRepository(db, zerolog.Nop())
+
+	deleted, err := repo.DeletePublished(context.Background(), before)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), deleted)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepository_LastID(t *testing.T) {
+	db, mock := testutils.NewGormMySQL(t)
+
+	mock.ExpectQuery("SELECT COALESCE\\(MAX\\(id\\), 0\\) FROM `outbox`").
+		WillReturnRows(sqlmock.NewRows([]string{"last_id"}).AddRow(42))
+
+	repo := outbox.NewOutboxRepository(db, zerolog.Nop())
+
+	lastID, err := repo.LastID(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), lastID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepository_ListAfter(t *testing.T) {
+	db, mock := testutils.NewGormMySQL(t)
+
+	mock.ExpectQuery("SELECT \\* FROM `outbox` WHERE id > \\? ORDER BY id LIMIT \\?").
+		WithArgs(41, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_type"}).AddRow(42, "book.created").AddRow(43, "book.updated"))
+
+	repo := outbox.NewOutboxRepository(db, zerolog.Nop())
+
+	messages, err := repo.ListAfter(context.Background(), 41, 2)
+
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, uint64(42), messages[0].ID)
+	assert.Equal(t, uint64(43), messages[1].ID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
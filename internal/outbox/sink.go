@@ -0,0 +1,33 @@
+package outbox
+
+import (
+	"context"
+
+	"go-boilerplate-rest-api-chi/internal/event"
+)
+
+// Sink is a destination of the relayed events. Publish must only return once the event is
+// durably handed over, since the message is then marked as published.
+//
+//go:generate mockgen -destination=../mocks/mock_outbox_sink.go -package=mocks go-boilerplate-rest-api-chi/internal/outbox Sink
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, e event.Event) error
+}
+
+// BusSink hands the events to the in-process subscribers of an event.Bus.
+type BusSink struct {
+	bus *event.Bus
+}
+
+func NewBusSink(bus *event.Bus) *BusSink {
+	return &BusSink{bus: bus}
+}
+
+func (s *BusSink) Name() string {
+	return "bus"
+}
+
+func (s *BusSink) Publish(ctx context.Context, e event.Event) error {
+	return s.bus.Publish(ctx, e)
+}
@@ -0,0 +1,94 @@
+package pagination
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Params is the page requested with ?page= (1-based) and ?page_size=.
+type Params struct {
+	Page     int
+	PageSize int
+	// skip shifts a Window that does not start on a page boundary.
+	skip int
+}
+
+// Window selects limit items from offset, for the cursor-based connections of the GraphQL
+// API.
+func Window(offset, limit int) Params {
+	if offset%limit == 0 {
+		return Params{Page: offset/limit + 1, PageSize: limit}
+	}
+
+	return Params{Page: 1, PageSize: limit, skip: offset}
+}
+
+type Meta struct {
+	Page       int   `json:"page" xml:"page" example:"1"`
+	PageSize   int   `json:"page_size" xml:"page_size" example:"20"`
+	TotalItems int64 `json:"total_items" xml:"total_items" example:"42"`
+	TotalPages int   `json:"total_pages" xml:"total_pages" example:"3"`
+}
+
+// Parse reads the pagination query parameters of r, falling back to the first page
+// of DefaultPageSize items. Invalid values are reported in the ValidationErrorDetail format.
+func Parse(r *http.Request) (Params, []response.ValidationErrorDetail) {
+	params := Params{Page: 1, PageSize: DefaultPageSize}
+	query := r.URL.Query()
+
+	var errs []response.ValidationErrorDetail
+
+	if raw := query.Get("page"); raw != "" {
+		page, err := strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			errs = append(errs, response.ValidationErrorDetail{
+				Field:   "page",
+				Message: "page must be a positive integer",
+			})
+		}
+		params.Page = page
+	}
+
+	if raw := query.Get("page_size"); raw != "" {
+		pageSize, err := strconv.Atoi(raw)
+		if err != nil || pageSize < 1 || pageSize > MaxPageSize {
+			errs = append(errs, response.ValidationErrorDetail{
+				Field:   "page_size",
+				Message: fmt.Sprintf("page_size must be between 1 and %d", MaxPageSize),
+			})
+		}
+		params.PageSize = pageSize
+	}
+
+	if len(errs) > 0 {
+		return Params{}, errs
+	}
+
+	return params, nil
+}
+
+func (p Params) Offset() int {
+	return (p.Page-1)*p.PageSize + p.skip
+}
+
+func NewMeta(params Params, total int64) Meta {
+	totalPages := 0
+	if params.PageSize > 0 {
+		totalPages = int((total + int64(params.PageSize) - 1) / int64(params.PageSize))
+	}
+
+	return Meta{
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}
+}
@@ -0,0 +1,104 @@
+package pagination_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go-boilerplate-rest-api-chi/internal/pagination"
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedParams pagination.Params
+		expectedErrors []response.ValidationErrorDetail
+	}{
+		{
+			name:           "defaults",
+			query:          "",
+			expectedParams: pagination.Params{Page: 1, PageSize: pagination.DefaultPageSize},
+		},
+		{
+			name:           "explicit page",
+			query:          "?page=3&page_size=5",
+			expectedParams: pagination.Params{Page: 3, PageSize: 5},
+		},
+		{
+			name:  "error invalid page",
+			query: "?page=0",
+			expectedErrors: []response.ValidationErrorDetail{{
+				Field:   "page",
+				Message: "page must be a positive integer",
+			}},
+		},
+		{
+			name:  "error page size too large",
+			query: "?page_size=1000",
+			expectedErrors: []response.ValidationErrorDetail{{
+				Field:   "page_size",
+				Message: "page_size must be between 1 and 100",
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/"+test.query, nil)
+
+			params, errs := pagination.Parse(req)
+
+			assert.Equal(t, test.expectedErrors, errs)
+			assert.Equal(t, test.expectedParams, params)
+		})
+	}
+}
+
+func TestNewMeta(t *testing.T) {
+	meta := pagination.NewMeta(pagination.Params{Page: 2, PageSize: 20}, 41)
+
+	assert.Equal(t, pagination.Meta{Page: 2, PageSize: 20, TotalItems: 41, TotalPages: 3}, meta)
+	assert.Equal(t, 20, pagination.Params{Page: 2, PageSize: 20}.Offset())
+}
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		name           string
+		offset         int
+		limit          int
+		expectedParams pagination.Params
+		expectedOffset int
+	}{
+		{
+			name:           "first page",
+			offset:         0,
+			limit:          10,
+			expectedParams: pagination.Params{Page: 1, PageSize: 10},
+			expectedOffset: 0,
+		},
+		{
+			name:           "page boundary",
+			offset:         20,
+			limit:          10,
+			expectedParams: pagination.Params{Page: 3, PageSize: 10},
+			expectedOffset: 20,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params := pagination.Window(test.offset, test.limit)
+
+			assert.Equal(t, test.expectedParams, params)
+			assert.Equal(t, test.expectedOffset, params.Offset())
+		})
+	}
+
+	params := pagination.Window(7, 10)
+	assert.Equal(t, 7, params.Offset())
+	assert.Equal(t, 10, params.PageSize)
+}
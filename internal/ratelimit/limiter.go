@@ -0,0 +1,178 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+// KeyFunc identifies the client of a request, such as user:42, or returns false when the
+// request does not carry the identity it looks for.
+type KeyFunc func(r *http.Request) (string, bool)
+
+// ByAPIKey identifies the clients authenticated with an API key by its ID: api_key:<id>.
+func ByAPIKey(r *http.Request) (string, bool) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok || principal.APIKeyID == "" {
+		return "", false
+	}
+	return "api_key:" + principal.APIKeyID, true
+}
+
+// ByUser identifies the authenticated users by their subject: user:<subject>.
+func ByUser(r *http.Request) (string, bool) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok || principal.APIKeyID != "" {
+		return "", false
+	}
+	return "user:" + principal.Subject, true
+}
+
+// ByIP identifies the clients by their address, set by middleware.RealIP behind a proxy:
+// ip:<address>.
+func ByIP(r *http.Request) (string, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if host == "" {
+		return "", false
+	}
+	return "ip:" + host, true
+}
+
+var keyFuncs = map[string]KeyFunc{
+	"api_key": ByAPIKey,
+	"user":    ByUser,
+	"ip":      ByIP,
+}
+
+// defaultKeys identify the clients when the configuration names no key.
+var defaultKeys = []string{"api_key", "user", "ip"}
+
+// Limiter limits the requests of the clients, identified by the first of its keys found in
+// the request. The clients of a plan get its quota instead of the global one, and its route
+// limits instead of the ones of every client.
+type Limiter struct {
+	store      Store
+	keys       []KeyFunc
+	global     config.RateLimit
+	clients    map[string]string
+	plans      map[string]config.RateLimit
+	planRoutes map[string]config.RateLimit
+	logger     zerolog.Logger
+}
+
+func NewLimiter(store Store, cfg config.RateLimitConfig, logger zerolog.Logger) *Limiter {
+	l := &Limiter{
+		store:      store,
+		global:     cfg.Global,
+		clients:    cfg.Clients,
+		plans:      cfg.Plans,
+		planRoutes: cfg.PlanRoutes,
+		logger:     logger,
+	}
+
+	keys := cfg.Keys
+	if len(keys) == 0 {
+		keys = defaultKeys
+	}
+	for _, name := range keys {
+		if key, ok := keyFuncs[name]; ok {
+			l.keys = append(l.keys, key)
+		}
+	}
+	return l
+}
+
+// Global applies the global quota, or the one of the plan of the client.
+func (l *Limiter) Global(next http.Handler) http.Handler {
+	return l.limit("global", func(client string) config.RateLimit {
+		if limit, ok := l.plans[l.clients[client]]; ok {
+			return limit
+		}
+		return l.global
+	}, next)
+}
+
+// Route applies limit to the requests of route, counted apart from the global quota. The
+// clients of a plan setting a limit on route get that one instead.
+func (l *Limiter) Route(route string, limit config.RateLimit) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return l.limit("route:"+route, func(client string) config.RateLimit {
+			if plan, ok := l.clients[client]; ok {
+				if planLimit, ok := l.planRoutes[plan+":"+route]; ok {
+					return planLimit
+				}
+			}
+			return limit
+		}, next)
+	}
+}
+
+func (l *Limiter) limit(scope string, limitOf func(client string) config.RateLimit, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, ok := l.identify(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limit := limitOf(client)
+		if limit.Requests == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		count, reset, err := l.store.Increment(r.Context(), "ratelimit:"+scope+":"+client, limit.Window)
+		if err != nil {
+			// An unreachable store must not take the API down with it.
+			l.logger.Error().Err(err).Str("scope", scope).Msg("failed to count the request, it is let through")
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		remaining := max(0, limit.Requests-count)
+		resetSeconds := int(math.Ceil(reset.Seconds()))
+		setHeaders(w.Header(), limit, remaining, resetSeconds)
+
+		if count > limit.Requests {
+			w.Header().Set("Retry-After", strconv.Itoa(resetSeconds))
+			response.Error(w, http.StatusTooManyRequests, "Too many requests")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *Limiter) identify(r *http.Request) (string, bool) {
+	for _, key := range l.keys {
+		if client, ok := key(r); ok {
+			return client, true
+		}
+	}
+	return "", false
+}
+
+// setHeaders writes the RateLimit headers of the IETF draft. Under several limits, they
+// describe the one with the fewest requests remaining.
+func setHeaders(header http.Header, limit config.RateLimit, remaining, reset int) {
+	if current, err := strconv.Atoi(header.Get("RateLimit-Remaining")); err == nil && current <= remaining {
+		return
+	}
+
+	header.Set("RateLimit-Limit", strconv.Itoa(limit.Requests))
+	header.Set("RateLimit-Remaining", strconv.Itoa(remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(reset))
+	header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, int(limit.Window/time.Second)))
+}
@@ -0,0 +1,217 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/ratelimit"
+)
+
+type failingStore struct{}
+
+func (failingStore) Increment(context.Context, string, time.Duration) (int, time.Duration, error) {
+	return 0, 0, errors.New("connection refused")
+}
+
+func TestLimiter_Global(t *testing.T) {
+	cfg := config.RateLimitConfig{
+		Global:  config.RateLimit{Requests: 2, Window: time.Minute},
+		Plans:   map[string]config.RateLimit{"pro": {Requests: 3, Window: time.Minute}, "unlimited": {}},
+		Clients: map[string]string{"user:pro": "pro", "api_key:partner": "unlimited"},
+	}
+
+	type call struct {
+		remoteAddr string
+		subject    string
+		apiKeyID   string
+	}
+
+	tests := []struct {
+		name              string
+		keys              []string
+		calls             []call
+		expectedStatus    int
+		expectedHeaders   map[string]string
+		expectedNoHeaders bool
+	}{
+		{
+			name:           "success under the limit",
+			calls:          []call{{remoteAddr: "192.0.2.1:1234"}, {remoteAddr: "192.0.2.1:5678"}},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"RateLimit-Limit":     "2",
+				"RateLimit-Remaining": "0",
+				"RateLimit-Reset":     "60",
+				"RateLimit-Policy":    "2;w=60",
+			},
+		},
+		{
+			name:           "error over the limit",
+			calls:          []call{{remoteAddr: "192.0.2.1:1234"}, {remoteAddr: "192.0.2.1:1234"}, {remoteAddr: "192.0.2.1:1234"}},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedHeaders: map[string]string{
+				"RateLimit-Remaining": "0",
+				"Retry-After":         "60",
+			},
+		},
+		{
+			name:           "success users behind one address counted apart",
+			calls:          []call{{remoteAddr: "192.0.2.1:1234", subject: "1"}, {remoteAddr: "192.0.2.1:1234", subject: "2"}, {remoteAddr: "192.0.2.1:1234", subject: "3"}},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"RateLimit-Remaining": "1",
+			},
+		},
+		{
+			name:           "error api key counted across addresses",
+			calls:          []call{{remoteAddr: "192.0.2.1:1234", apiKeyID: "1"}, {remoteAddr: "192.0.2.2:1234", apiKeyID: "1"}, {remoteAddr: "192.0.2.3:1234", apiKeyID: "1"}},
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:           "success plan quota",
+			calls:          []call{{subject: "pro"}, {subject: "pro"}, {subject: "pro"}},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"RateLimit-Limit":     "3",
+				"RateLimit-Remaining": "0",
+				"RateLimit-Policy":    "3;w=60",
+			},
+		},
+		{
+			name:              "success unlimited plan",
+			calls:             []call{{apiKeyID: "partner"}, {apiKeyID: "partner"}, {apiKeyID: "partner"}},
+			expectedStatus:    http.StatusOK,
+			expectedNoHeaders: true,
+		},
+		{
+			name:              "success unidentified client",
+			keys:              []string{"user"},
+			calls:             []call{{remoteAddr: "192.0.2.1:1234"}, {remoteAddr: "192.0.2.1:1234"}, {remoteAddr: "192.0.2.1:1234"}},
+			expectedStatus:    http.StatusOK,
+			expectedNoHeaders: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := cfg
+			cfg.Keys = test.keys
+			limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), cfg, zerolog.Nop())
+			handler := limiter.Global(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			var rr *httptest.ResponseRecorder
+			for _, c := range test.calls {
+				req := httptest.NewRequest(http.MethodGet, "/api/books", nil)
+				req.RemoteAddr = c.remoteAddr
+				if c.subject != "" || c.apiKeyID != "" {
+					req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Subject: c.subject, APIKeyID: c.apiKeyID}))
+				}
+
+				rr = httptest.NewRecorder()
+				handler.ServeHTTP(rr, req)
+			}
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, rr.Header().Get(name), name)
+			}
+			if test.expectedNoHeaders {
+				assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+			}
+			if test.expectedStatus == http.StatusTooManyRequests {
+				assert.JSONEq(t, `{"status":"error","message":"Too many requests"}`, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestLimiter_Route(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), config.RateLimitConfig{
+		Global: config.RateLimit{Requests: 10, Window: time.Minute},
+	}, zerolog.Nop())
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	books := limiter.Global(limiter.Route("/books", config.RateLimit{Requests: 1, Window: 30 * time.Second})(next))
+	authors := limiter.Global(next)
+
+	serve := func(handler http.Handler) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rr
+	}
+
+	// The headers describe the limit with the fewest requests remaining.
+	rr := serve(books)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "1;w=30", rr.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+
+	rr = serve(books)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "30", rr.Header().Get("Retry-After"))
+
+	// The other routes only count against the global quota.
+	rr = serve(authors)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "10;w=60", rr.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "7", rr.Header().Get("RateLimit-Remaining"))
+}
+
+func TestLimiter_RoutePlan(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), config.RateLimitConfig{
+		Keys:       []string{"user"},
+		Plans:      map[string]config.RateLimit{"pro": {Requests: 100, Window: time.Minute}, "free": {Requests: 10, Window: time.Minute}},
+		PlanRoutes: map[string]config.RateLimit{"pro:/books": {Requests: 3, Window: time.Minute}},
+		Clients:    map[string]string{"user:pro": "pro", "user:free": "free"},
+	}, zerolog.Nop())
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	books := limiter.Route("/books", config.RateLimit{Requests: 1, Window: time.Minute})(next)
+
+	serve := func(subject string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Subject: subject}))
+		rr := httptest.NewRecorder()
+		books.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// The plan naming the route replaces its limit.
+	for range 3 {
+		assert.Equal(t, http.StatusOK, serve("pro").Code)
+	}
+	rr := serve("pro")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "3;w=60", rr.Header().Get("RateLimit-Policy"))
+
+	// The other clients keep the route limit, whatever their plan.
+	for _, subject := range []string{"free", "anonymous"} {
+		rr := serve(subject)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "1;w=60", rr.Header().Get("RateLimit-Policy"))
+		assert.Equal(t, http.StatusTooManyRequests, serve(subject).Code)
+	}
+}
+
+func TestLimiter_StoreError(t *testing.T) {
+	limiter := ratelimit.NewLimiter(failingStore{}, config.RateLimitConfig{
+		Global: config.RateLimit{Requests: 1, Window: time.Minute},
+	}, zerolog.Nop())
+	handler := limiter.Global(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }))
+
+	for range 3 {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+	}
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// incrementScript counts a request and opens the window on the first one, atomically so that
+// the replicas never lose the expiry of a key.
+var incrementScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`)
+
+// RedisStore shares the counters of the replicas in Redis.
+type RedisStore struct {
+	client redis.Scripter
+}
+
+func NewRedisStore(client redis.Scripter) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// ConnectRedis connects a RedisStore to url, such as redis://localhost:6379/0. The client is
+// closed when ctx is cancelled.
+func ConnectRedis(ctx context.Context, url string) (*RedisStore, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	client := redis.NewClient(options)
+	go func() {
+		<-ctx.Done()
+		_ = client.Close()
+	}()
+
+	return NewRedisStore(client), nil
+}
+
+func (s *RedisStore) Increment(ctx context.Context, key string, window time.Duration) (int, time.Duration, error) {
+	result, err := incrementScript.Run(ctx, s.client, []string{key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(result) != 2 {
+		return 0, 0, fmt.Errorf("unexpected reply of the rate limit script: %v", result)
+	}
+
+	return int(result[0]), time.Duration(result[1]) * time.Millisecond, nil
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store counts the requests of the keys over fixed windows. The replicas share their counters
+// when they share the store.
+type Store interface {
+	// Increment counts a request of key in its current window, opened by the first request
+	// with the given length, and returns the requests counted so far and the time left
+	// before the window resets.
+	Increment(ctx context.Context, key string, window time.Duration) (count int, reset time.Duration, err error)
+}
+
+// sweepInterval is how often MemoryStore forgets the expired windows.
+const sweepInterval = time.Minute
+
+type counter struct {
+	count   int
+	resetAt time.Time
+}
+
+// MemoryStore counts in process, for a single replica.
+type MemoryStore struct {
+	mu       sync.Mutex
+	counters map[string]*counter
+	sweepAt  time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		counters: make(map[string]*counter),
+		sweepAt:  time.Now().Add(sweepInterval),
+	}
+}
+
+func (s *MemoryStore) Increment(_ context.Context, key string, window time.Duration) (int, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.After(s.sweepAt) {
+		for k, c := range s.counters {
+			if !now.Before(c.resetAt) {
+				delete(s.counters, k)
+			}
+		}
+		s.sweepAt = now.Add(sweepInterval)
+	}
+
+	c, ok := s.counters[key]
+	if !ok || !now.Before(c.resetAt) {
+		c = &counter{resetAt: now.Add(window)}
+		s.counters[key] = c
+	}
+	c.count++
+
+	return c.count, c.resetAt.Sub(now), nil
+}
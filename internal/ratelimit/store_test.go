@@ -0,0 +1,78 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/ratelimit"
+)
+
+func TestMemoryStore_Increment(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	ctx := context.Background()
+
+	count, reset, err := store.Increment(ctx, "ratelimit:global:ip:192.0.2.1", 50*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.LessOrEqual(t, reset, 50*time.Millisecond)
+
+	count, _, _ = store.Increment(ctx, "ratelimit:global:ip:192.0.2.1", 50*time.Millisecond)
+	assert.Equal(t, 2, count)
+
+	count, _, _ = store.Increment(ctx, "ratelimit:global:ip:192.0.2.2", 50*time.Millisecond)
+	assert.Equal(t, 1, count, "the keys are counted apart")
+
+	time.Sleep(60 * time.Millisecond)
+	count, _, _ = store.Increment(ctx, "ratelimit:global:ip:192.0.2.1", 50*time.Millisecond)
+	assert.Equal(t, 1, count, "a new window starts after the reset")
+}
+
+func TestRedisStore_Increment(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	// Two replicas share the counters of the Redis server.
+	replica1 := ratelimit.NewRedisStore(client)
+	replica2 := ratelimit.NewRedisStore(client)
+	ctx := context.Background()
+
+	count, reset, err := replica1.Increment(ctx, "ratelimit:global:user:42", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, time.Minute, reset)
+
+	server.FastForward(20 * time.Second)
+	count, reset, err = replica2.Increment(ctx, "ratelimit:global:user:42", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 40*time.Second, reset, "the window keeps the expiry of the first request")
+
+	server.FastForward(40 * time.Second)
+	count, _, err = replica1.Increment(ctx, "ratelimit:global:user:42", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	server.Close()
+	_, _, err = replica1.Increment(ctx, "ratelimit:global:user:42", time.Minute)
+	assert.Error(t, err)
+}
+
+func TestConnectRedis(t *testing.T) {
+	_, err := ratelimit.ConnectRedis(t.Context(), "localhost:6379")
+	assert.Error(t, err)
+
+	server := miniredis.RunT(t)
+	store, err := ratelimit.ConnectRedis(t.Context(), "redis://"+server.Addr()+"/0")
+	require.NoError(t, err)
+
+	count, _, err := store.Increment(context.Background(), "ratelimit:global:ip:192.0.2.1", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
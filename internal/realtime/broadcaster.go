@@ -0,0 +1,120 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog"
+)
+
+// Broadcaster carries the envelopes between the hubs of the replicas. Every hub receives
+// every envelope, its own included.
+type Broadcaster interface {
+	Publish(ctx context.Context, envelope Envelope) error
+	Subscribe(handler func(Envelope)) (unsubscribe func(), err error)
+}
+
+// LocalBroadcaster delivers the envelopes in process, for a single replica.
+type LocalBroadcaster struct {
+	mu       sync.RWMutex
+	next     int
+	handlers map[int]func(Envelope)
+}
+
+func NewLocalBroadcaster() *LocalBroadcaster {
+	return &LocalBroadcaster{handlers: make(map[int]func(Envelope))}
+}
+
+func (b *LocalBroadcaster) Publish(_ context.Context, envelope Envelope) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, handler := range b.handlers {
+		handler(envelope)
+	}
+
+	return nil
+}
+
+func (b *LocalBroadcaster) Subscribe(handler func(Envelope)) (func(), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.next
+	b.next++
+	b.handlers[id] = handler
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, id)
+	}, nil
+}
+
+// NATSConn is the part of *nats.Conn used by NATSBroadcaster.
+type NATSConn interface {
+	Publish(subject string, data []byte) error
+	Subscribe(subject string, handler nats.MsgHandler) (*nats.Subscription, error)
+}
+
+// NATSBroadcaster shares the envelopes of the replicas through a core NATS subject. Presence
+// is synced periodically, so an envelope lost while NATS is unreachable is recovered.
+type NATSBroadcaster struct {
+	conn    NATSConn
+	subject string
+	logger  zerolog.Logger
+}
+
+// ConnectNATS connects a NATSBroadcaster to url, which is drained when ctx is cancelled.
+func ConnectNATS(ctx context.Context, url, subject string, logger zerolog.Logger) (*NATSBroadcaster, error) {
+	conn, err := nats.Connect(url,
+		nats.Name("go-boilerplate-rest-api-chi-realtime"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = conn.Drain()
+	}()
+
+	return NewNATSBroadcaster(conn, subject, logger), nil
+}
+
+func NewNATSBroadcaster(conn NATSConn, subject string, logger zerolog.Logger) *NATSBroadcaster {
+	return &NATSBroadcaster{
+		conn:    conn,
+		subject: subject,
+		logger:  logger,
+	}
+}
+
+func (b *NATSBroadcaster) Publish(_ context.Context, envelope Envelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	return b.conn.Publish(b.subject, data)
+}
+
+func (b *NATSBroadcaster) Subscribe(handler func(Envelope)) (func(), error) {
+	subscription, err := b.conn.Subscribe(b.subject, func(msg *nats.Msg) {
+		var envelope Envelope
+		if err := json.Unmarshal(msg.Data, &envelope); err != nil {
+			b.logger.Warn().Err(err).Msg("dropping malformed realtime envelope")
+			return
+		}
+		handler(envelope)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return func() { _ = subscription.Unsubscribe() }, nil
+}
@@ -0,0 +1,87 @@
+package realtime_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/realtime"
+)
+
+func TestLocalBroadcaster(t *testing.T) {
+	broadcaster := realtime.NewLocalBroadcaster()
+
+	var first, second []realtime.Envelope
+	unsubscribe, err := broadcaster.Subscribe(func(envelope realtime.Envelope) { first = append(first, envelope) })
+	require.NoError(t, err)
+	_, err = broadcaster.Subscribe(func(envelope realtime.Envelope) { second = append(second, envelope) })
+	require.NoError(t, err)
+
+	require.NoError(t, broadcaster.Publish(context.Background(), realtime.Envelope{Kind: "join"}))
+	unsubscribe()
+	require.NoError(t, broadcaster.Publish(context.Background(), realtime.Envelope{Kind: "leave"}))
+
+	assert.Equal(t, []realtime.Envelope{{Kind: "join"}}, first)
+	assert.Equal(t, []realtime.Envelope{{Kind: "join"}, {Kind: "leave"}}, second)
+}
+
+type stubNATSConn struct {
+	subject  string
+	handlers []nats.MsgHandler
+}
+
+func (c *stubNATSConn) Publish(subject string, data []byte) error {
+	for _, handler := range c.handlers {
+		handler(&nats.Msg{Subject: subject, Data: data})
+	}
+	c.subject = subject
+	return nil
+}
+
+func (c *stubNATSConn) Subscribe(_ string, handler nats.MsgHandler) (*nats.Subscription, error) {
+	c.handlers = append(c.handlers, handler)
+	return nil, nil
+}
+
+func TestNATSBroadcaster(t *testing.T) {
+	conn := &stubNATSConn{}
+	broadcaster := realtime.NewNATSBroadcaster(conn, "library.realtime", zerolog.Nop())
+
+	var received []realtime.Envelope
+	unsubscribe, err := broadcaster.Subscribe(func(envelope realtime.Envelope) { received = append(received, envelope) })
+	require.NoError(t, err)
+	t.Cleanup(unsubscribe)
+
+	envelope := realtime.Envelope{
+		Kind: "join",
+		Viewings: []realtime.Viewing{{
+			BookID:       uuid.New(),
+			ConnectionID: "connection-1",
+			Viewer:       realtime.Viewer{UserID: "user-1", Name: "Victor"},
+		}},
+	}
+	require.NoError(t, broadcaster.Publish(context.Background(), envelope))
+
+	// Malformed payloads from another publisher on the subject are dropped.
+	conn.handlers[0](&nats.Msg{Data: []byte("not json")})
+
+	assert.Equal(t, "library.realtime", conn.subject)
+	assert.Equal(t, []realtime.Envelope{envelope}, received)
+
+	data, err := json.Marshal(envelope)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"kind": "join",
+		"viewings": [{
+			"book_id": "`+envelope.Viewings[0].BookID.String()+`",
+			"connection_id": "connection-1",
+			"viewer": {"user_id": "user-1", "name": "Victor"}
+		}]
+	}`, string(data))
+}
@@ -0,0 +1,189 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/book"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+const (
+	defaultPingInterval = 30 * time.Second
+	defaultWriteTimeout = 10 * time.Second
+	readLimit           = 4096
+)
+
+type Handler struct {
+	hub            *Hub
+	books          book.BookService
+	pingInterval   time.Duration
+	writeTimeout   time.Duration
+	originPatterns []string
+	logger         zerolog.Logger
+}
+
+func NewHandler(hub *Hub, books book.BookService, cfg config.RealtimeConfig, logger zerolog.Logger) *Handler {
+	if cfg.PingInterval <= 0 {
+		cfg.PingInterval = defaultPingInterval
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = defaultWriteTimeout
+	}
+
+	return &Handler{
+		hub:            hub,
+		books:          books,
+		pingInterval:   cfg.PingInterval,
+		writeTimeout:   cfg.WriteTimeout,
+		originPatterns: cfg.OriginPatterns,
+		logger:         logger,
+	}
+}
+
+func (h *Handler) Routes() http.Handler {
+	r := chi.NewRouter()
+
+	// routes
+	r.With(auth.Required).Get("/", h.Connect)
+
+	return r
+}
+
+// Connect godoc
+//
+//	@Summary		Open the WebSocket API
+//	@Description	Upgrades to a WebSocket with the "library.v1" subprotocol. Browsers, which cannot set the Authorization header, offer the token as a "bearer.<token>" subprotocol. Clients send {"type":"subscribe"|"unsubscribe","book_id":"..."}, a book of their tenant; the server pushes "subscribed"/"unsubscribed" acknowledgements, the "presence" of the viewers of the books followed, the "book.updated" and "book.deleted" changes made by other users, and "error" messages. The connection is closed with status 1001 when the server shuts down.
+//	@Tags			realtime
+//	@Security		ApiKeyAuth
+//	@Success		101	{string}	string	"switching protocols"
+//	@Failure		401	{object}	response.ErrorResponse
+//	@Failure		503	{object}	response.ErrorResponse
+//	@Router			/ws [get]
+func (h *Handler) Connect(w http.ResponseWriter, r *http.Request) {
+	principal, _ := auth.FromContext(r.Context())
+
+	client, err := h.hub.Register(r.Context(), principal)
+	if err != nil {
+		response.Error(w, http.StatusServiceUnavailable, "Server shutting down")
+		return
+	}
+	// The leave must reach the other replicas even though the request is over.
+	defer h.hub.Unregister(context.WithoutCancel(r.Context()), client)
+
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		Subprotocols:   []string{Subprotocol},
+		OriginPatterns: h.originPatterns,
+	})
+	if err != nil {
+		// Accept has already answered the request.
+		h.logger.Debug().Err(err).Msg("websocket handshake failed")
+		return
+	}
+	defer func() { _ = conn.CloseNow() }()
+
+	conn.SetReadLimit(readLimit)
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	go h.write(ctx, cancel, conn, client)
+	h.read(ctx, conn, client)
+}
+
+func (h *Handler) read(ctx context.Context, conn *websocket.Conn, client *Client) {
+	for {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+
+		var message ClientMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			client.send(ServerMessage{Type: MessageError, Message: "malformed message"})
+			continue
+		}
+
+		h.handle(ctx, client, message)
+	}
+}
+
+func (h *Handler) handle(ctx context.Context, client *Client, message ClientMessage) {
+	if message.Type != MessageSubscribe && message.Type != MessageUnsubscribe {
+		client.send(ServerMessage{Type: MessageError, Message: fmt.Sprintf("unknown message type %q", message.Type)})
+		return
+	}
+
+	bookID, err := uuid.Parse(message.BookID)
+	if err != nil {
+		client.send(ServerMessage{Type: MessageError, Message: fmt.Sprintf("invalid book_id %q", message.BookID)})
+		return
+	}
+
+	if message.Type == MessageUnsubscribe {
+		h.hub.Unsubscribe(ctx, client, bookID)
+		return
+	}
+
+	// ctx carries the tenant of the client: the books of the other tenants are not found.
+	if _, err := h.books.GetBookByID(ctx, bookID); err != nil {
+		message := "failed to find the book"
+		if errors.Is(err, book.ErrNotFound) {
+			message = "book not found"
+		} else {
+			h.logger.Error().Err(err).Msg("unexpected error")
+		}
+		client.send(ServerMessage{Type: MessageError, BookID: bookID, Message: message})
+		return
+	}
+
+	if err := h.hub.Subscribe(ctx, client, bookID); err != nil {
+		client.send(ServerMessage{Type: MessageError, BookID: bookID, Message: err.Error()})
+	}
+}
+
+// write sends the messages of the client and pings it. Cancelling ctx on a failure also
+// ends read.
+func (h *Handler) write(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn, client *Client) {
+	defer cancel()
+
+	ping := time.NewTicker(h.pingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-client.Done():
+			status, reason := client.CloseStatus()
+			_ = conn.Close(status, reason)
+			return
+		case message := <-client.Messages():
+			writeCtx, cancelWrite := context.WithTimeout(ctx, h.writeTimeout)
+			err := wsjson.Write(writeCtx, conn, message)
+			cancelWrite()
+			if err != nil {
+				return
+			}
+		case <-ping.C:
+			pingCtx, cancelPing := context.WithTimeout(ctx, h.writeTimeout)
+			err := conn.Ping(pingCtx)
+			cancelPing()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package realtime_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/book"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/realtime"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+)
+
+// tokens maps the bearer tokens of the tests to their user, and tenants to their tenant.
+var (
+	tokens = map[string]*auth.Principal{
+		"token-victor":  {Subject: "user-1", Name: "Victor"},
+		"token-emile":   {Subject: "user-2", Name: "Emile"},
+		"token-gustave": {Subject: "user-3", Name: "Gustave"},
+	}
+	tenants = map[string]string{
+		"token-victor":  "acme",
+		"token-emile":   "acme",
+		"token-gustave": "globex",
+	}
+)
+
+// catalogue stands for the book service, books listing the books of each tenant.
+func catalogue(t *testing.T, books map[string][]uuid.UUID) book.BookService {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	service := mocks.NewMockBookService(ctrl)
+	service.EXPECT().GetBookByID(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, bookID uuid.UUID, _ ...string) (*entity.Book, error) {
+			tenantID, _ := tenant.FromContext(ctx)
+			if !slices.Contains(books[tenantID], bookID) {
+				return nil, book.ErrNotFound
+			}
+			return &entity.Book{ID: bookID}, nil
+		}).
+		AnyTimes()
+
+	return service
+}
+
+func newServer(t *testing.T, hub *realtime.Hub, books book.BookService) *httptest.Server {
+	t.Helper()
+
+	authenticator := auth.AuthenticatorFunc(func(r *http.Request) (*auth.Principal, error) {
+		token := auth.BearerToken(r)
+		if token == "" {
+			return nil, auth.ErrNoCredentials
+		}
+		if principal, ok := tokens[token]; ok {
+			return principal, nil
+		}
+		return nil, auth.ErrInvalidCredentials
+	})
+
+	r := chi.NewRouter()
+	r.Use(auth.Middleware(authenticator))
+	// Stands for the tenant resolver.
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(tenant.WithID(r.Context(), tenants[auth.BearerToken(r)])))
+		})
+	})
+	r.Mount("/ws", realtime.NewHandler(hub, books, config.RealtimeConfig{}, zerolog.Nop()).Routes())
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func dial(t *testing.T, server *httptest.Server, subprotocols ...string) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	conn, resp, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws", &websocket.DialOptions{
+		Subprotocols: subprotocols,
+	})
+	if conn != nil {
+		t.Cleanup(func() { _ = conn.CloseNow() })
+	}
+
+	return conn, resp, err
+}
+
+func read(t *testing.T, conn *websocket.Conn) realtime.ServerMessage {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var message realtime.ServerMessage
+	require.NoError(t, wsjson.Read(ctx, conn, &message))
+	return message
+}
+
+func write(t *testing.T, conn *websocket.Conn, message any) {
+	t.Helper()
+	require.NoError(t, wsjson.Write(context.Background(), conn, message))
+}
+
+func TestHandler_Connect(t *testing.T) {
+	tests := []struct {
+		name           string
+		subprotocols   []string
+		expectedStatus int
+	}{
+		{
+			name:           "error no credentials",
+			subprotocols:   []string{realtime.Subprotocol},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "error invalid token",
+			subprotocols:   []string{realtime.Subprotocol, "bearer.unknown"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "success token as subprotocol",
+			subprotocols:   []string{realtime.Subprotocol, "bearer.token-victor"},
+			expectedStatus: http.StatusSwitchingProtocols,
+		},
+	}
+
+	hub := startHub(t, realtime.NewLocalBroadcaster(), config.RealtimeConfig{})
+	server := newServer(t, hub, catalogue(t, nil))
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn, resp, err := dial(t, server, test.subprotocols...)
+
+			require.NotNil(t, resp)
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+			if test.expectedStatus == http.StatusSwitchingProtocols {
+				require.NoError(t, err)
+				assert.Equal(t, realtime.Subprotocol, conn.Subprotocol())
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestHandler_Messages(t *testing.T) {
+	bookID := uuid.New()
+
+	hub := startHub(t, realtime.NewLocalBroadcaster(), config.RealtimeConfig{})
+	server := newServer(t, hub, catalogue(t, map[string][]uuid.UUID{"acme": {bookID}}))
+
+	victor, _, err := dial(t, server, realtime.Subprotocol, "bearer.token-victor")
+	require.NoError(t, err)
+	emile, _, err := dial(t, server, realtime.Subprotocol, "bearer.token-emile")
+	require.NoError(t, err)
+	gustave, _, err := dial(t, server, realtime.Subprotocol, "bearer.token-gustave")
+	require.NoError(t, err)
+
+	write(t, victor, realtime.ClientMessage{Type: "subscribe", BookID: bookID.String()})
+	assert.Equal(t, realtime.ServerMessage{Type: "subscribed", BookID: bookID}, read(t, victor))
+	assert.Equal(t, "presence", read(t, victor).Type)
+
+	write(t, emile, realtime.ClientMessage{Type: "subscribe", BookID: bookID.String()})
+	assert.Equal(t, "subscribed", read(t, emile).Type)
+
+	presence := realtime.ServerMessage{
+		Type:    "presence",
+		BookID:  bookID,
+		Viewers: []realtime.Viewer{{UserID: "user-1", Name: "Victor"}, {UserID: "user-2", Name: "Emile"}},
+	}
+	assert.Equal(t, presence, read(t, emile))
+	assert.Equal(t, presence, read(t, victor))
+
+	e := event.New(event.BookUpdated, bookID, map[string]any{"title": "Les Contemplations"})
+	e.Actor = "user-1"
+	e.Tenant = "acme"
+	require.NoError(t, hub.Publish(context.Background(), e))
+	assert.Equal(t, realtime.ServerMessage{
+		Type:   "book.updated",
+		BookID: bookID,
+		Actor:  "user-1",
+		Data:   map[string]any{"title": "Les Contemplations"},
+	}, read(t, emile))
+
+	// The books of the other tenants are not found.
+	write(t, gustave, realtime.ClientMessage{Type: "subscribe", BookID: bookID.String()})
+	assert.Equal(t, realtime.ServerMessage{Type: "error", BookID: bookID, Message: "book not found"}, read(t, gustave))
+
+	write(t, emile, realtime.ClientMessage{Type: "subscribe", BookID: "not-a-uuid"})
+	assert.Equal(t, realtime.ServerMessage{Type: "error", Message: `invalid book_id "not-a-uuid"`}, read(t, emile))
+
+	write(t, emile, realtime.ClientMessage{Type: "edit"})
+	assert.Equal(t, realtime.ServerMessage{Type: "error", Message: `unknown message type "edit"`}, read(t, emile))
+
+	require.NoError(t, emile.Write(context.Background(), websocket.MessageText, []byte("{")))
+	assert.Equal(t, realtime.ServerMessage{Type: "error", Message: "malformed message"}, read(t, emile))
+
+	// Disconnecting leaves the presence of the book.
+	require.NoError(t, emile.Close(websocket.StatusNormalClosure, ""))
+	assert.Equal(t, realtime.ServerMessage{
+		Type:    "presence",
+		BookID:  bookID,
+		Viewers: []realtime.Viewer{{UserID: "user-1", Name: "Victor"}},
+	}, read(t, victor))
+}
+
+func TestHandler_Shutdown(t *testing.T) {
+	hub := startHub(t, realtime.NewLocalBroadcaster(), config.RealtimeConfig{})
+	server := newServer(t, hub, catalogue(t, nil))
+
+	conn, _, err := dial(t, server, realtime.Subprotocol, "bearer.token-victor")
+	require.NoError(t, err)
+
+	shutdown := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		shutdown <- hub.Shutdown(ctx)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, _, err = conn.Read(ctx)
+
+	assert.Equal(t, websocket.StatusGoingAway, websocket.CloseStatus(err))
+	require.NoError(t, <-shutdown)
+
+	_, resp, err := dial(t, server, realtime.Subprotocol, "bearer.token-victor")
+	assert.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
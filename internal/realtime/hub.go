@@ -0,0 +1,410 @@
+package realtime
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+)
+
+const (
+	defaultPresenceInterval = 15 * time.Second
+	defaultClientBuffer     = 32
+	// presenceTTL is counted in presence intervals: a viewer is dropped after missing that
+	// many syncs, which clears the viewers of a replica that died.
+	presenceTTL      = 3
+	maxSubscriptions = 100
+)
+
+var (
+	ErrClosed               = errors.New("hub closed")
+	ErrTooManySubscriptions = errors.New("too many subscriptions")
+)
+
+// Client is a connection to the hub. Done is closed when the hub drops the client, either
+// because it fell behind by more than its buffer or because the hub is shutting down.
+type Client struct {
+	id       string
+	viewer   Viewer
+	tenant   string
+	messages chan ServerMessage
+	books    map[uuid.UUID]struct{}
+
+	done      chan struct{}
+	closeOnce sync.Once
+	status    websocket.StatusCode
+	reason    string
+}
+
+func (c *Client) Messages() <-chan ServerMessage {
+	return c.messages
+}
+
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// CloseStatus is the status to close the connection with once Done is closed.
+func (c *Client) CloseStatus() (websocket.StatusCode, string) {
+	return c.status, c.reason
+}
+
+func (c *Client) viewing(bookID uuid.UUID) Viewing {
+	return Viewing{Tenant: c.tenant, BookID: bookID, ConnectionID: c.id, Viewer: c.viewer}
+}
+
+func (c *Client) send(message ServerMessage) {
+	select {
+	case c.messages <- message:
+	default:
+		c.close(websocket.StatusPolicyViolation, "client too slow")
+	}
+}
+
+func (c *Client) close(status websocket.StatusCode, reason string) {
+	c.closeOnce.Do(func() {
+		c.status = status
+		c.reason = reason
+		close(c.done)
+	})
+}
+
+type presence struct {
+	viewer  Viewer
+	expires time.Time
+}
+
+// presenceKey is a book of a tenant: the viewers of a book are only shown to its tenant.
+type presenceKey struct {
+	tenant string
+	bookID uuid.UUID
+}
+
+// Hub tracks the books followed by the local connections and the viewers of those books
+// across the replicas. Every change goes through the broadcaster, the local ones included,
+// so all the hubs apply the same envelopes.
+type Hub struct {
+	broadcaster      Broadcaster
+	presenceInterval time.Duration
+	clientBuffer     int
+	logger           zerolog.Logger
+
+	mu       sync.Mutex
+	clients  map[*Client]struct{}
+	books    map[uuid.UUID]map[*Client]struct{}
+	presence map[presenceKey]map[string]presence
+	closed   bool
+	active   sync.WaitGroup
+}
+
+// NewHub uses the defaults of config.RealtimeConfig for the zero values of cfg.
+func NewHub(broadcaster Broadcaster, cfg config.RealtimeConfig, logger zerolog.Logger) *Hub {
+	if cfg.PresenceInterval <= 0 {
+		cfg.PresenceInterval = defaultPresenceInterval
+	}
+	if cfg.ClientBuffer <= 0 {
+		cfg.ClientBuffer = defaultClientBuffer
+	}
+
+	return &Hub{
+		broadcaster:      broadcaster,
+		presenceInterval: cfg.PresenceInterval,
+		clientBuffer:     cfg.ClientBuffer,
+		logger:           logger,
+		clients:          make(map[*Client]struct{}),
+		books:            make(map[uuid.UUID]map[*Client]struct{}),
+		presence:         make(map[presenceKey]map[string]presence),
+	}
+}
+
+// Start subscribes the hub to the broadcaster and syncs the presence until ctx is
+// cancelled, which closes the hub.
+func (h *Hub) Start(ctx context.Context) error {
+	unsubscribe, err := h.broadcaster.Subscribe(h.receive)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer unsubscribe()
+
+		ticker := time.NewTicker(h.presenceInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				h.Close()
+				return
+			case <-ticker.C:
+				h.sync(ctx)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Register adds a client, receiving the changes of the books of the tenant of ctx.
+func (h *Hub) Register(ctx context.Context, principal *auth.Principal) (*Client, error) {
+	tenantID, _ := tenant.FromContext(ctx)
+	client := &Client{
+		id:       uuid.NewString(),
+		tenant:   tenantID,
+		viewer:   Viewer{UserID: principal.Subject, Name: principal.Name},
+		messages: make(chan ServerMessage, h.clientBuffer),
+		books:    make(map[uuid.UUID]struct{}),
+		done:     make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, ErrClosed
+	}
+
+	h.clients[client] = struct{}{}
+	h.active.Add(1)
+
+	return client, nil
+}
+
+// Unregister removes the client and its presence.
+func (h *Hub) Unregister(ctx context.Context, client *Client) {
+	h.mu.Lock()
+	if _, ok := h.clients[client]; !ok {
+		h.mu.Unlock()
+		return
+	}
+
+	viewings := make([]Viewing, 0, len(client.books))
+	for bookID := range client.books {
+		h.unfollow(client, bookID)
+		viewings = append(viewings, client.viewing(bookID))
+	}
+	delete(h.clients, client)
+	h.active.Done()
+	h.mu.Unlock()
+
+	client.close(websocket.StatusNormalClosure, "")
+
+	if len(viewings) > 0 {
+		h.broadcast(ctx, Envelope{Kind: envelopeLeave, Viewings: viewings})
+	}
+}
+
+// Subscribe follows the book for the client. The caller checks that the book belongs to the
+// tenant of the client.
+func (h *Hub) Subscribe(ctx context.Context, client *Client, bookID uuid.UUID) error {
+	h.mu.Lock()
+	if _, ok := client.books[bookID]; ok {
+		h.mu.Unlock()
+		client.send(ServerMessage{Type: MessageSubscribed, BookID: bookID})
+		return nil
+	}
+
+	if len(client.books) >= maxSubscriptions {
+		h.mu.Unlock()
+		return ErrTooManySubscriptions
+	}
+
+	client.books[bookID] = struct{}{}
+	if h.books[bookID] == nil {
+		h.books[bookID] = make(map[*Client]struct{})
+	}
+	h.books[bookID][client] = struct{}{}
+	h.mu.Unlock()
+
+	client.send(ServerMessage{Type: MessageSubscribed, BookID: bookID})
+	h.broadcast(ctx, Envelope{Kind: envelopeJoin, Viewings: []Viewing{client.viewing(bookID)}})
+
+	return nil
+}
+
+func (h *Hub) Unsubscribe(ctx context.Context, client *Client, bookID uuid.UUID) {
+	h.mu.Lock()
+	_, ok := client.books[bookID]
+	if ok {
+		h.unfollow(client, bookID)
+	}
+	h.mu.Unlock()
+
+	client.send(ServerMessage{Type: MessageUnsubscribed, BookID: bookID})
+	if ok {
+		h.broadcast(ctx, Envelope{Kind: envelopeLeave, Viewings: []Viewing{client.viewing(bookID)}})
+	}
+}
+
+// Publish notifies the followers of the book of e, on every replica, except the user who
+// made the change.
+func (h *Hub) Publish(ctx context.Context, e event.Event) error {
+	return h.broadcaster.Publish(ctx, Envelope{Kind: envelopeEvent, Event: &e})
+}
+
+// Close drops every client; their connections are closed with StatusGoingAway.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	clients := make([]*Client, 0, len(h.clients))
+	for client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mu.Unlock()
+
+	for _, client := range clients {
+		client.close(websocket.StatusGoingAway, "server shutting down")
+	}
+}
+
+// Shutdown closes the hub and waits for the connections to unregister, or for ctx to end.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.Close()
+
+	done := make(chan struct{})
+	go func() {
+		h.active.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *Hub) receive(envelope Envelope) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	changed := make(map[presenceKey]struct{})
+	expires := time.Now().Add(presenceTTL * h.presenceInterval)
+
+	switch envelope.Kind {
+	case envelopeJoin, envelopeSync:
+		for _, viewing := range envelope.Viewings {
+			key := presenceKey{tenant: viewing.Tenant, bookID: viewing.BookID}
+			viewers := h.presence[key]
+			if viewers == nil {
+				viewers = make(map[string]presence)
+				h.presence[key] = viewers
+			}
+
+			if _, ok := viewers[viewing.ConnectionID]; !ok {
+				changed[key] = struct{}{}
+			}
+			viewers[viewing.ConnectionID] = presence{viewer: viewing.Viewer, expires: expires}
+		}
+	case envelopeLeave:
+		for _, viewing := range envelope.Viewings {
+			key := presenceKey{tenant: viewing.Tenant, bookID: viewing.BookID}
+			if _, ok := h.presence[key][viewing.ConnectionID]; ok {
+				h.removePresence(key, viewing.ConnectionID)
+				changed[key] = struct{}{}
+			}
+		}
+	case envelopeEvent:
+		if e := envelope.Event; e != nil {
+			for client := range h.books[e.AggregateID] {
+				if client.viewer.UserID == e.Actor || client.tenant != e.Tenant {
+					continue
+				}
+				client.send(ServerMessage{Type: string(e.Type), BookID: e.AggregateID, Actor: e.Actor, Data: e.Data})
+			}
+		}
+	}
+
+	for key := range changed {
+		h.pushPresence(key)
+	}
+}
+
+// sync refreshes the presence of the local connections on every replica and drops the
+// viewers whose replica stopped syncing.
+func (h *Hub) sync(ctx context.Context) {
+	h.mu.Lock()
+	now := time.Now()
+	for key, viewers := range h.presence {
+		expired := false
+		for connectionID, p := range viewers {
+			if now.After(p.expires) {
+				h.removePresence(key, connectionID)
+				expired = true
+			}
+		}
+		if expired {
+			h.pushPresence(key)
+		}
+	}
+
+	var viewings []Viewing
+	for client := range h.clients {
+		for bookID := range client.books {
+			viewings = append(viewings, client.viewing(bookID))
+		}
+	}
+	h.mu.Unlock()
+
+	if len(viewings) > 0 {
+		h.broadcast(ctx, Envelope{Kind: envelopeSync, Viewings: viewings})
+	}
+}
+
+func (h *Hub) broadcast(ctx context.Context, envelope Envelope) {
+	if err := h.broadcaster.Publish(ctx, envelope); err != nil {
+		h.logger.Warn().Err(err).Str("kind", envelope.Kind).Msg("failed to broadcast realtime envelope")
+	}
+}
+
+// unfollow must be called with h.mu held.
+func (h *Hub) unfollow(client *Client, bookID uuid.UUID) {
+	delete(client.books, bookID)
+	delete(h.books[bookID], client)
+	if len(h.books[bookID]) == 0 {
+		delete(h.books, bookID)
+	}
+}
+
+// removePresence must be called with h.mu held.
+func (h *Hub) removePresence(key presenceKey, connectionID string) {
+	delete(h.presence[key], connectionID)
+	if len(h.presence[key]) == 0 {
+		delete(h.presence, key)
+	}
+}
+
+// pushPresence sends the viewers of the book, one entry per user, to its local followers of
+// the same tenant. It must be called with h.mu held.
+func (h *Hub) pushPresence(key presenceKey) {
+	followers := h.books[key.bookID]
+	if len(followers) == 0 {
+		return
+	}
+
+	var viewers []Viewer
+	for _, p := range h.presence[key] {
+		if !slices.ContainsFunc(viewers, func(viewer Viewer) bool { return viewer.UserID == p.viewer.UserID }) {
+			viewers = append(viewers, p.viewer)
+		}
+	}
+	slices.SortFunc(viewers, func(a, b Viewer) int { return cmp.Compare(a.UserID, b.UserID) })
+
+	for client := range followers {
+		if client.tenant == key.tenant {
+			client.send(ServerMessage{Type: MessagePresence, BookID: key.bookID, Viewers: viewers})
+		}
+	}
+}
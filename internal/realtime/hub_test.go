@@ -0,0 +1,193 @@
+package realtime_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/realtime"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+)
+
+func next(t *testing.T, client *realtime.Client) realtime.ServerMessage {
+	t.Helper()
+
+	select {
+	case message := <-client.Messages():
+		return message
+	case <-time.After(time.Second):
+		require.FailNow(t, "no message received")
+		return realtime.ServerMessage{}
+	}
+}
+
+func assertNoMessage(t *testing.T, client *realtime.Client) {
+	t.Helper()
+
+	select {
+	case message := <-client.Messages():
+		assert.Fail(t, "unexpected message", "%+v", message)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func startHub(t *testing.T, broadcaster realtime.Broadcaster, cfg config.RealtimeConfig) *realtime.Hub {
+	t.Helper()
+
+	hub := realtime.NewHub(broadcaster, cfg, zerolog.Nop())
+	require.NoError(t, hub.Start(t.Context()))
+	return hub
+}
+
+func TestHub_AcrossReplicas(t *testing.T) {
+	ctx := context.Background()
+	broadcaster := realtime.NewLocalBroadcaster()
+	first := startHub(t, broadcaster, config.RealtimeConfig{})
+	second := startHub(t, broadcaster, config.RealtimeConfig{})
+
+	bookID := uuid.New()
+	victor := realtime.Viewer{UserID: "user-1", Name: "Victor"}
+	emile := realtime.Viewer{UserID: "user-2", Name: "Emile"}
+
+	a, err := first.Register(t.Context(), &auth.Principal{Subject: "user-1", Name: "Victor"})
+	require.NoError(t, err)
+	b, err := second.Register(t.Context(), &auth.Principal{Subject: "user-2", Name: "Emile"})
+	require.NoError(t, err)
+
+	require.NoError(t, first.Subscribe(ctx, a, bookID))
+	assert.Equal(t, realtime.ServerMessage{Type: "subscribed", BookID: bookID}, next(t, a))
+	assert.Equal(t, realtime.ServerMessage{Type: "presence", BookID: bookID, Viewers: []realtime.Viewer{victor}}, next(t, a))
+
+	require.NoError(t, second.Subscribe(ctx, b, bookID))
+	assert.Equal(t, realtime.ServerMessage{Type: "subscribed", BookID: bookID}, next(t, b))
+	assert.Equal(t, realtime.ServerMessage{Type: "presence", BookID: bookID, Viewers: []realtime.Viewer{victor, emile}}, next(t, b))
+	assert.Equal(t, realtime.ServerMessage{Type: "presence", BookID: bookID, Viewers: []realtime.Viewer{victor, emile}}, next(t, a))
+
+	// Changes are pushed to the other followers of the book, not to their author.
+	e := event.New(event.BookUpdated, bookID, map[string]any{"title": "Les Contemplations"})
+	e.Actor = "user-1"
+	require.NoError(t, first.Publish(ctx, e))
+	assert.Equal(t, realtime.ServerMessage{Type: "book.updated", BookID: bookID, Actor: "user-1", Data: e.Data}, next(t, b))
+	assertNoMessage(t, a)
+
+	require.NoError(t, first.Publish(ctx, event.New(event.BookUpdated, uuid.New(), nil)))
+	assertNoMessage(t, b)
+
+	second.Unsubscribe(ctx, b, bookID)
+	assert.Equal(t, realtime.ServerMessage{Type: "unsubscribed", BookID: bookID}, next(t, b))
+	assert.Equal(t, realtime.ServerMessage{Type: "presence", BookID: bookID, Viewers: []realtime.Viewer{victor}}, next(t, a))
+
+	require.NoError(t, second.Subscribe(ctx, b, bookID))
+	assert.Equal(t, "subscribed", next(t, b).Type)
+	assert.Len(t, next(t, a).Viewers, 2)
+
+	second.Unregister(ctx, b)
+	assert.Equal(t, realtime.ServerMessage{Type: "presence", BookID: bookID, Viewers: []realtime.Viewer{victor}}, next(t, a))
+}
+
+func TestHub_PresenceByTenant(t *testing.T) {
+	ctx := context.Background()
+	hub := startHub(t, realtime.NewLocalBroadcaster(), config.RealtimeConfig{})
+
+	bookID := uuid.New()
+	a, err := hub.Register(tenant.WithID(t.Context(), "acme"), &auth.Principal{Subject: "user-1", Name: "Victor"})
+	require.NoError(t, err)
+	b, err := hub.Register(tenant.WithID(t.Context(), "globex"), &auth.Principal{Subject: "user-2", Name: "Emile"})
+	require.NoError(t, err)
+
+	require.NoError(t, hub.Subscribe(ctx, a, bookID))
+	assert.Equal(t, "subscribed", next(t, a).Type)
+	assert.Equal(t, []realtime.Viewer{{UserID: "user-1", Name: "Victor"}}, next(t, a).Viewers)
+
+	// The viewers of the same book ID in another tenant are not shown.
+	require.NoError(t, hub.Subscribe(ctx, b, bookID))
+	assert.Equal(t, "subscribed", next(t, b).Type)
+	assert.Equal(t, []realtime.Viewer{{UserID: "user-2", Name: "Emile"}}, next(t, b).Viewers)
+	assertNoMessage(t, a)
+
+	hub.Unregister(ctx, b)
+	assertNoMessage(t, a)
+}
+
+func TestHub_PresenceExpires(t *testing.T) {
+	ctx := context.Background()
+	broadcaster := realtime.NewLocalBroadcaster()
+	hub := startHub(t, broadcaster, config.RealtimeConfig{PresenceInterval: 20 * time.Millisecond})
+
+	bookID := uuid.New()
+	client, err := hub.Register(t.Context(), &auth.Principal{Subject: "user-1"})
+	require.NoError(t, err)
+	require.NoError(t, hub.Subscribe(ctx, client, bookID))
+	assert.Equal(t, "subscribed", next(t, client).Type)
+	assert.Len(t, next(t, client).Viewers, 1)
+
+	// A replica that died after a join never syncs nor leaves.
+	require.NoError(t, broadcaster.Publish(ctx, realtime.Envelope{
+		Kind:     "join",
+		Viewings: []realtime.Viewing{{BookID: bookID, ConnectionID: "gone", Viewer: realtime.Viewer{UserID: "user-2"}}},
+	}))
+	assert.Len(t, next(t, client).Viewers, 2)
+
+	assert.Equal(t, realtime.ServerMessage{Type: "presence", BookID: bookID, Viewers: []realtime.Viewer{{UserID: "user-1"}}}, next(t, client))
+}
+
+func TestHub_TooManySubscriptions(t *testing.T) {
+	ctx := context.Background()
+	hub := startHub(t, realtime.NewLocalBroadcaster(), config.RealtimeConfig{ClientBuffer: 1000})
+
+	client, err := hub.Register(t.Context(), &auth.Principal{Subject: "user-1"})
+	require.NoError(t, err)
+
+	for range 100 {
+		require.NoError(t, hub.Subscribe(ctx, client, uuid.New()))
+	}
+
+	assert.ErrorIs(t, hub.Subscribe(ctx, client, uuid.New()), realtime.ErrTooManySubscriptions)
+}
+
+func TestHub_SlowClient(t *testing.T) {
+	ctx := context.Background()
+	hub := startHub(t, realtime.NewLocalBroadcaster(), config.RealtimeConfig{ClientBuffer: 1})
+
+	client, err := hub.Register(t.Context(), &auth.Principal{Subject: "user-1"})
+	require.NoError(t, err)
+
+	// The acknowledgement fills the buffer, the presence overflows it.
+	require.NoError(t, hub.Subscribe(ctx, client, uuid.New()))
+
+	<-client.Done()
+	status, _ := client.CloseStatus()
+	assert.Equal(t, websocket.StatusPolicyViolation, status)
+}
+
+func TestHub_Shutdown(t *testing.T) {
+	hub := startHub(t, realtime.NewLocalBroadcaster(), config.RealtimeConfig{})
+
+	client, err := hub.Register(t.Context(), &auth.Principal{Subject: "user-1"})
+	require.NoError(t, err)
+
+	go func() {
+		<-client.Done()
+		hub.Unregister(context.Background(), client)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, hub.Shutdown(ctx))
+
+	status, reason := client.CloseStatus()
+	assert.Equal(t, websocket.StatusGoingAway, status)
+	assert.Equal(t, "server shutting down", reason)
+
+	_, err = hub.Register(t.Context(), &auth.Principal{Subject: "user-2"})
+	assert.ErrorIs(t, err, realtime.ErrClosed)
+}
@@ -0,0 +1,64 @@
+package realtime
+
+import (
+	"github.com/google/uuid"
+
+	"go-boilerplate-rest-api-chi/internal/event"
+)
+
+// Subprotocol is the WebSocket subprotocol negotiated with the clients.
+const Subprotocol = "library.v1"
+
+const (
+	MessageSubscribe    = "subscribe"
+	MessageUnsubscribe  = "unsubscribe"
+	MessageSubscribed   = "subscribed"
+	MessageUnsubscribed = "unsubscribed"
+	MessagePresence     = "presence"
+	MessageError        = "error"
+)
+
+// ClientMessage is sent by the clients to follow a book or to stop following it.
+type ClientMessage struct {
+	Type   string `json:"type"`
+	BookID string `json:"book_id"`
+}
+
+// ServerMessage is pushed to the clients: acknowledgements, the viewers of a book, the
+// changes made to it by other users (typed after the event, e.g. "book.updated") and errors.
+type ServerMessage struct {
+	Type    string    `json:"type"`
+	BookID  uuid.UUID `json:"book_id,omitzero"`
+	Viewers []Viewer  `json:"viewers,omitempty"`
+	Actor   string    `json:"actor,omitempty"`
+	Data    any       `json:"data,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+type Viewer struct {
+	UserID string `json:"user_id"`
+	Name   string `json:"name,omitempty"`
+}
+
+const (
+	envelopeJoin  = "join"
+	envelopeLeave = "leave"
+	envelopeSync  = "sync"
+	envelopeEvent = "event"
+)
+
+// Envelope is what the hubs of the replicas exchange through the broadcaster.
+type Envelope struct {
+	Kind     string       `json:"kind"`
+	Viewings []Viewing    `json:"viewings,omitempty"`
+	Event    *event.Event `json:"event,omitempty"`
+}
+
+// Viewing is a connection following a book of its tenant. Connection IDs are unique across
+// replicas.
+type Viewing struct {
+	Tenant       string    `json:"tenant,omitempty"`
+	BookID       uuid.UUID `json:"book_id"`
+	ConnectionID string    `json:"connection_id"`
+	Viewer       Viewer    `json:"viewer"`
+}
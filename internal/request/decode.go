@@ -0,0 +1,301 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+// DefaultMaxBodySize bounds the bodies of the routes without MaxBodySize.
+const DefaultMaxBodySize int64 = 1 << 20
+
+type maxBodySizeKey struct{}
+
+// MaxBodySize bounds the bodies of the requests below it to n bytes, zero keeping
+// DefaultMaxBodySize: reading past it fails with an *http.MaxBytesError, whatever reads the
+// body, and DecodeJSON refuses it with TooLarge.
+func MaxBodySize(n int64) func(http.Handler) http.Handler {
+	if n <= 0 {
+		n = DefaultMaxBodySize
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = http.MaxBytesReader(w, r.Body, n)
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), maxBodySizeKey{}, n)))
+		})
+	}
+}
+
+func maxBodySize(ctx context.Context) int64 {
+	if n, ok := ctx.Value(maxBodySizeKey{}).(int64); ok && n > 0 {
+		return n
+	}
+	return DefaultMaxBodySize
+}
+
+// Error is a body DecodeJSON refused, with the status to answer and the detail of the
+// problems.
+type Error struct {
+	Status  int
+	Message string
+	Errors  []response.ValidationErrorDetail
+}
+
+func (e *Error) Error() string {
+	if len(e.Errors) == 0 {
+		return e.Message
+	}
+	return e.Message + ": " + e.Errors[0].Message
+}
+
+// DecodeJSON strictly decodes the JSON body of r into dst: the content type must be JSON,
+// the body must fit in the limit of MaxBodySize and hold a single value without unknown
+// fields. The errors are *Error, written by WriteError.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	if err := checkContentType(r); err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize(r.Context())))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return TooLarge(maxBytesErr.Limit)
+		}
+		return invalidBody("body", fmt.Sprintf("body could not be read: %v", err), "read")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		return decodeError(data, err)
+	}
+
+	offset := decoder.InputOffset()
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return invalidBody("body", fmt.Sprintf("body must hold a single JSON value, unexpected data %s", position(data, offset+int64(skip(data[offset:], " \t\r\n")))), "trailing_data")
+	}
+
+	return nil
+}
+
+// TooLarge is the error of a body over limit bytes, answered 413.
+func TooLarge(limit int64) *Error {
+	return &Error{
+		Status:  http.StatusRequestEntityTooLarge,
+		Message: "Request body too large",
+		Errors: []response.ValidationErrorDetail{{
+			Field:   "body",
+			Message: fmt.Sprintf("body must not exceed %d bytes", limit),
+			Tag:     "max_size",
+			Param:   strconv.FormatInt(limit, 10),
+		}},
+	}
+}
+
+// WriteError answers the error of DecodeJSON in the ValidationErrorResponse format.
+func WriteError(w http.ResponseWriter, err error) {
+	var requestErr *Error
+	if !errors.As(err, &requestErr) {
+		requestErr = invalidBody("body", err.Error(), "")
+	}
+
+	response.JSON(w, requestErr.Status, response.ValidationErrorResponse{
+		Status:  "error",
+		Message: requestErr.Message,
+		Errors:  requestErr.Errors,
+	})
+}
+
+func checkContentType(r *http.Request) error {
+	contentType := r.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) {
+		return nil
+	}
+
+	return &Error{
+		Status:  http.StatusUnsupportedMediaType,
+		Message: "Unsupported media type",
+		Errors: []response.ValidationErrorDetail{{
+			Field:   "Content-Type",
+			Message: fmt.Sprintf("Content-Type must be application/json, got %q", contentType),
+			Tag:     "content_type",
+			Param:   "application/json",
+		}},
+	}
+}
+
+func decodeError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return invalidBody("body", "body must not be empty", "required")
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return invalidBody("body", fmt.Sprintf("body is truncated %s", position(data, int64(len(data)))), "syntax")
+	case errors.As(err, &syntaxErr):
+		// The offset of a SyntaxError is the byte after the invalid one.
+		return invalidBody("body", fmt.Sprintf("body is malformed %s: %s", position(data, syntaxErr.Offset-1), syntaxErr), "syntax")
+	case errors.As(err, &typeErr):
+		field := fieldPath(typeErr.Field)
+		return invalidBody(field, fmt.Sprintf("%s must be %s, got %s %s", field, jsonType(typeErr.Type.Kind()), typeErr.Value, position(data, valueStart(data, typeErr))), "type")
+	}
+
+	if name, found := strings.CutPrefix(err.Error(), "json: unknown field "); found {
+		name, _ = strconv.Unquote(name)
+		message := fmt.Sprintf("%s is not a known field", name)
+		if offset, ok := keyOffset(data, name); ok {
+			message += " " + position(data, offset)
+		}
+		return invalidBody(name, message, "unknown_field")
+	}
+
+	return invalidBody("body", fmt.Sprintf("body is invalid: %v", err), "")
+}
+
+func invalidBody(field, message, tag string) *Error {
+	return &Error{
+		Status:  http.StatusBadRequest,
+		Message: "Invalid request body",
+		Errors:  []response.ValidationErrorDetail{{Field: field, Message: message, Tag: tag}},
+	}
+}
+
+// position describes offset as a line and a column of characters, counted from 1, to locate
+// the problem in the body as sent.
+func position(data []byte, offset int64) string {
+	offset = max(0, min(offset, int64(len(data))))
+	line := bytes.Count(data[:offset], []byte("\n")) + 1
+	column := utf8.RuneCount(data[bytes.LastIndexByte(data[:offset], '\n')+1:offset]) + 1
+
+	return fmt.Sprintf("at line %d, column %d (offset %d)", line, column, offset)
+}
+
+// fieldPath writes the dotted path of a decoding error like the validation errors, such as
+// contributors[1].role.
+func fieldPath(path string) string {
+	if path == "" {
+		return "body"
+	}
+
+	var b strings.Builder
+	for i, part := range strings.Split(path, ".") {
+		switch _, err := strconv.Atoi(part); {
+		case err == nil:
+			b.WriteString("[" + part + "]")
+		case i > 0:
+			b.WriteString("." + part)
+		default:
+			b.WriteString(part)
+		}
+	}
+	return b.String()
+}
+
+// valueStart finds the offset of the value of err: the decoder reports the end of the
+// literals, and the byte after the opening delimiter of the objects and arrays.
+func valueStart(data []byte, err *json.UnmarshalTypeError) int64 {
+	end := min(err.Offset, int64(len(data)))
+	if err.Value == "object" || err.Value == "array" || end < 1 {
+		return max(0, end-1)
+	}
+
+	if data[end-1] != '"' {
+		return int64(bytes.LastIndexAny(data[:end], " \t\r\n:,[") + 1)
+	}
+	for start := end - 2; start >= 0; start-- {
+		if data[start] == '"' && (start == 0 || data[start-1] != '\\') {
+			return start
+		}
+	}
+	return 0
+}
+
+// jsonType names the JSON type decoded into kind.
+func jsonType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	default:
+		return "a " + kind.String()
+	}
+}
+
+// keyOffset finds the offset of the first object key called name in data, the decoder only
+// reporting the name of the unknown fields.
+func keyOffset(data []byte, name string) (int64, bool) {
+	type container struct {
+		object bool
+		// expectingKey is whether the next string of the object is a key.
+		expectingKey bool
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var open []*container
+	for {
+		before := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err != nil {
+			return 0, false
+		}
+
+		var parent *container
+		if len(open) > 0 {
+			parent = open[len(open)-1]
+		}
+
+		if token, ok := token.(string); ok && parent != nil && parent.object && parent.expectingKey {
+			if token == name {
+				// The offset before the token precedes the separators and the spaces.
+				return before + int64(skip(data[before:], " \t\r\n,:")), true
+			}
+			parent.expectingKey = false
+			continue
+		}
+
+		// Any other token is, or starts, a value: the next string of the parent is a key.
+		if parent != nil && parent.object {
+			parent.expectingKey = true
+		}
+		if delim, ok := token.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				open = append(open, &container{object: delim == '{', expectingKey: delim == '{'})
+			default:
+				open = open[:len(open)-1]
+			}
+		}
+	}
+}
+
+// skip counts the leading bytes of data in cutset.
+func skip(data []byte, cutset string) int {
+	return len(data) - len(bytes.TrimLeft(data, cutset))
+}
@@ -0,0 +1,176 @@
+package request_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/request"
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+type contributor struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type book struct {
+	Title        string        `json:"title"`
+	Pages        int           `json:"pages"`
+	Contributors []contributor `json:"contributors"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		maxBodySize    int64
+		expectedBook   book
+		expectedStatus int
+		expectedError  response.ValidationErrorDetail
+	}{
+		{
+			name:         "success",
+			contentType:  "application/json; charset=utf-8",
+			body:         `{"title": "Les Misérables", "pages": 1232, "contributors": [{"name": "Victor Hugo", "role": "author"}]}`,
+			expectedBook: book{Title: "Les Misérables", Pages: 1232, Contributors: []contributor{{Name: "Victor Hugo", Role: "author"}}},
+		},
+		{
+			name:         "success json suffix",
+			contentType:  "application/merge-patch+json",
+			body:         "{\"title\": \"Les Misérables\"}\n",
+			expectedBook: book{Title: "Les Misérables"},
+		},
+		{
+			name:           "error missing content type",
+			body:           `{"title": "Les Misérables"}`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedError:  response.ValidationErrorDetail{Field: "Content-Type", Message: `Content-Type must be application/json, got ""`, Tag: "content_type", Param: "application/json"},
+		},
+		{
+			name:           "error form content type",
+			contentType:    "application/x-www-form-urlencoded",
+			body:           "title=Les+Mis%C3%A9rables",
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedError:  response.ValidationErrorDetail{Field: "Content-Type", Message: `Content-Type must be application/json, got "application/x-www-form-urlencoded"`, Tag: "content_type", Param: "application/json"},
+		},
+		{
+			name:           "error too large",
+			contentType:    "application/json",
+			body:           `{"title": "Les Misérables"}`,
+			maxBodySize:    16,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedError:  response.ValidationErrorDetail{Field: "body", Message: "body must not exceed 16 bytes", Tag: "max_size", Param: "16"},
+		},
+		{
+			name:           "error empty",
+			contentType:    "application/json",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  response.ValidationErrorDetail{Field: "body", Message: "body must not be empty", Tag: "required"},
+		},
+		{
+			name:           "error unknown field",
+			contentType:    "application/json",
+			body:           "{\n  \"title\": \"Les Misérables\",\n  \"descripton\": \"Jean Valjean\"\n}",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  response.ValidationErrorDetail{Field: "descripton", Message: "descripton is not a known field at line 3, column 3 (offset 34)", Tag: "unknown_field"},
+		},
+		{
+			name:           "error unknown nested field",
+			contentType:    "application/json",
+			body:           `{"contributors": [{"name": "Victor Hugo", "role": "author", "rôle": "x"}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  response.ValidationErrorDetail{Field: "rôle", Message: "rôle is not a known field at line 1, column 61 (offset 60)", Tag: "unknown_field"},
+		},
+		{
+			name:           "error syntax",
+			contentType:    "application/json",
+			body:           "{\n  \"title\": \"Les Misérables\",\n}",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  response.ValidationErrorDetail{Field: "body", Message: "body is malformed at line 3, column 1 (offset 32): invalid character '}' looking for beginning of object key string", Tag: "syntax"},
+		},
+		{
+			name:           "error truncated",
+			contentType:    "application/json",
+			body:           `{"title": "Les`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  response.ValidationErrorDetail{Field: "body", Message: "body is truncated at line 1, column 15 (offset 14)", Tag: "syntax"},
+		},
+		{
+			name:           "error type",
+			contentType:    "application/json",
+			body:           `{"title": "Les Misérables", "pages": "1232"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  response.ValidationErrorDetail{Field: "pages", Message: "pages must be a number, got string at line 1, column 38 (offset 38)", Tag: "type"},
+		},
+		{
+			name:           "error nested type",
+			contentType:    "application/json",
+			body:           `{"contributors": [{"name": "Victor Hugo"}, {"name": 42}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  response.ValidationErrorDetail{Field: "contributors[1].name", Message: "contributors[1].name must be a string, got number at line 1, column 53 (offset 52)", Tag: "type"},
+		},
+		{
+			name:           "error trailing data",
+			contentType:    "application/json",
+			body:           `{"title": "Les Misérables"} {"title": "Notre-Dame de Paris"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  response.ValidationErrorDetail{Field: "body", Message: "body must hold a single JSON value, unexpected data at line 1, column 29 (offset 29)", Tag: "trailing_data"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var decoded book
+			var decodeErr error
+			var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if decodeErr = request.DecodeJSON(w, r, &decoded); decodeErr != nil {
+					request.WriteError(w, decodeErr)
+				}
+			})
+			if test.maxBodySize > 0 {
+				handler = request.MaxBodySize(test.maxBodySize)(handler)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(test.body))
+			if test.contentType != "" {
+				req.Header.Set("Content-Type", test.contentType)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if test.expectedStatus == 0 {
+				require.NoError(t, decodeErr)
+				assert.Equal(t, test.expectedBook, decoded)
+				return
+			}
+
+			var requestErr *request.Error
+			require.ErrorAs(t, decodeErr, &requestErr)
+			assert.Equal(t, test.expectedStatus, rr.Code)
+			assert.Equal(t, []response.ValidationErrorDetail{test.expectedError}, requestErr.Errors)
+			assert.Contains(t, rr.Body.String(), `"status":"error"`)
+		})
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	var readErr error
+	handler := request.MaxBodySize(16)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Any reader of the body is bounded, not only DecodeJSON.
+		_, readErr = io.ReadAll(r.Body)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(`{"title": "Les Misérables"}`))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var maxBytesErr *http.MaxBytesError
+	require.ErrorAs(t, readErr, &maxBytesErr)
+	assert.Equal(t, int64(16), maxBytesErr.Limit)
+}
@@ -0,0 +1,305 @@
+package response
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var ErrNotRepresentable = errors.New("value cannot be represented in the requested format")
+
+// Renderer encodes a response payload in one media type.
+type Renderer interface {
+	ContentType() string
+	Render(w io.Writer, data any) error
+}
+
+// Tabular is implemented by payloads that can be rendered as CSV.
+type Tabular interface {
+	CSVHeader() []string
+	CSVRecords() [][]string
+}
+
+type registration struct {
+	format   string
+	renderer Renderer
+}
+
+// mediaTypeAliases maps the non-standard media types clients still send to the registered ones.
+var mediaTypeAliases = map[string]string{
+	"text/xml":                "application/xml",
+	"application/x-msgpack":   "application/msgpack",
+	"application/vnd.msgpack": "application/msgpack",
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = []registration{
+		{format: "json", renderer: JSONRenderer{}},
+		{format: "xml", renderer: XMLRenderer{}},
+		{format: "msgpack", renderer: MessagePackRenderer{}},
+		{format: "csv", renderer: CSVRenderer{}},
+	}
+)
+
+// Register adds a renderer selectable with ?format=<format> or through its content type
+// in the Accept header. Registering an existing format replaces it.
+func Register(format string, renderer Renderer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for i, reg := range registry {
+		if reg.format == format {
+			registry[i].renderer = renderer
+			return
+		}
+	}
+
+	registry = append(registry, registration{format: format, renderer: renderer})
+}
+
+// Render writes data with the renderer negotiated from the ?format= query parameter
+// or the Accept header, and answers 406 when no registered renderer is acceptable.
+// Error payloads keep going through Error so clients always get a readable JSON body.
+func Render(w http.ResponseWriter, r *http.Request, statusCode int, data any) {
+	w.Header().Add("Vary", "Accept")
+
+	renderer, ok := Negotiate(r)
+	if !ok {
+		Error(w, http.StatusNotAcceptable, "Not acceptable")
+		return
+	}
+
+	var buffer bytes.Buffer
+	if err := renderer.Render(&buffer, data); err != nil {
+		if errors.Is(err, ErrNotRepresentable) {
+			Error(w, http.StatusNotAcceptable, "Not acceptable")
+			return
+		}
+
+		Error(w, http.StatusInternalServerError, "Failed to encode response")
+		return
+	}
+
+	w.Header().Set("Content-Type", renderer.ContentType())
+	w.WriteHeader(statusCode)
+	_, _ = buffer.WriteTo(w)
+}
+
+// Acceptable answers 406 before running the handler when no renderer acceptable to the request
+// can represent payloads like sample, so that mutating handlers do not run their side effects
+// for a response the client would refuse.
+func Acceptable(sample any) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			renderer, ok := Negotiate(r)
+			if ok && errors.Is(renderer.Render(io.Discard, sample), ErrNotRepresentable) {
+				ok = false
+			}
+			if !ok {
+				w.Header().Add("Vary", "Accept")
+				Error(w, http.StatusNotAcceptable, "Not acceptable")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Negotiate picks the renderer for r. The ?format= override wins over the Accept header:
+// each renderer is weighted by the most specific range matching it, and the highest weight
+// wins, then the most specific range, then the registration order. A missing Accept header
+// selects JSON.
+func Negotiate(r *http.Request) (Renderer, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if format := r.URL.Query().Get("format"); format != "" {
+		for _, reg := range registry {
+			if strings.EqualFold(reg.format, format) {
+				return reg.renderer, true
+			}
+		}
+		return nil, false
+	}
+
+	accept := r.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return registry[0].renderer, true
+	}
+
+	ranges := parseAccept(accept)
+
+	var best Renderer
+	var bestRange acceptRange
+	for _, reg := range registry {
+		mediaRange, ok := mostSpecific(ranges, mediaType(reg.renderer.ContentType()))
+		if !ok || mediaRange.quality <= 0 {
+			continue
+		}
+		if best == nil || mediaRange.quality > bestRange.quality ||
+			(mediaRange.quality == bestRange.quality && mediaRange.specificity() > bestRange.specificity()) {
+			best, bestRange = reg.renderer, mediaRange
+		}
+	}
+
+	return best, best != nil
+}
+
+// mostSpecific returns the range of ranges that gives its weight to contentType: the most
+// specific one matching it, so application/json;q=0 refuses JSON even along with */*.
+func mostSpecific(ranges []acceptRange, contentType string) (acceptRange, bool) {
+	var found acceptRange
+	ok := false
+	for _, mediaRange := range ranges {
+		if mediaRange.matches(contentType) && (!ok || mediaRange.specificity() > found.specificity()) {
+			found, ok = mediaRange, true
+		}
+	}
+	return found, ok
+}
+
+type acceptRange struct {
+	value   string
+	quality float64
+}
+
+func (a acceptRange) matches(contentType string) bool {
+	if a.value == "*/*" || a.value == contentType {
+		return true
+	}
+
+	if prefix, ok := strings.CutSuffix(a.value, "/*"); ok {
+		return strings.HasPrefix(contentType, prefix+"/")
+	}
+
+	return false
+}
+
+func (a acceptRange) specificity() int {
+	switch {
+	case a.value == "*/*":
+		return 0
+	case strings.HasSuffix(a.value, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// parseAccept returns the media ranges of an Accept header with their q weight, keeping the
+// ones refused with q=0 to exclude their types from the wildcards.
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		value := mediaType(params[0])
+		if value == "" {
+			continue
+		}
+		if alias, ok := mediaTypeAliases[value]; ok {
+			value = alias
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			key, raw, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || strings.TrimSpace(key) != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimSpace(raw), 64); err == nil {
+				quality = q
+			}
+		}
+
+		ranges = append(ranges, acceptRange{value: value, quality: quality})
+	}
+
+	return ranges
+}
+
+func mediaType(contentType string) string {
+	value, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+type JSONRenderer struct{}
+
+func (JSONRenderer) ContentType() string {
+	return "application/json"
+}
+
+func (JSONRenderer) Render(w io.Writer, data any) error {
+	return json.NewEncoder(w).Encode(data)
+}
+
+type XMLRenderer struct{}
+
+func (XMLRenderer) ContentType() string {
+	return "application/xml"
+}
+
+func (XMLRenderer) Render(w io.Writer, data any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	if err := encoder.EncodeElement(data, xml.StartElement{Name: xml.Name{Local: "response"}}); err != nil {
+		var unsupported *xml.UnsupportedTypeError
+		if errors.As(err, &unsupported) {
+			return ErrNotRepresentable
+		}
+		return err
+	}
+
+	return encoder.Close()
+}
+
+type MessagePackRenderer struct{}
+
+func (MessagePackRenderer) ContentType() string {
+	return "application/msgpack"
+}
+
+// Render reuses the json struct tags so field names and omitempty rules match the JSON output.
+func (MessagePackRenderer) Render(w io.Writer, data any) error {
+	encoder := msgpack.NewEncoder(w)
+	encoder.SetCustomStructTag("json")
+
+	return encoder.Encode(data)
+}
+
+type CSVRenderer struct{}
+
+func (CSVRenderer) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (CSVRenderer) Render(w io.Writer, data any) error {
+	tabular, ok := data.(Tabular)
+	if !ok {
+		return ErrNotRepresentable
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(tabular.CSVHeader()); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(tabular.CSVRecords()); err != nil {
+		return err
+	}
+
+	return writer.Error()
+}
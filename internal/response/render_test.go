@@ -0,0 +1,260 @@
+package response_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+type item struct {
+	ID    string `json:"id" xml:"id"`
+	Title string `json:"title,omitempty" xml:"title,omitempty"`
+}
+
+type itemsPayload struct {
+	Status string `json:"status" xml:"status"`
+	Items  []item `json:"items" xml:"items>item"`
+}
+
+func (p itemsPayload) CSVHeader() []string {
+	return []string{"id", "title"}
+}
+
+func (p itemsPayload) CSVRecords() [][]string {
+	records := make([][]string, len(p.Items))
+	for i, it := range p.Items {
+		records[i] = []string{it.ID, it.Title}
+	}
+	return records
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name                string
+		url                 string
+		accept              string
+		expectedOK          bool
+		expectedContentType string
+	}{
+		{name: "default to json", url: "/", expectedOK: true, expectedContentType: "application/json"},
+		{name: "wildcard", url: "/", accept: "*/*", expectedOK: true, expectedContentType: "application/json"},
+		{name: "exact xml", url: "/", accept: "application/xml", expectedOK: true, expectedContentType: "application/xml"},
+		{name: "text xml alias", url: "/", accept: "text/xml", expectedOK: true, expectedContentType: "application/xml"},
+		{name: "msgpack alias", url: "/", accept: "application/x-msgpack", expectedOK: true, expectedContentType: "application/msgpack"},
+		{
+			name:                "highest q weight wins",
+			url:                 "/",
+			accept:              "application/json;q=0.5, application/msgpack;q=0.9, */*;q=0.1",
+			expectedOK:          true,
+			expectedContentType: "application/msgpack",
+		},
+		{
+			name:                "specific range wins over wildcard at same weight",
+			url:                 "/",
+			accept:              "*/*, text/csv",
+			expectedOK:          true,
+			expectedContentType: "text/csv; charset=utf-8",
+		},
+		{name: "subtype wildcard", url: "/", accept: "text/*", expectedOK: true, expectedContentType: "text/csv; charset=utf-8"},
+		{name: "q zero refuses the type", url: "/", accept: "application/json;q=0", expectedOK: false},
+		{
+			name:                "q zero refuses the type matched by a wildcard",
+			url:                 "/",
+			accept:              "*/*, application/json;q=0",
+			expectedOK:          true,
+			expectedContentType: "application/xml",
+		},
+		{name: "q zero refuses the types of a subtype wildcard", url: "/", accept: "text/*;q=0, text/csv;q=0", expectedOK: false},
+		{
+			name:                "most specific range gives the weight",
+			url:                 "/",
+			accept:              "application/*;q=0.2, application/xml;q=0.8, */*;q=0.5",
+			expectedOK:          true,
+			expectedContentType: "application/xml",
+		},
+		{name: "unsupported type", url: "/", accept: "application/pdf", expectedOK: false},
+		{name: "format override", url: "/?format=xml", accept: "application/json", expectedOK: true, expectedContentType: "application/xml"},
+		{name: "unsupported format override", url: "/?format=pdf", expectedOK: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, test.url, nil)
+			if test.accept != "" {
+				req.Header.Set("Accept", test.accept)
+			}
+
+			renderer, ok := response.Negotiate(req)
+
+			assert.Equal(t, test.expectedOK, ok)
+			if test.expectedOK {
+				assert.Equal(t, test.expectedContentType, renderer.ContentType())
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	payload := itemsPayload{
+		Status: "success",
+		Items:  []item{{ID: "1", Title: "Book1"}, {ID: "2"}},
+	}
+
+	tests := []struct {
+		name                string
+		accept              string
+		data                any
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "json",
+			accept:              "application/json",
+			data:                payload,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody:        `{"status":"success","items":[{"id":"1","title":"Book1"},{"id":"2"}]}` + "\n",
+		},
+		{
+			name:                "xml",
+			accept:              "application/xml",
+			data:                payload,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/xml",
+			expectedBody: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<response><status>success</status><items><item><id>1</id><title>Book1</title></item><item><id>2</id></item></items></response>`,
+		},
+		{
+			name:                "csv",
+			accept:              "text/csv",
+			data:                payload,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "id,title\n1,Book1\n2,\n",
+		},
+		{
+			name:                "error csv not representable",
+			accept:              "text/csv",
+			data:                map[string]string{"status": "success"},
+			expectedStatus:      http.StatusNotAcceptable,
+			expectedContentType: "application/json",
+			expectedBody:        `{"status":"error","message":"Not acceptable"}` + "\n",
+		},
+		{
+			name:                "error xml not representable",
+			accept:              "application/xml",
+			data:                map[string]string{"status": "success"},
+			expectedStatus:      http.StatusNotAcceptable,
+			expectedContentType: "application/json",
+			expectedBody:        `{"status":"error","message":"Not acceptable"}` + "\n",
+		},
+		{
+			name:                "error not acceptable",
+			accept:              "application/pdf",
+			data:                payload,
+			expectedStatus:      http.StatusNotAcceptable,
+			expectedContentType: "application/json",
+			expectedBody:        `{"status":"error","message":"Not acceptable"}` + "\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept", test.accept)
+			w := httptest.NewRecorder()
+
+			response.Render(w, req, http.StatusOK, test.data)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			assert.Equal(t, test.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, "Accept", w.Header().Get("Vary"))
+			assert.Equal(t, test.expectedBody, w.Body.String())
+		})
+	}
+
+	t.Run("msgpack", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/?format=msgpack", nil)
+		w := httptest.NewRecorder()
+
+		response.Render(w, req, http.StatusCreated, payload)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "application/msgpack", w.Header().Get("Content-Type"))
+
+		var decoded map[string]any
+		require.NoError(t, msgpack.Unmarshal(w.Body.Bytes(), &decoded))
+		assert.Equal(t, "success", decoded["status"])
+		assert.Len(t, decoded["items"], 2)
+		assert.NotContains(t, decoded["items"].([]any)[1], "title")
+	})
+}
+
+func TestAcceptable(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		accept         string
+		sample         any
+		expectedStatus int
+	}{
+		{name: "acceptable", url: "/", accept: "application/xml", sample: itemsPayload{}, expectedStatus: http.StatusCreated},
+		{name: "no acceptable renderer", url: "/", accept: "image/png", sample: itemsPayload{}, expectedStatus: http.StatusNotAcceptable},
+		{name: "unknown format", url: "/?format=pdf", sample: itemsPayload{}, expectedStatus: http.StatusNotAcceptable},
+		{name: "format not representing the payload", url: "/?format=csv", sample: item{}, expectedStatus: http.StatusNotAcceptable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			called := false
+			handler := response.Acceptable(test.sample)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusCreated)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, test.url, nil)
+			if test.accept != "" {
+				req.Header.Set("Accept", test.accept)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			assert.Equal(t, test.expectedStatus != http.StatusNotAcceptable, called)
+		})
+	}
+}
+
+type yamlRenderer struct{}
+
+func (yamlRenderer) ContentType() string {
+	return "application/yaml"
+}
+
+func (yamlRenderer) Render(w io.Writer, _ any) error {
+	_, err := io.WriteString(w, "status: success\n")
+	return err
+}
+
+func TestRegister(t *testing.T) {
+	response.Register("yaml", yamlRenderer{})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "application/yaml")
+	w := httptest.NewRecorder()
+
+	response.Render(w, req, http.StatusOK, map[string]string{"status": "success"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+	assert.Equal(t, "status: success\n", w.Body.String())
+}
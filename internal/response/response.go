@@ -6,24 +6,26 @@ import (
 )
 
 type SuccessResponse struct {
-	Status  string `json:"status" example:"success"`
-	Message string `json:"message" example:"Operation completed successfully"`
+	Status  string `json:"status" xml:"status" example:"success"`
+	Message string `json:"message" xml:"message" example:"Operation completed successfully"`
 }
 
 type ErrorResponse struct {
-	Status  string `json:"status" example:"error"`
-	Message string `json:"message" example:"An error occurred"`
+	Status  string `json:"status" xml:"status" example:"error"`
+	Message string `json:"message" xml:"message" example:"An error occurred"`
 }
 
 type ValidationErrorDetail struct {
-	Field   string `json:"field" example:"email"`
-	Message string `json:"message" example:"Email is required"`
+	Field   string `json:"field" xml:"field" example:"email"`
+	Message string `json:"message" xml:"message" example:"email is required"`
+	Tag     string `json:"tag,omitempty" xml:"tag,omitempty" example:"required"`
+	Param   string `json:"param,omitempty" xml:"param,omitempty"`
 }
 
 type ValidationErrorResponse struct {
-	Status  string                  `json:"status" example:"error"`
-	Message string                  `json:"message" example:"Validation failed"`
-	Errors  []ValidationErrorDetail `json:"errors"`
+	Status  string                  `json:"status" xml:"status" example:"error"`
+	Message string                  `json:"message" xml:"message" example:"Validation failed"`
+	Errors  []ValidationErrorDetail `json:"errors" xml:"errors>error"`
 }
 
 func JSON(w http.ResponseWriter, statusCode int, data any) {
@@ -0,0 +1,26 @@
+package retry
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff returns the delay before the attempt following the given one: base doubled at
+// every attempt, capped at max, with the upper half randomized so receivers recovering
+// from an outage are not hit by every retry at once.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+
+	return half + rand.N(half+1)
+}
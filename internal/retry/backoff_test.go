@@ -0,0 +1,31 @@
+package retry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go-boilerplate-rest-api-chi/internal/retry"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt     int
+		expectedMin time.Duration
+		expectedMax time.Duration
+	}{
+		{attempt: 1, expectedMin: 5 * time.Second, expectedMax: 10 * time.Second},
+		{attempt: 3, expectedMin: 20 * time.Second, expectedMax: 40 * time.Second},
+		{attempt: 20, expectedMin: 30 * time.Minute, expectedMax: time.Hour},
+	}
+
+	for _, test := range tests {
+		for range 20 {
+			delay := retry.Backoff(test.attempt, 10*time.Second, time.Hour)
+
+			assert.GreaterOrEqual(t, delay, test.expectedMin)
+			assert.LessOrEqual(t, delay, test.expectedMax)
+		}
+	}
+}
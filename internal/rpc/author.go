@@ -0,0 +1,116 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/author"
+	"go-boilerplate-rest-api-chi/internal/author/dto"
+	internalValidator "go-boilerplate-rest-api-chi/internal/validator"
+	libraryv1 "go-boilerplate-rest-api-chi/pkg/pb/library/v1"
+)
+
+type AuthorServer struct {
+	libraryv1.UnimplementedAuthorServiceServer
+
+	service   author.AuthorService
+	validator *internalValidator.Validator
+	logger    zerolog.Logger
+}
+
+func NewAuthorServer(service author.AuthorService, validator *internalValidator.Validator, logger zerolog.Logger) *AuthorServer {
+	return &AuthorServer{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (s *AuthorServer) CreateAuthor(ctx context.Context, req *libraryv1.CreateAuthorRequest) (*libraryv1.CreateAuthorResponse, error) {
+	input := &dto.CreateAuthorRequest{Name: req.GetName()}
+	if err := s.validator.Struct(input); err != nil {
+		return nil, invalidArgument(s.validator.FormatErrors(ctx, err))
+	}
+
+	created, err := s.service.CreateAuthor(ctx, input)
+	if err != nil {
+		return nil, toStatus(s.logger, err)
+	}
+
+	return &libraryv1.CreateAuthorResponse{Author: toAuthor(created)}, nil
+}
+
+func (s *AuthorServer) GetAuthor(ctx context.Context, req *libraryv1.GetAuthorRequest) (*libraryv1.GetAuthorResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := s.service.GetAuthorByID(ctx, id)
+	if err != nil {
+		return nil, toStatus(s.logger, err)
+	}
+
+	return &libraryv1.GetAuthorResponse{Author: toAuthor(found)}, nil
+}
+
+func (s *AuthorServer) RenameAuthor(ctx context.Context, req *libraryv1.RenameAuthorRequest) (*libraryv1.RenameAuthorResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dto.RenameAuthorRequest{Name: req.GetName()}
+	if err := s.validator.Struct(input); err != nil {
+		return nil, invalidArgument(s.validator.FormatErrors(ctx, err))
+	}
+
+	renamed, err := s.service.RenameAuthor(ctx, id, input)
+	if err != nil {
+		return nil, toStatus(s.logger, err)
+	}
+
+	return &libraryv1.RenameAuthorResponse{Author: toAuthor(renamed)}, nil
+}
+
+func (s *AuthorServer) GetAuthorStats(ctx context.Context, req *libraryv1.GetAuthorStatsRequest) (*libraryv1.GetAuthorStatsResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.service.GetAuthorStats(ctx, id)
+	if err != nil {
+		return nil, toStatus(s.logger, err)
+	}
+
+	return &libraryv1.GetAuthorStatsResponse{Stats: toAuthorStats(stats)}, nil
+}
+
+func (s *AuthorServer) ListAuthorBooks(ctx context.Context, req *libraryv1.ListAuthorBooksRequest) (*libraryv1.ListAuthorBooksResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := parsePage(req.GetPage(), req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	books, total, err := s.service.GetAuthorBooks(ctx, id, params)
+	if err != nil {
+		return nil, toStatus(s.logger, err)
+	}
+
+	resp := &libraryv1.ListAuthorBooksResponse{
+		Books:     make([]*libraryv1.Book, len(books)),
+		TotalSize: total,
+	}
+	for i := range books {
+		resp.Books[i] = toBook(&books[i])
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,131 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/book"
+	"go-boilerplate-rest-api-chi/internal/book/dto"
+	internalValidator "go-boilerplate-rest-api-chi/internal/validator"
+	libraryv1 "go-boilerplate-rest-api-chi/pkg/pb/library/v1"
+)
+
+type BookServer struct {
+	libraryv1.UnimplementedBookServiceServer
+
+	service   book.BookService
+	validator *internalValidator.Validator
+	logger    zerolog.Logger
+}
+
+func NewBookServer(service book.BookService, validator *internalValidator.Validator, logger zerolog.Logger) *BookServer {
+	return &BookServer{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (s *BookServer) CreateBook(ctx context.Context, req *libraryv1.CreateBookRequest) (*libraryv1.CreateBookResponse, error) {
+	input := &dto.CreateBookRequest{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		AuthorID:    req.GetAuthorId(),
+	}
+	if err := s.validator.Struct(input); err != nil {
+		return nil, invalidArgument(s.validator.FormatErrors(ctx, err))
+	}
+
+	created, err := s.service.CreateBook(ctx, input)
+	if err != nil {
+		return nil, toStatus(s.logger, err)
+	}
+
+	return &libraryv1.CreateBookResponse{Book: toBook(created)}, nil
+}
+
+func (s *BookServer) GetBook(ctx context.Context, req *libraryv1.GetBookRequest) (*libraryv1.GetBookResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	var expand []string
+	if req.GetIncludeAuthor() {
+		expand = append(expand, "author")
+	}
+
+	found, err := s.service.GetBookByID(ctx, id, expand...)
+	if err != nil {
+		return nil, toStatus(s.logger, err)
+	}
+
+	return &libraryv1.GetBookResponse{Book: toBook(found)}, nil
+}
+
+func (s *BookServer) ListBooks(ctx context.Context, req *libraryv1.ListBooksRequest) (*libraryv1.ListBooksResponse, error) {
+	params, err := parsePage(req.GetPage(), req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &dto.BookFilter{
+		Title:    req.GetTitle(),
+		AuthorID: req.GetAuthorId(),
+	}
+	if err := s.validator.Struct(filter); err != nil {
+		return nil, invalidArgument(s.validator.FormatErrors(ctx, err))
+	}
+
+	books, total, err := s.service.ListBooks(ctx, filter, params)
+	if err != nil {
+		return nil, toStatus(s.logger, err)
+	}
+
+	resp := &libraryv1.ListBooksResponse{
+		Books:     make([]*libraryv1.Book, len(books)),
+		TotalSize: total,
+	}
+	for i, b := range books {
+		resp.Books[i] = toBook(b)
+	}
+
+	return resp, nil
+}
+
+func (s *BookServer) UpdateBook(ctx context.Context, req *libraryv1.UpdateBookRequest) (*libraryv1.UpdateBookResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dto.UpdateBookRequest{Description: req.GetDescription()}
+	if err := s.validator.Struct(input); err != nil {
+		return nil, invalidArgument(s.validator.FormatErrors(ctx, err))
+	}
+
+	if err := s.service.UpdateBook(ctx, input, id); err != nil {
+		return nil, toStatus(s.logger, err)
+	}
+
+	updated, err := s.service.GetBookByID(ctx, id)
+	if err != nil {
+		return nil, toStatus(s.logger, err)
+	}
+
+	return &libraryv1.UpdateBookResponse{Book: toBook(updated)}, nil
+}
+
+func (s *BookServer) DeleteBook(ctx context.Context, req *libraryv1.DeleteBookRequest) (*libraryv1.DeleteBookResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteBook(ctx, id); err != nil {
+		return nil, toStatus(s.logger, err)
+	}
+
+	return &libraryv1.DeleteBookResponse{}, nil
+}
@@ -0,0 +1,80 @@
+package rpc
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/pagination"
+	libraryv1 "go-boilerplate-rest-api-chi/pkg/pb/library/v1"
+)
+
+func toBook(b *entity.Book) *libraryv1.Book {
+	book := &libraryv1.Book{
+		Id:          b.ID.String(),
+		Title:       b.Title,
+		Description: b.Description,
+		AuthorId:    b.AuthorID.String(),
+		CreateTime:  timestamppb.New(b.CreatedAt),
+		UpdateTime:  timestamppb.New(b.UpdatedAt),
+	}
+	if b.Author != nil {
+		book.Author = toAuthor(b.Author)
+	}
+
+	return book
+}
+
+func toAuthor(a *entity.Author) *libraryv1.Author {
+	return &libraryv1.Author{
+		Id:         a.ID.String(),
+		Name:       a.Name,
+		CreateTime: timestamppb.New(a.CreatedAt),
+		UpdateTime: timestamppb.New(a.UpdatedAt),
+	}
+}
+
+func toAuthorStats(s *entity.AuthorStats) *libraryv1.AuthorStats {
+	stats := &libraryv1.AuthorStats{BookCount: s.BookCount}
+	if s.FirstPublishedAt != nil {
+		stats.FirstPublishedTime = timestamppb.New(*s.FirstPublishedAt)
+	}
+	if s.LatestPublishedAt != nil {
+		stats.LatestPublishedTime = timestamppb.New(*s.LatestPublishedAt)
+	}
+
+	return stats
+}
+
+func parseID(raw string) (uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid id")
+	}
+	return id, nil
+}
+
+// parsePage applies the defaults and bounds of pagination.Parse, zero values standing for
+// unset fields.
+func parsePage(page, pageSize int32) (pagination.Params, error) {
+	params := pagination.Params{Page: int(page), PageSize: int(pageSize)}
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.PageSize == 0 {
+		params.PageSize = pagination.DefaultPageSize
+	}
+
+	if params.Page < 1 {
+		return pagination.Params{}, status.Error(codes.InvalidArgument, "page must be a positive integer")
+	}
+	if params.PageSize < 1 || params.PageSize > pagination.MaxPageSize {
+		return pagination.Params{}, status.Error(codes.InvalidArgument, fmt.Sprintf("page_size must be between 1 and %d", pagination.MaxPageSize))
+	}
+
+	return params, nil
+}
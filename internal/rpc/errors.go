@@ -0,0 +1,57 @@
+package rpc
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-boilerplate-rest-api-chi/internal/author"
+	"go-boilerplate-rest-api-chi/internal/book"
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+// toStatus maps the domain errors like the handleError of the REST handlers. Unexpected
+// errors are logged and hidden from the client.
+func toStatus(logger zerolog.Logger, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, book.ErrNotFound):
+		return status.Error(codes.NotFound, "Book not found")
+	case errors.Is(err, book.ErrDuplicate):
+		return status.Error(codes.AlreadyExists, "Book with this name already exists")
+	case errors.Is(err, book.ErrInvalidAuthorId):
+		return status.Error(codes.InvalidArgument, "invalid author ID")
+	case errors.Is(err, author.ErrNotFound):
+		return status.Error(codes.NotFound, "Author not found")
+	case errors.Is(err, author.ErrDuplicate):
+		return status.Error(codes.AlreadyExists, "Author with this name already exists")
+	default:
+		logger.Error().Err(err).Msg("unexpected error")
+		return status.Error(codes.Internal, "Internal server error")
+	}
+}
+
+// invalidArgument reports the field errors in a BadRequest detail.
+func invalidArgument(fields []response.ValidationErrorDetail) error {
+	violations := make([]*errdetails.BadRequest_FieldViolation, len(fields))
+	for i, field := range fields {
+		violations[i] = &errdetails.BadRequest_FieldViolation{
+			Field:       field.Field,
+			Description: field.Message,
+		}
+	}
+
+	st, err := status.New(codes.InvalidArgument, "Validation failed").
+		WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "Validation failed")
+	}
+
+	return st.Err()
+}
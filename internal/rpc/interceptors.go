@@ -0,0 +1,203 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	internalValidator "go-boilerplate-rest-api-chi/internal/validator"
+)
+
+const requestIDHeader = "x-request-id"
+
+// interceptor is the logic shared by the unary and the stream interceptors: it returns the
+// context to handle the call with, or an error to reject it.
+type interceptor func(ctx context.Context, method string, handle func(ctx context.Context) error) error
+
+func unary(interceptors ...interceptor) grpc.ServerOption {
+	chain := make([]grpc.UnaryServerInterceptor, len(interceptors))
+	for i, intercept := range interceptors {
+		chain[i] = func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			var resp any
+			err := intercept(ctx, info.FullMethod, func(ctx context.Context) error {
+				var err error
+				resp, err = handler(ctx, req)
+				return err
+			})
+			return resp, err
+		}
+	}
+
+	return grpc.ChainUnaryInterceptor(chain...)
+}
+
+func stream(interceptors ...interceptor) grpc.ServerOption {
+	chain := make([]grpc.StreamServerInterceptor, len(interceptors))
+	for i, intercept := range interceptors {
+		chain[i] = func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return intercept(ss.Context(), info.FullMethod, func(ctx context.Context) error {
+				return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+			})
+		}
+	}
+
+	return grpc.ChainStreamInterceptor(chain...)
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// requestID reuses the x-request-id of the caller, or generates one, and sends it back. It
+// is stored like the chi middleware does, so middleware.GetReqID works for both servers.
+func requestID(ctx context.Context, _ string, handle func(ctx context.Context) error) error {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDHeader); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, id))
+
+	return handle(context.WithValue(ctx, middleware.RequestIDKey, id))
+}
+
+// language stores the languages of the accept-language metadata, for the validation errors
+// to be written in the language of the caller.
+func language(ctx context.Context, _ string, handle func(ctx context.Context) error) error {
+	var acceptLanguage string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		acceptLanguage = strings.Join(md.Get("accept-language"), ",")
+	}
+
+	return handle(internalValidator.WithLanguages(ctx, acceptLanguage))
+}
+
+func logging(logger zerolog.Logger) interceptor {
+	return func(ctx context.Context, method string, handle func(ctx context.Context) error) error {
+		start := time.Now()
+		err := handle(ctx)
+
+		logger.Info().
+			Str("request_id", middleware.GetReqID(ctx)).
+			Str("method", method).
+			Str("code", status.Code(err).String()).
+			Dur("duration", time.Since(start)).
+			Msg("grpc call")
+
+		return err
+	}
+}
+
+func recovery(logger zerolog.Logger) interceptor {
+	return func(ctx context.Context, method string, handle func(ctx context.Context) error) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error().
+					Str("request_id", middleware.GetReqID(ctx)).
+					Str("method", method).
+					Interface("panic", r).
+					Bytes("stack", debug.Stack()).
+					Msg("grpc call panicked")
+				err = status.Error(codes.Internal, "Internal server error")
+			}
+		}()
+
+		return handle(ctx)
+	}
+}
+
+// authentication applies the rules of auth.Middleware to the authorization metadata: calls
+// without credentials go on anonymously, invalid credentials are rejected.
+func authentication(authenticator auth.Authenticator) interceptor {
+	return func(ctx context.Context, _ string, handle func(ctx context.Context) error) error {
+		r, err := http.NewRequestWithContext(ctx, http.MethodPost, "/", http.NoBody)
+		if err != nil {
+			return status.Error(codes.Internal, "Internal server error")
+		}
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			for _, value := range md.Get("authorization") {
+				r.Header.Add("Authorization", value)
+			}
+			for _, value := range md.Get("x-api-key") {
+				r.Header.Add("X-API-Key", value)
+			}
+		}
+
+		principal, err := authenticator.Authenticate(r)
+		switch {
+		case errors.Is(err, auth.ErrNoCredentials):
+			return handle(ctx)
+		case err != nil:
+			return status.Error(codes.Unauthenticated, "Unauthorized")
+		default:
+			return handle(auth.WithPrincipal(ctx, principal))
+		}
+	}
+}
+
+// tenancy applies the rules of tenant.Resolver to the metadata and the authority of the
+// calls to the library services. The health and reflection services serve every tenant.
+func tenancy(resolver *tenant.Resolver, logger zerolog.Logger) interceptor {
+	return func(ctx context.Context, method string, handle func(ctx context.Context) error) error {
+		if strings.HasPrefix(method, "/grpc.") {
+			return handle(ctx)
+		}
+
+		r, err := http.NewRequestWithContext(ctx, http.MethodPost, "/", http.NoBody)
+		if err != nil {
+			return status.Error(codes.Internal, "Internal server error")
+		}
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			for key, values := range md {
+				for _, value := range values {
+					r.Header.Add(key, value)
+				}
+			}
+			if authority := md.Get(":authority"); len(authority) > 0 {
+				r.Host = authority[0]
+			}
+		}
+
+		id, err := resolver.Resolve(r)
+		switch {
+		case errors.Is(err, tenant.ErrRequired):
+			return status.Error(codes.InvalidArgument, "Tenant is required")
+		case errors.Is(err, tenant.ErrAnonymous):
+			return status.Error(codes.Unauthenticated, "Unauthorized")
+		case errors.Is(err, tenant.ErrMismatch):
+			return status.Error(codes.PermissionDenied, "Forbidden")
+		case errors.Is(err, tenant.ErrNotFound):
+			return status.Error(codes.NotFound, "Tenant not found")
+		case errors.Is(err, tenant.ErrDisabled):
+			return status.Error(codes.PermissionDenied, "Tenant is disabled")
+		case err != nil:
+			logger.Error().Err(err).Str("method", method).Msg("unexpected error")
+			return status.Error(codes.Internal, "Internal server error")
+		default:
+			return handle(tenant.WithID(ctx, id))
+		}
+	}
+}
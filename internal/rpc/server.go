@@ -0,0 +1,84 @@
+package rpc
+
+import (
+	"context"
+	"net"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	libraryv1 "go-boilerplate-rest-api-chi/pkg/pb/library/v1"
+)
+
+// Server is the gRPC server of the books and authors, with the standard health service and,
+// when enabled, the reflection service used by grpcurl and the like.
+type Server struct {
+	server *grpc.Server
+	health *health.Server
+	logger zerolog.Logger
+}
+
+func NewServer(books *BookServer, authors *AuthorServer, authenticator auth.Authenticator, tenants *tenant.Resolver, cfg config.GRPCConfig, logger zerolog.Logger) *Server {
+	interceptors := []interceptor{
+		requestID,
+		language,
+		logging(logger),
+		recovery(logger),
+		authentication(authenticator),
+		tenancy(tenants, logger),
+	}
+
+	server := grpc.NewServer(
+		unary(interceptors...),
+		stream(interceptors...),
+	)
+
+	libraryv1.RegisterBookServiceServer(server, books)
+	libraryv1.RegisterAuthorServiceServer(server, authors)
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	for name := range server.GetServiceInfo() {
+		healthServer.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+
+	if cfg.Reflection {
+		reflection.Register(server)
+	}
+
+	return &Server{
+		server: server,
+		health: healthServer,
+		logger: logger,
+	}
+}
+
+func (s *Server) Serve(listener net.Listener) error {
+	return s.server.Serve(listener)
+}
+
+// Shutdown reports the services as not serving, then waits for the calls in flight. The
+// calls still running when ctx is done are cancelled.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
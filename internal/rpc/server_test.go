@@ -0,0 +1,387 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/author"
+	authorDto "go-boilerplate-rest-api-chi/internal/author/dto"
+	"go-boilerplate-rest-api-chi/internal/book"
+	bookDto "go-boilerplate-rest-api-chi/internal/book/dto"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/pagination"
+	"go-boilerplate-rest-api-chi/internal/rpc"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	"go-boilerplate-rest-api-chi/internal/validator"
+	libraryv1 "go-boilerplate-rest-api-chi/pkg/pb/library/v1"
+)
+
+var (
+	bookID   = uuid.MustParse("f3c1b7a2-6d0e-4a8b-9c5d-2e7f1a3b4c5d")
+	authorID = uuid.MustParse("aeca0955-bae4-47e9-9f85-6818dc68ca51")
+)
+
+type fixture struct {
+	server  *rpc.Server
+	conn    *grpc.ClientConn
+	books   *mocks.MockBookService
+	authors *mocks.MockAuthorService
+	tenants *mocks.MockTenantService
+}
+
+func newFixture(t *testing.T) *fixture {
+	return newTenancyFixture(t, config.TenancyConfig{})
+}
+
+func newTenancyFixture(t *testing.T, tenancy config.TenancyConfig) *fixture {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	f := &fixture{
+		books:   mocks.NewMockBookService(ctrl),
+		authors: mocks.NewMockAuthorService(ctrl),
+		tenants: mocks.NewMockTenantService(ctrl),
+	}
+
+	authenticator := auth.AuthenticatorFunc(func(r *http.Request) (*auth.Principal, error) {
+		switch auth.BearerToken(r) {
+		case "":
+			return nil, auth.ErrNoCredentials
+		case "token-victor":
+			return &auth.Principal{Subject: "user-1"}, nil
+		case "token-acme":
+			return &auth.Principal{Subject: "user-2", Tenant: "acme"}, nil
+		default:
+			return nil, auth.ErrInvalidCredentials
+		}
+	})
+
+	v := validator.New()
+	f.server = rpc.NewServer(
+		rpc.NewBookServer(f.books, v, zerolog.Nop()),
+		rpc.NewAuthorServer(f.authors, v, zerolog.Nop()),
+		authenticator,
+		tenant.NewResolver(f.tenants, tenancy, zerolog.Nop()),
+		config.GRPCConfig{Reflection: true},
+		zerolog.Nop(),
+	)
+
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = f.server.Serve(listener) }()
+	t.Cleanup(func() { _ = f.server.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	f.conn = conn
+
+	return f
+}
+
+func TestBookServer_CreateBook(t *testing.T) {
+	f := newFixture(t)
+	client := libraryv1.NewBookServiceClient(f.conn)
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	f.books.EXPECT().
+		CreateBook(gomock.Any(), &bookDto.CreateBookRequest{Title: "Les Misérables", Description: "Jean Valjean", AuthorID: authorID.String()}).
+		DoAndReturn(func(ctx context.Context, _ *bookDto.CreateBookRequest) (*entity.Book, error) {
+			// The caller is known to the service, to stamp the events with it.
+			principal, ok := auth.FromContext(ctx)
+			require.True(t, ok)
+			assert.Equal(t, "user-1", principal.Subject)
+			tenantID, _ := tenant.FromContext(ctx)
+			assert.Equal(t, entity.DefaultTenantID, tenantID)
+
+			return &entity.Book{ID: bookID, Title: "Les Misérables", Description: "Jean Valjean", AuthorID: authorID, CreatedAt: created, UpdatedAt: created}, nil
+		})
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token-victor", "x-request-id", "request-1")
+	resp, err := client.CreateBook(ctx, &libraryv1.CreateBookRequest{
+		Title:       "Les Misérables",
+		Description: "Jean Valjean",
+		AuthorId:    authorID.String(),
+	}, grpc.Header(&header))
+
+	require.NoError(t, err)
+	assert.Equal(t, bookID.String(), resp.GetBook().GetId())
+	assert.Equal(t, authorID.String(), resp.GetBook().GetAuthorId())
+	assert.Equal(t, created, resp.GetBook().GetCreateTime().AsTime())
+	assert.Equal(t, []string{"request-1"}, header.Get("x-request-id"))
+}
+
+func TestBookServer_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		call          func(ctx context.Context, client libraryv1.BookServiceClient) error
+		token         string
+		configureMock func(*mocks.MockBookService)
+		expectedCode  codes.Code
+		expectedMsg   string
+	}{
+		{
+			name: "error book not found",
+			call: func(ctx context.Context, client libraryv1.BookServiceClient) error {
+				_, err := client.GetBook(ctx, &libraryv1.GetBookRequest{Id: bookID.String(), IncludeAuthor: true})
+				return err
+			},
+			configureMock: func(books *mocks.MockBookService) {
+				books.EXPECT().GetBookByID(gomock.Any(), bookID, "author").Return(nil, book.ErrNotFound)
+			},
+			expectedCode: codes.NotFound,
+			expectedMsg:  "Book not found",
+		},
+		{
+			name: "error author not found",
+			call: func(ctx context.Context, client libraryv1.BookServiceClient) error {
+				_, err := client.CreateBook(ctx, &libraryv1.CreateBookRequest{Title: "t", Description: "d", AuthorId: authorID.String()})
+				return err
+			},
+			configureMock: func(books *mocks.MockBookService) {
+				books.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(nil, author.ErrNotFound)
+			},
+			expectedCode: codes.NotFound,
+			expectedMsg:  "Author not found",
+		},
+		{
+			name: "error duplicate book",
+			call: func(ctx context.Context, client libraryv1.BookServiceClient) error {
+				_, err := client.CreateBook(ctx, &libraryv1.CreateBookRequest{Title: "t", Description: "d", AuthorId: authorID.String()})
+				return err
+			},
+			configureMock: func(books *mocks.MockBookService) {
+				books.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(nil, book.ErrDuplicate)
+			},
+			expectedCode: codes.AlreadyExists,
+			expectedMsg:  "Book with this name already exists",
+		},
+		{
+			name: "error invalid id",
+			call: func(ctx context.Context, client libraryv1.BookServiceClient) error {
+				_, err := client.DeleteBook(ctx, &libraryv1.DeleteBookRequest{Id: "42"})
+				return err
+			},
+			expectedCode: codes.InvalidArgument,
+			expectedMsg:  "invalid id",
+		},
+		{
+			name: "error invalid page size",
+			call: func(ctx context.Context, client libraryv1.BookServiceClient) error {
+				_, err := client.ListBooks(ctx, &libraryv1.ListBooksRequest{PageSize: 1000})
+				return err
+			},
+			expectedCode: codes.InvalidArgument,
+			expectedMsg:  "page_size must be between 1 and 100",
+		},
+		{
+			name: "error internal hidden",
+			call: func(ctx context.Context, client libraryv1.BookServiceClient) error {
+				_, err := client.ListBooks(ctx, &libraryv1.ListBooksRequest{})
+				return err
+			},
+			configureMock: func(books *mocks.MockBookService) {
+				books.EXPECT().
+					ListBooks(gomock.Any(), &bookDto.BookFilter{}, pagination.Params{Page: 1, PageSize: pagination.DefaultPageSize}).
+					Return(nil, int64(0), errors.New("connection refused"))
+			},
+			expectedCode: codes.Internal,
+			expectedMsg:  "Internal server error",
+		},
+		{
+			name: "error panic recovered",
+			call: func(ctx context.Context, client libraryv1.BookServiceClient) error {
+				_, err := client.DeleteBook(ctx, &libraryv1.DeleteBookRequest{Id: bookID.String()})
+				return err
+			},
+			configureMock: func(books *mocks.MockBookService) {
+				books.EXPECT().DeleteBook(gomock.Any(), bookID).DoAndReturn(func(context.Context, uuid.UUID) error {
+					panic("boom")
+				})
+			},
+			expectedCode: codes.Internal,
+			expectedMsg:  "Internal server error",
+		},
+		{
+			name: "error invalid token",
+			call: func(ctx context.Context, client libraryv1.BookServiceClient) error {
+				_, err := client.DeleteBook(ctx, &libraryv1.DeleteBookRequest{Id: bookID.String()})
+				return err
+			},
+			token:        "unknown",
+			expectedCode: codes.Unauthenticated,
+			expectedMsg:  "Unauthorized",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := newFixture(t)
+			if test.configureMock != nil {
+				test.configureMock(f.books)
+			}
+
+			ctx := context.Background()
+			if test.token != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+test.token)
+			}
+
+			err := test.call(ctx, libraryv1.NewBookServiceClient(f.conn))
+
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			assert.Equal(t, test.expectedCode, st.Code())
+			assert.Equal(t, test.expectedMsg, st.Message())
+		})
+	}
+}
+
+func TestAuthorServer_Validation(t *testing.T) {
+	f := newFixture(t)
+	client := libraryv1.NewAuthorServiceClient(f.conn)
+
+	_, err := client.CreateAuthor(context.Background(), &libraryv1.CreateAuthorRequest{})
+
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+	badRequest := st.Details()[0].(*errdetails.BadRequest)
+	assert.Equal(t, "name", badRequest.GetFieldViolations()[0].GetField())
+	assert.Equal(t, "name is required", badRequest.GetFieldViolations()[0].GetDescription())
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "accept-language", "fr-FR")
+	_, err = client.CreateAuthor(ctx, &libraryv1.CreateAuthorRequest{})
+
+	st, _ = status.FromError(err)
+	require.Len(t, st.Details(), 1)
+	badRequest = st.Details()[0].(*errdetails.BadRequest)
+	assert.Equal(t, "name est obligatoire", badRequest.GetFieldViolations()[0].GetDescription())
+}
+
+func TestAuthorServer_RenameAuthor(t *testing.T) {
+	f := newFixture(t)
+	client := libraryv1.NewAuthorServiceClient(f.conn)
+
+	f.authors.EXPECT().
+		RenameAuthor(gomock.Any(), authorID, &authorDto.RenameAuthorRequest{Name: "Victor Hugo"}).
+		Return(&entity.Author{ID: authorID, Name: "Victor Hugo"}, nil)
+
+	resp, err := client.RenameAuthor(context.Background(), &libraryv1.RenameAuthorRequest{Id: authorID.String(), Name: "Victor Hugo"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Victor Hugo", resp.GetAuthor().GetName())
+}
+
+func TestServer_Health(t *testing.T) {
+	f := newFixture(t)
+	client := healthpb.NewHealthClient(f.conn)
+
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "library.v1.BookService"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	require.NoError(t, f.server.Shutdown(context.Background()))
+
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.Error(t, err)
+}
+
+func TestServer_Tenancy(t *testing.T) {
+	tests := []struct {
+		name           string
+		metadata       []string
+		configureMock  func(f *fixture)
+		expectedCode   codes.Code
+		expectedTenant string
+	}{
+		{
+			name:     "success tenant metadata",
+			metadata: []string{"x-tenant-id", "acme"},
+			configureMock: func(f *fixture) {
+				f.tenants.EXPECT().GetTenant(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme"}, nil)
+			},
+			expectedCode:   codes.OK,
+			expectedTenant: "acme",
+		},
+		{
+			name:     "success tenant of the token",
+			metadata: []string{"authorization", "Bearer token-acme"},
+			configureMock: func(f *fixture) {
+				f.tenants.EXPECT().GetTenant(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme"}, nil)
+			},
+			expectedCode:   codes.OK,
+			expectedTenant: "acme",
+		},
+		{
+			name:          "error no tenant",
+			configureMock: func(f *fixture) {},
+			expectedCode:  codes.InvalidArgument,
+		},
+		{
+			name:          "error other tenant than the token",
+			metadata:      []string{"authorization", "Bearer token-acme", "x-tenant-id", "globex"},
+			configureMock: func(f *fixture) {},
+			expectedCode:  codes.PermissionDenied,
+		},
+		{
+			name:     "error unknown tenant",
+			metadata: []string{"x-tenant-id", "globex"},
+			configureMock: func(f *fixture) {
+				f.tenants.EXPECT().GetTenant(gomock.Any(), "globex").Return(nil, tenant.ErrNotFound)
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := newTenancyFixture(t, config.TenancyConfig{Enabled: true, Header: "X-Tenant-ID"})
+			test.configureMock(f)
+			if test.expectedCode == codes.OK {
+				f.books.EXPECT().GetBookByID(gomock.Any(), bookID).
+					DoAndReturn(func(ctx context.Context, _ uuid.UUID, _ ...string) (*entity.Book, error) {
+						tenantID, _ := tenant.FromContext(ctx)
+						assert.Equal(t, test.expectedTenant, tenantID)
+						return &entity.Book{ID: bookID, AuthorID: authorID}, nil
+					})
+			}
+
+			ctx := metadata.AppendToOutgoingContext(context.Background(), test.metadata...)
+			_, err := libraryv1.NewBookServiceClient(f.conn).GetBook(ctx, &libraryv1.GetBookRequest{Id: bookID.String()})
+
+			assert.Equal(t, test.expectedCode, status.Code(err))
+		})
+	}
+
+	t.Run("success health without tenant", func(t *testing.T) {
+		f := newTenancyFixture(t, config.TenancyConfig{Enabled: true, Header: "X-Tenant-ID"})
+
+		_, err := healthpb.NewHealthClient(f.conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+		assert.NoError(t, err)
+	})
+}
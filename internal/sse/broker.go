@@ -0,0 +1,197 @@
+package sse
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/event"
+)
+
+const (
+	defaultReplaySize   = 1000
+	defaultClientBuffer = 64
+)
+
+// Message is an event with its position in the feed.
+type Message struct {
+	ID    string
+	seq   uint64
+	Event event.Event
+}
+
+// Filter selects the events of a client. Empty fields select everything.
+type Filter struct {
+	Types  []string
+	IDs    []uuid.UUID
+	Tenant string
+}
+
+func (f Filter) Match(e event.Event) bool {
+	if f.Tenant != "" && e.Tenant != f.Tenant {
+		return false
+	}
+
+	if len(f.Types) > 0 && !slices.ContainsFunc(f.Types, func(pattern string) bool {
+		return event.Match(pattern, e.Type)
+	}) {
+		return false
+	}
+
+	return len(f.IDs) == 0 || slices.Contains(f.IDs, e.AggregateID)
+}
+
+// Client receives the messages matching its filter. Its channel is closed when the client
+// falls behind by more than its buffer, or when the broker is closed; the client then
+// reconnects with the ID of the last message it got.
+type Client struct {
+	messages chan Message
+	filter   Filter
+}
+
+func (c *Client) Messages() <-chan Message {
+	return c.messages
+}
+
+// Broker fans the events out to the connected clients and keeps the last ones for the
+// clients resuming with Last-Event-ID. Message IDs are "<epoch>-<sequence>": the epoch
+// changes at every start, so an ID from a previous run replays the whole buffer.
+type Broker struct {
+	mu           sync.Mutex
+	epoch        int64
+	seq          uint64
+	replay       []Message
+	replaySize   int
+	clientBuffer int
+	clients      map[*Client]struct{}
+	closed       bool
+}
+
+// NewBroker uses the defaults of config.EventsConfig for the zero values of cfg.
+func NewBroker(cfg config.EventsConfig) *Broker {
+	if cfg.ReplaySize <= 0 {
+		cfg.ReplaySize = defaultReplaySize
+	}
+	if cfg.ClientBuffer <= 0 {
+		cfg.ClientBuffer = defaultClientBuffer
+	}
+
+	return &Broker{
+		epoch:        time.Now().UnixNano(),
+		replaySize:   cfg.ReplaySize,
+		clientBuffer: cfg.ClientBuffer,
+		clients:      make(map[*Client]struct{}),
+	}
+}
+
+// Publish is the event.Handler feeding the broker. It never blocks on a slow client.
+func (b *Broker) Publish(_ context.Context, e event.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+
+	b.seq++
+	message := Message{ID: fmt.Sprintf("%d-%d", b.epoch, b.seq), seq: b.seq, Event: e}
+
+	if len(b.replay) == b.replaySize {
+		b.replay = slices.Delete(b.replay, 0, 1)
+	}
+	b.replay = append(b.replay, message)
+
+	for client := range b.clients {
+		if !client.filter.Match(e) {
+			continue
+		}
+
+		select {
+		case client.messages <- message:
+		default:
+			delete(b.clients, client)
+			close(client.messages)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe registers a client and returns the buffered messages it missed since
+// lastEventID, which is empty for a new client.
+func (b *Broker) Subscribe(filter Filter, lastEventID string) (*Client, []Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	client := &Client{
+		messages: make(chan Message, b.clientBuffer),
+		filter:   filter,
+	}
+
+	if b.closed {
+		close(client.messages)
+		return client, nil
+	}
+
+	b.clients[client] = struct{}{}
+
+	if lastEventID == "" {
+		return client, nil
+	}
+
+	var backlog []Message
+	after := b.resumeAfter(lastEventID)
+	for _, message := range b.replay {
+		if message.seq > after && filter.Match(message.Event) {
+			backlog = append(backlog, message)
+		}
+	}
+
+	return client, backlog
+}
+
+func (b *Broker) Unsubscribe(client *Client) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.clients[client]; ok {
+		delete(b.clients, client)
+		close(client.messages)
+	}
+}
+
+// Close disconnects every client, which lets the server shut down without waiting for the
+// streams to end.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for client := range b.clients {
+		delete(b.clients, client)
+		close(client.messages)
+	}
+}
+
+// resumeAfter returns the sequence following which the buffer is replayed: the one of
+// lastEventID, or 0 when it comes from another run or cannot be parsed.
+func (b *Broker) resumeAfter(lastEventID string) uint64 {
+	epoch, seq, ok := strings.Cut(lastEventID, "-")
+	if !ok || epoch != strconv.FormatInt(b.epoch, 10) {
+		return 0
+	}
+
+	after, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return after
+}
@@ -0,0 +1,133 @@
+package sse_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/sse"
+)
+
+func TestBroker_Publish(t *testing.T) {
+	broker := sse.NewBroker(config.EventsConfig{})
+
+	bookID := uuid.New()
+	books, _ := broker.Subscribe(sse.Filter{Types: []string{"book.*"}}, "")
+	oneBook, _ := broker.Subscribe(sse.Filter{IDs: []uuid.UUID{bookID}}, "")
+
+	publish(t, broker, event.New(event.BookCreated, bookID, nil))
+	publish(t, broker, event.New(event.AuthorCreated, uuid.New(), nil))
+	publish(t, broker, event.New(event.BookUpdated, uuid.New(), nil))
+
+	assert.Equal(t, []event.Type{event.BookCreated, event.BookUpdated}, drain(books))
+	assert.Equal(t, []event.Type{event.BookCreated}, drain(oneBook))
+}
+
+func TestBroker_SlowClientIsDisconnected(t *testing.T) {
+	broker := sse.NewBroker(config.EventsConfig{ClientBuffer: 2})
+
+	slow, _ := broker.Subscribe(sse.Filter{}, "")
+
+	for range 3 {
+		publish(t, broker, event.New(event.BookCreated, uuid.New(), nil))
+	}
+
+	assert.Len(t, drain(slow), 2)
+	_, open := <-slow.Messages()
+	assert.False(t, open)
+}
+
+func TestBroker_Subscribe_Resume(t *testing.T) {
+	broker := sse.NewBroker(config.EventsConfig{ReplaySize: 3})
+
+	first, _ := broker.Subscribe(sse.Filter{}, "")
+
+	for _, eventType := range []event.Type{event.BookCreated, event.BookUpdated, event.AuthorCreated, event.BookDeleted} {
+		publish(t, broker, event.New(eventType, uuid.New(), nil))
+	}
+
+	var ids []string
+	for range 4 {
+		ids = append(ids, (<-first.Messages()).ID)
+	}
+
+	tests := []struct {
+		name          string
+		filter        sse.Filter
+		lastEventID   string
+		expectedTypes []event.Type
+	}{
+		{
+			name:        "new client gets no backlog",
+			lastEventID: "",
+		},
+		{
+			name:          "resume after the second event",
+			lastEventID:   ids[1],
+			expectedTypes: []event.Type{event.AuthorCreated, event.BookDeleted},
+		},
+		{
+			name:          "resume applies the filter",
+			filter:        sse.Filter{Types: []string{"book.*"}},
+			lastEventID:   ids[1],
+			expectedTypes: []event.Type{event.BookDeleted},
+		},
+		{
+			name:          "id from another run replays the bounded buffer",
+			lastEventID:   "42-7",
+			expectedTypes: []event.Type{event.BookUpdated, event.AuthorCreated, event.BookDeleted},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, backlog := broker.Subscribe(test.filter, test.lastEventID)
+			t.Cleanup(func() { broker.Unsubscribe(client) })
+
+			var types []event.Type
+			for _, message := range backlog {
+				types = append(types, message.Event.Type)
+			}
+			assert.Equal(t, test.expectedTypes, types)
+		})
+	}
+}
+
+func TestBroker_Close(t *testing.T) {
+	broker := sse.NewBroker(config.EventsConfig{})
+
+	client, _ := broker.Subscribe(sse.Filter{}, "")
+	broker.Close()
+
+	_, open := <-client.Messages()
+	assert.False(t, open)
+
+	late, _ := broker.Subscribe(sse.Filter{}, "")
+	_, open = <-late.Messages()
+	assert.False(t, open)
+}
+
+func publish(t *testing.T, broker *sse.Broker, e event.Event) {
+	t.Helper()
+	require.NoError(t, broker.Publish(context.Background(), e))
+}
+
+func drain(client *sse.Client) []event.Type {
+	var types []event.Type
+	for {
+		select {
+		case message, ok := <-client.Messages():
+			if !ok {
+				return types
+			}
+			types = append(types, message.Event.Type)
+		default:
+			return types
+		}
+	}
+}
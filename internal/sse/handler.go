@@ -0,0 +1,177 @@
+package sse
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/response"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+)
+
+const (
+	defaultHeartbeatInterval = 15 * time.Second
+	// retryDelay is the reconnection delay advised to the clients, in milliseconds.
+	retryDelay = 3000
+)
+
+type EventsHandler struct {
+	broker            *Broker
+	heartbeatInterval time.Duration
+	logger            zerolog.Logger
+}
+
+func NewEventsHandler(broker *Broker, cfg config.EventsConfig, logger zerolog.Logger) *EventsHandler {
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = defaultHeartbeatInterval
+	}
+
+	return &EventsHandler{
+		broker:            broker,
+		heartbeatInterval: cfg.HeartbeatInterval,
+		logger:            logger,
+	}
+}
+
+func (h *EventsHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+
+	// routes
+	r.Get("/", h.Stream)
+
+	return r
+}
+
+// Stream godoc
+//
+//	@Summary		Stream change events
+//	@Description	Server-Sent Events feed of the book and author changes. Every message has the event type as "event", an "id" to send back in Last-Event-ID when reconnecting, and the event as JSON "data". Comment lines are sent as heartbeats. A client falling too far behind is disconnected and resumes from its last event.
+//	@Tags			events
+//	@Produce		text/event-stream
+//	@Param			types			query		string	false	"Comma separated event types, wildcards allowed, e.g. book.*,author.renamed"
+//	@Param			ids				query		string	false	"Comma separated IDs of the books or authors to follow"
+//	@Param			Last-Event-ID	header		string	false	"ID of the last event received, to resume the feed"
+//	@Success		200				{string}	string	"event stream"
+//	@Failure		400				{object}	response.ValidationErrorResponse
+//	@Router			/events [get]
+func (h *EventsHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	filter, errs := parseFilter(r)
+	if errs != nil {
+		response.ValidationError(w, errs)
+		return
+	}
+
+	filter.Tenant, _ = tenant.FromContext(r.Context())
+
+	client, backlog := h.broker.Subscribe(filter, r.Header.Get("Last-Event-ID"))
+	defer h.broker.Unsubscribe(client)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Keeps reverse proxies such as nginx from buffering the stream.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	controller := http.NewResponseController(w)
+	write := func(format string, args ...any) error {
+		// A client that stops reading must not hold the handler forever.
+		_ = controller.SetWriteDeadline(time.Now().Add(2 * h.heartbeatInterval))
+
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return err
+		}
+		return controller.Flush()
+	}
+
+	if err := write("retry: %d\n\n", retryDelay); err != nil {
+		return
+	}
+
+	for _, message := range backlog {
+		if err := h.writeMessage(write, message); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if err := write(": heartbeat\n\n"); err != nil {
+				return
+			}
+		case message, ok := <-client.Messages():
+			if !ok {
+				return
+			}
+			if err := h.writeMessage(write, message); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (h *EventsHandler) writeMessage(write func(format string, args ...any) error, message Message) error {
+	data, err := json.Marshal(message.Event)
+	if err != nil {
+		h.logger.Error().Err(err).Str("event_id", message.Event.ID.String()).Msg("failed to encode event")
+		return nil
+	}
+
+	return write("id: %s\nevent: %s\ndata: %s\n\n", message.ID, message.Event.Type, data)
+}
+
+func parseFilter(r *http.Request) (Filter, []response.ValidationErrorDetail) {
+	var filter Filter
+	var errs []response.ValidationErrorDetail
+
+	query := r.URL.Query()
+
+	for _, pattern := range splitList(query.Get("types")) {
+		if !event.ValidPattern(pattern) {
+			errs = append(errs, response.ValidationErrorDetail{
+				Field:   "types",
+				Message: fmt.Sprintf("unknown event type %q", pattern),
+			})
+			continue
+		}
+		filter.Types = append(filter.Types, pattern)
+	}
+
+	for _, value := range splitList(query.Get("ids")) {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			errs = append(errs, response.ValidationErrorDetail{
+				Field:   "ids",
+				Message: fmt.Sprintf("invalid uuid %q", value),
+			})
+			continue
+		}
+		filter.IDs = append(filter.IDs, id)
+	}
+
+	return filter, errs
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
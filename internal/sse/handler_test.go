@@ -0,0 +1,120 @@
+package sse_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/response"
+	"go-boilerplate-rest-api-chi/internal/sse"
+)
+
+func TestEventsHandler_Stream(t *testing.T) {
+	broker := sse.NewBroker(config.EventsConfig{})
+	handler := sse.NewEventsHandler(broker, config.EventsConfig{HeartbeatInterval: 50 * time.Millisecond}, zerolog.Nop())
+
+	r := chi.NewRouter()
+	r.Mount("/events", handler.Routes())
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	missed := event.New(event.BookCreated, uuid.New(), nil)
+	publish(t, broker, missed)
+	lastEventID := resumeID(t, broker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events?types=book.*", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", lastEventID)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	assert.Equal(t, "retry: 3000", readFrame(t, reader))
+
+	publish(t, broker, event.New(event.AuthorCreated, uuid.New(), nil))
+	updated := event.New(event.BookUpdated, uuid.New(), map[string]string{"description": "new"})
+	publish(t, broker, updated)
+
+	frame := readFrame(t, reader)
+	assert.Contains(t, frame, "event: book.updated\n")
+
+	data, ok := strings.CutPrefix(frame[strings.LastIndex(frame, "\n")+1:], "data: ")
+	require.True(t, ok)
+
+	var received event.Event
+	require.NoError(t, json.Unmarshal([]byte(data), &received))
+	assert.Equal(t, updated.ID, received.ID)
+
+	assert.Equal(t, ": heartbeat", readFrame(t, reader))
+}
+
+func TestEventsHandler_Stream_InvalidFilter(t *testing.T) {
+	handler := sse.NewEventsHandler(sse.NewBroker(config.EventsConfig{}), config.EventsConfig{}, zerolog.Nop())
+
+	r := chi.NewRouter()
+	r.Mount("/events", handler.Routes())
+
+	req := httptest.NewRequest(http.MethodGet, "/events?types=book.*,genre.created&ids=42", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var body response.ValidationErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, []response.ValidationErrorDetail{
+		{Field: "types", Message: `unknown event type "genre.created"`},
+		{Field: "ids", Message: `invalid uuid "42"`},
+	}, body.Errors)
+}
+
+// resumeID returns the ID of the last published message, as a client would have kept it.
+func resumeID(t *testing.T, broker *sse.Broker) string {
+	t.Helper()
+
+	client, backlog := broker.Subscribe(sse.Filter{}, "0-0")
+	broker.Unsubscribe(client)
+	require.NotEmpty(t, backlog)
+
+	return backlog[len(backlog)-1].ID
+}
+
+// readFrame reads the lines of the next frame, up to the blank line ending it.
+func readFrame(t *testing.T, reader *bufio.Reader) string {
+	t.Helper()
+
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return strings.Join(lines, "\n")
+		}
+		lines = append(lines, line)
+	}
+}
@@ -0,0 +1,46 @@
+package tenant
+
+import (
+	"context"
+
+	"go-boilerplate-rest-api-chi/internal/cache"
+	"go-boilerplate-rest-api-chi/internal/entity"
+)
+
+// cachedTenantRepository serves GetTenantByID, run by the resolver on every request, from a
+// cache.
+type cachedTenantRepository struct {
+	TenantRepository
+	cache *cache.Cache
+}
+
+// NewCachedTenantRepository decorates repository with c. The entries are invalidated by the
+// CreateTenant and UpdateTenant of the decorator, whatever the replica.
+func NewCachedTenantRepository(repository TenantRepository, c *cache.Cache) TenantRepository {
+	return &cachedTenantRepository{
+		TenantRepository: repository,
+		cache:            c,
+	}
+}
+
+func (r *cachedTenantRepository) CreateTenant(ctx context.Context, tenant *entity.Tenant) (*entity.Tenant, error) {
+	created, err := r.TenantRepository.CreateTenant(ctx, tenant)
+	if err == nil {
+		r.cache.Invalidate(ctx, created.ID)
+	}
+
+	return created, err
+}
+
+func (r *cachedTenantRepository) GetTenantByID(ctx context.Context, tenantID string) (*entity.Tenant, error) {
+	return cache.Fetch(ctx, r.cache, tenantID, func(ctx context.Context) (*entity.Tenant, error) {
+		return r.TenantRepository.GetTenantByID(ctx, tenantID)
+	})
+}
+
+func (r *cachedTenantRepository) UpdateTenant(ctx context.Context, tenantID string, updates map[string]any) error {
+	err := r.TenantRepository.UpdateTenant(ctx, tenantID, updates)
+	r.cache.Invalidate(ctx, tenantID)
+
+	return err
+}
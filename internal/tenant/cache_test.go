@@ -0,0 +1,91 @@
+package tenant_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"go-boilerplate-rest-api-chi/internal/cache"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+)
+
+func TestCachedTenantRepository(t *testing.T) {
+	ctx := context.Background()
+	disabledAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		configureMock func(*mocks.MockTenantRepository)
+		run           func(t *testing.T, repository tenant.TenantRepository)
+	}{
+		{
+			name: "success lookups cached",
+			configureMock: func(mockRepo *mocks.MockTenantRepository) {
+				mockRepo.EXPECT().GetTenantByID(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme", Name: "Acme"}, nil).Times(1)
+			},
+			run: func(t *testing.T, repository tenant.TenantRepository) {
+				for range 3 {
+					result, err := repository.GetTenantByID(ctx, "acme")
+					require.NoError(t, err)
+					assert.Equal(t, "Acme", result.Name)
+				}
+			},
+		},
+		{
+			name: "success update invalidates",
+			configureMock: func(mockRepo *mocks.MockTenantRepository) {
+				gomock.InOrder(
+					mockRepo.EXPECT().GetTenantByID(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme"}, nil),
+					mockRepo.EXPECT().UpdateTenant(gomock.Any(), "acme", gomock.Any()).Return(nil),
+					mockRepo.EXPECT().GetTenantByID(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme", DisabledAt: &disabledAt}, nil),
+				)
+			},
+			run: func(t *testing.T, repository tenant.TenantRepository) {
+				_, _ = repository.GetTenantByID(ctx, "acme")
+				require.NoError(t, repository.UpdateTenant(ctx, "acme", map[string]any{"disabled_at": disabledAt}))
+
+				result, err := repository.GetTenantByID(ctx, "acme")
+				require.NoError(t, err)
+				assert.True(t, disabledAt.Equal(*result.DisabledAt))
+			},
+		},
+		{
+			name: "error not found not cached",
+			configureMock: func(mockRepo *mocks.MockTenantRepository) {
+				gomock.InOrder(
+					mockRepo.EXPECT().GetTenantByID(gomock.Any(), "acme").Return(nil, tenant.ErrNotFound),
+					mockRepo.EXPECT().CreateTenant(gomock.Any(), gomock.Any()).Return(&entity.Tenant{ID: "acme"}, nil),
+					mockRepo.EXPECT().GetTenantByID(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme"}, nil),
+				)
+			},
+			run: func(t *testing.T, repository tenant.TenantRepository) {
+				_, err := repository.GetTenantByID(ctx, "acme")
+				assert.Equal(t, tenant.ErrNotFound, err)
+
+				_, err = repository.CreateTenant(ctx, &entity.Tenant{ID: "acme"})
+				require.NoError(t, err)
+
+				_, err = repository.GetTenantByID(ctx, "acme")
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := mocks.NewMockTenantRepository(ctrl)
+			test.configureMock(mockRepo)
+
+			c := cache.New("tenants", cache.NewMemoryStore(100), cache.NewLocalNotifier(), time.Minute, zerolog.Nop())
+			test.run(t, tenant.NewCachedTenantRepository(mockRepo, c))
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package dto
+
+type CreateTenantRequest struct {
+	// ID names the tenant in its subdomain and in the X-Tenant-ID header.
+	ID   string `json:"id" validate:"required,slug,max=63" example:"city-library"`
+	Name string `json:"name" validate:"required,notblank,no_html,max=100" example:"City library"`
+}
+
+type UpdateTenantRequest struct {
+	Name *string `json:"name" validate:"omitempty,notblank,no_html,max=100" example:"City library"`
+	// Disabled refuses the requests to the catalogue of the tenant, its data being kept.
+	Disabled *bool `json:"disabled" example:"false"`
+}
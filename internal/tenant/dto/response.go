@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"time"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+)
+
+type TenantResponse struct {
+	ID         string     `json:"id" xml:"id" example:"city-library"`
+	Name       string     `json:"name" xml:"name" example:"City library"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty" xml:"disabled_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" xml:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" xml:"updated_at"`
+}
+
+func ToTenantResponse(tenant *entity.Tenant) *TenantResponse {
+	return &TenantResponse{
+		ID:         tenant.ID,
+		Name:       tenant.Name,
+		DisabledAt: tenant.DisabledAt,
+		CreatedAt:  tenant.CreatedAt,
+		UpdatedAt:  tenant.UpdatedAt,
+	}
+}
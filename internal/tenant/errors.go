@@ -0,0 +1,17 @@
+package tenant
+
+import "errors"
+
+var (
+	ErrNotFound  = errors.New("tenant not found")
+	ErrDuplicate = errors.New("tenant already exists")
+	ErrDisabled  = errors.New("tenant disabled")
+	// ErrRequired is returned by the resolver when the request names no tenant.
+	ErrRequired = errors.New("tenant required")
+	// ErrMismatch is returned by the resolver when the request names another tenant than the
+	// one of its token.
+	ErrMismatch = errors.New("tenant not granted")
+	// ErrAnonymous is returned by the resolver when the request has no principal while the
+	// tenants are reserved to the authenticated callers.
+	ErrAnonymous = errors.New("tenant requires an authenticated caller")
+)
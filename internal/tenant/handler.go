@@ -0,0 +1,241 @@
+package tenant
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/request"
+	"go-boilerplate-rest-api-chi/internal/response"
+	"go-boilerplate-rest-api-chi/internal/tenant/dto"
+	internalValidator "go-boilerplate-rest-api-chi/internal/validator"
+)
+
+// AdminScope grants the management of the tenants of the deployment.
+const AdminScope = "tenants:admin"
+
+type TenantSuccessResponse struct {
+	Status  string              `json:"status" xml:"status" example:"success"`
+	Message string              `json:"message" xml:"message" example:"Tenant created successfully"`
+	Tenant  *dto.TenantResponse `json:"tenant" xml:"tenant"`
+}
+
+type TenantsSuccessResponse struct {
+	Status  string               `json:"status" xml:"status" example:"success"`
+	Message string               `json:"message" xml:"message" example:"Tenants retrieved successfully"`
+	Tenants []dto.TenantResponse `json:"tenants" xml:"tenants>tenant"`
+}
+
+type TenantHandler struct {
+	service   TenantService
+	validator *internalValidator.Validator
+	logger    zerolog.Logger
+}
+
+func NewTenantHandler(service TenantService, validator *internalValidator.Validator, logger zerolog.Logger) *TenantHandler {
+	return &TenantHandler{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *TenantHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Use(auth.RequireScope(AdminScope), DeploymentOnly)
+
+	// routes
+	r.With(response.Acceptable(TenantSuccessResponse{})).Post("/", h.CreateTenant)
+	r.Get("/", h.GetTenants)
+	r.Get("/{tenant_id}", h.GetTenant)
+	r.With(response.Acceptable(TenantSuccessResponse{})).Patch("/{tenant_id}", h.UpdateTenant)
+
+	return r
+}
+
+// CreateTenant godoc
+//
+//	@Summary		Create a tenant
+//	@Description	Host a new catalogue, named by its ID in the X-Tenant-ID header or in the subdomain. Requires the tenants:admin scope, held by a caller of the whole deployment.
+//	@Tags			tenants
+//	@Accept			json
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Param			tenant	body		dto.CreateTenantRequest	true	"Tenant data"
+//	@Success		201		{object}	TenantSuccessResponse
+//	@Failure		400		{object}	response.ValidationErrorResponse
+//	@Failure		401		{object}	response.ErrorResponse
+//	@Failure		403		{object}	response.ErrorResponse
+//	@Failure		409		{object}	response.ErrorResponse
+//	@Failure		413		{object}	response.ValidationErrorResponse
+//	@Failure		415		{object}	response.ValidationErrorResponse
+//	@Failure		500		{object}	response.ErrorResponse
+//	@Security		ApiKeyAuth
+//	@Router			/tenants [post]
+func (h *TenantHandler) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateTenantRequest
+
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.validator.FormatErrors(r.Context(), err)
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	tenant, err := h.service.CreateTenant(r.Context(), &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Render(w, r, http.StatusCreated, TenantSuccessResponse{
+		Status:  "success",
+		Message: "Tenant created successfully",
+		Tenant:  dto.ToTenantResponse(tenant),
+	})
+}
+
+// GetTenants godoc
+//
+//	@Summary		List tenants
+//	@Description	Get every tenant, disabled ones included. Requires the tenants:admin scope, held by a caller of the whole deployment.
+//	@Tags			tenants
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Success		200	{object}	TenantsSuccessResponse
+//	@Failure		401	{object}	response.ErrorResponse
+//	@Failure		403	{object}	response.ErrorResponse
+//	@Failure		500	{object}	response.ErrorResponse
+//	@Security		ApiKeyAuth
+//	@Router			/tenants [get]
+func (h *TenantHandler) GetTenants(w http.ResponseWriter, r *http.Request) {
+	tenants, err := h.service.GetTenants(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	tenantsResponse := make([]dto.TenantResponse, len(tenants))
+	for i, tenant := range tenants {
+		tenantsResponse[i] = *dto.ToTenantResponse(tenant)
+	}
+
+	response.Render(w, r, http.StatusOK, TenantsSuccessResponse{
+		Status:  "success",
+		Message: "Tenants retrieved successfully",
+		Tenants: tenantsResponse,
+	})
+}
+
+// GetTenant godoc
+//
+//	@Summary		Get a tenant
+//	@Description	Requires the tenants:admin scope, held by a caller of the whole deployment.
+//	@Tags			tenants
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Param			tenant_id	path		string	true	"Tenant ID"
+//	@Success		200			{object}	TenantSuccessResponse
+//	@Failure		401			{object}	response.ErrorResponse
+//	@Failure		403			{object}	response.ErrorResponse
+//	@Failure		404			{object}	response.ErrorResponse
+//	@Failure		500			{object}	response.ErrorResponse
+//	@Security		ApiKeyAuth
+//	@Router			/tenants/{tenant_id} [get]
+func (h *TenantHandler) GetTenant(w http.ResponseWriter, r *http.Request) {
+	tenant, err := h.service.GetTenant(r.Context(), chi.URLParam(r, "tenant_id"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Render(w, r, http.StatusOK, TenantSuccessResponse{
+		Status:  "success",
+		Message: "Tenant retrieved successfully",
+		Tenant:  dto.ToTenantResponse(tenant),
+	})
+}
+
+// UpdateTenant godoc
+//
+//	@Summary		Update a tenant
+//	@Description	Rename a tenant, or disable it: the requests to its catalogue are refused with 403 while its data is kept. Requires the tenants:admin scope, held by a caller of the whole deployment.
+//	@Tags			tenants
+//	@Accept			json
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Param			tenant_id	path		string					true	"Tenant ID"
+//	@Param			tenant		body		dto.UpdateTenantRequest	true	"Tenant changes"
+//	@Success		200			{object}	TenantSuccessResponse
+//	@Failure		400			{object}	response.ValidationErrorResponse
+//	@Failure		401			{object}	response.ErrorResponse
+//	@Failure		403			{object}	response.ErrorResponse
+//	@Failure		404			{object}	response.ErrorResponse
+//	@Failure		413			{object}	response.ValidationErrorResponse
+//	@Failure		415			{object}	response.ValidationErrorResponse
+//	@Failure		500			{object}	response.ErrorResponse
+//	@Security		ApiKeyAuth
+//	@Router			/tenants/{tenant_id} [patch]
+func (h *TenantHandler) UpdateTenant(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateTenantRequest
+
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.validator.FormatErrors(r.Context(), err)
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	tenant, err := h.service.UpdateTenant(r.Context(), chi.URLParam(r, "tenant_id"), &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Render(w, r, http.StatusOK, TenantSuccessResponse{
+		Status:  "success",
+		Message: "Tenant updated successfully",
+		Tenant:  dto.ToTenantResponse(tenant),
+	})
+}
+
+// DeploymentOnly refuses the callers bound to a tenant, whatever their scopes: the tenants, and
+// the other resources shared by every tenant, are managed by the callers of the whole
+// deployment.
+func DeploymentOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal, _ := auth.FromContext(r.Context()); principal.Tenant != "" {
+			response.Error(w, http.StatusForbidden, "Forbidden")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *TenantHandler) handleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		response.Error(w, http.StatusNotFound, "Tenant not found")
+	case errors.Is(err, ErrDuplicate):
+		response.Error(w, http.StatusConflict, "Tenant already exists")
+	default:
+		h.logger.Error().Err(err).Msg("unexpected error")
+		response.Error(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
@@ -0,0 +1,236 @@
+package tenant_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/response"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	"go-boilerplate-rest-api-chi/internal/tenant/dto"
+	"go-boilerplate-rest-api-chi/internal/validator"
+)
+
+var admin = &auth.Principal{Subject: "admin-1", Scopes: []string{tenant.AdminScope}}
+
+func serve(handler *tenant.TenantHandler, principal *auth.Principal, method, target string, body any) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buf).Encode(body)
+	}
+
+	req := httptest.NewRequest(method, target, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if principal != nil {
+		req = req.WithContext(auth.WithPrincipal(req.Context(), principal))
+	}
+	w := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Mount("/tenants", handler.Routes())
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestTenantHandler_CreateTenant(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		principal          *auth.Principal
+		requestBody        any
+		configureMock      func(*mocks.MockTenantService)
+		expectedStatusCode int
+		expectedResponse   any
+	}{
+		{
+			name:        "success create tenant",
+			principal:   admin,
+			requestBody: dto.CreateTenantRequest{ID: "acme", Name: "Acme library"},
+			configureMock: func(mockService *mocks.MockTenantService) {
+				mockService.EXPECT().
+					CreateTenant(gomock.Any(), &dto.CreateTenantRequest{ID: "acme", Name: "Acme library"}).
+					Return(&entity.Tenant{ID: "acme", Name: "Acme library", CreatedAt: createdAt, UpdatedAt: createdAt}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse: tenant.TenantSuccessResponse{
+				Status:  "success",
+				Message: "Tenant created successfully",
+				Tenant:  &dto.TenantResponse{ID: "acme", Name: "Acme library", CreatedAt: createdAt, UpdatedAt: createdAt},
+			},
+		},
+		{
+			name:               "error validation fails id not a slug",
+			principal:          admin,
+			requestBody:        dto.CreateTenantRequest{ID: "Acme Library", Name: "Acme library"},
+			configureMock:      func(mockService *mocks.MockTenantService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ValidationErrorResponse{
+				Status:  "error",
+				Message: "Validation failed",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "id",
+					Message: "id must contain only lowercase letters, numbers and hyphens",
+					Tag:     "slug",
+				}},
+			},
+		},
+		{
+			name:        "error duplicate tenant",
+			principal:   admin,
+			requestBody: dto.CreateTenantRequest{ID: "acme", Name: "Acme library"},
+			configureMock: func(mockService *mocks.MockTenantService) {
+				mockService.EXPECT().CreateTenant(gomock.Any(), gomock.Any()).Return(nil, tenant.ErrDuplicate)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "Tenant already exists"},
+		},
+		{
+			name:               "error anonymous",
+			requestBody:        dto.CreateTenantRequest{ID: "acme", Name: "Acme library"},
+			configureMock:      func(mockService *mocks.MockTenantService) {},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "Unauthorized"},
+		},
+		{
+			name:               "error admin bound to a tenant",
+			principal:          &auth.Principal{Subject: "api_key:acme", Scopes: []string{tenant.AdminScope}, Tenant: "acme"},
+			requestBody:        dto.CreateTenantRequest{ID: "globex", Name: "Globex library"},
+			configureMock:      func(mockService *mocks.MockTenantService) {},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "Forbidden"},
+		},
+		{
+			name:               "error missing admin scope",
+			principal:          &auth.Principal{Subject: "user-1", Scopes: []string{"books:write"}},
+			requestBody:        dto.CreateTenantRequest{ID: "acme", Name: "Acme library"},
+			configureMock:      func(mockService *mocks.MockTenantService) {},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "Forbidden"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockService := mocks.NewMockTenantService(ctrl)
+			test.configureMock(mockService)
+			handler := tenant.NewTenantHandler(mockService, validator.New(), zerolog.Nop())
+
+			w := serve(handler, test.principal, http.MethodPost, "/tenants", test.requestBody)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+
+			expectedJSON, err := json.Marshal(test.expectedResponse)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, string(expectedJSON), w.Body.String())
+		})
+	}
+}
+
+func TestTenantHandler_GetTenants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockTenantService(ctrl)
+	handler := tenant.NewTenantHandler(mockService, validator.New(), zerolog.Nop())
+
+	disabledAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	mockService.EXPECT().GetTenants(gomock.Any()).Return([]*entity.Tenant{
+		{ID: "acme", Name: "Acme library", DisabledAt: &disabledAt},
+		{ID: entity.DefaultTenantID, Name: "Default"},
+	}, nil)
+
+	w := serve(handler, admin, http.MethodGet, "/tenants", nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body tenant.TenantsSuccessResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Tenants, 2)
+	assert.Equal(t, &disabledAt, body.Tenants[0].DisabledAt)
+	assert.Nil(t, body.Tenants[1].DisabledAt)
+}
+
+func TestTenantHandler_GetAndUpdateTenant(t *testing.T) {
+	disabled := true
+
+	tests := []struct {
+		name               string
+		method             string
+		requestBody        any
+		configureMock      func(*mocks.MockTenantService)
+		expectedStatusCode int
+		expectedMessage    string
+	}{
+		{
+			name:   "success get tenant",
+			method: http.MethodGet,
+			configureMock: func(mockService *mocks.MockTenantService) {
+				mockService.EXPECT().GetTenant(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme"}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedMessage:    "Tenant retrieved successfully",
+		},
+		{
+			name:   "error get unknown tenant",
+			method: http.MethodGet,
+			configureMock: func(mockService *mocks.MockTenantService) {
+				mockService.EXPECT().GetTenant(gomock.Any(), "acme").Return(nil, tenant.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedMessage:    "Tenant not found",
+		},
+		{
+			name:        "success disable tenant",
+			method:      http.MethodPatch,
+			requestBody: dto.UpdateTenantRequest{Disabled: &disabled},
+			configureMock: func(mockService *mocks.MockTenantService) {
+				mockService.EXPECT().
+					UpdateTenant(gomock.Any(), "acme", &dto.UpdateTenantRequest{Disabled: &disabled}).
+					Return(&entity.Tenant{ID: "acme", DisabledAt: &time.Time{}}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedMessage:    "Tenant updated successfully",
+		},
+		{
+			name:        "error update unknown tenant",
+			method:      http.MethodPatch,
+			requestBody: dto.UpdateTenantRequest{Disabled: &disabled},
+			configureMock: func(mockService *mocks.MockTenantService) {
+				mockService.EXPECT().UpdateTenant(gomock.Any(), "acme", gomock.Any()).Return(nil, tenant.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedMessage:    "Tenant not found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mocks.NewMockTenantService(ctrl)
+			test.configureMock(mockService)
+			handler := tenant.NewTenantHandler(mockService, validator.New(), zerolog.Nop())
+
+			w := serve(handler, admin, test.method, "/tenants/acme", test.requestBody)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			var body response.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, test.expectedMessage, body.Message)
+		})
+	}
+}
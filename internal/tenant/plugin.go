@@ -0,0 +1,87 @@
+package tenant
+
+import (
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNoTenant rejects the queries on the data of the tenants run without a tenant in their
+// context: a forgotten scope fails instead of reaching every catalogue.
+var ErrNoTenant = errors.New("no tenant in context")
+
+// field is the field of the models holding the data of a tenant.
+const field = "TenantID"
+
+// Plugin scopes every query on the models with a TenantID to the tenant of its context:
+// the selects, updates and deletes are filtered by tenant_id, and the created records get
+// the tenant, whatever their TenantID was set to. The contexts of AllTenants are left alone.
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "tenant"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	return errors.Join(
+		db.Callback().Create().Before("gorm:create").Register("tenant:create", assign),
+		db.Callback().Query().Before("gorm:query").Register("tenant:query", filter),
+		db.Callback().Row().Before("gorm:row").Register("tenant:row", filter),
+		db.Callback().Update().Before("gorm:update").Register("tenant:update", filter),
+		db.Callback().Delete().Before("gorm:delete").Register("tenant:delete", filter),
+	)
+}
+
+func filter(db *gorm.DB) {
+	id, ok := scope(db)
+	if !ok {
+		return
+	}
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: db.Statement.Schema.LookUpField(field).DBName}, Value: id},
+	}})
+}
+
+func assign(db *gorm.DB) {
+	id, ok := scope(db)
+	if !ok {
+		return
+	}
+
+	tenantField := db.Statement.Schema.LookUpField(field)
+	set := func(value reflect.Value) {
+		if err := tenantField.Set(db.Statement.Context, value, id); err != nil {
+			_ = db.AddError(err)
+		}
+	}
+
+	switch value := db.Statement.ReflectValue; value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := range value.Len() {
+			set(reflect.Indirect(value.Index(i)))
+		}
+	case reflect.Struct:
+		set(value)
+	}
+}
+
+// scope returns the tenant of a statement on the data of the tenants. The statement fails
+// when its context has none, unless it was opted out with AllTenants.
+func scope(db *gorm.DB) (string, bool) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.Schema.LookUpField(field) == nil {
+		return "", false
+	}
+	if isAllTenants(db.Statement.Context) {
+		return "", false
+	}
+
+	id, ok := FromContext(db.Statement.Context)
+	if !ok {
+		_ = db.AddError(ErrNoTenant)
+		return "", false
+	}
+	return id, true
+}
@@ -0,0 +1,179 @@
+package tenant_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	"go-boilerplate-rest-api-chi/internal/test-utils"
+)
+
+func TestPlugin(t *testing.T) {
+	bookID := uuid.MustParse("f0b4a7f2-4a1c-4d6e-9b8a-2c3d4e5f6a7b")
+	acme := tenant.WithID(context.Background(), "acme")
+
+	tests := []struct {
+		name          string
+		configureMock func(sqlmock.Sqlmock)
+		run           func(*gorm.DB) error
+		expectedError error
+	}{
+		{
+			name: "success select filtered",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `books` WHERE `books`.`tenant_id` = ?")).
+					WithArgs("acme").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "title"}))
+			},
+			run: func(db *gorm.DB) error {
+				var books []entity.Book
+				return db.WithContext(acme).Find(&books).Error
+			},
+		},
+		{
+			name: "success count filtered",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `authors` WHERE `authors`.`tenant_id` = ?")).
+					WithArgs("acme").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+			run: func(db *gorm.DB) error {
+				var count int64
+				return db.WithContext(acme).Model(&entity.Author{}).Count(&count).Error
+			},
+		},
+		{
+			name: "success update filtered",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `books` SET `title`=?,`updated_at`=? WHERE id = ? AND `books`.`tenant_id` = ?")).
+					WithArgs("Les contemplations", sqlmock.AnyArg(), bookID, "acme").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			run: func(db *gorm.DB) error {
+				return db.WithContext(acme).Model(&entity.Book{}).Where("id = ?", bookID).Update("title", "Les contemplations").Error
+			},
+		},
+		{
+			name: "success delete filtered",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `books` WHERE id = ? AND `books`.`tenant_id` = ?")).
+					WithArgs(bookID, "acme").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			run: func(db *gorm.DB) error {
+				return db.WithContext(acme).Delete(&entity.Book{}, "id = ?", bookID).Error
+			},
+		},
+		{
+			name: "success create assigned to the tenant",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `authors`")).
+					WithArgs(sqlmock.AnyArg(), "acme", "Victor Hugo", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			run: func(db *gorm.DB) error {
+				return db.WithContext(acme).Create(&entity.Author{TenantID: "other", Name: "Victor Hugo"}).Error
+			},
+		},
+		{
+			name: "success create in batch assigned to the tenant",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `authors`")).
+					WithArgs(
+						sqlmock.AnyArg(), "acme", "Victor Hugo", sqlmock.AnyArg(), sqlmock.AnyArg(),
+						sqlmock.AnyArg(), "acme", "George Sand", sqlmock.AnyArg(), sqlmock.AnyArg(),
+					).
+					WillReturnResult(sqlmock.NewResult(1, 2))
+			},
+			run: func(db *gorm.DB) error {
+				authors := []*entity.Author{{Name: "Victor Hugo"}, {Name: "George Sand"}}
+				return db.WithContext(acme).Create(&authors).Error
+			},
+		},
+		{
+			name: "success model without tenant untouched",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tenants`")).
+					WithoutArgs().
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+			},
+			run: func(db *gorm.DB) error {
+				var tenants []entity.Tenant
+				return db.WithContext(context.Background()).Find(&tenants).Error
+			},
+		},
+		{
+			name: "success select across tenants",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `books`")).
+					WithoutArgs().
+					WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "title"}))
+			},
+			run: func(db *gorm.DB) error {
+				var books []entity.Book
+				return db.WithContext(tenant.AllTenants(acme)).Find(&books).Error
+			},
+		},
+		{
+			name: "success create across tenants keeps the tenant",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `authors`")).
+					WithArgs(sqlmock.AnyArg(), "globex", "Victor Hugo", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			run: func(db *gorm.DB) error {
+				return db.WithContext(tenant.AllTenants(context.Background())).Create(&entity.Author{TenantID: "globex", Name: "Victor Hugo"}).Error
+			},
+		},
+		{
+			name: "success scoped again after all tenants",
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `books` WHERE `books`.`tenant_id` = ?")).
+					WithArgs("globex").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "title"}))
+			},
+			run: func(db *gorm.DB) error {
+				var books []entity.Book
+				return db.WithContext(tenant.WithID(tenant.AllTenants(acme), "globex")).Find(&books).Error
+			},
+		},
+		{
+			name: "error select without tenant",
+			run: func(db *gorm.DB) error {
+				var books []entity.Book
+				return db.WithContext(context.Background()).Find(&books).Error
+			},
+			expectedError: tenant.ErrNoTenant,
+		},
+		{
+			name: "error create without tenant",
+			run: func(db *gorm.DB) error {
+				return db.WithContext(context.Background()).Create(&entity.Author{Name: "Victor Hugo"}).Error
+			},
+			expectedError: tenant.ErrNoTenant,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := testutils.NewGormMySQL(t)
+			require.NoError(t, db.Use(tenant.Plugin{}))
+			if test.configureMock != nil {
+				test.configureMock(mock)
+			}
+
+			err := test.run(db)
+
+			assert.ErrorIs(t, err, test.expectedError)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
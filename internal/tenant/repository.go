@@ -0,0 +1,84 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+)
+
+//go:generate mockgen -destination=../mocks/mock_tenant_repository.go -package=mocks go-boilerplate-rest-api-chi/internal/tenant TenantRepository
+type TenantRepository interface {
+	CreateTenant(ctx context.Context, tenant *entity.Tenant) (*entity.Tenant, error)
+	GetTenants(ctx context.Context) ([]*entity.Tenant, error)
+	GetTenantByID(ctx context.Context, tenantID string) (*entity.Tenant, error)
+	UpdateTenant(ctx context.Context, tenantID string, updates map[string]any) error
+}
+
+type tenantRepository struct {
+	db     *gorm.DB
+	logger zerolog.Logger
+}
+
+func NewTenantRepository(db *gorm.DB, logger zerolog.Logger) TenantRepository {
+	return &tenantRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *tenantRepository) CreateTenant(ctx context.Context, tenant *entity.Tenant) (*entity.Tenant, error) {
+	if err := r.db.WithContext(ctx).Create(tenant).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrDuplicate
+		}
+
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return tenant, nil
+}
+
+func (r *tenantRepository) GetTenants(ctx context.Context) ([]*entity.Tenant, error) {
+	var tenants []*entity.Tenant
+
+	if err := r.db.WithContext(ctx).Order("id").Find(&tenants).Error; err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return tenants, nil
+}
+
+func (r *tenantRepository) GetTenantByID(ctx context.Context, tenantID string) (*entity.Tenant, error) {
+	var tenant *entity.Tenant
+
+	if err := r.db.WithContext(ctx).First(&tenant, "id = ?", tenantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return tenant, nil
+}
+
+func (r *tenantRepository) UpdateTenant(ctx context.Context, tenantID string, updates map[string]any) error {
+	result := r.db.WithContext(ctx).Model(&entity.Tenant{ID: tenantID}).Updates(updates)
+	if result.Error != nil {
+		r.logger.Error().Err(result.Error).Msg("database error")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
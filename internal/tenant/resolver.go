@@ -0,0 +1,123 @@
+package tenant
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+// Resolver finds the tenant of the requests, as TenancyConfig describes. The tenants are
+// looked up on every request, through a cached repository in the API.
+type Resolver struct {
+	service     TenantService
+	enabled     bool
+	header      string
+	baseDomain  string
+	requireAuth bool
+	logger      zerolog.Logger
+}
+
+func NewResolver(service TenantService, cfg config.TenancyConfig, logger zerolog.Logger) *Resolver {
+	return &Resolver{
+		service:     service,
+		enabled:     cfg.Enabled,
+		header:      cfg.Header,
+		baseDomain:  strings.ToLower(strings.Trim(cfg.BaseDomain, ".")),
+		requireAuth: cfg.RequireAuth,
+		logger:      logger,
+	}
+}
+
+// Resolve returns the ID of the active tenant of r. A caller bound to a tenant by its token
+// may only name that tenant, the others any tenant unless they are anonymous and the tenants
+// require authentication.
+func (res *Resolver) Resolve(r *http.Request) (string, error) {
+	if !res.enabled {
+		return entity.DefaultTenantID, nil
+	}
+
+	requested := res.requested(r)
+	var claimed string
+	principal, ok := auth.FromContext(r.Context())
+	switch {
+	case ok:
+		claimed = principal.Tenant
+	case res.requireAuth:
+		return "", ErrAnonymous
+	}
+
+	switch {
+	case claimed != "" && requested != "" && requested != claimed:
+		return "", ErrMismatch
+	case claimed != "":
+		requested = claimed
+	case requested == "":
+		return "", ErrRequired
+	}
+
+	tenant, err := res.service.GetTenant(r.Context(), requested)
+	if err != nil {
+		return "", err
+	}
+	if tenant.DisabledAt != nil {
+		return "", ErrDisabled
+	}
+
+	return tenant.ID, nil
+}
+
+// requested reads the tenant named by the header, or by the subdomain of the host.
+func (res *Resolver) requested(r *http.Request) string {
+	if res.header != "" {
+		if id := strings.TrimSpace(r.Header.Get(res.header)); id != "" {
+			return id
+		}
+	}
+
+	if res.baseDomain == "" {
+		return ""
+	}
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	subdomain, ok := strings.CutSuffix(strings.ToLower(host), "."+res.baseDomain)
+	if !ok || strings.Contains(subdomain, ".") {
+		return ""
+	}
+	return subdomain
+}
+
+// Middleware scopes the requests to their tenant, see WithID. It follows the authentication,
+// for the tenant claim of the token to be read.
+func (res *Resolver) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := res.Resolve(r)
+		switch {
+		case errors.Is(err, ErrRequired):
+			response.Error(w, http.StatusBadRequest, "Tenant is required")
+		case errors.Is(err, ErrAnonymous):
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+			response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		case errors.Is(err, ErrMismatch):
+			response.Error(w, http.StatusForbidden, "Forbidden")
+		case errors.Is(err, ErrNotFound):
+			response.Error(w, http.StatusNotFound, "Tenant not found")
+		case errors.Is(err, ErrDisabled):
+			response.Error(w, http.StatusForbidden, "Tenant is disabled")
+		case err != nil:
+			res.logger.Error().Err(err).Msg("unexpected error")
+			response.Error(w, http.StatusInternalServerError, "Internal server error")
+		default:
+			next.ServeHTTP(w, r.WithContext(WithID(r.Context(), id)))
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package tenant_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/response"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+)
+
+func TestResolver_Middleware(t *testing.T) {
+	enabled := config.TenancyConfig{Enabled: true, Header: "X-Tenant-ID", BaseDomain: "library.example.com"}
+	disabledAt := time.Now()
+
+	tests := []struct {
+		name               string
+		config             config.TenancyConfig
+		host               string
+		header             string
+		principal          *auth.Principal
+		configureMock      func(*mocks.MockTenantService)
+		expectedStatusCode int
+		expectedTenant     string
+		expectedResponse   any
+	}{
+		{
+			name:               "success tenancy disabled",
+			config:             config.TenancyConfig{Header: "X-Tenant-ID"},
+			header:             "acme",
+			configureMock:      func(*mocks.MockTenantService) {},
+			expectedStatusCode: http.StatusOK,
+			expectedTenant:     entity.DefaultTenantID,
+		},
+		{
+			name:   "success header",
+			config: enabled,
+			header: "acme",
+			configureMock: func(mockService *mocks.MockTenantService) {
+				mockService.EXPECT().GetTenant(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme"}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedTenant:     "acme",
+		},
+		{
+			name:   "success subdomain",
+			config: enabled,
+			host:   "Acme.Library.example.com:8080",
+			configureMock: func(mockService *mocks.MockTenantService) {
+				mockService.EXPECT().GetTenant(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme"}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedTenant:     "acme",
+		},
+		{
+			name:      "success tenant of the token",
+			config:    enabled,
+			principal: &auth.Principal{Subject: "user-1", Tenant: "acme"},
+			configureMock: func(mockService *mocks.MockTenantService) {
+				mockService.EXPECT().GetTenant(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme"}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedTenant:     "acme",
+		},
+		{
+			name:               "error no tenant",
+			config:             enabled,
+			host:               "library.example.com",
+			configureMock:      func(*mocks.MockTenantService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "Tenant is required"},
+		},
+		{
+			name:               "error anonymous while authentication is required",
+			config:             config.TenancyConfig{Enabled: true, Header: "X-Tenant-ID", RequireAuth: true},
+			header:             "acme",
+			configureMock:      func(*mocks.MockTenantService) {},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "Unauthorized"},
+		},
+		{
+			name:      "success authenticated while authentication is required",
+			config:    config.TenancyConfig{Enabled: true, Header: "X-Tenant-ID", RequireAuth: true},
+			header:    "acme",
+			principal: &auth.Principal{Subject: "user-1"},
+			configureMock: func(mockService *mocks.MockTenantService) {
+				mockService.EXPECT().GetTenant(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme"}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedTenant:     "acme",
+		},
+		{
+			name:               "error nested subdomain",
+			config:             enabled,
+			host:               "a.acme.library.example.com",
+			configureMock:      func(*mocks.MockTenantService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "Tenant is required"},
+		},
+		{
+			name:               "error other tenant than the token",
+			config:             enabled,
+			header:             "globex",
+			principal:          &auth.Principal{Subject: "user-1", Tenant: "acme"},
+			configureMock:      func(*mocks.MockTenantService) {},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "Forbidden"},
+		},
+		{
+			name:   "error unknown tenant",
+			config: enabled,
+			header: "initech",
+			configureMock: func(mockService *mocks.MockTenantService) {
+				mockService.EXPECT().GetTenant(gomock.Any(), "initech").Return(nil, tenant.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "Tenant not found"},
+		},
+		{
+			name:   "error disabled tenant",
+			config: enabled,
+			header: "acme",
+			configureMock: func(mockService *mocks.MockTenantService) {
+				mockService.EXPECT().GetTenant(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme", DisabledAt: &disabledAt}, nil)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "Tenant is disabled"},
+		},
+		{
+			name:   "error database",
+			config: enabled,
+			header: "acme",
+			configureMock: func(mockService *mocks.MockTenantService) {
+				mockService.EXPECT().GetTenant(gomock.Any(), "acme").Return(nil, errors.New("connection refused"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "Internal server error"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mocks.NewMockTenantService(ctrl)
+			test.configureMock(mockService)
+
+			var resolved string
+			resolver := tenant.NewResolver(mockService, test.config, zerolog.Nop())
+			handler := resolver.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				resolved, _ = tenant.FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/books", nil)
+			if test.host != "" {
+				req.Host = test.host
+			}
+			if test.header != "" {
+				req.Header.Set("X-Tenant-ID", test.header)
+			}
+			if test.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), test.principal))
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedTenant, resolved)
+			if test.expectedResponse != nil {
+				var body response.ErrorResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+				assert.Equal(t, test.expectedResponse, body)
+			}
+		})
+	}
+}
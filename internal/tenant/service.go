@@ -0,0 +1,75 @@
+package tenant
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/tenant/dto"
+)
+
+//go:generate mockgen -destination=../mocks/mock_tenant_service.go -package=mocks go-boilerplate-rest-api-chi/internal/tenant TenantService
+type TenantService interface {
+	CreateTenant(ctx context.Context, req *dto.CreateTenantRequest) (*entity.Tenant, error)
+	GetTenants(ctx context.Context) ([]*entity.Tenant, error)
+	GetTenant(ctx context.Context, tenantID string) (*entity.Tenant, error)
+	// UpdateTenant renames, disables or enables a tenant. Disabling keeps the catalogue.
+	UpdateTenant(ctx context.Context, tenantID string, req *dto.UpdateTenantRequest) (*entity.Tenant, error)
+}
+
+type tenantService struct {
+	repository TenantRepository
+	logger     zerolog.Logger
+}
+
+func NewTenantService(repository TenantRepository, logger zerolog.Logger) TenantService {
+	return &tenantService{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+func (s *tenantService) CreateTenant(ctx context.Context, req *dto.CreateTenantRequest) (*entity.Tenant, error) {
+	return s.repository.CreateTenant(ctx, &entity.Tenant{
+		ID:   req.ID,
+		Name: req.Name,
+	})
+}
+
+func (s *tenantService) GetTenants(ctx context.Context) ([]*entity.Tenant, error) {
+	return s.repository.GetTenants(ctx)
+}
+
+func (s *tenantService) GetTenant(ctx context.Context, tenantID string) (*entity.Tenant, error) {
+	return s.repository.GetTenantByID(ctx, tenantID)
+}
+
+func (s *tenantService) UpdateTenant(ctx context.Context, tenantID string, req *dto.UpdateTenantRequest) (*entity.Tenant, error) {
+	tenant, err := s.repository.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]any)
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Disabled != nil && *req.Disabled != (tenant.DisabledAt != nil) {
+		if *req.Disabled {
+			updates["disabled_at"] = time.Now().UTC()
+		} else {
+			updates["disabled_at"] = nil
+		}
+	}
+	if len(updates) == 0 {
+		return tenant, nil
+	}
+
+	if err := s.repository.UpdateTenant(ctx, tenantID, updates); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetTenantByID(ctx, tenantID)
+}
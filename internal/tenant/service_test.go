@@ -0,0 +1,99 @@
+package tenant_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	"go-boilerplate-rest-api-chi/internal/tenant/dto"
+)
+
+func TestTenantService_UpdateTenant(t *testing.T) {
+	name := "Acme public library"
+	enable, disable := false, true
+	disabledAt := time.Now()
+
+	tests := []struct {
+		name          string
+		input         *dto.UpdateTenantRequest
+		configureMock func(*mocks.MockTenantRepository)
+		expectedError error
+	}{
+		{
+			name:  "success rename tenant",
+			input: &dto.UpdateTenantRequest{Name: &name},
+			configureMock: func(mockRepo *mocks.MockTenantRepository) {
+				gomock.InOrder(
+					mockRepo.EXPECT().GetTenantByID(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme"}, nil),
+					mockRepo.EXPECT().UpdateTenant(gomock.Any(), "acme", map[string]any{"name": name}).Return(nil),
+					mockRepo.EXPECT().GetTenantByID(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme", Name: name}, nil),
+				)
+			},
+		},
+		{
+			name:  "success disable tenant",
+			input: &dto.UpdateTenantRequest{Disabled: &disable},
+			configureMock: func(mockRepo *mocks.MockTenantRepository) {
+				gomock.InOrder(
+					mockRepo.EXPECT().GetTenantByID(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme"}, nil),
+					mockRepo.EXPECT().
+						UpdateTenant(gomock.Any(), "acme", gomock.Cond(func(updates map[string]any) bool {
+							_, ok := updates["disabled_at"].(time.Time)
+							return ok && len(updates) == 1
+						})).
+						Return(nil),
+					mockRepo.EXPECT().GetTenantByID(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme", DisabledAt: &disabledAt}, nil),
+				)
+			},
+		},
+		{
+			name:  "success enable tenant",
+			input: &dto.UpdateTenantRequest{Disabled: &enable},
+			configureMock: func(mockRepo *mocks.MockTenantRepository) {
+				gomock.InOrder(
+					mockRepo.EXPECT().GetTenantByID(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme", DisabledAt: &disabledAt}, nil),
+					mockRepo.EXPECT().UpdateTenant(gomock.Any(), "acme", map[string]any{"disabled_at": nil}).Return(nil),
+					mockRepo.EXPECT().GetTenantByID(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme"}, nil),
+				)
+			},
+		},
+		{
+			name:  "success already disabled tenant unchanged",
+			input: &dto.UpdateTenantRequest{Disabled: &disable},
+			configureMock: func(mockRepo *mocks.MockTenantRepository) {
+				mockRepo.EXPECT().GetTenantByID(gomock.Any(), "acme").Return(&entity.Tenant{ID: "acme", DisabledAt: &disabledAt}, nil)
+			},
+		},
+		{
+			name:  "error unknown tenant",
+			input: &dto.UpdateTenantRequest{Name: &name},
+			configureMock: func(mockRepo *mocks.MockTenantRepository) {
+				mockRepo.EXPECT().GetTenantByID(gomock.Any(), "acme").Return(nil, tenant.ErrNotFound)
+			},
+			expectedError: tenant.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := mocks.NewMockTenantRepository(ctrl)
+			test.configureMock(mockRepo)
+			service := tenant.NewTenantService(mockRepo, zerolog.Nop())
+
+			result, err := service.UpdateTenant(context.Background(), "acme", test.input)
+
+			assert.Equal(t, test.expectedError, err)
+			if test.expectedError == nil {
+				assert.Equal(t, "acme", result.ID)
+			}
+		})
+	}
+}
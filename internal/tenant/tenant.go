@@ -0,0 +1,30 @@
+package tenant
+
+import "context"
+
+type contextKey struct{}
+
+// allTenants marks a context opted out of the scope of Plugin.
+type allTenants struct{}
+
+// WithID scopes the queries run with ctx to the data of a tenant, see Plugin.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// AllTenants opts the queries run with ctx out of the scope of Plugin, for the background jobs
+// serving every tenant: they reach the data of every tenant, and the records they create keep
+// their TenantID. A later WithID scopes the context again.
+func AllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, allTenants{})
+}
+
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok && id != ""
+}
+
+func isAllTenants(ctx context.Context) bool {
+	_, ok := ctx.Value(contextKey{}).(allTenants)
+	return ok
+}
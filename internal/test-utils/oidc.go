@@ -0,0 +1,158 @@
+package testutils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// OIDCServer is a local OpenID provider serving its discovery document and its JWKS, to test
+// the oidc mode without Keycloak or Auth0. Its issuer is the URL of the server.
+type OIDCServer struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	keys         []oidcKey
+	jwksRequests int
+	cacheControl string
+}
+
+type oidcKey struct {
+	kid    string
+	signer crypto.Signer
+}
+
+// NewOIDCServer starts a provider signing with an RSA key, closed at the end of the test.
+func NewOIDCServer(t *testing.T) *OIDCServer {
+	t.Helper()
+
+	s := &OIDCServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]string{"issuer": s.URL, "jwks_uri": s.URL + "/jwks"})
+	})
+	mux.HandleFunc("GET /jwks", s.serveJWKS)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	s.RotateKey(key, false)
+
+	return s
+}
+
+// Issuer is the issuer of the tokens, to configure as AUTH_OIDC_ISSUER.
+func (s *OIDCServer) Issuer() string {
+	return s.URL
+}
+
+// RotateKey makes key, RSA or ECDSA, the signing key. The previous keys stay in the JWKS
+// when keepPrevious is set, as the providers do for the tokens already issued.
+func (s *OIDCServer) RotateKey(key crypto.Signer, keepPrevious bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := oidcKey{kid: "key-" + strconv.Itoa(len(s.keys)+1), signer: key}
+	if keepPrevious {
+		s.keys = append([]oidcKey{next}, s.keys...)
+	} else {
+		s.keys = []oidcKey{next}
+	}
+}
+
+// SetCacheControl sets the Cache-Control header of the JWKS.
+func (s *OIDCServer) SetCacheControl(value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cacheControl = value
+}
+
+// JWKSRequests counts the requests for the JWKS.
+func (s *OIDCServer) JWKSRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.jwksRequests
+}
+
+// Sign issues a token with the current signing key. The issuer is added when claims has none.
+func (s *OIDCServer) Sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	s.mu.Lock()
+	key := s.keys[0]
+	s.mu.Unlock()
+
+	if _, ok := claims["iss"]; !ok {
+		claims["iss"] = s.URL
+	}
+
+	method := jwt.SigningMethod(jwt.SigningMethodRS256)
+	if _, ok := key.signer.(*ecdsa.PrivateKey); ok {
+		method = jwt.SigningMethodES256
+	}
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = key.kid
+
+	signed, err := token.SignedString(key.signer)
+	require.NoError(t, err)
+	return signed
+}
+
+func (s *OIDCServer) serveJWKS(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jwksRequests++
+	keys := make([]map[string]string, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, jwk(key))
+	}
+	if s.cacheControl != "" {
+		w.Header().Set("Cache-Control", s.cacheControl)
+	}
+	writeJSON(w, map[string]any{"keys": keys})
+}
+
+func jwk(key oidcKey) map[string]string {
+	encode := base64.RawURLEncoding.EncodeToString
+
+	switch public := key.signer.Public().(type) {
+	case *rsa.PublicKey:
+		return map[string]string{
+			"kty": "RSA", "kid": key.kid, "use": "sig", "alg": "RS256",
+			"n": encode(public.N.Bytes()),
+			"e": encode(big.NewInt(int64(public.E)).Bytes()),
+		}
+	case *ecdsa.PublicKey:
+		point, _ := public.ECDH()
+		// The uncompressed point is 0x04 followed by the coordinates.
+		coordinates := point.Bytes()[1:]
+		return map[string]string{
+			"kty": "EC", "kid": key.kid, "use": "sig", "alg": "ES256", "crv": "P-256",
+			"x": encode(coordinates[:len(coordinates)/2]),
+			"y": encode(coordinates[len(coordinates)/2:]),
+		}
+	default:
+		panic("unsupported key type")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,78 @@
+package validator
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type languagesKey struct{}
+
+// Languages stores the languages of the Accept-Language header in the request context, for
+// FormatErrors to answer in the language of the caller.
+func Languages(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(WithLanguages(r.Context(), r.Header.Get("Accept-Language"))))
+	})
+}
+
+// WithLanguages returns a copy of ctx holding the languages of acceptLanguage, a value of the
+// Accept-Language header, by order of preference.
+func WithLanguages(ctx context.Context, acceptLanguage string) context.Context {
+	return context.WithValue(ctx, languagesKey{}, parseAcceptLanguage(acceptLanguage))
+}
+
+func languagesFrom(ctx context.Context) []string {
+	languages, _ := ctx.Value(languagesKey{}).([]string)
+	return languages
+}
+
+// parseAcceptLanguage lists the locales of value from the most to the least preferred, each
+// region being followed by its base language so fr-CA matches fr.
+func parseAcceptLanguage(value string) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+
+	var tags []weighted
+	for part := range strings.SplitSeq(value, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		tags = append(tags, weighted{tag: tag, quality: quality})
+	}
+
+	slices.SortStableFunc(tags, func(a, b weighted) int {
+		return cmp.Compare(b.quality, a.quality)
+	})
+
+	languages := make([]string, 0, len(tags))
+	for _, t := range tags {
+		locale := strings.ReplaceAll(t.tag, "-", "_")
+		languages = append(languages, locale)
+		if base, _, found := strings.Cut(locale, "_"); found {
+			languages = append(languages, base)
+		}
+	}
+
+	return languages
+}
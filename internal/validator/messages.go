@@ -0,0 +1,96 @@
+package validator
+
+const (
+	invalidKey = "invalid"
+
+	// itemsSuffix selects the wording of the length tags for slices and maps.
+	itemsSuffix = "-items"
+)
+
+// Messages holds the templates of a validation error per locale, {0} being the field and {1}
+// the parameter of the tag.
+type Messages map[string]string
+
+// messages holds the texts of the built-in tags of go-playground/validator.
+var messages = map[string]Messages{
+	invalidKey: {
+		"en": "{0} is invalid",
+		"fr": "{0} est invalide",
+	},
+	"required": {
+		"en": "{0} is required",
+		"fr": "{0} est obligatoire",
+	},
+	"email": {
+		"en": "{0} must be a valid email address",
+		"fr": "{0} doit être une adresse e-mail valide",
+	},
+	"min": {
+		"en": "{0} must be at least {1} characters",
+		"fr": "{0} doit contenir au moins {1} caractères",
+	},
+	"min" + itemsSuffix: {
+		"en": "{0} must contain at least {1} items",
+		"fr": "{0} doit contenir au moins {1} éléments",
+	},
+	"max": {
+		"en": "{0} must be at most {1} characters",
+		"fr": "{0} doit contenir au plus {1} caractères",
+	},
+	"max" + itemsSuffix: {
+		"en": "{0} must contain at most {1} items",
+		"fr": "{0} doit contenir au plus {1} éléments",
+	},
+	"len": {
+		"en": "{0} must be exactly {1} characters",
+		"fr": "{0} doit contenir exactement {1} caractères",
+	},
+	"len" + itemsSuffix: {
+		"en": "{0} must contain exactly {1} items",
+		"fr": "{0} doit contenir exactement {1} éléments",
+	},
+	"url": {
+		"en": "{0} must be a valid URL",
+		"fr": "{0} doit être une URL valide",
+	},
+	"uuid": {
+		"en": "{0} must be a valid UUID",
+		"fr": "{0} doit être un UUID valide",
+	},
+	"isbn": {
+		"en": "{0} must be a valid ISBN",
+		"fr": "{0} doit être un ISBN valide",
+	},
+	"alpha": {
+		"en": "{0} must contain only letters",
+		"fr": "{0} ne doit contenir que des lettres",
+	},
+	"alphanum": {
+		"en": "{0} must contain only letters and numbers",
+		"fr": "{0} ne doit contenir que des lettres et des chiffres",
+	},
+	"numeric": {
+		"en": "{0} must be a number",
+		"fr": "{0} doit être un nombre",
+	},
+	"oneof": {
+		"en": "{0} must be one of: {1}",
+		"fr": "{0} doit valoir l'une des valeurs : {1}",
+	},
+	"gt": {
+		"en": "{0} must be greater than {1}",
+		"fr": "{0} doit être supérieur à {1}",
+	},
+	"gte": {
+		"en": "{0} must be greater than or equal to {1}",
+		"fr": "{0} doit être supérieur ou égal à {1}",
+	},
+	"lt": {
+		"en": "{0} must be less than {1}",
+		"fr": "{0} doit être inférieur à {1}",
+	},
+	"lte": {
+		"en": "{0} must be less than or equal to {1}",
+		"fr": "{0} doit être inférieur ou égal à {1}",
+	},
+}
@@ -0,0 +1,96 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	htmlPattern = regexp.MustCompile(`<[a-zA-Z/!?][^>]*>`)
+)
+
+// rules are the custom tags available to every DTO.
+var rules = []struct {
+	tag      string
+	fn       validator.Func
+	messages Messages
+}{
+	{
+		tag: "slug",
+		fn: func(fl validator.FieldLevel) bool {
+			return slugPattern.MatchString(fl.Field().String())
+		},
+		messages: Messages{
+			"en": "{0} must contain only lowercase letters, numbers and hyphens",
+			"fr": "{0} ne doit contenir que des minuscules, des chiffres et des tirets",
+		},
+	},
+	{
+		tag: "no_html",
+		fn: func(fl validator.FieldLevel) bool {
+			return !htmlPattern.MatchString(fl.Field().String())
+		},
+		messages: Messages{
+			"en": "{0} must not contain HTML",
+			"fr": "{0} ne doit pas contenir de HTML",
+		},
+	},
+	{
+		tag: "notblank",
+		fn: func(fl validator.FieldLevel) bool {
+			return strings.TrimSpace(fl.Field().String()) != ""
+		},
+		messages: Messages{
+			"en": "{0} must not be blank",
+			"fr": "{0} ne doit pas être vide",
+		},
+	},
+}
+
+const dateRangeTag = "date_range"
+
+var dateRangeMessages = Messages{
+	"en": "{0} must be after {1}",
+	"fr": "{0} doit être postérieur à {1}",
+}
+
+// DateRange is a struct rule checking that the time.Time field end, when set, is after the
+// field start. The error is reported on end.
+func DateRange(start, end string) validator.StructLevelFunc {
+	return func(sl validator.StructLevel) {
+		current := sl.Current()
+		startTime, startSet := timeField(current, start)
+		endTime, endSet := timeField(current, end)
+		if !startSet || !endSet || endTime.After(startTime) {
+			return
+		}
+
+		endField, _ := current.Type().FieldByName(end)
+		startField, _ := current.Type().FieldByName(start)
+		sl.ReportError(current.FieldByName(end).Interface(), jsonName(endField), end, dateRangeTag, jsonName(startField))
+	}
+}
+
+// timeField reads the time.Time or *time.Time field name of v, reporting whether it is set.
+// It panics when the field is missing or of another type, as the rule is misconfigured.
+func timeField(v reflect.Value, name string) (time.Time, bool) {
+	if value := v.FieldByName(name); value.IsValid() {
+		switch t := value.Interface().(type) {
+		case time.Time:
+			return t, !t.IsZero()
+		case *time.Time:
+			if t == nil {
+				return time.Time{}, false
+			}
+			return *t, !t.IsZero()
+		}
+	}
+
+	panic(fmt.Sprintf("validator: %s.%s is not a time", v.Type(), name))
+}
@@ -1,21 +1,72 @@
 package validator
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/fr"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 
 	"go-boilerplate-rest-api-chi/internal/response"
 )
 
 type Validator struct {
-	validate *validator.Validate
+	validate   *validator.Validate
+	translator *ut.UniversalTranslator
 }
 
 func New() *Validator {
-	return &Validator{
-		validate: validator.New(),
+	validate := validator.New()
+	validate.RegisterTagNameFunc(jsonName)
+
+	v := &Validator{
+		validate:   validate,
+		translator: ut.New(en.New(), en.New(), fr.New()),
+	}
+
+	for tag, texts := range messages {
+		v.RegisterMessages(tag, texts)
+	}
+	for _, rule := range rules {
+		v.RegisterRule(rule.tag, rule.fn, rule.messages)
+	}
+	v.RegisterMessages(dateRangeTag, dateRangeMessages)
+
+	return v
+}
+
+// RegisterRule adds the custom tag to the validator, with the messages of its errors. The
+// domain packages call it at startup for their own rules; it panics when the tag cannot be
+// registered.
+func (v *Validator) RegisterRule(tag string, fn validator.Func, messages Messages) {
+	if err := v.validate.RegisterValidation(tag, fn); err != nil {
+		panic(err)
+	}
+	v.RegisterMessages(tag, messages)
+}
+
+// RegisterStructRule runs fn on every validation of the types, for the rules spanning several
+// fields. The tags fn reports need messages, see RegisterMessages.
+func (v *Validator) RegisterStructRule(fn validator.StructLevelFunc, types ...any) {
+	v.validate.RegisterStructValidation(fn, types...)
+}
+
+// RegisterMessages sets the messages of tag, replacing the existing ones. It panics on the
+// locales without translator.
+func (v *Validator) RegisterMessages(tag string, messages Messages) {
+	for locale, text := range messages {
+		trans, found := v.translator.GetTranslator(locale)
+		if !found {
+			panic(fmt.Sprintf("validator: unsupported locale %q", locale))
+		}
+		if err := trans.Add(tag, text, true); err != nil {
+			panic(err)
+		}
 	}
 }
 
@@ -23,15 +74,20 @@ func (v *Validator) Struct(s any) error {
 	return v.validate.Struct(s)
 }
 
-func (v *Validator) FormatErrors(err error) []response.ValidationErrorDetail {
+// FormatErrors lists the failed constraints of err, the fields being named by their JSON path
+// and the messages written in the language stored in ctx by WithLanguages, English otherwise.
+func (v *Validator) FormatErrors(ctx context.Context, err error) []response.ValidationErrorDetail {
 	var validationErrors []response.ValidationErrorDetail
 
 	var ve validator.ValidationErrors
 	if errors.As(err, &ve) {
+		trans, _ := v.translator.FindTranslator(languagesFrom(ctx)...)
 		for _, fe := range ve {
 			validationErrors = append(validationErrors, response.ValidationErrorDetail{
-				Field:   fe.Field(),
-				Message: getErrorMessage(fe),
+				Field:   fieldPath(fe),
+				Message: translate(trans, fe),
+				Tag:     fe.Tag(),
+				Param:   fe.Param(),
 			})
 		}
 	}
@@ -39,37 +95,44 @@ func (v *Validator) FormatErrors(err error) []response.ValidationErrorDetail {
 	return validationErrors
 }
 
-func getErrorMessage(fe validator.FieldError) string {
-	field := fe.Field()
-
-	switch fe.Tag() {
-	case "required":
-		return fmt.Sprintf("%s is required", field)
-	case "email":
-		return fmt.Sprintf("%s must be a valid email address", field)
-	case "min":
-		return fmt.Sprintf("%s must be at least %s characters", field, fe.Param())
-	case "max":
-		return fmt.Sprintf("%s must be at most %s characters", field, fe.Param())
-	case "url":
-		return fmt.Sprintf("%s must be a valid URL", field)
-	case "alpha":
-		return fmt.Sprintf("%s must contain only letters", field)
-	case "alphanum":
-		return fmt.Sprintf("%s must contain only letters and numbers", field)
-	case "numeric":
-		return fmt.Sprintf("%s must be a number", field)
-	case "len":
-		return fmt.Sprintf("%s must be exactly %s characters", field, fe.Param())
-	case "gt":
-		return fmt.Sprintf("%s must be greater than %s", field, fe.Param())
-	case "gte":
-		return fmt.Sprintf("%s must be greater than or equal to %s", field, fe.Param())
-	case "lt":
-		return fmt.Sprintf("%s must be less than %s", field, fe.Param())
-	case "lte":
-		return fmt.Sprintf("%s must be less than or equal to %s", field, fe.Param())
+// jsonName names the struct fields after their json tag, so the errors use the names sent by
+// the clients.
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
 	default:
-		return fmt.Sprintf("%s is invalid", field)
+		return name
+	}
+}
+
+// fieldPath is the namespace of fe without the name of the validated struct, such as
+// contributors[1].role.
+func fieldPath(fe validator.FieldError) string {
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+	return path
+}
+
+// translate writes the message of fe, preferring the wording for collections when the field
+// is one, and falling back to a generic message for the tags without translation.
+func translate(trans ut.Translator, fe validator.FieldError) string {
+	switch fe.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if message, err := trans.T(fe.Tag()+itemsSuffix, fe.Field(), fe.Param()); err == nil {
+			return message
+		}
 	}
+
+	if message, err := trans.T(fe.Tag(), fe.Field(), fe.Param()); err == nil {
+		return message
+	}
+
+	message, _ := trans.T(invalidKey, fe.Field())
+	return message
 }
@@ -1,11 +1,33 @@
 package validator_test
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	playground "github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+
+	"go-boilerplate-rest-api-chi/internal/response"
 	"go-boilerplate-rest-api-chi/internal/validator"
 )
 
+type contributor struct {
+	Name string `json:"name" validate:"required"`
+	Role string `json:"role" validate:"required,oneof=author translator"`
+}
+
+type publication struct {
+	Title        string        `json:"title" validate:"required,max=5"`
+	AuthorID     string        `json:"author_id" validate:"omitempty,uuid"`
+	Tags         []string      `json:"tags" validate:"min=1"`
+	Contributors []contributor `json:"contributors" validate:"dive"`
+	Internal     string        `json:"-" validate:"required"`
+}
+
 func TestNew(t *testing.T) {
 	t.Run("no error", func(t *testing.T) {
 		v := validator.New()
@@ -14,3 +36,233 @@ func TestNew(t *testing.T) {
 		}
 	})
 }
+
+func TestValidator_FormatErrors(t *testing.T) {
+	valid := publication{
+		Title:        "Ruy",
+		Tags:         []string{"drame"},
+		Contributors: []contributor{{Name: "Victor Hugo", Role: "author"}},
+		Internal:     "set",
+	}
+
+	tests := []struct {
+		name           string
+		input          func(p *publication)
+		acceptLanguage string
+		expectedErrors []response.ValidationErrorDetail
+	}{
+		{
+			name:           "success valid",
+			input:          func(*publication) {},
+			expectedErrors: nil,
+		},
+		{
+			name:  "error json names",
+			input: func(p *publication) { p.Title = ""; p.AuthorID = "hugo" },
+			expectedErrors: []response.ValidationErrorDetail{
+				{Field: "title", Message: "title is required", Tag: "required"},
+				{Field: "author_id", Message: "author_id must be a valid UUID", Tag: "uuid"},
+			},
+		},
+		{
+			name: "error nested array path",
+			input: func(p *publication) {
+				p.Contributors = append(p.Contributors, contributor{Name: "Jean-Claude", Role: "editor"})
+			},
+			expectedErrors: []response.ValidationErrorDetail{
+				{Field: "contributors[1].role", Message: "role must be one of: author translator", Tag: "oneof", Param: "author translator"},
+			},
+		},
+		{
+			name:  "error params and items",
+			input: func(p *publication) { p.Title = "Les Misérables"; p.Tags = []string{} },
+			expectedErrors: []response.ValidationErrorDetail{
+				{Field: "title", Message: "title must be at most 5 characters", Tag: "max", Param: "5"},
+				{Field: "tags", Message: "tags must contain at least 1 items", Tag: "min", Param: "1"},
+			},
+		},
+		{
+			name:  "error ignored json field keeps its go name",
+			input: func(p *publication) { p.Internal = "" },
+			expectedErrors: []response.ValidationErrorDetail{
+				{Field: "Internal", Message: "Internal is required", Tag: "required"},
+			},
+		},
+		{
+			name:           "error french",
+			input:          func(p *publication) { p.Title = ""; p.Tags = nil },
+			acceptLanguage: "fr",
+			expectedErrors: []response.ValidationErrorDetail{
+				{Field: "title", Message: "title est obligatoire", Tag: "required"},
+				{Field: "tags", Message: "tags doit contenir au moins 1 éléments", Tag: "min", Param: "1"},
+			},
+		},
+		{
+			name:           "error french region and weights",
+			input:          func(p *publication) { p.Title = "" },
+			acceptLanguage: "de;q=0.9, fr-CA, en;q=0.8",
+			expectedErrors: []response.ValidationErrorDetail{
+				{Field: "title", Message: "title est obligatoire", Tag: "required"},
+			},
+		},
+		{
+			name:           "error excluded language",
+			input:          func(p *publication) { p.Title = "" },
+			acceptLanguage: "fr;q=0, en;q=0.5",
+			expectedErrors: []response.ValidationErrorDetail{
+				{Field: "title", Message: "title is required", Tag: "required"},
+			},
+		},
+		{
+			name:           "error unsupported language falls back to english",
+			input:          func(p *publication) { p.Title = "" },
+			acceptLanguage: "ja",
+			expectedErrors: []response.ValidationErrorDetail{
+				{Field: "title", Message: "title is required", Tag: "required"},
+			},
+		},
+	}
+
+	v := validator.New()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := valid
+			input.Contributors = append([]contributor(nil), valid.Contributors...)
+			test.input(&input)
+
+			ctx := validator.WithLanguages(context.Background(), test.acceptLanguage)
+			errs := v.FormatErrors(ctx, v.Struct(&input))
+
+			assert.Equal(t, test.expectedErrors, errs)
+		})
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	v := validator.New()
+	err := v.Struct(&contributor{Role: "author"})
+
+	var errs []response.ValidationErrorDetail
+	handler := validator.Languages(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		errs = v.FormatErrors(r.Context(), err)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Accept-Language", "fr-FR,fr;q=0.9")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, []response.ValidationErrorDetail{{Field: "name", Message: "name est obligatoire", Tag: "required"}}, errs)
+}
+
+type edition struct {
+	Slug      string     `json:"slug" validate:"omitempty,slug"`
+	Summary   string     `json:"summary" validate:"omitempty,notblank,no_html"`
+	ISBN      string     `json:"isbn" validate:"omitempty,isbn"`
+	Publisher string     `json:"publisher" validate:"omitempty,publisher"`
+	StartsAt  time.Time  `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at"`
+}
+
+func TestValidator_Rules(t *testing.T) {
+	starts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := starts.Add(-time.Hour)
+	after := starts.Add(time.Hour)
+
+	tests := []struct {
+		name           string
+		input          edition
+		acceptLanguage string
+		expectedErrors []response.ValidationErrorDetail
+	}{
+		{
+			name:           "success valid",
+			input:          edition{Slug: "les-miserables", Summary: "Jean Valjean", ISBN: "978-2-07-040850-4", Publisher: "gallimard", StartsAt: starts, EndsAt: &after},
+			expectedErrors: nil,
+		},
+		{
+			name:           "success open range",
+			input:          edition{EndsAt: &before},
+			expectedErrors: nil,
+		},
+		{
+			name:  "error slug",
+			input: edition{Slug: "Les Misérables"},
+			expectedErrors: []response.ValidationErrorDetail{
+				{Field: "slug", Message: "slug must contain only lowercase letters, numbers and hyphens", Tag: "slug"},
+			},
+		},
+		{
+			name:  "error blank",
+			input: edition{Summary: "   "},
+			expectedErrors: []response.ValidationErrorDetail{
+				{Field: "summary", Message: "summary must not be blank", Tag: "notblank"},
+			},
+		},
+		{
+			name:  "error html",
+			input: edition{Summary: `Jean <script>alert("Valjean")</script>`},
+			expectedErrors: []response.ValidationErrorDetail{
+				{Field: "summary", Message: "summary must not contain HTML", Tag: "no_html"},
+			},
+		},
+		{
+			name:  "error isbn",
+			input: edition{ISBN: "978-2-07-040850-5"},
+			expectedErrors: []response.ValidationErrorDetail{
+				{Field: "isbn", Message: "isbn must be a valid ISBN", Tag: "isbn"},
+			},
+		},
+		{
+			name:           "error registered rule",
+			input:          edition{Publisher: "Gallimard"},
+			acceptLanguage: "fr",
+			expectedErrors: []response.ValidationErrorDetail{
+				{Field: "publisher", Message: "publisher doit être en minuscules", Tag: "publisher"},
+			},
+		},
+		{
+			name:  "error date range",
+			input: edition{StartsAt: starts, EndsAt: &before},
+			expectedErrors: []response.ValidationErrorDetail{
+				{Field: "ends_at", Message: "ends_at must be after starts_at", Tag: "date_range", Param: "starts_at"},
+			},
+		},
+		{
+			name:           "error date range french",
+			input:          edition{StartsAt: starts, EndsAt: &starts},
+			acceptLanguage: "fr",
+			expectedErrors: []response.ValidationErrorDetail{
+				{Field: "ends_at", Message: "ends_at doit être postérieur à starts_at", Tag: "date_range", Param: "starts_at"},
+			},
+		},
+	}
+
+	v := validator.New()
+	v.RegisterRule("publisher", func(fl playground.FieldLevel) bool {
+		return fl.Field().String() == strings.ToLower(fl.Field().String())
+	}, validator.Messages{
+		"en": "{0} must be lowercase",
+		"fr": "{0} doit être en minuscules",
+	})
+	v.RegisterStructRule(validator.DateRange("StartsAt", "EndsAt"), edition{})
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := validator.WithLanguages(context.Background(), test.acceptLanguage)
+			errs := v.FormatErrors(ctx, v.Struct(&test.input))
+
+			assert.Equal(t, test.expectedErrors, errs)
+		})
+	}
+}
+
+func TestValidator_RegisterMessages(t *testing.T) {
+	v := validator.New()
+
+	assert.Panics(t, func() { v.RegisterMessages("required", validator.Messages{"de": "{0} ist erforderlich"}) })
+	assert.Panics(t, func() { v.RegisterRule("", func(playground.FieldLevel) bool { return true }, nil) })
+
+	v.RegisterMessages("required", validator.Messages{"en": "{0} is mandatory"})
+	errs := v.FormatErrors(context.Background(), v.Struct(&contributor{Role: "author"}))
+	assert.Equal(t, []response.ValidationErrorDetail{{Field: "name", Message: "name is mandatory", Tag: "required"}}, errs)
+}
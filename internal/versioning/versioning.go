@@ -0,0 +1,60 @@
+package versioning
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+// Header selects the version of the routes requested without a version in their path. It is
+// also set on the responses of the versioned routes.
+const Header = "API-Version"
+
+// Select serves the requests with the handler of the version named in Header, fallback when
+// the header is missing. Unknown versions are rejected.
+func Select(versions map[string]http.Handler, fallback string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := r.Header.Get(Header)
+		if version == "" {
+			version = fallback
+		}
+
+		handler, ok := versions[version]
+		if !ok {
+			response.Error(w, http.StatusBadRequest, fmt.Sprintf("Unsupported API version %q", version))
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// Version tags the responses with the version serving them.
+func Version(version string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(Header, version)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Deprecate announces the deprecation (RFC 9745) and the removal (RFC 8594) of a version, and
+// links to its successor. Zero times are left out.
+func Deprecate(deprecatedAt, sunsetAt time.Time, successor string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !deprecatedAt.IsZero() {
+				w.Header().Set("Deprecation", fmt.Sprintf("@%d", deprecatedAt.Unix()))
+			}
+			if !sunsetAt.IsZero() {
+				w.Header().Set("Sunset", sunsetAt.UTC().Format(http.TimeFormat))
+			}
+			w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
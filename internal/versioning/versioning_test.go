@@ -0,0 +1,82 @@
+package versioning_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go-boilerplate-rest-api-chi/internal/versioning"
+)
+
+func named(name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(name))
+	})
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		name               string
+		version            string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "success default version",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "v1",
+		},
+		{
+			name:               "success version from header",
+			version:            "2",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "v2",
+		},
+		{
+			name:               "error unknown version",
+			version:            "3",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"status":"error","message":"Unsupported API version \"3\""}` + "\n",
+		},
+	}
+
+	handler := versioning.Select(map[string]http.Handler{"1": named("v1"), "2": named("v2")}, "1")
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/books", nil)
+			if test.version != "" {
+				req.Header.Set(versioning.Header, test.version)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expectedStatusCode, rec.Code)
+			assert.Equal(t, test.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestDeprecate(t *testing.T) {
+	deprecatedAt := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	sunsetAt := time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	handler := versioning.Version("1")(versioning.Deprecate(deprecatedAt, sunsetAt, "/api/v2")(named("v1")))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/books", nil))
+
+	assert.Equal(t, "1", rec.Header().Get(versioning.Header))
+	assert.Equal(t, "@1793491200", rec.Header().Get("Deprecation"))
+	assert.Equal(t, "Sat, 01 May 2027 00:00:00 GMT", rec.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v2>; rel="successor-version"`, rec.Header().Get("Link"))
+
+	rec = httptest.NewRecorder()
+	versioning.Deprecate(time.Time{}, time.Time{}, "/api/v2")(named("v1")).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/books", nil))
+
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+	assert.Empty(t, rec.Header().Get("Sunset"))
+}
@@ -0,0 +1,259 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/retry"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+)
+
+const (
+	defaultMaxAttempts  = 8
+	defaultBaseDelay    = 10 * time.Second
+	defaultMaxDelay     = time.Hour
+	defaultTimeout      = 10 * time.Second
+	defaultPollInterval = 5 * time.Second
+
+	dueBatchSize = 50
+	// maxErrorLength bounds the receiver response kept in LastError.
+	maxErrorLength = 512
+)
+
+// Dispatcher is the event.Publisher that fans events out to the matching subscriptions.
+// Deliveries are persisted before being sent, so the pending ones survive a restart and
+// are picked up again by Run.
+type Dispatcher struct {
+	repository WebhookRepository
+	client     *http.Client
+	cfg        config.WebhookConfig
+	logger     zerolog.Logger
+	wake       chan struct{}
+	now        func() time.Time
+}
+
+// NewDispatcher uses an http.Client with cfg.Timeout, refusing to dial the loopback, private
+// and link-local addresses, when client is nil. Zero values of cfg fall back to the defaults
+// of config.WebhookConfig.
+func NewDispatcher(repository WebhookRepository, client *http.Client, cfg config.WebhookConfig, logger zerolog.Logger) *Dispatcher {
+	cfg = withDefaults(cfg)
+
+	if client == nil {
+		client = newClient(cfg.Timeout)
+	}
+
+	return &Dispatcher{
+		repository: repository,
+		client:     client,
+		cfg:        cfg,
+		logger:     logger,
+		wake:       make(chan struct{}, 1),
+		now:        time.Now,
+	}
+}
+
+// Publish queues a delivery of e for every matching subscription of its tenant.
+func (d *Dispatcher) Publish(ctx context.Context, e event.Event) error {
+	if e.Tenant == "" {
+		return nil
+	}
+	ctx = tenant.WithID(ctx, e.Tenant)
+
+	subscriptions, err := d.repository.GetActiveSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []*entity.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if !subscribed(subscription, e.Type) {
+			continue
+		}
+
+		deliveries = append(deliveries, &entity.WebhookDelivery{
+			TenantID:       subscription.TenantID,
+			SubscriptionID: subscription.ID,
+			EventID:        e.ID,
+			EventType:      string(e.Type),
+			Payload:        payload,
+			Status:         entity.WebhookDeliveryPending,
+			NextAttemptAt:  d.now(),
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := d.repository.CreateDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+
+	d.Wake()
+	return nil
+}
+
+// Wake makes Run look for due deliveries without waiting for the next poll.
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends the due deliveries until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			d.logger.Error().Err(err).Msg("failed to deliver webhooks")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// DeliverDue makes one attempt for every pending delivery whose next attempt is due, whatever
+// its tenant.
+func (d *Dispatcher) DeliverDue(ctx context.Context) error {
+	ctx = tenant.AllTenants(ctx)
+
+	deliveries, err := d.repository.GetDueDeliveries(ctx, d.now(), dueBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// The lease covers the request timeout, after which another replica may retry.
+		claimed, err := d.repository.ClaimDelivery(ctx, delivery, d.now().Add(d.cfg.Timeout+d.cfg.BaseDelay))
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := d.Deliver(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Deliver makes one attempt and records its outcome: succeeded, pending with the next
+// attempt scheduled, or dead once MaxAttempts is reached or when the target is refused.
+func (d *Dispatcher) Deliver(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	ctx = tenant.WithID(ctx, delivery.TenantID)
+	statusCode, sendErr := d.send(ctx, delivery)
+
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = ""
+
+	switch {
+	case sendErr == nil:
+		delivery.Status = entity.WebhookDeliverySucceeded
+	case delivery.Attempts >= d.cfg.MaxAttempts, errors.Is(sendErr, ErrForbiddenTarget):
+		delivery.Status = entity.WebhookDeliveryDead
+		delivery.LastError = sendErr.Error()
+		d.logger.Warn().Err(sendErr).Str("delivery_id", delivery.ID.String()).Msg("webhook delivery moved to the dead-letter queue")
+	default:
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = d.now().Add(retry.Backoff(delivery.Attempts, d.cfg.BaseDelay, d.cfg.MaxDelay))
+	}
+
+	return d.repository.UpdateDelivery(ctx, delivery)
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *entity.WebhookDelivery) (int, error) {
+	if delivery.Subscription == nil {
+		return 0, ErrSubscriptionNotFound
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := d.now()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "go-boilerplate-rest-api-chi-webhooks")
+	request.Header.Set(HeaderID, delivery.ID.String())
+	request.Header.Set(HeaderEvent, delivery.EventType)
+	request.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp.Unix(), 10))
+	request.Header.Set(HeaderSignature, Sign(delivery.Subscription.Secret, timestamp, delivery.Payload))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorLength))
+		return response.StatusCode, fmt.Errorf("receiver answered %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	_, _ = io.Copy(io.Discard, response.Body)
+	return response.StatusCode, nil
+}
+
+func withDefaults(cfg config.WebhookConfig) config.WebhookConfig {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = defaultBaseDelay
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = defaultMaxDelay
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+
+	return cfg
+}
+
+func subscribed(subscription *entity.WebhookSubscription, eventType event.Type) bool {
+	for _, pattern := range strings.Split(subscription.EventTypes, ",") {
+		if event.Match(pattern, eventType) {
+			return true
+		}
+	}
+
+	return false
+}
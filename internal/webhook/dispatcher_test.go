@@ -0,0 +1,208 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	"go-boilerplate-rest-api-chi/internal/webhook"
+)
+
+func TestDispatcher_Publish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	bookSubscription := &entity.WebhookSubscription{ID: uuid.New(), TenantID: "acme", EventTypes: "book.created,book.deleted"}
+	authorSubscription := &entity.WebhookSubscription{ID: uuid.New(), TenantID: "acme", EventTypes: "author.*"}
+
+	mockRepo := mocks.NewMockWebhookRepository(ctrl)
+	// Only the subscriptions of the tenant of the event are read.
+	mockRepo.EXPECT().
+		GetActiveSubscriptions(gomock.Cond(func(ctx context.Context) bool {
+			tenantID, _ := tenant.FromContext(ctx)
+			return tenantID == "acme"
+		})).
+		Return([]*entity.WebhookSubscription{bookSubscription, authorSubscription}, nil)
+	mockRepo.EXPECT().
+		CreateDeliveries(gomock.Any(), gomock.Len(1)).
+		DoAndReturn(func(_ context.Context, deliveries []*entity.WebhookDelivery) error {
+			assert.Equal(t, authorSubscription.ID, deliveries[0].SubscriptionID)
+			assert.Equal(t, "acme", deliveries[0].TenantID)
+			assert.Equal(t, "author.created", deliveries[0].EventType)
+			assert.Equal(t, entity.WebhookDeliveryPending, deliveries[0].Status)
+			assert.JSONEq(t, `{"name":"Victor Hugo"}`, string(mustData(t, deliveries[0].Payload)))
+			return nil
+		})
+
+	dispatcher := webhook.NewDispatcher(mockRepo, nil, config.WebhookConfig{}, zerolog.Nop())
+
+	e := event.New(event.AuthorCreated, uuid.New(), map[string]string{"name": "Victor Hugo"})
+	e.Tenant = "acme"
+
+	err := dispatcher.Publish(context.Background(), e)
+
+	require.NoError(t, err)
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	cfg := config.WebhookConfig{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour, Timeout: time.Second}
+
+	tests := []struct {
+		name               string
+		receiverStatusCode int
+		attempts           int
+		expectedStatus     entity.WebhookDeliveryStatus
+		expectedError      string
+	}{
+		{
+			name:               "success delivered",
+			receiverStatusCode: http.StatusNoContent,
+			expectedStatus:     entity.WebhookDeliverySucceeded,
+		},
+		{
+			name:               "error receiver failure is retried",
+			receiverStatusCode: http.StatusInternalServerError,
+			expectedStatus:     entity.WebhookDeliveryPending,
+			expectedError:      "receiver answered 500: boom",
+		},
+		{
+			name:               "error last attempt goes to the dead-letter queue",
+			receiverStatusCode: http.StatusInternalServerError,
+			attempts:           2,
+			expectedStatus:     entity.WebhookDeliveryDead,
+			expectedError:      "receiver answered 500: boom",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			payload := []byte(`{"type":"book.created"}`)
+
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+
+				assert.Equal(t, "book.created", r.Header.Get(webhook.HeaderEvent))
+				assert.NoError(t, webhook.Verify("s3cr3t", r.Header.Get(webhook.HeaderTimestamp), r.Header.Get(webhook.HeaderSignature), body, time.Minute))
+
+				w.WriteHeader(test.receiverStatusCode)
+				if test.receiverStatusCode >= 300 {
+					_, _ = w.Write([]byte("boom"))
+				}
+			}))
+			t.Cleanup(receiver.Close)
+
+			delivery := &entity.WebhookDelivery{
+				ID:            uuid.New(),
+				EventType:     "book.created",
+				Payload:       payload,
+				Status:        entity.WebhookDeliveryPending,
+				Attempts:      test.attempts,
+				NextAttemptAt: time.Now(),
+				Subscription:  &entity.WebhookSubscription{URL: receiver.URL, Secret: "s3cr3t"},
+			}
+
+			mockRepo := mocks.NewMockWebhookRepository(ctrl)
+			mockRepo.EXPECT().UpdateDelivery(gomock.Any(), delivery).Return(nil)
+
+			dispatcher := webhook.NewDispatcher(mockRepo, receiver.Client(), cfg, zerolog.Nop())
+
+			err := dispatcher.Deliver(context.Background(), delivery)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedStatus, delivery.Status)
+			assert.Equal(t, test.attempts+1, delivery.Attempts)
+			assert.Equal(t, test.receiverStatusCode, delivery.LastStatusCode)
+			assert.Equal(t, test.expectedError, delivery.LastError)
+			if test.expectedStatus == entity.WebhookDeliveryPending {
+				assert.True(t, delivery.NextAttemptAt.After(time.Now().Add(cfg.BaseDelay/2-time.Second)))
+			}
+		})
+	}
+}
+
+func TestDispatcher_DeliverDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	received := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(receiver.Close)
+
+	subscription := &entity.WebhookSubscription{URL: receiver.URL, Secret: "s3cr3t"}
+	claimed := &entity.WebhookDelivery{ID: uuid.New(), Subscription: subscription}
+	takenByAnotherReplica := &entity.WebhookDelivery{ID: uuid.New(), Subscription: subscription}
+
+	mockRepo := mocks.NewMockWebhookRepository(ctrl)
+	mockRepo.EXPECT().
+		GetDueDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]*entity.WebhookDelivery{claimed, takenByAnotherReplica}, nil)
+	mockRepo.EXPECT().ClaimDelivery(gomock.Any(), claimed, gomock.Any()).Return(true, nil)
+	mockRepo.EXPECT().ClaimDelivery(gomock.Any(), takenByAnotherReplica, gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().UpdateDelivery(gomock.Any(), claimed).Return(nil)
+
+	dispatcher := webhook.NewDispatcher(mockRepo, receiver.Client(), config.WebhookConfig{}, zerolog.Nop())
+
+	err := dispatcher.DeliverDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, received)
+	assert.Equal(t, entity.WebhookDeliverySucceeded, claimed.Status)
+}
+
+func TestDispatcher_Deliver_PrivateTarget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	received := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+	}))
+	t.Cleanup(receiver.Close)
+
+	// The subscriptions created before the validation, or whose host name resolves to a
+	// private address, are refused when dialed.
+	delivery := &entity.WebhookDelivery{ID: uuid.New(), Subscription: &entity.WebhookSubscription{URL: receiver.URL, Secret: "s3cr3t"}}
+
+	mockRepo := mocks.NewMockWebhookRepository(ctrl)
+	mockRepo.EXPECT().UpdateDelivery(gomock.Any(), delivery).Return(nil)
+
+	dispatcher := webhook.NewDispatcher(mockRepo, nil, config.WebhookConfig{}, zerolog.Nop())
+
+	require.NoError(t, dispatcher.Deliver(context.Background(), delivery))
+	assert.False(t, received)
+	assert.Equal(t, entity.WebhookDeliveryDead, delivery.Status, "a refused target is not retried")
+	assert.Contains(t, delivery.LastError, webhook.ErrForbiddenTarget.Error())
+}
+
+func mustData(t *testing.T, payload []byte) []byte {
+	t.Helper()
+
+	var e struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(payload, &e))
+
+	return e.Data
+}
@@ -0,0 +1,13 @@
+package dto
+
+type CreateSubscriptionRequest struct {
+	URL        string   `json:"url" validate:"required,url,webhook_url" example:"https://example.com/hooks/library"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,required,event_pattern" example:"book.created,author.*"`
+	// Secret signs the payloads. A random one is generated when it is left empty.
+	Secret string `json:"secret" validate:"omitempty,min=16"`
+}
+
+type DeliveryFilter struct {
+	Status         string `json:"status" validate:"omitempty,oneof=pending succeeded dead"`
+	SubscriptionID string `json:"subscription_id" validate:"omitempty,uuid"`
+}
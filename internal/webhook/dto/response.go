@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+)
+
+type SubscriptionResponse struct {
+	ID         string    `json:"id" xml:"id" example:"6f0b8a5e-3b7e-4c55-9f0e-8b1c2d3e4f50"`
+	URL        string    `json:"url" xml:"url" example:"https://example.com/hooks/library"`
+	EventTypes []string  `json:"event_types" xml:"event_types>event_type" example:"book.created,author.*"`
+	Active     bool      `json:"active" xml:"active" example:"true"`
+	Secret     string    `json:"secret,omitempty" xml:"secret,omitempty"`
+	CreatedAt  time.Time `json:"created_at" xml:"created_at"`
+}
+
+// ToSubscriptionResponse leaves the secret out; it is only returned once, on creation.
+func ToSubscriptionResponse(subscription *entity.WebhookSubscription) *SubscriptionResponse {
+	return &SubscriptionResponse{
+		ID:         subscription.ID.String(),
+		URL:        subscription.URL,
+		EventTypes: strings.Split(subscription.EventTypes, ","),
+		Active:     subscription.Active,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+type DeliveryResponse struct {
+	ID             string    `json:"id" xml:"id" example:"0d6f1b8e-8e0a-4a43-9a55-2f1c8b7b3e42"`
+	SubscriptionID string    `json:"subscription_id" xml:"subscription_id" example:"6f0b8a5e-3b7e-4c55-9f0e-8b1c2d3e4f50"`
+	EventID        string    `json:"event_id" xml:"event_id" example:"5c1e8a2b-7d4f-4e3a-9b6c-1a2b3c4d5e6f"`
+	EventType      string    `json:"event_type" xml:"event_type" example:"book.created"`
+	Status         string    `json:"status" xml:"status" example:"pending"`
+	Attempts       int       `json:"attempts" xml:"attempts" example:"1"`
+	NextAttemptAt  time.Time `json:"next_attempt_at" xml:"next_attempt_at"`
+	LastStatusCode int       `json:"last_status_code,omitempty" xml:"last_status_code,omitempty" example:"500"`
+	LastError      string    `json:"last_error,omitempty" xml:"last_error,omitempty"`
+	CreatedAt      time.Time `json:"created_at" xml:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" xml:"updated_at"`
+}
+
+func ToDeliveryResponse(delivery *entity.WebhookDelivery) *DeliveryResponse {
+	return &DeliveryResponse{
+		ID:             delivery.ID.String(),
+		SubscriptionID: delivery.SubscriptionID.String(),
+		EventID:        delivery.EventID.String(),
+		EventType:      delivery.EventType,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+	}
+}
@@ -0,0 +1,12 @@
+package webhook
+
+import "errors"
+
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrUnknownEventType     = errors.New("unknown event type")
+	// ErrForbiddenTarget refuses the deliveries to the loopback, private and link-local
+	// addresses.
+	ErrForbiddenTarget = errors.New("webhook target address not allowed")
+)
@@ -0,0 +1,324 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/request"
+	"go-boilerplate-rest-api-chi/internal/response"
+	internalValidator "go-boilerplate-rest-api-chi/internal/validator"
+	"go-boilerplate-rest-api-chi/internal/webhook/dto"
+)
+
+// AdminScope grants the management of the webhook endpoints and of their deliveries.
+const AdminScope = "webhooks:admin"
+
+type SubscriptionSuccessResponse struct {
+	Status       string                    `json:"status" xml:"status" example:"success"`
+	Message      string                    `json:"message" xml:"message" example:"Webhook subscription created successfully"`
+	Subscription *dto.SubscriptionResponse `json:"subscription" xml:"subscription"`
+}
+
+type SubscriptionsSuccessResponse struct {
+	Status        string                     `json:"status" xml:"status" example:"success"`
+	Message       string                     `json:"message" xml:"message" example:"Webhook subscriptions retrieved successfully"`
+	Subscriptions []dto.SubscriptionResponse `json:"subscriptions" xml:"subscriptions>subscription"`
+}
+
+type DeliverySuccessResponse struct {
+	Status   string                `json:"status" xml:"status" example:"success"`
+	Message  string                `json:"message" xml:"message" example:"Webhook delivery retrieved successfully"`
+	Delivery *dto.DeliveryResponse `json:"delivery" xml:"delivery"`
+}
+
+type DeliveriesSuccessResponse struct {
+	Status     string                 `json:"status" xml:"status" example:"success"`
+	Message    string                 `json:"message" xml:"message" example:"Webhook deliveries retrieved successfully"`
+	Deliveries []dto.DeliveryResponse `json:"deliveries" xml:"deliveries>delivery"`
+}
+
+type WebhookHandler struct {
+	service   WebhookService
+	validator *internalValidator.Validator
+	logger    zerolog.Logger
+}
+
+func NewWebhookHandler(service WebhookService, validator *internalValidator.Validator, logger zerolog.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *WebhookHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Use(auth.RequireScope(AdminScope))
+
+	// routes
+	r.With(response.Acceptable(SubscriptionSuccessResponse{})).Post("/subscriptions", h.CreateSubscription)
+	r.Get("/subscriptions", h.GetSubscriptions)
+	r.Delete("/subscriptions/{subscription_id}", h.DeleteSubscription)
+	r.Get("/deliveries", h.GetDeliveries)
+	r.Get("/deliveries/{delivery_id}", h.GetDeliveryByID)
+	r.With(response.Acceptable(DeliverySuccessResponse{})).Post("/deliveries/{delivery_id}/replay", h.ReplayDelivery)
+
+	return r
+}
+
+// CreateSubscription godoc
+//
+//	@Summary		Register a webhook endpoint
+//	@Description	Subscribe an endpoint to event types (book.created, book.updated, book.deleted, author.created, author.renamed, or wildcards such as author.*). Payloads are signed with HMAC-SHA256 over "<X-Webhook-Timestamp>.<body>" in X-Webhook-Signature. The secret is only returned here. Requires the webhooks:admin scope.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Param			subscription	body		dto.CreateSubscriptionRequest	true	"Subscription data"
+//	@Success		201				{object}	SubscriptionSuccessResponse
+//	@Failure		400				{object}	response.ValidationErrorResponse
+//	@Failure		401				{object}	response.ErrorResponse
+//	@Failure		403				{object}	response.ErrorResponse
+//	@Failure		413				{object}	response.ValidationErrorResponse
+//	@Failure		415				{object}	response.ValidationErrorResponse
+//	@Failure		500				{object}	response.ErrorResponse
+//	@Security		ApiKeyAuth
+//	@Router			/webhooks/subscriptions [post]
+func (h *WebhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateSubscriptionRequest
+
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.validator.FormatErrors(r.Context(), err)
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	subscription, err := h.service.CreateSubscription(r.Context(), &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	subscriptionResponse := dto.ToSubscriptionResponse(subscription)
+	subscriptionResponse.Secret = subscription.Secret
+
+	response.Render(w, r, http.StatusCreated, SubscriptionSuccessResponse{
+		Status:       "success",
+		Message:      "Webhook subscription created successfully",
+		Subscription: subscriptionResponse,
+	})
+}
+
+// GetSubscriptions godoc
+//
+//	@Summary		List webhook subscriptions
+//	@Description	Get every registered webhook endpoint, without their secrets. Requires the webhooks:admin scope.
+//	@Tags			webhooks
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Success		200	{object}	SubscriptionsSuccessResponse
+//	@Failure		401	{object}	response.ErrorResponse
+//	@Failure		403	{object}	response.ErrorResponse
+//	@Failure		500	{object}	response.ErrorResponse
+//	@Security		ApiKeyAuth
+//	@Router			/webhooks/subscriptions [get]
+func (h *WebhookHandler) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.service.GetSubscriptions(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	subscriptionsResponse := make([]dto.SubscriptionResponse, len(subscriptions))
+	for i, subscription := range subscriptions {
+		subscriptionsResponse[i] = *dto.ToSubscriptionResponse(subscription)
+	}
+
+	response.Render(w, r, http.StatusOK, SubscriptionsSuccessResponse{
+		Status:        "success",
+		Message:       "Webhook subscriptions retrieved successfully",
+		Subscriptions: subscriptionsResponse,
+	})
+}
+
+// DeleteSubscription godoc
+//
+//	@Summary		Delete a webhook subscription
+//	@Description	Delete a webhook endpoint and its delivery history. Requires the webhooks:admin scope.
+//	@Tags			webhooks
+//	@Produce		json
+//	@Param			subscription_id	path		string	true	"Subscription ID"	format(uuid)
+//	@Success		200				{object}	response.SuccessResponse
+//	@Failure		400				{object}	response.ErrorResponse
+//	@Failure		401				{object}	response.ErrorResponse
+//	@Failure		403				{object}	response.ErrorResponse
+//	@Failure		404				{object}	response.ErrorResponse
+//	@Failure		500				{object}	response.ErrorResponse
+//	@Security		ApiKeyAuth
+//	@Router			/webhooks/subscriptions/{subscription_id} [delete]
+func (h *WebhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := uuid.Parse(chi.URLParam(r, "subscription_id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid uuid")
+		return
+	}
+
+	if err := h.service.DeleteSubscription(r.Context(), subscriptionID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, "Webhook subscription deleted successfully")
+}
+
+// GetDeliveries godoc
+//
+//	@Summary		List webhook deliveries
+//	@Description	Get the delivery history, newest first. Use status=dead to read the dead-letter queue. Requires the webhooks:admin scope.
+//	@Tags			webhooks
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Param			status			query		string	false	"Delivery status"	Enums(pending, succeeded, dead)
+//	@Param			subscription_id	query		string	false	"Subscription ID"	format(uuid)
+//	@Success		200				{object}	DeliveriesSuccessResponse
+//	@Failure		400				{object}	response.ValidationErrorResponse
+//	@Failure		401				{object}	response.ErrorResponse
+//	@Failure		403				{object}	response.ErrorResponse
+//	@Failure		500				{object}	response.ErrorResponse
+//	@Security		ApiKeyAuth
+//	@Router			/webhooks/deliveries [get]
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := &dto.DeliveryFilter{
+		Status:         query.Get("status"),
+		SubscriptionID: query.Get("subscription_id"),
+	}
+
+	if err := h.validator.Struct(filter); err != nil {
+		validationErrors := h.validator.FormatErrors(r.Context(), err)
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	deliveries, err := h.service.GetDeliveries(r.Context(), filter)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	deliveriesResponse := make([]dto.DeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		deliveriesResponse[i] = *dto.ToDeliveryResponse(delivery)
+	}
+
+	response.Render(w, r, http.StatusOK, DeliveriesSuccessResponse{
+		Status:     "success",
+		Message:    "Webhook deliveries retrieved successfully",
+		Deliveries: deliveriesResponse,
+	})
+}
+
+// GetDeliveryByID godoc
+//
+//	@Summary		Get a webhook delivery
+//	@Description	Get a single delivery with its attempts and last error. Requires the webhooks:admin scope.
+//	@Tags			webhooks
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Param			delivery_id	path		string	true	"Delivery ID"	format(uuid)
+//	@Success		200			{object}	DeliverySuccessResponse
+//	@Failure		400			{object}	response.ErrorResponse
+//	@Failure		401			{object}	response.ErrorResponse
+//	@Failure		403			{object}	response.ErrorResponse
+//	@Failure		404			{object}	response.ErrorResponse
+//	@Failure		500			{object}	response.ErrorResponse
+//	@Security		ApiKeyAuth
+//	@Router			/webhooks/deliveries/{delivery_id} [get]
+func (h *WebhookHandler) GetDeliveryByID(w http.ResponseWriter, r *http.Request) {
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "delivery_id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid uuid")
+		return
+	}
+
+	delivery, err := h.service.GetDeliveryByID(r.Context(), deliveryID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Render(w, r, http.StatusOK, DeliverySuccessResponse{
+		Status:   "success",
+		Message:  "Webhook delivery retrieved successfully",
+		Delivery: dto.ToDeliveryResponse(delivery),
+	})
+}
+
+// ReplayDelivery godoc
+//
+//	@Summary		Replay a webhook delivery
+//	@Description	Queue the payload of a delivery again, for instance one from the dead-letter queue. Requires the webhooks:admin scope.
+//	@Tags			webhooks
+//	@Produce		json
+//	@Produce		xml
+//	@Produce		application/msgpack
+//	@Param			delivery_id	path		string	true	"Delivery ID"	format(uuid)
+//	@Success		202			{object}	DeliverySuccessResponse
+//	@Failure		400			{object}	response.ErrorResponse
+//	@Failure		401			{object}	response.ErrorResponse
+//	@Failure		403			{object}	response.ErrorResponse
+//	@Failure		404			{object}	response.ErrorResponse
+//	@Failure		500			{object}	response.ErrorResponse
+//	@Security		ApiKeyAuth
+//	@Router			/webhooks/deliveries/{delivery_id}/replay [post]
+func (h *WebhookHandler) ReplayDelivery(w http.ResponseWriter, r *http.Request) {
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "delivery_id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid uuid")
+		return
+	}
+
+	delivery, err := h.service.ReplayDelivery(r.Context(), deliveryID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Render(w, r, http.StatusAccepted, DeliverySuccessResponse{
+		Status:   "success",
+		Message:  "Webhook delivery queued for replay",
+		Delivery: dto.ToDeliveryResponse(delivery),
+	})
+}
+
+func (h *WebhookHandler) handleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrSubscriptionNotFound):
+		response.Error(w, http.StatusNotFound, "Webhook subscription not found")
+	case errors.Is(err, ErrDeliveryNotFound):
+		response.Error(w, http.StatusNotFound, "Webhook delivery not found")
+	case errors.Is(err, ErrUnknownEventType):
+		response.ValidationError(w, []response.ValidationErrorDetail{{
+			Field:   "event_types",
+			Message: err.Error(),
+		}})
+	default:
+		h.logger.Error().Err(err).Msg("unexpected error")
+		response.Error(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
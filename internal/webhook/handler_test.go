@@ -0,0 +1,399 @@
+package webhook_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"go-boilerplate-rest-api-chi/internal/auth"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/response"
+	"go-boilerplate-rest-api-chi/internal/validator"
+	"go-boilerplate-rest-api-chi/internal/webhook"
+	"go-boilerplate-rest-api-chi/internal/webhook/dto"
+)
+
+var admin = &auth.Principal{Subject: "admin-1", Scopes: []string{webhook.AdminScope}}
+
+func TestWebhookHandler_CreateSubscription(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		requestBody        interface{}
+		configureMock      func(*mocks.MockWebhookService)
+		expectedStatusCode int
+		expectedResponse   interface{}
+	}{
+		{
+			name: "success create subscription",
+			requestBody: dto.CreateSubscriptionRequest{
+				URL:        "https://example.com/hooks",
+				EventTypes: []string{"book.created", "author.*"},
+			},
+			configureMock: func(mockService *mocks.MockWebhookService) {
+				mockService.EXPECT().
+					CreateSubscription(gomock.Any(), &dto.CreateSubscriptionRequest{
+						URL:        "https://example.com/hooks",
+						EventTypes: []string{"book.created", "author.*"},
+					}).
+					Return(&entity.WebhookSubscription{
+						ID:         uuid.MustParse("6f0b8a5e-3b7e-4c55-9f0e-8b1c2d3e4f50"),
+						URL:        "https://example.com/hooks",
+						Secret:     "generated-secret",
+						EventTypes: "book.created,author.*",
+						Active:     true,
+						CreatedAt:  createdAt,
+					}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse: &webhook.SubscriptionSuccessResponse{
+				Status:  "success",
+				Message: "Webhook subscription created successfully",
+				Subscription: &dto.SubscriptionResponse{
+					ID:         "6f0b8a5e-3b7e-4c55-9f0e-8b1c2d3e4f50",
+					URL:        "https://example.com/hooks",
+					EventTypes: []string{"book.created", "author.*"},
+					Active:     true,
+					Secret:     "generated-secret",
+					CreatedAt:  createdAt,
+				},
+			},
+		},
+		{
+			name: "error validation fails invalid url",
+			requestBody: dto.CreateSubscriptionRequest{
+				URL:        "not-a-url",
+				EventTypes: []string{"book.created"},
+			},
+			configureMock:      func(mockService *mocks.MockWebhookService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ValidationErrorResponse{
+				Status:  "error",
+				Message: "Validation failed",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "url",
+					Message: "url must be a valid URL",
+					Tag:     "url",
+				}},
+			},
+		},
+		{
+			name: "error validation fails scheme other than http",
+			requestBody: dto.CreateSubscriptionRequest{
+				URL:        "ftp://example.com/hooks",
+				EventTypes: []string{"book.created"},
+			},
+			configureMock:      func(mockService *mocks.MockWebhookService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ValidationErrorResponse{
+				Status:  "error",
+				Message: "Validation failed",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "url",
+					Message: "url must be an http or https URL outside of the private networks",
+					Tag:     "webhook_url",
+				}},
+			},
+		},
+		{
+			name: "error validation fails loopback target",
+			requestBody: dto.CreateSubscriptionRequest{
+				URL:        "http://127.0.0.1:8080/hooks",
+				EventTypes: []string{"book.created"},
+			},
+			configureMock:      func(mockService *mocks.MockWebhookService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ValidationErrorResponse{
+				Status:  "error",
+				Message: "Validation failed",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "url",
+					Message: "url must be an http or https URL outside of the private networks",
+					Tag:     "webhook_url",
+				}},
+			},
+		},
+		{
+			name: "error validation fails link-local target",
+			requestBody: dto.CreateSubscriptionRequest{
+				URL:        "http://169.254.169.254/latest/meta-data",
+				EventTypes: []string{"book.created"},
+			},
+			configureMock:      func(mockService *mocks.MockWebhookService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ValidationErrorResponse{
+				Status:  "error",
+				Message: "Validation failed",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "url",
+					Message: "url must be an http or https URL outside of the private networks",
+					Tag:     "webhook_url",
+				}},
+			},
+		},
+		{
+			name: "error validation fails private target",
+			requestBody: dto.CreateSubscriptionRequest{
+				URL:        "https://10.0.0.12/hooks",
+				EventTypes: []string{"book.created"},
+			},
+			configureMock:      func(mockService *mocks.MockWebhookService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ValidationErrorResponse{
+				Status:  "error",
+				Message: "Validation failed",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "url",
+					Message: "url must be an http or https URL outside of the private networks",
+					Tag:     "webhook_url",
+				}},
+			},
+		},
+		{
+			name: "error validation fails localhost",
+			requestBody: dto.CreateSubscriptionRequest{
+				URL:        "http://localhost/hooks",
+				EventTypes: []string{"book.created"},
+			},
+			configureMock:      func(mockService *mocks.MockWebhookService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ValidationErrorResponse{
+				Status:  "error",
+				Message: "Validation failed",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "url",
+					Message: "url must be an http or https URL outside of the private networks",
+					Tag:     "webhook_url",
+				}},
+			},
+		},
+		{
+			name: "error unknown event type",
+			requestBody: dto.CreateSubscriptionRequest{
+				URL:        "https://example.com/hooks",
+				EventTypes: []string{"book.created", "publisher.created"},
+			},
+			configureMock:      func(mockService *mocks.MockWebhookService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ValidationErrorResponse{
+				Status:  "error",
+				Message: "Validation failed",
+				Errors: []response.ValidationErrorDetail{{
+					Field:   "event_types[1]",
+					Message: "event_types[1] must be a known event type or pattern",
+					Tag:     "event_pattern",
+				}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockService := mocks.NewMockWebhookService(ctrl)
+			test.configureMock(mockService)
+
+			v := validator.New()
+			webhook.RegisterValidations(v)
+			handler := webhook.NewWebhookHandler(mockService, v, zerolog.Nop())
+
+			b, err := json.Marshal(test.requestBody)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/subscriptions", bytes.NewBuffer(b))
+			req.Header.Set("Content-Type", "application/json")
+			req = req.WithContext(auth.WithPrincipal(req.Context(), admin))
+			w := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Mount("/webhooks", handler.Routes())
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+
+			expectedJSON, err := json.Marshal(test.expectedResponse)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, string(expectedJSON), w.Body.String())
+		})
+	}
+}
+
+func TestWebhookHandler_ReplayDelivery(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		idInUrlParam       string
+		configureMock      func(*mocks.MockWebhookService)
+		expectedStatusCode int
+		expectedResponse   interface{}
+	}{
+		{
+			name:         "success replay delivery",
+			idInUrlParam: "0d6f1b8e-8e0a-4a43-9a55-2f1c8b7b3e42",
+			configureMock: func(mockService *mocks.MockWebhookService) {
+				mockService.EXPECT().
+					ReplayDelivery(gomock.Any(), uuid.MustParse("0d6f1b8e-8e0a-4a43-9a55-2f1c8b7b3e42")).
+					Return(&entity.WebhookDelivery{
+						ID:             uuid.MustParse("9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"),
+						SubscriptionID: uuid.MustParse("6f0b8a5e-3b7e-4c55-9f0e-8b1c2d3e4f50"),
+						EventID:        uuid.MustParse("5c1e8a2b-7d4f-4e3a-9b6c-1a2b3c4d5e6f"),
+						EventType:      "book.created",
+						Status:         entity.WebhookDeliveryPending,
+						NextAttemptAt:  createdAt,
+						CreatedAt:      createdAt,
+						UpdatedAt:      createdAt,
+					}, nil)
+			},
+			expectedStatusCode: http.StatusAccepted,
+			expectedResponse: &webhook.DeliverySuccessResponse{
+				Status:  "success",
+				Message: "Webhook delivery queued for replay",
+				Delivery: &dto.DeliveryResponse{
+					ID:             "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d",
+					SubscriptionID: "6f0b8a5e-3b7e-4c55-9f0e-8b1c2d3e4f50",
+					EventID:        "5c1e8a2b-7d4f-4e3a-9b6c-1a2b3c4d5e6f",
+					EventType:      "book.created",
+					Status:         "pending",
+					NextAttemptAt:  createdAt,
+					CreatedAt:      createdAt,
+					UpdatedAt:      createdAt,
+				},
+			},
+		},
+		{
+			name:               "error invalid uuid",
+			idInUrlParam:       "invalid-uuid",
+			configureMock:      func(mockService *mocks.MockWebhookService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: response.ErrorResponse{
+				Status:  "error",
+				Message: "Invalid uuid",
+			},
+		},
+		{
+			name:         "error delivery not found",
+			idInUrlParam: "0d6f1b8e-8e0a-4a43-9a55-2f1c8b7b3e42",
+			configureMock: func(mockService *mocks.MockWebhookService) {
+				mockService.EXPECT().
+					ReplayDelivery(gomock.Any(), uuid.MustParse("0d6f1b8e-8e0a-4a43-9a55-2f1c8b7b3e42")).
+					Return(nil, webhook.ErrDeliveryNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: response.ErrorResponse{
+				Status:  "error",
+				Message: "Webhook delivery not found",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockService := mocks.NewMockWebhookService(ctrl)
+			test.configureMock(mockService)
+
+			handler := webhook.NewWebhookHandler(mockService, validator.New(), zerolog.Nop())
+
+			url := fmt.Sprintf("/webhooks/deliveries/%s/replay", test.idInUrlParam)
+			req := httptest.NewRequest(http.MethodPost, url, nil)
+			req = req.WithContext(auth.WithPrincipal(req.Context(), admin))
+			w := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Mount("/webhooks", handler.Routes())
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+
+			expectedJSON, err := json.Marshal(test.expectedResponse)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, string(expectedJSON), w.Body.String())
+		})
+	}
+}
+
+func TestWebhookHandler_Authorization(t *testing.T) {
+	routes := []struct {
+		method string
+		target string
+	}{
+		{http.MethodPost, "/webhooks/subscriptions"},
+		{http.MethodGet, "/webhooks/subscriptions"},
+		{http.MethodDelete, "/webhooks/subscriptions/6f0b8a5e-3b7e-4c55-9f0e-8b1c2d3e4f50"},
+		{http.MethodGet, "/webhooks/deliveries"},
+		{http.MethodGet, "/webhooks/deliveries/0d6f1b8e-8e0a-4a43-9a55-2f1c8b7b3e42"},
+		{http.MethodPost, "/webhooks/deliveries/0d6f1b8e-8e0a-4a43-9a55-2f1c8b7b3e42/replay"},
+	}
+
+	tests := []struct {
+		name               string
+		principal          *auth.Principal
+		expectedStatusCode int
+		expectedResponse   response.ErrorResponse
+	}{
+		{
+			name:               "error anonymous",
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "Unauthorized"},
+		},
+		{
+			name:               "error missing admin scope",
+			principal:          &auth.Principal{Subject: "user-1", Scopes: []string{"books:write"}},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   response.ErrorResponse{Status: "error", Message: "Forbidden"},
+		},
+	}
+
+	for _, test := range tests {
+		for _, route := range routes {
+			t.Run(test.name+" "+route.method+" "+route.target, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				t.Cleanup(ctrl.Finish)
+
+				// The service is never reached.
+				handler := webhook.NewWebhookHandler(mocks.NewMockWebhookService(ctrl), validator.New(), zerolog.Nop())
+
+				req := httptest.NewRequest(route.method, route.target, bytes.NewBufferString(`{"url": "http://169.254.169.254/", "event_types": ["*"]}`))
+				req.Header.Set("Content-Type", "application/json")
+				if test.principal != nil {
+					req = req.WithContext(auth.WithPrincipal(req.Context(), test.principal))
+				}
+				w := httptest.NewRecorder()
+
+				r := chi.NewRouter()
+				r.Mount("/webhooks", handler.Routes())
+
+				r.ServeHTTP(w, req)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+
+				expectedJSON, err := json.Marshal(test.expectedResponse)
+				require.NoError(t, err)
+
+				assert.JSONEq(t, string(expectedJSON), w.Body.String())
+			})
+		}
+	}
+}
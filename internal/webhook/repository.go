@@ -0,0 +1,192 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/webhook/dto"
+)
+
+// WebhookRepository reads the subscriptions and the deliveries of the tenant of the context,
+// through tenant.Plugin; the dispatcher reads the due deliveries across the tenants, see
+// tenant.AllTenants.
+//
+//go:generate mockgen -destination=../mocks/mock_webhook_repository.go -package=mocks go-boilerplate-rest-api-chi/internal/webhook WebhookRepository
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error)
+	GetActiveSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID uuid.UUID) error
+	CreateDeliveries(ctx context.Context, deliveries []*entity.WebhookDelivery) error
+	GetDeliveries(ctx context.Context, filter *dto.DeliveryFilter) ([]*entity.WebhookDelivery, error)
+	GetDeliveryByID(ctx context.Context, deliveryID uuid.UUID) (*entity.WebhookDelivery, error)
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*entity.WebhookDelivery, error)
+	ClaimDelivery(ctx context.Context, delivery *entity.WebhookDelivery, until time.Time) (bool, error)
+	UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+}
+
+type webhookRepository struct {
+	db     *gorm.DB
+	logger zerolog.Logger
+}
+
+func NewWebhookRepository(db *gorm.DB, logger zerolog.Logger) WebhookRepository {
+	return &webhookRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, subscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+	if err := r.db.WithContext(ctx).Create(subscription).Error; err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (r *webhookRepository) GetSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error) {
+	var subscriptions []*entity.WebhookSubscription
+
+	if err := r.db.WithContext(ctx).Order("created_at").Find(&subscriptions).Error; err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (r *webhookRepository) GetActiveSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error) {
+	var subscriptions []*entity.WebhookSubscription
+
+	if err := r.db.WithContext(ctx).Where("active = ?", true).Find(&subscriptions).Error; err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, subscriptionID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entity.WebhookSubscription{}, "id = ?", subscriptionID)
+	if result.Error != nil {
+		r.logger.Error().Err(result.Error).Msg("database error")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []*entity.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := r.db.WithContext(ctx).Create(deliveries).Error; err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return err
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) GetDeliveries(ctx context.Context, filter *dto.DeliveryFilter) ([]*entity.WebhookDelivery, error) {
+	var deliveries []*entity.WebhookDelivery
+
+	query := r.db.WithContext(ctx).Order("created_at DESC")
+	if filter != nil && filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter != nil && filter.SubscriptionID != "" {
+		query = query.Where("subscription_id = ?", filter.SubscriptionID)
+	}
+
+	if err := query.Find(&deliveries).Error; err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhookRepository) GetDeliveryByID(ctx context.Context, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	var delivery *entity.WebhookDelivery
+
+	if err := r.db.WithContext(ctx).Preload("Subscription").First(&delivery, "id = ?", deliveryID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDeliveryNotFound
+		}
+
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (r *webhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+	var deliveries []*entity.WebhookDelivery
+
+	err := r.db.WithContext(ctx).
+		Preload("Subscription").
+		Where("status = ? AND next_attempt_at <= ?", entity.WebhookDeliveryPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// ClaimDelivery pushes the next attempt of delivery to until, unless another replica did it
+// first. Only the caller that gets true may send the delivery.
+func (r *webhookRepository) ClaimDelivery(ctx context.Context, delivery *entity.WebhookDelivery, until time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", delivery.ID, entity.WebhookDeliveryPending, delivery.NextAttemptAt).
+		Update("next_attempt_at", until)
+	if result.Error != nil {
+		r.logger.Error().Err(result.Error).Msg("database error")
+		return false, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	delivery.NextAttemptAt = until
+	return true, nil
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	err := r.db.WithContext(ctx).
+		Model(&entity.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":           delivery.Status,
+			"attempts":         delivery.Attempts,
+			"next_attempt_at":  delivery.NextAttemptAt,
+			"last_status_code": delivery.LastStatusCode,
+			"last_error":       delivery.LastError,
+		}).Error
+	if err != nil {
+		r.logger.Error().Err(err).Msg("database error")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package webhook_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	"go-boilerplate-rest-api-chi/internal/test-utils"
+	"go-boilerplate-rest-api-chi/internal/webhook"
+)
+
+func TestWebhookRepository_DeleteSubscription(t *testing.T) {
+	tests := []struct {
+		name           string
+		subscriptionID uuid.UUID
+		configureMock  func(sqlmock.Sqlmock, uuid.UUID)
+		expectedError  error
+	}{
+		{
+			name:           "success delete subscription",
+			subscriptionID: uuid.MustParse("6f0b8a5e-3b7e-4c55-9f0e-8b1c2d3e4f50"),
+			configureMock: func(mock sqlmock.Sqlmock, id uuid.UUID) {
+				mock.ExpectExec("DELETE FROM `webhook_subscriptions` WHERE id = \\? AND `webhook_subscriptions`.`tenant_id` = \\?").
+					WithArgs(id, "acme").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:           "error subscription not found",
+			subscriptionID: uuid.MustParse("6f0b8a5e-3b7e-4c55-9f0e-8b1c2d3e4f50"),
+			configureMock: func(mock sqlmock.Sqlmock, id uuid.UUID) {
+				mock.ExpectExec("DELETE FROM `webhook_subscriptions` WHERE id = \\? AND `webhook_subscriptions`.`tenant_id` = \\?").
+					WithArgs(id, "acme").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedError: webhook.ErrSubscriptionNotFound,
+		},
+		{
+			name:           "error database connection failed",
+			subscriptionID: uuid.MustParse("6f0b8a5e-3b7e-4c55-9f0e-8b1c2d3e4f50"),
+			configureMock: func(mock sqlmock.Sqlmock, id uuid.UUID) {
+				mock.ExpectExec("DELETE FROM `webhook_subscriptions` WHERE id = \\? AND `webhook_subscriptions`.`tenant_id` = \\?").
+					WithArgs(id, "acme").
+					WillReturnError(gorm.ErrInvalidDB)
+			},
+			expectedError: gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := testutils.NewGormMySQL(t)
+			require.NoError(t, db.Use(tenant.Plugin{}))
+			test.configureMock(mock, test.subscriptionID)
+
+			repo := webhook.NewWebhookRepository(db, zerolog.Nop())
+
+			err := repo.DeleteSubscription(tenant.WithID(context.Background(), "acme"), test.subscriptionID)
+
+			assert.ErrorIs(t, err, test.expectedError)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWebhookRepository_ClaimDelivery(t *testing.T) {
+	tests := []struct {
+		name          string
+		rowsAffected  int64
+		expectedClaim bool
+	}{
+		{
+			name:          "success claim delivery",
+			rowsAffected:  1,
+			expectedClaim: true,
+		},
+		{
+			name:          "success already claimed by another replica",
+			rowsAffected:  0,
+			expectedClaim: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := testutils.NewGormMySQL(t)
+
+			dueAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+			until := dueAt.Add(time.Minute)
+			delivery := &entity.WebhookDelivery{ID: uuid.New(), NextAttemptAt: dueAt}
+
+			mock.ExpectExec("UPDATE `webhook_deliveries` SET `next_attempt_at`=\\?,`updated_at`=\\? WHERE id = \\? AND status = \\? AND next_attempt_at = \\?").
+				WithArgs(until, sqlmock.AnyArg(), delivery.ID, entity.WebhookDeliveryPending, dueAt).
+				WillReturnResult(sqlmock.NewResult(0, test.rowsAffected))
+
+			repo := webhook.NewWebhookRepository(db, zerolog.Nop())
+
+			claimed, err := repo.ClaimDelivery(context.Background(), delivery, until)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedClaim, claimed)
+			if test.expectedClaim {
+				assert.Equal(t, until, delivery.NextAttemptAt)
+			} else {
+				assert.Equal(t, dueAt, delivery.NextAttemptAt)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
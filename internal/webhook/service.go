@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/event"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	"go-boilerplate-rest-api-chi/internal/webhook/dto"
+)
+
+// WebhookService manages the subscriptions and the deliveries of the tenant of the context.
+//
+//go:generate mockgen -destination=../mocks/mock_webhook_service.go -package=mocks go-boilerplate-rest-api-chi/internal/webhook WebhookService
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, req *dto.CreateSubscriptionRequest) (*entity.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID uuid.UUID) error
+	GetDeliveries(ctx context.Context, filter *dto.DeliveryFilter) ([]*entity.WebhookDelivery, error)
+	GetDeliveryByID(ctx context.Context, deliveryID uuid.UUID) (*entity.WebhookDelivery, error)
+	ReplayDelivery(ctx context.Context, deliveryID uuid.UUID) (*entity.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	repository WebhookRepository
+	logger     zerolog.Logger
+}
+
+func NewWebhookService(repository WebhookRepository, logger zerolog.Logger) WebhookService {
+	return &webhookService{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+func (s *webhookService) CreateSubscription(ctx context.Context, req *dto.CreateSubscriptionRequest) (*entity.WebhookSubscription, error) {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, tenant.ErrNoTenant
+	}
+
+	for _, pattern := range req.EventTypes {
+		if !event.ValidPattern(pattern) {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownEventType, pattern)
+		}
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	subscription := &entity.WebhookSubscription{
+		TenantID:   tenantID,
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: strings.Join(req.EventTypes, ","),
+		Active:     true,
+	}
+
+	return s.repository.CreateSubscription(ctx, subscription)
+}
+
+func (s *webhookService) GetSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error) {
+	return s.repository.GetSubscriptions(ctx)
+}
+
+func (s *webhookService) DeleteSubscription(ctx context.Context, subscriptionID uuid.UUID) error {
+	return s.repository.DeleteSubscription(ctx, subscriptionID)
+}
+
+func (s *webhookService) GetDeliveries(ctx context.Context, filter *dto.DeliveryFilter) ([]*entity.WebhookDelivery, error) {
+	return s.repository.GetDeliveries(ctx, filter)
+}
+
+func (s *webhookService) GetDeliveryByID(ctx context.Context, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	return s.repository.GetDeliveryByID(ctx, deliveryID)
+}
+
+// ReplayDelivery queues a new delivery of the same payload, keeping the original one in
+// the history. It works for any status, dead letters included.
+func (s *webhookService) ReplayDelivery(ctx context.Context, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	original, err := s.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	replay := &entity.WebhookDelivery{
+		TenantID:       original.TenantID,
+		SubscriptionID: original.SubscriptionID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		Status:         entity.WebhookDeliveryPending,
+		NextAttemptAt:  time.Now(),
+	}
+
+	if err := s.repository.CreateDeliveries(ctx, []*entity.WebhookDelivery{replay}); err != nil {
+		return nil, err
+	}
+
+	return replay, nil
+}
+
+func generateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,165 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/internal/mocks"
+	"go-boilerplate-rest-api-chi/internal/tenant"
+	"go-boilerplate-rest-api-chi/internal/webhook"
+	"go-boilerplate-rest-api-chi/internal/webhook/dto"
+)
+
+func TestWebhookService_CreateSubscription(t *testing.T) {
+	acme := tenant.WithID(context.Background(), "acme")
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		input         *dto.CreateSubscriptionRequest
+		configureMock func(*mocks.MockWebhookRepository)
+		expectedError error
+	}{
+		{
+			name: "success create subscription with generated secret",
+			ctx:  acme,
+			input: &dto.CreateSubscriptionRequest{
+				URL:        "https://example.com/hooks",
+				EventTypes: []string{"book.created", "author.*"},
+			},
+			configureMock: func(mockRepo *mocks.MockWebhookRepository) {
+				mockRepo.EXPECT().
+					CreateSubscription(gomock.Any(), gomock.Cond(func(s *entity.WebhookSubscription) bool {
+						return s.TenantID == "acme" && s.EventTypes == "book.created,author.*" && len(s.Secret) == 64 && s.Active
+					})).
+					DoAndReturn(func(_ context.Context, s *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+						return s, nil
+					})
+			},
+		},
+		{
+			name: "error unknown event type",
+			ctx:  acme,
+			input: &dto.CreateSubscriptionRequest{
+				URL:        "https://example.com/hooks",
+				EventTypes: []string{"publisher.created"},
+			},
+			configureMock: func(mockRepo *mocks.MockWebhookRepository) {},
+			expectedError: webhook.ErrUnknownEventType,
+		},
+		{
+			name: "error no tenant",
+			ctx:  context.Background(),
+			input: &dto.CreateSubscriptionRequest{
+				URL:        "https://example.com/hooks",
+				EventTypes: []string{"book.created"},
+			},
+			configureMock: func(mockRepo *mocks.MockWebhookRepository) {},
+			expectedError: tenant.ErrNoTenant,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+			webhookRepoMock := mocks.NewMockWebhookRepository(ctrl)
+
+			test.configureMock(webhookRepoMock)
+			service := webhook.NewWebhookService(webhookRepoMock, zerolog.Nop())
+
+			result, err := service.CreateSubscription(test.ctx, test.input)
+
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.input.URL, result.URL)
+			}
+		})
+	}
+}
+
+func TestWebhookService_ReplayDelivery(t *testing.T) {
+	deliveryID := uuid.MustParse("0d6f1b8e-8e0a-4a43-9a55-2f1c8b7b3e42")
+
+	tests := []struct {
+		name          string
+		configureMock func(*mocks.MockWebhookRepository)
+		expectedError error
+	}{
+		{
+			name: "success replay dead delivery",
+			configureMock: func(mockRepo *mocks.MockWebhookRepository) {
+				original := &entity.WebhookDelivery{
+					ID:             deliveryID,
+					TenantID:       "acme",
+					SubscriptionID: uuid.New(),
+					EventID:        uuid.New(),
+					EventType:      "book.created",
+					Payload:        []byte(`{}`),
+					Status:         entity.WebhookDeliveryDead,
+					Attempts:       8,
+				}
+
+				mockRepo.EXPECT().GetDeliveryByID(gomock.Any(), deliveryID).Return(original, nil)
+				mockRepo.EXPECT().
+					CreateDeliveries(gomock.Any(), gomock.Cond(func(deliveries []*entity.WebhookDelivery) bool {
+						replay := deliveries[0]
+						return len(deliveries) == 1 &&
+							replay.TenantID == "acme" &&
+							replay.EventID == original.EventID &&
+							replay.SubscriptionID == original.SubscriptionID &&
+							replay.Status == entity.WebhookDeliveryPending &&
+							replay.Attempts == 0
+					})).
+					Return(nil)
+			},
+		},
+		{
+			name: "error delivery not found",
+			configureMock: func(mockRepo *mocks.MockWebhookRepository) {
+				mockRepo.EXPECT().GetDeliveryByID(gomock.Any(), deliveryID).Return(nil, webhook.ErrDeliveryNotFound)
+			},
+			expectedError: webhook.ErrDeliveryNotFound,
+		},
+		{
+			name: "error database error",
+			configureMock: func(mockRepo *mocks.MockWebhookRepository) {
+				mockRepo.EXPECT().GetDeliveryByID(gomock.Any(), deliveryID).Return(&entity.WebhookDelivery{}, nil)
+				mockRepo.EXPECT().CreateDeliveries(gomock.Any(), gomock.Any()).Return(errors.New("database connection failed"))
+			},
+			expectedError: errors.New("database connection failed"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+			webhookRepoMock := mocks.NewMockWebhookRepository(ctrl)
+
+			test.configureMock(webhookRepoMock)
+			service := webhook.NewWebhookService(webhookRepoMock, zerolog.Nop())
+
+			result, err := service.ReplayDelivery(tenant.WithID(context.Background(), "acme"), deliveryID)
+
+			if test.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, test.expectedError.Error(), err.Error())
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package webhook_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go-boilerplate-rest-api-chi/internal/webhook"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"type":"book.created"}`)
+	signature := webhook.Sign("secret", now, body)
+
+	tests := []struct {
+		name          string
+		secret        string
+		timestamp     time.Time
+		body          []byte
+		expectedError error
+	}{
+		{
+			name:      "valid signature",
+			secret:    "secret",
+			timestamp: now,
+			body:      body,
+		},
+		{
+			name:          "error wrong secret",
+			secret:        "other",
+			timestamp:     now,
+			body:          body,
+			expectedError: webhook.ErrInvalidSignature,
+		},
+		{
+			name:          "error tampered body",
+			secret:        "secret",
+			timestamp:     now,
+			body:          []byte(`{"type":"book.deleted"}`),
+			expectedError: webhook.ErrInvalidSignature,
+		},
+		{
+			name:          "error expired timestamp",
+			secret:        "secret",
+			timestamp:     now.Add(-time.Hour),
+			body:          body,
+			expectedError: webhook.ErrInvalidSignature,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sig := signature
+			if !test.timestamp.Equal(now) {
+				sig = webhook.Sign(test.secret, test.timestamp, test.body)
+			}
+
+			err := webhook.Verify(test.secret, strconv.FormatInt(test.timestamp.Unix(), 10), sig, test.body, 5*time.Minute)
+
+			assert.ErrorIs(t, err, test.expectedError)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"github.com/go-playground/validator/v10"
+
+	"go-boilerplate-rest-api-chi/internal/event"
+	internalValidator "go-boilerplate-rest-api-chi/internal/validator"
+)
+
+// RegisterValidations adds the rules of the webhook DTOs to v.
+func RegisterValidations(v *internalValidator.Validator) {
+	v.RegisterRule("event_pattern", func(fl validator.FieldLevel) bool {
+		return event.ValidPattern(fl.Field().String())
+	}, internalValidator.Messages{
+		"en": "{0} must be a known event type or pattern",
+		"fr": "{0} doit être un type d'événement connu ou un motif",
+	})
+	v.RegisterRule("webhook_url", func(fl validator.FieldLevel) bool {
+		return validTarget(fl.Field().String())
+	}, internalValidator.Messages{
+		"en": "{0} must be an http or https URL outside of the private networks",
+		"fr": "{0} doit être une URL http ou https hors des réseaux privés",
+	})
+}
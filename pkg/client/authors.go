@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"go-boilerplate-rest-api-chi/internal/author/dto"
+)
+
+type (
+	Author              = dto.AuthorDetailResponse
+	AuthorBook          = dto.AuthorBookResponse
+	CreateAuthorRequest = dto.CreateAuthorRequest
+	RenameAuthorRequest = dto.RenameAuthorRequest
+)
+
+type AuthorBookPage struct {
+	Books      []AuthorBook `json:"books"`
+	Pagination Pagination   `json:"pagination"`
+}
+
+type authorEnvelope struct {
+	Author *Author `json:"author"`
+}
+
+func (c *Client) CreateAuthor(ctx context.Context, req CreateAuthorRequest) (*Author, error) {
+	var out authorEnvelope
+	if err := c.do(ctx, http.MethodPost, "/authors", nil, req, &out); err != nil {
+		return nil, err
+	}
+	return out.Author, nil
+}
+
+func (c *Client) GetAuthor(ctx context.Context, id uuid.UUID) (*Author, error) {
+	var out authorEnvelope
+	if err := c.do(ctx, http.MethodGet, "/authors/"+id.String(), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Author, nil
+}
+
+func (c *Client) RenameAuthor(ctx context.Context, id uuid.UUID, req RenameAuthorRequest) (*Author, error) {
+	var out authorEnvelope
+	if err := c.do(ctx, http.MethodPatch, "/authors/"+id.String(), nil, req, &out); err != nil {
+		return nil, err
+	}
+	return out.Author, nil
+}
+
+// ListAuthorBooksPage returns a single page of the books of an author, page and pageSize
+// defaulting to the first page of the default size of the server.
+func (c *Client) ListAuthorBooksPage(ctx context.Context, id uuid.UUID, page, pageSize int) (*AuthorBookPage, error) {
+	var out AuthorBookPage
+	if err := c.do(ctx, http.MethodGet, "/authors/"+id.String()+"/books", pageQuery(page, pageSize), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAuthorBooks iterates over the books of an author, fetching the pages of pageSize books
+// as the iteration goes. It stops after the first error.
+func (c *Client) ListAuthorBooks(ctx context.Context, id uuid.UUID, pageSize int) iter.Seq2[AuthorBook, error] {
+	return paginate(1, func(page int) ([]AuthorBook, Pagination, error) {
+		out, err := c.ListAuthorBooksPage(ctx, id, page, pageSize)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		return out.Books, out.Pagination, nil
+	})
+}
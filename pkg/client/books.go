@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"go-boilerplate-rest-api-chi/internal/book/dto"
+	"go-boilerplate-rest-api-chi/internal/pagination"
+)
+
+type (
+	Book              = dto.BookResponseV2
+	CreateBookRequest = dto.CreateBookRequest
+	UpdateBookRequest = dto.UpdateBookRequest
+	Pagination        = pagination.Meta
+)
+
+type ListBooksOptions struct {
+	Title    string
+	AuthorID uuid.UUID
+	// Page and PageSize default to the first page of the default size of the server.
+	Page     int
+	PageSize int
+}
+
+type BookPage struct {
+	Books      []Book     `json:"books"`
+	Pagination Pagination `json:"pagination"`
+}
+
+type bookEnvelope struct {
+	Book *Book `json:"book"`
+}
+
+func (c *Client) CreateBook(ctx context.Context, req CreateBookRequest) (*Book, error) {
+	var out bookEnvelope
+	if err := c.do(ctx, http.MethodPost, "/books", nil, req, &out); err != nil {
+		return nil, err
+	}
+	return out.Book, nil
+}
+
+func (c *Client) GetBook(ctx context.Context, id uuid.UUID) (*Book, error) {
+	var out bookEnvelope
+	if err := c.do(ctx, http.MethodGet, "/books/"+id.String(), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Book, nil
+}
+
+func (c *Client) UpdateBook(ctx context.Context, id uuid.UUID, req UpdateBookRequest) (*Book, error) {
+	var out bookEnvelope
+	if err := c.do(ctx, http.MethodPatch, "/books/"+id.String(), nil, req, &out); err != nil {
+		return nil, err
+	}
+	return out.Book, nil
+}
+
+func (c *Client) DeleteBook(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, "/books/"+id.String(), nil, nil, nil)
+}
+
+// ListBooksPage returns a single page of the books.
+func (c *Client) ListBooksPage(ctx context.Context, opts ListBooksOptions) (*BookPage, error) {
+	query := pageQuery(opts.Page, opts.PageSize)
+	if opts.Title != "" {
+		query.Set("title", opts.Title)
+	}
+	if opts.AuthorID != uuid.Nil {
+		query.Set("author_id", opts.AuthorID.String())
+	}
+
+	var out BookPage
+	if err := c.do(ctx, http.MethodGet, "/books", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBooks iterates over the books from opts.Page on, fetching the pages as the iteration
+// goes. It stops after the first error.
+func (c *Client) ListBooks(ctx context.Context, opts ListBooksOptions) iter.Seq2[Book, error] {
+	return paginate(opts.Page, func(page int) ([]Book, Pagination, error) {
+		opts.Page = page
+		out, err := c.ListBooksPage(ctx, opts)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		return out.Books, out.Pagination, nil
+	})
+}
+
+func pageQuery(page, pageSize int) url.Values {
+	query := url.Values{}
+	if page > 0 {
+		query.Set("page", strconv.Itoa(page))
+	}
+	if pageSize > 0 {
+		query.Set("page_size", strconv.Itoa(pageSize))
+	}
+	return query
+}
+
+func paginate[T any](first int, fetch func(page int) ([]T, Pagination, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		page := max(first, 1)
+		for {
+			items, meta, err := fetch(page)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if meta.Page >= meta.TotalPages || len(items) == 0 {
+				return
+			}
+			page = meta.Page + 1
+		}
+	}
+}
@@ -0,0 +1,224 @@
+// Package client is the typed Go client of the API. It targets the v2 routes and reuses the
+// DTOs of the server, aliased here to be usable outside of this module.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	basePath = "/api/v2"
+
+	defaultMaxAttempts = 3
+	defaultBaseDelay   = 200 * time.Millisecond
+	defaultMaxDelay    = 5 * time.Second
+)
+
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	token       func(ctx context.Context) (string, error)
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+type Option func(*Client)
+
+// WithHTTPClient replaces http.DefaultClient, to set timeouts or a custom transport.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken authenticates every request with a static bearer token.
+func WithToken(token string) Option {
+	return WithTokenFunc(func(context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// WithTokenFunc authenticates every request with the bearer token returned by fn, called
+// before each attempt so that refreshed tokens are picked up.
+func WithTokenFunc(fn func(ctx context.Context) (string, error)) Option {
+	return func(c *Client) {
+		c.token = fn
+	}
+}
+
+// WithRetry sets the number of attempts of a request and the bounds of the exponential
+// backoff between them. A single attempt disables the retries.
+func WithRetry(maxAttempts int, baseDelay, maxDelay time.Duration) Option {
+	return func(c *Client) {
+		c.maxAttempts = maxAttempts
+		c.baseDelay = baseDelay
+		c.maxDelay = maxDelay
+	}
+}
+
+// New returns a client of the API served at baseURL, e.g. http://localhost:8080.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		httpClient:  http.DefaultClient,
+		maxAttempts: defaultMaxAttempts,
+		baseDelay:   defaultBaseDelay,
+		maxDelay:    defaultMaxDelay,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.maxAttempts < 1 {
+		c.maxAttempts = 1
+	}
+
+	return c
+}
+
+// do sends the request, retrying it on the failures the server did not act on, and decodes
+// the JSON response into out when it is not nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+	}
+
+	target := c.baseURL + basePath + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, method, target, body)
+		if err != nil {
+			if ctx.Err() != nil || attempt >= c.maxAttempts || !idempotent(method) {
+				return err
+			}
+			if err := c.wait(ctx, c.backoff(attempt)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if attempt < c.maxAttempts && retryable(method, resp.StatusCode) {
+			delay := retryAfter(resp, c.backoff(attempt))
+			drain(resp)
+			if err := c.wait(ctx, delay); err != nil {
+				return err
+			}
+			continue
+		}
+
+		defer drain(resp)
+		if resp.StatusCode >= http.StatusBadRequest {
+			return decodeError(resp)
+		}
+		if out == nil {
+			return nil
+		}
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+		return nil
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, target string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.token != nil {
+		token, err := c.token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+func (c *Client) wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// backoff doubles the base delay at every attempt up to the max delay, with the upper half
+// randomized.
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.baseDelay
+	for i := 1; i < attempt && delay < c.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > c.maxDelay {
+		delay = c.maxDelay
+	}
+
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + rand.N(half+1)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryable reports the responses worth another attempt. The throttled requests never
+// reached the handlers, the gateway errors might have and are only retried when idempotent.
+func retryable(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent(method)
+	}
+	return false
+}
+
+// retryAfter honours the Retry-After header, in seconds, over the computed backoff.
+func retryAfter(resp *http.Response, fallback time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// drain lets the connection be reused.
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,249 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"go-boilerplate-rest-api-chi/internal/api"
+	"go-boilerplate-rest-api-chi/internal/config"
+	"go-boilerplate-rest-api-chi/internal/entity"
+	"go-boilerplate-rest-api-chi/pkg/client"
+)
+
+const secret = "test-secret"
+
+// newServer serves the real router, wrapped by middleware when it is not nil.
+func newServer(t *testing.T, middleware func(http.Handler) http.Handler) *httptest.Server {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "library.db")), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&entity.Book{}, &entity.Author{}, &entity.WebhookSubscription{}, &entity.WebhookDelivery{}, &entity.OutboxMessage{}))
+
+	cfg := config.Config{
+		Api:  config.ApiConfig{Environment: "production"},
+		Auth: config.AuthConfig{JWTSecret: secret},
+	}
+	handler, _ := api.CreateApi(t.Context(), cfg, zerolog.Nop(), db, api.NewRepositories(t.Context(), cfg, zerolog.Nop(), db))
+	if middleware != nil {
+		handler = middleware(handler)
+	}
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestClient_Books(t *testing.T) {
+	server := newServer(t, nil)
+	c := client.New(server.URL)
+	ctx := context.Background()
+
+	author, err := c.CreateAuthor(ctx, client.CreateAuthorRequest{Name: "Victor Hugo"})
+	require.NoError(t, err)
+	authorID := uuid.MustParse(author.ID)
+
+	created, err := c.CreateBook(ctx, client.CreateBookRequest{Title: "Les Misérables", Description: "Jean Valjean", AuthorID: author.ID})
+	require.NoError(t, err)
+	assert.Equal(t, "Les Misérables", created.Title)
+	assert.Equal(t, author.ID, created.Author.ID)
+	bookID := uuid.MustParse(created.ID)
+
+	found, err := c.GetBook(ctx, bookID)
+	require.NoError(t, err)
+	assert.Equal(t, "Victor Hugo", found.Author.Name)
+
+	updated, err := c.UpdateBook(ctx, bookID, client.UpdateBookRequest{Description: "Cosette"})
+	require.NoError(t, err)
+	assert.Equal(t, "Cosette", updated.Description)
+
+	for _, title := range []string{"Notre-Dame de Paris", "Les Contemplations", "Quatrevingt-treize", "L'Homme qui rit"} {
+		_, err := c.CreateBook(ctx, client.CreateBookRequest{Title: title, Description: "-", AuthorID: author.ID})
+		require.NoError(t, err)
+	}
+
+	page, err := c.ListBooksPage(ctx, client.ListBooksOptions{PageSize: 2})
+	require.NoError(t, err)
+	assert.Len(t, page.Books, 2)
+	assert.Equal(t, client.Pagination{Page: 1, PageSize: 2, TotalItems: 5, TotalPages: 3}, page.Pagination)
+
+	var titles []string
+	for book, err := range c.ListBooks(ctx, client.ListBooksOptions{AuthorID: authorID, PageSize: 2}) {
+		require.NoError(t, err)
+		titles = append(titles, book.Title)
+	}
+	assert.Equal(t, []string{"Les Misérables", "Notre-Dame de Paris", "Les Contemplations", "Quatrevingt-treize", "L'Homme qui rit"}, titles)
+
+	var authorBooks int
+	for _, err := range c.ListAuthorBooks(ctx, authorID, 2) {
+		require.NoError(t, err)
+		authorBooks++
+	}
+	assert.Equal(t, 5, authorBooks)
+
+	require.NoError(t, c.DeleteBook(ctx, bookID))
+
+	_, err = c.GetBook(ctx, bookID)
+	assert.ErrorIs(t, err, client.ErrNotFound)
+}
+
+func TestClient_Errors(t *testing.T) {
+	server := newServer(t, nil)
+	c := client.New(server.URL)
+	ctx := context.Background()
+
+	author, err := c.CreateAuthor(ctx, client.CreateAuthorRequest{Name: "Victor Hugo"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		call        func() error
+		assertError func(t *testing.T, err error)
+	}{
+		{
+			name: "error validation",
+			call: func() error {
+				_, err := c.CreateBook(ctx, client.CreateBookRequest{AuthorID: author.ID})
+				return err
+			},
+			assertError: func(t *testing.T, err error) {
+				var validationErr *client.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, "Validation failed", validationErr.Message)
+				assert.Equal(t, []client.ValidationErrorDetail{
+					{Field: "title", Message: "title is required", Tag: "required"},
+					{Field: "description", Message: "description is required", Tag: "required"},
+				}, validationErr.Errors)
+			},
+		},
+		{
+			name: "error not found",
+			call: func() error {
+				_, err := c.GetAuthor(ctx, uuid.New())
+				return err
+			},
+			assertError: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, client.ErrNotFound)
+				var apiErr *client.Error
+				require.ErrorAs(t, err, &apiErr)
+				assert.Equal(t, "Author not found", apiErr.Message)
+			},
+		},
+		{
+			name: "error unauthorized",
+			call: func() error {
+				_, err := client.New(server.URL, client.WithToken("unknown")).GetAuthor(ctx, uuid.New())
+				return err
+			},
+			assertError: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, client.ErrUnauthorized)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.assertError(t, test.call())
+		})
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	var calls atomic.Int32
+	server := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	c := client.New(server.URL, client.WithRetry(3, time.Millisecond, 10*time.Millisecond))
+
+	_, err := c.GetAuthor(context.Background(), uuid.New())
+
+	assert.ErrorIs(t, err, client.ErrNotFound)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_RetryNotIdempotent(t *testing.T) {
+	var calls atomic.Int32
+	server := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+	})
+
+	c := client.New(server.URL, client.WithRetry(3, time.Millisecond, 10*time.Millisecond))
+
+	_, err := c.CreateAuthor(context.Background(), client.CreateAuthorRequest{Name: "Victor Hugo"})
+
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_ContextCancellation(t *testing.T) {
+	server := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+	})
+
+	c := client.New(server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.GetBook(ctx, uuid.New())
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestClient_Token(t *testing.T) {
+	server := newServer(t, nil)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	c := client.New(server.URL, client.WithToken(token))
+
+	// The token is accepted, the author is simply missing.
+	_, err = c.GetAuthor(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, client.ErrNotFound)
+}
+
+func TestClient_TokenError(t *testing.T) {
+	server := newServer(t, nil)
+	c := client.New(server.URL, client.WithTokenFunc(func(context.Context) (string, error) {
+		return "", errors.New("token expired")
+	}))
+
+	_, err := c.GetBook(context.Background(), uuid.New())
+
+	assert.ErrorContains(t, err, "token expired")
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go-boilerplate-rest-api-chi/internal/response"
+)
+
+// The errors matched by errors.Is on the *Error of the corresponding status.
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+)
+
+var statusErrors = map[int]error{
+	http.StatusUnauthorized: ErrUnauthorized,
+	http.StatusForbidden:    ErrForbidden,
+	http.StatusNotFound:     ErrNotFound,
+	http.StatusConflict:     ErrConflict,
+}
+
+type ValidationErrorDetail = response.ValidationErrorDetail
+
+// Error is an error response of the API.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("api: %d %s", e.StatusCode, e.Message)
+}
+
+func (e *Error) Is(target error) bool {
+	return statusErrors[e.StatusCode] == target
+}
+
+// ValidationError is a request rejected for its invalid fields.
+type ValidationError struct {
+	Message string
+	Errors  []ValidationErrorDetail
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, len(e.Errors))
+	for i, detail := range e.Errors {
+		fields[i] = detail.Field + ": " + detail.Message
+	}
+	return fmt.Sprintf("api: %s: %s", e.Message, strings.Join(fields, ", "))
+}
+
+func decodeError(resp *http.Response) error {
+	var body response.ValidationErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Message == "" {
+		return &Error{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+	}
+
+	if resp.StatusCode == http.StatusBadRequest && len(body.Errors) > 0 {
+		return &ValidationError{Message: body.Message, Errors: body.Errors}
+	}
+
+	return &Error{StatusCode: resp.StatusCode, Message: body.Message}
+}
@@ -0,0 +1,613 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: library/v1/author_service.proto
+
+package libraryv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateAuthorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAuthorRequest) Reset() {
+	*x = CreateAuthorRequest{}
+	mi := &file_library_v1_author_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAuthorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAuthorRequest) ProtoMessage() {}
+
+func (x *CreateAuthorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_author_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAuthorRequest.ProtoReflect.Descriptor instead.
+func (*CreateAuthorRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_author_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateAuthorRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateAuthorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Author        *Author                `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAuthorResponse) Reset() {
+	*x = CreateAuthorResponse{}
+	mi := &file_library_v1_author_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAuthorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAuthorResponse) ProtoMessage() {}
+
+func (x *CreateAuthorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_author_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAuthorResponse.ProtoReflect.Descriptor instead.
+func (*CreateAuthorResponse) Descriptor() ([]byte, []int) {
+	return file_library_v1_author_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateAuthorResponse) GetAuthor() *Author {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+type GetAuthorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAuthorRequest) Reset() {
+	*x = GetAuthorRequest{}
+	mi := &file_library_v1_author_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAuthorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAuthorRequest) ProtoMessage() {}
+
+func (x *GetAuthorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_author_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAuthorRequest.ProtoReflect.Descriptor instead.
+func (*GetAuthorRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_author_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetAuthorRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetAuthorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Author        *Author                `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAuthorResponse) Reset() {
+	*x = GetAuthorResponse{}
+	mi := &file_library_v1_author_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAuthorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAuthorResponse) ProtoMessage() {}
+
+func (x *GetAuthorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_author_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAuthorResponse.ProtoReflect.Descriptor instead.
+func (*GetAuthorResponse) Descriptor() ([]byte, []int) {
+	return file_library_v1_author_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetAuthorResponse) GetAuthor() *Author {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+type RenameAuthorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameAuthorRequest) Reset() {
+	*x = RenameAuthorRequest{}
+	mi := &file_library_v1_author_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameAuthorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameAuthorRequest) ProtoMessage() {}
+
+func (x *RenameAuthorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_author_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameAuthorRequest.ProtoReflect.Descriptor instead.
+func (*RenameAuthorRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_author_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RenameAuthorRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RenameAuthorRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type RenameAuthorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Author        *Author                `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameAuthorResponse) Reset() {
+	*x = RenameAuthorResponse{}
+	mi := &file_library_v1_author_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameAuthorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameAuthorResponse) ProtoMessage() {}
+
+func (x *RenameAuthorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_author_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameAuthorResponse.ProtoReflect.Descriptor instead.
+func (*RenameAuthorResponse) Descriptor() ([]byte, []int) {
+	return file_library_v1_author_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RenameAuthorResponse) GetAuthor() *Author {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+type GetAuthorStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAuthorStatsRequest) Reset() {
+	*x = GetAuthorStatsRequest{}
+	mi := &file_library_v1_author_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAuthorStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAuthorStatsRequest) ProtoMessage() {}
+
+func (x *GetAuthorStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_author_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAuthorStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetAuthorStatsRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_author_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetAuthorStatsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetAuthorStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         *AuthorStats           `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAuthorStatsResponse) Reset() {
+	*x = GetAuthorStatsResponse{}
+	mi := &file_library_v1_author_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAuthorStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAuthorStatsResponse) ProtoMessage() {}
+
+func (x *GetAuthorStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_author_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAuthorStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetAuthorStatsResponse) Descriptor() ([]byte, []int) {
+	return file_library_v1_author_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetAuthorStatsResponse) GetStats() *AuthorStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+type ListAuthorBooksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// 1-based, defaults to the first page.
+	Page int32 `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	// Defaults to 20, at most 100.
+	PageSize      int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuthorBooksRequest) Reset() {
+	*x = ListAuthorBooksRequest{}
+	mi := &file_library_v1_author_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuthorBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuthorBooksRequest) ProtoMessage() {}
+
+func (x *ListAuthorBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_author_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuthorBooksRequest.ProtoReflect.Descriptor instead.
+func (*ListAuthorBooksRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_author_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListAuthorBooksRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ListAuthorBooksRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListAuthorBooksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListAuthorBooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Books         []*Book                `protobuf:"bytes,1,rep,name=books,proto3" json:"books,omitempty"`
+	TotalSize     int64                  `protobuf:"varint,2,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuthorBooksResponse) Reset() {
+	*x = ListAuthorBooksResponse{}
+	mi := &file_library_v1_author_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuthorBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuthorBooksResponse) ProtoMessage() {}
+
+func (x *ListAuthorBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_author_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuthorBooksResponse.ProtoReflect.Descriptor instead.
+func (*ListAuthorBooksResponse) Descriptor() ([]byte, []int) {
+	return file_library_v1_author_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListAuthorBooksResponse) GetBooks() []*Book {
+	if x != nil {
+		return x.Books
+	}
+	return nil
+}
+
+func (x *ListAuthorBooksResponse) GetTotalSize() int64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+var File_library_v1_author_service_proto protoreflect.FileDescriptor
+
+const file_library_v1_author_service_proto_rawDesc = "" +
+	"\n" +
+	"\x1flibrary/v1/author_service.proto\x12\n" +
+	"library.v1\x1a\x1alibrary/v1/resources.proto\")\n" +
+	"\x13CreateAuthorRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"B\n" +
+	"\x14CreateAuthorResponse\x12*\n" +
+	"\x06author\x18\x01 \x01(\v2\x12.library.v1.AuthorR\x06author\"\"\n" +
+	"\x10GetAuthorRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"?\n" +
+	"\x11GetAuthorResponse\x12*\n" +
+	"\x06author\x18\x01 \x01(\v2\x12.library.v1.AuthorR\x06author\"9\n" +
+	"\x13RenameAuthorRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"B\n" +
+	"\x14RenameAuthorResponse\x12*\n" +
+	"\x06author\x18\x01 \x01(\v2\x12.library.v1.AuthorR\x06author\"'\n" +
+	"\x15GetAuthorStatsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"G\n" +
+	"\x16GetAuthorStatsResponse\x12-\n" +
+	"\x05stats\x18\x01 \x01(\v2\x17.library.v1.AuthorStatsR\x05stats\"Y\n" +
+	"\x16ListAuthorBooksRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"`\n" +
+	"\x17ListAuthorBooksResponse\x12&\n" +
+	"\x05books\x18\x01 \x03(\v2\x10.library.v1.BookR\x05books\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x02 \x01(\x03R\ttotalSize2\xb4\x03\n" +
+	"\rAuthorService\x12Q\n" +
+	"\fCreateAuthor\x12\x1f.library.v1.CreateAuthorRequest\x1a .library.v1.CreateAuthorResponse\x12H\n" +
+	"\tGetAuthor\x12\x1c.library.v1.GetAuthorRequest\x1a\x1d.library.v1.GetAuthorResponse\x12Q\n" +
+	"\fRenameAuthor\x12\x1f.library.v1.RenameAuthorRequest\x1a .library.v1.RenameAuthorResponse\x12W\n" +
+	"\x0eGetAuthorStats\x12!.library.v1.GetAuthorStatsRequest\x1a\".library.v1.GetAuthorStatsResponse\x12Z\n" +
+	"\x0fListAuthorBooks\x12\".library.v1.ListAuthorBooksRequest\x1a#.library.v1.ListAuthorBooksResponseB\xa6\x01\n" +
+	"\x0ecom.library.v1B\x12AuthorServiceProtoP\x01Z7go-boilerplate-rest-api-chi/pkg/pb/library/v1;libraryv1\xa2\x02\x03LXX\xaa\x02\n" +
+	"Library.V1\xca\x02\n" +
+	"Library\\V1\xe2\x02\x16Library\\V1\\GPBMetadata\xea\x02\vLibrary::V1b\x06proto3"
+
+var (
+	file_library_v1_author_service_proto_rawDescOnce sync.Once
+	file_library_v1_author_service_proto_rawDescData []byte
+)
+
+func file_library_v1_author_service_proto_rawDescGZIP() []byte {
+	file_library_v1_author_service_proto_rawDescOnce.Do(func() {
+		file_library_v1_author_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_library_v1_author_service_proto_rawDesc), len(file_library_v1_author_service_proto_rawDesc)))
+	})
+	return file_library_v1_author_service_proto_rawDescData
+}
+
+var file_library_v1_author_service_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_library_v1_author_service_proto_goTypes = []any{
+	(*CreateAuthorRequest)(nil),     // 0: library.v1.CreateAuthorRequest
+	(*CreateAuthorResponse)(nil),    // 1: library.v1.CreateAuthorResponse
+	(*GetAuthorRequest)(nil),        // 2: library.v1.GetAuthorRequest
+	(*GetAuthorResponse)(nil),       // 3: library.v1.GetAuthorResponse
+	(*RenameAuthorRequest)(nil),     // 4: library.v1.RenameAuthorRequest
+	(*RenameAuthorResponse)(nil),    // 5: library.v1.RenameAuthorResponse
+	(*GetAuthorStatsRequest)(nil),   // 6: library.v1.GetAuthorStatsRequest
+	(*GetAuthorStatsResponse)(nil),  // 7: library.v1.GetAuthorStatsResponse
+	(*ListAuthorBooksRequest)(nil),  // 8: library.v1.ListAuthorBooksRequest
+	(*ListAuthorBooksResponse)(nil), // 9: library.v1.ListAuthorBooksResponse
+	(*Author)(nil),                  // 10: library.v1.Author
+	(*AuthorStats)(nil),             // 11: library.v1.AuthorStats
+	(*Book)(nil),                    // 12: library.v1.Book
+}
+var file_library_v1_author_service_proto_depIdxs = []int32{
+	10, // 0: library.v1.CreateAuthorResponse.author:type_name -> library.v1.Author
+	10, // 1: library.v1.GetAuthorResponse.author:type_name -> library.v1.Author
+	10, // 2: library.v1.RenameAuthorResponse.author:type_name -> library.v1.Author
+	11, // 3: library.v1.GetAuthorStatsResponse.stats:type_name -> library.v1.AuthorStats
+	12, // 4: library.v1.ListAuthorBooksResponse.books:type_name -> library.v1.Book
+	0,  // 5: library.v1.AuthorService.CreateAuthor:input_type -> library.v1.CreateAuthorRequest
+	2,  // 6: library.v1.AuthorService.GetAuthor:input_type -> library.v1.GetAuthorRequest
+	4,  // 7: library.v1.AuthorService.RenameAuthor:input_type -> library.v1.RenameAuthorRequest
+	6,  // 8: library.v1.AuthorService.GetAuthorStats:input_type -> library.v1.GetAuthorStatsRequest
+	8,  // 9: library.v1.AuthorService.ListAuthorBooks:input_type -> library.v1.ListAuthorBooksRequest
+	1,  // 10: library.v1.AuthorService.CreateAuthor:output_type -> library.v1.CreateAuthorResponse
+	3,  // 11: library.v1.AuthorService.GetAuthor:output_type -> library.v1.GetAuthorResponse
+	5,  // 12: library.v1.AuthorService.RenameAuthor:output_type -> library.v1.RenameAuthorResponse
+	7,  // 13: library.v1.AuthorService.GetAuthorStats:output_type -> library.v1.GetAuthorStatsResponse
+	9,  // 14: library.v1.AuthorService.ListAuthorBooks:output_type -> library.v1.ListAuthorBooksResponse
+	10, // [10:15] is the sub-list for method output_type
+	5,  // [5:10] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_library_v1_author_service_proto_init() }
+func file_library_v1_author_service_proto_init() {
+	if File_library_v1_author_service_proto != nil {
+		return
+	}
+	file_library_v1_resources_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_library_v1_author_service_proto_rawDesc), len(file_library_v1_author_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_library_v1_author_service_proto_goTypes,
+		DependencyIndexes: file_library_v1_author_service_proto_depIdxs,
+		MessageInfos:      file_library_v1_author_service_proto_msgTypes,
+	}.Build()
+	File_library_v1_author_service_proto = out.File
+	file_library_v1_author_service_proto_goTypes = nil
+	file_library_v1_author_service_proto_depIdxs = nil
+}
@@ -0,0 +1,335 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: library/v1/resources.proto
+
+package libraryv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Book struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title       string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	AuthorId    string                 `protobuf:"bytes,4,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	// Only set when requested with include_author.
+	Author        *Author                `protobuf:"bytes,5,opt,name=author,proto3" json:"author,omitempty"`
+	CreateTime    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	UpdateTime    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Book) Reset() {
+	*x = Book{}
+	mi := &file_library_v1_resources_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Book) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Book) ProtoMessage() {}
+
+func (x *Book) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_resources_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Book.ProtoReflect.Descriptor instead.
+func (*Book) Descriptor() ([]byte, []int) {
+	return file_library_v1_resources_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Book) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Book) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Book) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Book) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *Book) GetAuthor() *Author {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+func (x *Book) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *Book) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+type Author struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreateTime    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	UpdateTime    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Author) Reset() {
+	*x = Author{}
+	mi := &file_library_v1_resources_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Author) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Author) ProtoMessage() {}
+
+func (x *Author) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_resources_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Author.ProtoReflect.Descriptor instead.
+func (*Author) Descriptor() ([]byte, []int) {
+	return file_library_v1_resources_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Author) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Author) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Author) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *Author) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+type AuthorStats struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	BookCount int64                  `protobuf:"varint,1,opt,name=book_count,json=bookCount,proto3" json:"book_count,omitempty"`
+	// Unset when the author has no book.
+	FirstPublishedTime  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=first_published_time,json=firstPublishedTime,proto3" json:"first_published_time,omitempty"`
+	LatestPublishedTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=latest_published_time,json=latestPublishedTime,proto3" json:"latest_published_time,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *AuthorStats) Reset() {
+	*x = AuthorStats{}
+	mi := &file_library_v1_resources_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorStats) ProtoMessage() {}
+
+func (x *AuthorStats) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_resources_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorStats.ProtoReflect.Descriptor instead.
+func (*AuthorStats) Descriptor() ([]byte, []int) {
+	return file_library_v1_resources_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AuthorStats) GetBookCount() int64 {
+	if x != nil {
+		return x.BookCount
+	}
+	return 0
+}
+
+func (x *AuthorStats) GetFirstPublishedTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstPublishedTime
+	}
+	return nil
+}
+
+func (x *AuthorStats) GetLatestPublishedTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LatestPublishedTime
+	}
+	return nil
+}
+
+var File_library_v1_resources_proto protoreflect.FileDescriptor
+
+const file_library_v1_resources_proto_rawDesc = "" +
+	"\n" +
+	"\x1alibrary/v1/resources.proto\x12\n" +
+	"library.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x91\x02\n" +
+	"\x04Book\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1b\n" +
+	"\tauthor_id\x18\x04 \x01(\tR\bauthorId\x12*\n" +
+	"\x06author\x18\x05 \x01(\v2\x12.library.v1.AuthorR\x06author\x12;\n" +
+	"\vcreate_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x12;\n" +
+	"\vupdate_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"updateTime\"\xa6\x01\n" +
+	"\x06Author\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12;\n" +
+	"\vcreate_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x12;\n" +
+	"\vupdate_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"updateTime\"\xca\x01\n" +
+	"\vAuthorStats\x12\x1d\n" +
+	"\n" +
+	"book_count\x18\x01 \x01(\x03R\tbookCount\x12L\n" +
+	"\x14first_published_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x12firstPublishedTime\x12N\n" +
+	"\x15latest_published_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x13latestPublishedTimeB\xa2\x01\n" +
+	"\x0ecom.library.v1B\x0eResourcesProtoP\x01Z7go-boilerplate-rest-api-chi/pkg/pb/library/v1;libraryv1\xa2\x02\x03LXX\xaa\x02\n" +
+	"Library.V1\xca\x02\n" +
+	"Library\\V1\xe2\x02\x16Library\\V1\\GPBMetadata\xea\x02\vLibrary::V1b\x06proto3"
+
+var (
+	file_library_v1_resources_proto_rawDescOnce sync.Once
+	file_library_v1_resources_proto_rawDescData []byte
+)
+
+func file_library_v1_resources_proto_rawDescGZIP() []byte {
+	file_library_v1_resources_proto_rawDescOnce.Do(func() {
+		file_library_v1_resources_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_library_v1_resources_proto_rawDesc), len(file_library_v1_resources_proto_rawDesc)))
+	})
+	return file_library_v1_resources_proto_rawDescData
+}
+
+var file_library_v1_resources_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_library_v1_resources_proto_goTypes = []any{
+	(*Book)(nil),                  // 0: library.v1.Book
+	(*Author)(nil),                // 1: library.v1.Author
+	(*AuthorStats)(nil),           // 2: library.v1.AuthorStats
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+}
+var file_library_v1_resources_proto_depIdxs = []int32{
+	1, // 0: library.v1.Book.author:type_name -> library.v1.Author
+	3, // 1: library.v1.Book.create_time:type_name -> google.protobuf.Timestamp
+	3, // 2: library.v1.Book.update_time:type_name -> google.protobuf.Timestamp
+	3, // 3: library.v1.Author.create_time:type_name -> google.protobuf.Timestamp
+	3, // 4: library.v1.Author.update_time:type_name -> google.protobuf.Timestamp
+	3, // 5: library.v1.AuthorStats.first_published_time:type_name -> google.protobuf.Timestamp
+	3, // 6: library.v1.AuthorStats.latest_published_time:type_name -> google.protobuf.Timestamp
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_library_v1_resources_proto_init() }
+func file_library_v1_resources_proto_init() {
+	if File_library_v1_resources_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_library_v1_resources_proto_rawDesc), len(file_library_v1_resources_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_library_v1_resources_proto_goTypes,
+		DependencyIndexes: file_library_v1_resources_proto_depIdxs,
+		MessageInfos:      file_library_v1_resources_proto_msgTypes,
+	}.Build()
+	File_library_v1_resources_proto = out.File
+	file_library_v1_resources_proto_goTypes = nil
+	file_library_v1_resources_proto_depIdxs = nil
+}